@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// activeRunners tracks the runners whose resources an interrupt must clean up.
+type activeRunners struct {
+	// mu guards runners.
+	mu sync.Mutex
+	// runners are the runners started so far.
+	runners []*CheckRunner
+}
+
+// add records a runner before it creates anything.
+func (a *activeRunners) add(r *CheckRunner) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runners = append(a.runners, r)
+}
+
+// cleanup cleans up every recorded runner at once and joins their errors.
+func (a *activeRunners) cleanup(ctx context.Context) error {
+	// Snapshot the runners so a run starting now does not race the cleanup.
+	a.mu.Lock()
+	runners := append([]*CheckRunner(nil), a.runners...)
+	a.mu.Unlock()
+
+	// Clean up concurrently so one slow runner does not use up the grace period of the rest.
+	errs := make([]error, len(runners))
+	var wg sync.WaitGroup
+	for i, runner := range runners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runner.cleanup(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
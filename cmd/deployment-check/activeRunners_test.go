@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestActiveRunnersCleanup validates an interrupt cleans up the resources of every started runner.
+func TestActiveRunnersCleanup(t *testing.T) {
+	// Start runners in two namespaces that each left a deployment behind.
+	base := buildTestRunner()
+	client := fake.NewClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: base.cfg.CheckDeploymentName, Namespace: "team-a"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: base.cfg.CheckDeploymentName, Namespace: "team-b"}},
+	)
+	active := &activeRunners{}
+	for _, namespace := range []string{"team-a", "team-b"} {
+		active.add(newCheckRunner(base.cfg.forNamespace(namespace), client, nil, time.Now()))
+	}
+
+	// Both deployments are removed.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := active.cleanup(ctx)
+	if err != nil {
+		t.Fatalf("expected cleanup to succeed but got %v", err)
+	}
+	deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+	if len(deployments.Items) != 0 {
+		t.Fatalf("expected every runner's deployment to be removed but %d remain", len(deployments.Items))
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// autopilotMinMillicores is the smallest CPU request Autopilot accepts for general-purpose pods.
+	autopilotMinMillicores = 50
+	// autopilotMinMemoryBytes is the smallest memory request Autopilot accepts (52Mi).
+	autopilotMinMemoryBytes = 52 * 1024 * 1024
+	// autopilotMaxMemoryPerMillicore caps memory at 6.5GiB per vCPU.
+	autopilotMaxMemoryPerMillicore = 6.5 * 1024 * 1024 * 1024 / 1000
+	// autopilotMinMemoryPerMillicore floors memory at 1GiB per vCPU.
+	autopilotMinMemoryPerMillicore = 1024 * 1024 * 1024 / 1000
+)
+
+// applyAutopilotResources adjusts resource requests to Autopilot minimums and ratios and pins limits to requests.
+func applyAutopilotResources(cfg *CheckConfig) {
+	// Raise requests to the Autopilot minimums.
+	if cfg.MillicoreRequest < autopilotMinMillicores {
+		cfg.MillicoreRequest = autopilotMinMillicores
+	}
+	if cfg.MemoryRequest < autopilotMinMemoryBytes {
+		cfg.MemoryRequest = autopilotMinMemoryBytes
+	}
+
+	// Keep memory within the allowed ratio to CPU.
+	minMemory := int(float64(cfg.MillicoreRequest) * autopilotMinMemoryPerMillicore)
+	maxMemory := int(float64(cfg.MillicoreRequest) * autopilotMaxMemoryPerMillicore)
+	if cfg.MemoryRequest < minMemory {
+		cfg.MemoryRequest = minMemory
+	}
+	if cfg.MemoryRequest > maxMemory {
+		cfg.MemoryRequest = maxMemory
+	}
+
+	// Autopilot rewrites limits to match requests, so set them that way up front.
+	cfg.MillicoreLimit = cfg.MillicoreRequest
+	cfg.MemoryLimit = cfg.MemoryRequest
+	log.Infoln("Autopilot mode adjusted resources to", cfg.MillicoreRequest, "millicores and", cfg.MemoryRequest, "bytes of memory.")
+}
+
+// applyAutopilotPodSpec makes the pod spec acceptable to Autopilot admission without relying on mutation.
+func applyAutopilotPodSpec(podSpec *corev1.PodSpec) {
+	// The check never talks to the API server, so avoid mounting a service account token.
+	automount := false
+	podSpec.AutomountServiceAccountToken = &automount
+
+	// Run as non-root with the runtime default seccomp profile.
+	runAsNonRoot := true
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	podSpec.SecurityContext.RunAsNonRoot = &runAsNonRoot
+	podSpec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+
+	// Drop privileges on every container, including init containers.
+	for i := range podSpec.InitContainers {
+		dropAutopilotPrivileges(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		dropAutopilotPrivileges(&podSpec.Containers[i])
+	}
+}
+
+// dropAutopilotPrivileges forbids privilege escalation and drops all capabilities on a container.
+func dropAutopilotPrivileges(container *corev1.Container) {
+	// Start from an empty security context when none is set.
+	allowPrivilegeEscalation := false
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+	container.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+	container.SecurityContext.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// retryBackoff returns a fresh exponential backoff with jitter shared by the HTTP prober and API retries.
+func (cfg *CheckConfig) retryBackoff() wait.Backoff {
+	// Grow from the initial delay by the factor until the cap, then keep retrying at the cap.
+	return wait.Backoff{
+		Duration: cfg.RetryBackoffInitial,
+		Factor:   cfg.RetryBackoffFactor,
+		Jitter:   cfg.RetryBackoffJitter,
+		Steps:    math.MaxInt32,
+		Cap:      cfg.RetryBackoffMax,
+	}
+}
+
+// sleepForRetry waits for the delay, returning early when ctx ends.
+func sleepForRetry(ctx context.Context, delay time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
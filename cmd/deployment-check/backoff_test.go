@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRetryBackoff validates retry delays grow exponentially within the jitter bound and settle at the cap.
+func TestRetryBackoff(t *testing.T) {
+	// Double from one second up to a four second cap with at most 50% jitter.
+	cfg := &CheckConfig{RetryBackoffInitial: time.Second, RetryBackoffMax: time.Second * 4, RetryBackoffFactor: 2, RetryBackoffJitter: 0.5}
+	backoff := cfg.retryBackoff()
+	for _, base := range []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 4, time.Second * 4} {
+		delay := backoff.Step()
+		if delay < base || delay > base+base/2 {
+			t.Fatalf("expected a delay between %s and %s but got %s", base, base+base/2, delay)
+		}
+	}
+
+	// Each call starts a fresh schedule.
+	fresh := cfg.retryBackoff()
+	delay := fresh.Step()
+	if delay > time.Second+time.Second/2 {
+		t.Fatalf("expected a fresh backoff to restart at the initial delay but got %s", delay)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// certManagerGroup is the cert-manager API group.
+	certManagerGroup = "cert-manager.io"
+	// certManagerIssuerKindIssuer references a namespaced Issuer.
+	certManagerIssuerKindIssuer = "Issuer"
+	// certManagerIssuerKindClusterIssuer references a ClusterIssuer.
+	certManagerIssuerKindClusterIssuer = "ClusterIssuer"
+	// certificateVolumeName names the issued certificate volume in the check pods.
+	certificateVolumeName = "check-tls"
+	// defaultCertManagerMountPath is where the issued certificate is mounted by default.
+	defaultCertManagerMountPath = "/etc/deployment-check/tls"
+	// defaultCertManagerTimeout is the window for the Certificate to become ready.
+	defaultCertManagerTimeout = time.Minute * 5
+)
+
+var (
+	// certificateResource identifies cert-manager Certificates.
+	certificateResource = schema.GroupVersionResource{Group: certManagerGroup, Version: "v1", Resource: "certificates"}
+)
+
+// checkCertificateName returns the name of the Certificate and of the Secret it issues into.
+func (cfg *CheckConfig) checkCertificateName() string {
+	// Derive the name from the deployment so both are cleaned up together.
+	return cfg.CheckDeploymentName + "-tls"
+}
+
+// certificateDNSNames returns the service names the issued certificate must cover, most specific last.
+func (cfg *CheckConfig) certificateDNSNames() []string {
+	// Cover the short, namespaced, and fully qualified service names.
+	return []string{
+		cfg.CheckServiceName,
+		cfg.CheckServiceName + "." + cfg.CheckNamespace,
+		cfg.CheckServiceName + "." + cfg.CheckNamespace + ".svc",
+		cfg.CheckServiceName + "." + cfg.CheckNamespace + ".svc." + cfg.ClusterDomain,
+	}
+}
+
+// createCertificateConfig builds the Certificate requested from the configured issuer for the check service names.
+func (r *CheckRunner) createCertificateConfig() *unstructured.Unstructured {
+	// Request a certificate for every service name from the configured issuer.
+	dnsNames := make([]interface{}, 0)
+	for _, name := range r.cfg.certificateDNSNames() {
+		dnsNames = append(dnsNames, name)
+	}
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": certManagerGroup + "/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      r.cfg.checkCertificateName(),
+			"namespace": r.cfg.CheckNamespace,
+		},
+		"spec": map[string]interface{}{
+			"secretName": r.cfg.checkCertificateName(),
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"name":  r.cfg.CertManagerIssuer,
+				"kind":  r.cfg.CertManagerIssuerKind,
+				"group": certManagerGroup,
+			},
+		},
+	}}
+	if len(r.cfg.ExtraLabels) != 0 {
+		certificate.SetLabels(r.cfg.resourceLabels())
+	}
+	annotations := r.resourceAnnotations(nil)
+	if len(annotations) != 0 {
+		certificate.SetAnnotations(annotations)
+	}
+	owners := r.ownerReferences()
+	if len(owners) != 0 {
+		certificate.SetOwnerReferences(owners)
+	}
+
+	return certificate
+}
+
+// createCertificateVolume builds the volume and mount carrying the issued certificate into the check container.
+func (r *CheckRunner) createCertificateVolume() (corev1.Volume, corev1.VolumeMount) {
+	// Mount the issued Secret read-only.
+	volume := corev1.Volume{
+		Name:         certificateVolumeName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: r.cfg.checkCertificateName()}},
+	}
+	mount := corev1.VolumeMount{Name: certificateVolumeName, MountPath: r.cfg.CertManagerMountPath, ReadOnly: true}
+
+	return volume, mount
+}
+
+// issueCertificate creates the Certificate and waits for cert-manager to report it ready.
+func (r *CheckRunner) issueCertificate(ctx context.Context) error {
+	// Create the Certificate through the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return err
+	}
+	requested := time.Now()
+	_, err = client.Resource(certificateResource).Namespace(r.cfg.CheckNamespace).Create(ctx, r.createCertificateConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create Certificate: %w", err)
+	}
+	log.Infoln("Created Certificate", r.cfg.checkCertificateName(), "from", r.cfg.CertManagerIssuerKind, r.cfg.CertManagerIssuer)
+	r.timeline.recordf("created certificate %s from %s %s", r.cfg.checkCertificateName(), r.cfg.CertManagerIssuerKind, r.cfg.CertManagerIssuer)
+
+	// Poll the Certificate until it is ready or the wait runs out.
+	deadline := time.Now().Add(r.cfg.CertManagerTimeout)
+	lastCondition := "none reported"
+	for {
+		certificate, err := client.Resource(certificateResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkCertificateName(), metav1.GetOptions{})
+		if err != nil {
+			log.Debugln("Failed to fetch Certificate:", err.Error())
+		}
+		if err == nil {
+			ready, condition := certificateReadyCondition(certificate)
+			if len(condition) != 0 {
+				lastCondition = condition
+				r.timeline.observe("certificate-ready", lastCondition, "certificate ready condition: "+lastCondition)
+			}
+			if ready {
+				issued := time.Since(requested).Round(time.Second)
+				log.Infoln("Certificate", r.cfg.checkCertificateName(), "issued after", issued)
+				r.timeline.recordf("certificate %s issued within %s", r.cfg.checkCertificateName(), issued)
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("certificate was not issued within %s; last Ready condition: %s", r.cfg.CertManagerTimeout, lastCondition)
+		}
+
+		// Wait before polling again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for the certificate to be issued; last Ready condition: %s", lastCondition)
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// certificateReadyCondition reports whether the Certificate is ready and describes its Ready condition.
+func certificateReadyCondition(certificate *unstructured.Unstructured) (bool, string) {
+	// Find the Ready condition, keeping the reason and message when it is not true.
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(conditionMap, "type")
+		if conditionType != "Ready" {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(conditionMap, "status")
+		if status == "True" {
+			return true, "Ready=True"
+		}
+		reason, _, _ := unstructured.NestedString(conditionMap, "reason")
+		message, _, _ := unstructured.NestedString(conditionMap, "message")
+		return false, fmt.Sprintf("Ready=%s reason: %s message: %s", status, reason, message)
+	}
+
+	return false, ""
+}
+
+// verifyCertificate validates the issued Secret and requires the service to serve it over HTTPS.
+func (r *CheckRunner) verifyCertificate(ctx context.Context, serviceIP string) error {
+	// Read the issued key pair and its CA.
+	secret, err := r.client.CoreV1().Secrets(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkCertificateName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get issued certificate secret: %w", err)
+	}
+	leaf, roots, err := parseIssuedCertificate(secret.Data[corev1.TLSCertKey], secret.Data["ca.crt"])
+	if err != nil {
+		return err
+	}
+
+	// Require the certificate to cover the service and chain to its CA.
+	serverName := r.cfg.certificateDNSNames()[3]
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: serverName, Roots: roots, Intermediates: issuedIntermediates(secret.Data[corev1.TLSCertKey])})
+	if err != nil {
+		return fmt.Errorf("issued certificate does not verify for %s: %w", serverName, err)
+	}
+	log.Infoln("Issued certificate for", serverName, "verifies and expires", leaf.NotAfter.Format(time.RFC3339))
+
+	// Request the service over HTTPS, trusting only the issued chain and requiring the issued leaf.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    roots,
+		ServerName: serverName,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 || !bytes.Equal(state.PeerCertificates[0].Raw, leaf.Raw) {
+				return fmt.Errorf("served certificate is not the one issued into secret %s", r.cfg.checkCertificateName())
+			}
+			return nil
+		},
+	}
+	address := "https://" + net.JoinHostPort(serviceIP, strconv.Itoa(int(r.cfg.CertManagerPort))) + r.cfg.HTTPPath
+	r.timeline.recordf("requesting %s with the issued certificate for %s", address, serverName)
+	err = r.requestEndpointWithTransport(ctx, transport, address, "")
+	if err != nil {
+		return fmt.Errorf("https request with the issued certificate failed: %w", err)
+	}
+
+	return nil
+}
+
+// parseIssuedCertificate returns the leaf certificate and the roots to trust, using the system roots when no CA was issued.
+func parseIssuedCertificate(certPEM []byte, caPEM []byte) (*x509.Certificate, *x509.CertPool, error) {
+	// Decode the leaf, which cert-manager writes first.
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("issued secret has no PEM certificate in %s", corev1.TLSCertKey)
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+
+	// Trust the issued CA when present; ACME issuers leave it empty.
+	if len(caPEM) == 0 {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load system roots: %w", err)
+		}
+		return leaf, roots, nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, nil, fmt.Errorf("issued secret has no PEM certificates in ca.crt")
+	}
+
+	return leaf, roots, nil
+}
+
+// issuedIntermediates collects the chain certificates that follow the leaf in the issued PEM.
+func issuedIntermediates(certPEM []byte) *x509.CertPool {
+	// Skip the leaf and add every following certificate.
+	pool := x509.NewCertPool()
+	rest := certPEM
+	leaf := true
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if leaf {
+			leaf = false
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		pool.AddCert(certificate)
+	}
+
+	return pool
+}
+
+// validCertManagerIssuerKind reports whether kind is an issuer kind cert-manager understands.
+func validCertManagerIssuerKind(kind string) bool {
+	return slices.Contains([]string{certManagerIssuerKindIssuer, certManagerIssuerKindClusterIssuer}, kind)
+}
+
+// deleteCertificateAndWait removes the Certificate and the Secret it issued, waiting for both to disappear.
+func (r *CheckRunner) deleteCertificateAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "Certificate and its secret", func(ctx context.Context) error {
+		// Delete the Certificate first so cert-manager does not reissue the Secret.
+		client, err := r.dynamicClient()
+		if err != nil {
+			return err
+		}
+		err = client.Resource(certificateResource).Namespace(r.cfg.CheckNamespace).Delete(ctx, r.cfg.checkCertificateName(), metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		// The issued Secret outlives its Certificate unless cert-manager sets owner references.
+		return r.client.CoreV1().Secrets(r.cfg.CheckNamespace).Delete(ctx, r.cfg.checkCertificateName(), metav1.DeleteOptions{})
+	}, r.certificateExists)
+}
+
+// certificateExists reports whether the Certificate or the Secret it issued is present.
+func (r *CheckRunner) certificateExists(ctx context.Context) (bool, error) {
+	// Look up the Certificate by name.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Resource(certificateResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkCertificateName(), metav1.GetOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+
+	// Look up the issued Secret by name.
+	_, err = r.client.CoreV1().Secrets(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkCertificateName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
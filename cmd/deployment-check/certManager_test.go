@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestCertificateReadyCondition validates the Ready condition is read with its reason when not ready.
+func TestCertificateReadyCondition(t *testing.T) {
+	// Build a certificate still waiting on its issuer.
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "DoesNotExist", "message": "Issuing certificate as Secret does not exist"},
+			},
+		},
+	}}
+	ready, condition := certificateReadyCondition(certificate)
+	if ready || condition != "Ready=False reason: DoesNotExist message: Issuing certificate as Secret does not exist" {
+		t.Fatalf("expected a not-ready condition with its reason but got %v %q", ready, condition)
+	}
+
+	// Report a ready certificate.
+	certificate.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+	}
+	ready, _ = certificateReadyCondition(certificate)
+	if !ready {
+		t.Fatalf("expected the certificate to be ready")
+	}
+}
+
+// TestParseIssuedCertificate validates an issued leaf verifies for the service name against the issued CA.
+func TestParseIssuedCertificate(t *testing.T) {
+	// Issue a leaf for the service names from a throwaway CA.
+	runner := buildTestRunner()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "check-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate leaf key: %v", err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     runner.cfg.certificateDNSNames(),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caTemplate, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	// Verify the leaf against the issued CA for the fully qualified service name.
+	leaf, roots, err := parseIssuedCertificate(certPEM, caPEM)
+	if err != nil {
+		t.Fatalf("unexpected error parsing the issued certificate: %v", err)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: runner.cfg.certificateDNSNames()[3], Roots: roots, Intermediates: issuedIntermediates(certPEM)})
+	if err != nil {
+		t.Fatalf("expected the issued leaf to verify but got %v", err)
+	}
+
+	// Reject a secret without a certificate.
+	_, _, err = parseIssuedCertificate(nil, caPEM)
+	if err == nil {
+		t.Fatalf("expected an error for a secret without a certificate")
+	}
+}
+
+// TestParseCertManagerPort validates the HTTPS port is required with an issuer and must be a check service port.
+func TestParseCertManagerPort(t *testing.T) {
+	// Reject an issuer without a port.
+	t.Setenv("CHECK_CERT_MANAGER_ISSUER", "check-issuer")
+	_, err := parseConfig()
+	if err == nil {
+		t.Fatalf("expected an error without CHECK_CERT_MANAGER_PORT")
+	}
+
+	// Reject a port the service does not expose.
+	t.Setenv("CHECK_CERT_MANAGER_PORT", "443")
+	_, err = parseConfig()
+	if err == nil {
+		t.Fatalf("expected an error for a port outside the check service")
+	}
+
+	// Accept an additional port.
+	t.Setenv("CHECK_ADDITIONAL_PORTS", "8443:443")
+	cfg, err := parseConfig()
+	if err != nil || cfg.CertManagerPort != 443 {
+		t.Fatalf("expected port 443 to parse but got %v", err)
+	}
+}
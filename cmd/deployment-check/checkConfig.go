@@ -1,8 +1,14 @@
 package main
 
 import (
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -11,6 +17,9 @@ import (
 	"github.com/kuberhealthy/kuberhealthy/v3/pkg/checkclient"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -23,16 +32,113 @@ const (
 	defaultCheckContainerPort = int32(8080)
 	// defaultCheckLoadBalancerPort sets the service port to hit inside the cluster.
 	defaultCheckLoadBalancerPort = int32(80)
+	// defaultLoadBalancerTimeout is the window for cloud load balancer provisioning.
+	defaultLoadBalancerTimeout = time.Minute * 10
+	// defaultHTTPPath is the path requested on check endpoints.
+	defaultHTTPPath = "/"
+	// defaultHTTPConcurrency is the number of load test requests in flight at once.
+	defaultHTTPConcurrency = 10
+	// defaultTLSMinVersion is the minimum TLS version accepted from https endpoints.
+	defaultTLSMinVersion = "1.2"
+	// defaultNodePortNodeCount is how many nodes are requested on node ports.
+	defaultNodePortNodeCount = 3
 
 	// defaultCheckDeploymentName is the name for the test deployment.
 	defaultCheckDeploymentName = "deployment-deployment"
 	// defaultCheckServiceName is the name for the test service.
 	defaultCheckServiceName = "deployment-svc"
+	// defaultCheckContainerName is the name of the check container in the pod template.
+	defaultCheckContainerName = "deployment-container"
 	// defaultCheckServiceAccount is the service account to run with.
 	defaultCheckServiceAccount = "default"
 	// defaultCheckNamespace is the default namespace to run in.
 	defaultCheckNamespace = "kuberhealthy"
 
+	// defaultMaxContainerRestarts is the restart count that fails the check as a crash loop; zero leaves it off.
+	defaultMaxContainerRestarts = 0
+	// defaultProbeInitialDelaySeconds delays the first liveness and readiness probe.
+	defaultProbeInitialDelaySeconds = int32(2)
+	// defaultProbePeriodSeconds is the liveness and readiness probe cadence.
+	defaultProbePeriodSeconds = int32(15)
+	// defaultProbeTimeoutSeconds bounds each probe.
+	defaultProbeTimeoutSeconds = int32(2)
+	// defaultProbeFailureThreshold is how many failed liveness or readiness probes the kubelet tolerates.
+	defaultProbeFailureThreshold = int32(5)
+	// defaultStartupProbeFailureThreshold is how many failed startup probes the kubelet tolerates before restarting.
+	defaultStartupProbeFailureThreshold = int32(30)
+	// defaultStartupProbePeriodSeconds is the startup probe cadence.
+	defaultStartupProbePeriodSeconds = int32(10)
+
+	// defaultFatalWaitingReasons are container waiting reasons that fail the check immediately; empty leaves fast-fail off so a transient pull error is not fatal on first sight.
+	defaultFatalWaitingReasons = ""
+	// defaultUnschedulableTimeout is how long a check pod may stay unschedulable before the check fails; zero leaves it off.
+	defaultUnschedulableTimeout = time.Duration(0)
+
+	// defaultAutoscalerTimeout is the window for new capacity to be provisioned and pods to become ready.
+	defaultAutoscalerTimeout = time.Minute * 10
+
+	// defaultKarpenterTimeout is the window for Karpenter to launch a node and pods to become ready.
+	defaultKarpenterTimeout = time.Minute * 10
+
+	// defaultRequestRetryTimeout caps the window for retrying an endpoint request.
+	defaultRequestRetryTimeout = time.Minute * 3
+	// defaultRequestMaxAttempts caps the number of endpoint request attempts.
+	defaultRequestMaxAttempts = 10
+	// defaultRetryBackoffInitial is the delay before the first retry.
+	defaultRetryBackoffInitial = time.Second * 5
+	// defaultRetryBackoffMax caps the delay between retries.
+	defaultRetryBackoffMax = time.Second * 30
+	// defaultRetryBackoffFactor multiplies the delay after each retry.
+	defaultRetryBackoffFactor = 2.0
+	// defaultRetryBackoffJitter adds up to this fraction of each delay at random.
+	defaultRetryBackoffJitter = 0.2
+	// defaultWatchTimeout bounds each watch before it is re-established.
+	defaultWatchTimeout = time.Minute
+
+	// defaultPodForceDeleteAfter is how long a check pod may stay terminating before it is force deleted.
+	defaultPodForceDeleteAfter = time.Minute
+	// defaultDeleteTimeout is how long a deployment delete may wait before cleanup reports the pods stuck terminating.
+	defaultDeleteTimeout = time.Minute * 5
+	// defaultPodTerminationGracePeriodSeconds is the check pods' termination grace period.
+	defaultPodTerminationGracePeriodSeconds = int64(1)
+	// defaultMinReadySeconds is how long a new pod must stay ready before the deployment counts it available.
+	defaultMinReadySeconds = int32(5)
+
+	// defaultIngressPath is the path routed through the ingress.
+	defaultIngressPath = "/"
+	// defaultIngressTimeout is the window for the ingress controller to publish an address.
+	defaultIngressTimeout = time.Minute * 5
+	// defaultGatewayPath is the path prefix routed through the Gateway.
+	defaultGatewayPath = "/"
+	// defaultGatewayTimeout is the window for an HTTPRoute to be accepted.
+	defaultGatewayTimeout = time.Minute * 5
+	// defaultOpenShiftRouteTimeout is the window for a router to admit the OpenShift Route.
+	defaultOpenShiftRouteTimeout = time.Minute * 5
+	// defaultClusterDomain is the cluster DNS domain.
+	defaultClusterDomain = "cluster.local"
+	// defaultServiceDNSSlowThreshold is the longest acceptable service name lookup.
+	defaultServiceDNSSlowThreshold = time.Second
+	// defaultFieldManager names the check's server-side apply field manager.
+	defaultFieldManager = "deployment-check"
+	// defaultExpiryAnnotation is the annotation key carrying a resource's expiry time.
+	defaultExpiryAnnotation = "kuberhealthy/expires-at"
+	// defaultScaleTimeout is the window for each scaling step to converge.
+	defaultScaleTimeout = time.Minute * 2
+	// defaultHPATargetCPUUtilization is the autoscaler CPU target as a percentage of requests.
+	defaultHPATargetCPUUtilization = 50
+	// defaultHPATimeout is the window for the autoscaler to scale up and, separately, back down.
+	defaultHPATimeout = time.Minute * 5
+	// defaultPDBTimeout is the window for each disruption budget change and the eviction replacement.
+	defaultPDBTimeout = time.Minute * 2
+	// defaultNetworkPolicyTimeout is the window for the CNI to enforce a network policy change.
+	defaultNetworkPolicyTimeout = time.Minute
+	// defaultPushgatewayJob is the Pushgateway job name for pushed metrics.
+	defaultPushgatewayJob = "deployment-check"
+	// defaultMetricsLinger is how long final metrics stay scrapeable after the run.
+	defaultMetricsLinger = time.Second * 30
+	// defaultPodDNSName is the name resolved from inside check pods.
+	defaultPodDNSName = "kubernetes.default.svc"
+
 	// defaultCheckDeploymentReplicas sets the default replica count.
 	defaultCheckDeploymentReplicas = 2
 
@@ -49,30 +155,160 @@ const (
 	defaultMemoryRequest = 20 * 1024 * 1024
 	// defaultMemoryLimit is the default memory limit in bytes (75Mi).
 	defaultMemoryLimit = 75 * 1024 * 1024
+	// defaultPVCSize is the default storage requested by the check claim.
+	defaultPVCSize = "1Gi"
+	// defaultPVCMountPath is where the check claim is mounted by default.
+	defaultPVCMountPath = "/data"
+)
+
+const (
+	// orphanPolicyClean removes orphaned resources and continues.
+	orphanPolicyClean = "clean"
+	// orphanPolicyWarn removes orphaned resources, logs a warning, and continues.
+	orphanPolicyWarn = "warn"
+	// orphanPolicyFail removes orphaned resources and fails the run.
+	orphanPolicyFail = "fail"
 )
 
+// checkPort pairs a container port with the service port that exposes it.
+type checkPort struct {
+	// ContainerPort is the port the check container listens on.
+	ContainerPort int32
+	// ServicePort is the port the service exposes for the container port.
+	ServicePort int32
+}
+
+// initContainerSpec describes an init container to run before the check container.
+type initContainerSpec struct {
+	// Image is the init container image.
+	Image string
+	// Command overrides the image entrypoint when set.
+	Command []string
+}
+
+// statusCodeRange is an inclusive range of acceptable HTTP status codes.
+type statusCodeRange struct {
+	// Min is the lowest acceptable status code.
+	Min int
+	// Max is the highest acceptable status code.
+	Max int
+}
+
+// expectedStatusCodes lists the HTTP status codes that count as a successful response.
+type expectedStatusCodes []statusCodeRange
+
+// matches reports whether code falls within any acceptable range.
+func (codes expectedStatusCodes) matches(code int) bool {
+	// Check each range in turn.
+	for _, codeRange := range codes {
+		if code >= codeRange.Min && code <= codeRange.Max {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String renders the ranges in the same form they are configured.
+func (codes expectedStatusCodes) String() string {
+	// Render single codes plainly and ranges with a dash.
+	parts := make([]string, 0, len(codes))
+	for _, codeRange := range codes {
+		if codeRange.Min == codeRange.Max {
+			parts = append(parts, strconv.Itoa(codeRange.Min))
+			continue
+		}
+		parts = append(parts, strconv.Itoa(codeRange.Min)+"-"+strconv.Itoa(codeRange.Max))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// targetsRemoteCluster reports whether the check targets a cluster other than the one it runs in.
+func (cfg *CheckConfig) targetsRemoteCluster() bool {
+	return len(cfg.KubeContext) != 0 || len(cfg.KubeAPIServer) != 0
+}
+
 // CheckConfig describes the deployment check configuration.
 type CheckConfig struct {
 	// Debug enables verbose logging for the check.
 	Debug bool
 	// KubeConfigPath points to the kubeconfig for out-of-cluster runs.
 	KubeConfigPath string
+	// KubeContext selects a kubeconfig context instead of the in-cluster config.
+	KubeContext string
+	// KubeAPIServer targets an explicit API server instead of the in-cluster config.
+	KubeAPIServer string
+	// KubeTokenFile holds the bearer token for KubeAPIServer.
+	KubeTokenFile string
+	// KubeCAFile holds the CA bundle for KubeAPIServer.
+	KubeCAFile string
+	// ImpersonateUser runs API calls as this user, or empty for the checker's own identity.
+	ImpersonateUser string
+	// ImpersonateGroups adds groups to the impersonated user.
+	ImpersonateGroups []string
+	// KubeClientQPS is the sustained API request rate, or zero for the client-go default.
+	KubeClientQPS float32
+	// KubeClientBurst is the API request burst, or zero for the client-go default.
+	KubeClientBurst int
+	// KubeClientTimeout bounds each API request, or zero for no limit.
+	KubeClientTimeout time.Duration
 	// CheckImageURL is the initial image for the test deployment.
 	CheckImageURL string
+	// ImageVerify confirms the initial pods run the configured image before later stages.
+	ImageVerify bool
+	// ImageDigest pins the repository digest the initial pods must report.
+	ImageDigest string
 	// CheckImageURLRollTo is the image used for rolling updates.
 	CheckImageURLRollTo string
+	// CheckImageRollSequence lists the images the rolling update walks through in order.
+	CheckImageRollSequence []string
 	// CheckImagePullSecret is the optional image pull secret name.
 	CheckImagePullSecret string
 	// CheckDeploymentName is the deployment name.
 	CheckDeploymentName string
 	// CheckServiceName is the service name.
 	CheckServiceName string
+	// CheckContainerName is the name of the check container.
+	CheckContainerName string
 	// CheckContainerPort is the container port for HTTP.
 	CheckContainerPort int32
 	// CheckLoadBalancerPort is the service port for HTTP.
 	CheckLoadBalancerPort int32
+	// Protocol selects how endpoints are validated, http or tcp.
+	Protocol string
+	// TCPBanner is text expected in the first bytes sent by the server in tcp mode.
+	TCPBanner string
+	// HTTPPath is the path requested on check endpoints.
+	HTTPPath string
+	// HTTPHeaders are sent with every endpoint request; a Host entry overrides the Host header.
+	HTTPHeaders http.Header
+	// HTTPExpectedCodes are the status codes accepted from check endpoints.
+	HTTPExpectedCodes expectedStatusCodes
+	// HTTPRequestCount is the number of load test requests sent to the service, or zero to skip the load test.
+	HTTPRequestCount int
+	// HTTPConcurrency is the number of load test requests in flight at once.
+	HTTPConcurrency int
+	// HTTPMaxErrorRate is the fraction of load test requests allowed to fail.
+	HTTPMaxErrorRate float64
+	// EndpointScheme is the scheme used to request check endpoints, http or https.
+	EndpointScheme string
+	// EndpointTLSConfig holds the TLS settings for https endpoint requests.
+	EndpointTLSConfig *tls.Config
+	// CheckServiceType is the type of service created for the check.
+	CheckServiceType corev1.ServiceType
+	// LoadBalancerTimeout is the window for the cloud provider to provision a load balancer.
+	LoadBalancerTimeout time.Duration
+	// NodePortNodeCount is how many nodes are requested on the allocated node ports.
+	NodePortNodeCount int
+	// CheckAdditionalPorts are extra container/service port pairs to validate.
+	CheckAdditionalPorts []checkPort
 	// CheckNamespace is the namespace for the check.
 	CheckNamespace string
+	// CreateNamespace runs each check in an ephemeral namespace that is created for the run and deleted afterward.
+	CreateNamespace bool
+	// NamespaceLabels are extra labels on the ephemeral namespace, such as Pod Security Admission levels.
+	NamespaceLabels map[string]string
 	// CheckDeploymentReplicas is the number of deployment replicas.
 	CheckDeploymentReplicas int
 	// CheckDeploymentTolerations are pod tolerations to apply.
@@ -89,14 +325,312 @@ type CheckConfig struct {
 	MemoryRequest int
 	// MemoryLimit is the memory limit in bytes.
 	MemoryLimit int
+	// EphemeralStorageRequest is the ephemeral-storage request; zero leaves it unset.
+	EphemeralStorageRequest resource.Quantity
+	// EphemeralStorageLimit is the ephemeral-storage limit; zero leaves it unset.
+	EphemeralStorageLimit resource.Quantity
+	// ExtendedResources are device resources such as GPUs requested and limited by each check pod.
+	ExtendedResources map[corev1.ResourceName]resource.Quantity
+	// InitContainers run in order before the check container starts.
+	InitContainers []initContainerSpec
+	// SidecarImage adds a sidecar container to the check pods when set.
+	SidecarImage string
+	// SidecarCommand overrides the sidecar image entrypoint when set.
+	SidecarCommand []string
+	// SidecarPort is probed for sidecar readiness when set.
+	SidecarPort int32
+	// SidecarNative runs the sidecar as a restartable init container.
+	SidecarNative bool
+	// EmptyDirMountPath mounts a scratch emptyDir volume in the check container when set.
+	EmptyDirMountPath string
+	// ConfigMapMountPath mounts a ConfigMap generated by the check in the check container when set.
+	ConfigMapMountPath string
+	// SecretName is an existing Secret mounted in the check container when set.
+	SecretName string
+	// SecretMountPath is where the existing Secret is mounted.
+	SecretMountPath string
+	// PVCStorageClass creates and mounts a persistent volume claim from this StorageClass when set.
+	PVCStorageClass string
+	// PVCSize is the storage requested by the claim.
+	PVCSize resource.Quantity
+	// PVCAccessMode is the access mode requested by the claim.
+	PVCAccessMode corev1.PersistentVolumeAccessMode
+	// PVCMountPath is where the claim is mounted in the check container.
+	PVCMountPath string
+	// ProjectedTokenAudience mounts a projected service account token for this audience and verifies it when set.
+	ProjectedTokenAudience string
+	// ProjectedTokenExpirationSeconds is the requested lifetime of the projected token.
+	ProjectedTokenExpirationSeconds int64
+	// ProjectedTokenMountPath is where the projected token is mounted.
+	ProjectedTokenMountPath string
+	// CertManagerIssuer is the issuer a Certificate is requested from; setting it enables the cert-manager stage.
+	CertManagerIssuer string
+	// CertManagerIssuerKind is Issuer or ClusterIssuer.
+	CertManagerIssuerKind string
+	// CertManagerMountPath is where the issued certificate is mounted.
+	CertManagerMountPath string
+	// CertManagerPort is the service port expected to serve the issued certificate over HTTPS.
+	CertManagerPort int32
+	// CertManagerTimeout is the window for the Certificate to become ready.
+	CertManagerTimeout time.Duration
+	// VolumeVerify execs into the check pods to confirm the mounted volumes are usable.
+	VolumeVerify bool
 	// CheckTimeLimit is the time budget for the full check.
 	CheckTimeLimit time.Duration
 	// RollingUpdate enables the rolling update flow.
 	RollingUpdate bool
+	// RollbackVerify rolls back to the original image after the rolling update and validates again.
+	RollbackVerify bool
+	// ExtraLabels are user labels added to every resource the check creates.
+	ExtraLabels map[string]string
+	// DeploymentAnnotations are extra annotations on the check deployment.
+	DeploymentAnnotations map[string]string
+	// PodAnnotations are extra annotations on the check pod template.
+	PodAnnotations map[string]string
+	// ServiceAnnotations are extra annotations on the check services.
+	ServiceAnnotations map[string]string
+	// MeshMode enables service mesh sidecar handling for the check pods.
+	MeshMode string
+	// MeshInject requests sidecar injection in mesh mode.
+	MeshInject bool
+	// MeshHoldApplication starts the check container only after the proxy is ready.
+	MeshHoldApplication bool
+	// MeshQuitOnCleanup asks the proxies to exit during cleanup so pods do not hang.
+	MeshQuitOnCleanup bool
 	// AdditionalEnvVars are extra env vars passed to the deployment container.
 	AdditionalEnvVars map[string]string
 	// ShutdownGracePeriod is the time allowed for cleanup on termination.
 	ShutdownGracePeriod time.Duration
+	// TerminationMessageFallbackToLogs uses container logs when no termination message is written.
+	TerminationMessageFallbackToLogs bool
+	// ProbeInitialDelaySeconds delays the first liveness and readiness probe.
+	ProbeInitialDelaySeconds int32
+	// ProbePeriodSeconds is the liveness and readiness probe cadence.
+	ProbePeriodSeconds int32
+	// ProbeTimeoutSeconds bounds each probe.
+	ProbeTimeoutSeconds int32
+	// ProbeFailureThreshold is how many failed liveness or readiness probes are tolerated.
+	ProbeFailureThreshold int32
+	// StartupProbe adds a startup probe that holds back liveness and readiness probing until it passes.
+	StartupProbe bool
+	// StartupProbeFailureThreshold is how many failed startup probes are tolerated before the container restarts.
+	StartupProbeFailureThreshold int32
+	// StartupProbePeriodSeconds is the startup probe cadence.
+	StartupProbePeriodSeconds int32
+	// ProbeType selects the probe handler: tcp, http, or exec.
+	ProbeType string
+	// ProbePath is the path requested by http probes.
+	ProbePath string
+	// ProbePort is the port tcp and http probes target, or zero for the container port.
+	ProbePort int32
+	// ProbeExecCommand is the command run by exec probes.
+	ProbeExecCommand []string
+	// MaxContainerRestarts fails the check once a container restarts this many times; zero disables it.
+	MaxContainerRestarts int
+	// OrphanPolicy controls how resources left by a previous run are handled.
+	OrphanPolicy string
+	// ResourceTTL is how long created resources may live before any run deletes them, or zero to skip stamping.
+	ResourceTTL time.Duration
+	// ExpiryAnnotation is the annotation key carrying the expiry time.
+	ExpiryAnnotation string
+	// StaleResourceAge is how old a check deployment or service under any name must be to be deleted, or zero to skip.
+	StaleResourceAge time.Duration
+	// OwnerReference makes the checker pod own the deployment and services for garbage collection.
+	OwnerReference bool
+	// RunLock holds a Lease named after the check deployment for the run so overlapping checkers do not collide.
+	RunLock bool
+	// RunLockFailWhenHeld reports a failure instead of skipping the run when another checker holds the run lock.
+	RunLockFailWhenHeld bool
+	// ServerSideApply creates and updates the deployment and service with server-side apply.
+	ServerSideApply bool
+	// FieldManager names the field manager used for server-side apply.
+	FieldManager string
+	// CleanupOnly removes leftover check resources and exits without running the check.
+	CleanupOnly bool
+	// FatalWaitingReasons are container waiting reasons that fail the check immediately.
+	FatalWaitingReasons map[string]bool
+	// PodErrorGrace delays fatal waiting reasons and pod event errors this long after pods are created; zero keeps the defaults.
+	PodErrorGrace time.Duration
+	// QuotaPreflight verifies the namespace's ResourceQuotas have room for the check pods before creating them.
+	QuotaPreflight bool
+	// UnschedulableTimeout fails the check once a pod stays unschedulable this long; zero disables it.
+	UnschedulableTimeout time.Duration
+	// AutoscalerMode verifies the cluster autoscaler provisions a node for the check pods.
+	AutoscalerMode bool
+	// AutoscalerTimeout is the window for provisioning and pod readiness in autoscaler mode.
+	AutoscalerTimeout time.Duration
+	// KarpenterMode verifies Karpenter launches a NodeClaim for the check pods.
+	KarpenterMode bool
+	// KarpenterTimeout is the window for provisioning and pod readiness in Karpenter mode.
+	KarpenterTimeout time.Duration
+	// MetricsAddress is the listen address for the /metrics endpoint, or empty to disable it.
+	MetricsAddress string
+	// MetricsLinger keeps the metrics endpoint up after the run so final values can be scraped.
+	MetricsLinger time.Duration
+	// ResultOutputPath receives a JSON result document at the end of the run, with - meaning stdout.
+	ResultOutputPath string
+	// PushgatewayURL is the Pushgateway that receives the final metrics, or empty to skip pushing.
+	PushgatewayURL string
+	// PushgatewayJob is the job name metrics are pushed under.
+	PushgatewayJob string
+	// FailureWebhookURL receives a JSON notification for failed runs, or empty to skip it.
+	FailureWebhookURL string
+	// NodePoolLabel runs the check once per distinct value of this node label when set.
+	NodePoolLabel string
+	// CheckNamespaces runs the full check once in each of these namespaces when set.
+	CheckNamespaces []string
+	// NamespacesParallel runs the per-namespace checks at the same time instead of one after another.
+	NamespacesParallel bool
+	// PriorityClassName is the priority class for the check pods.
+	PriorityClassName string
+	// HostAliases are extra /etc/hosts entries on the check pods.
+	HostAliases []corev1.HostAlias
+	// ReadinessGate is a pod condition type added as a readiness gate, which the checker sets once containers are ready.
+	ReadinessGate string
+	// MinZones is the least number of zones the ready pods must span, or zero to skip.
+	MinZones int
+	// TopologySpreadConstraints spread the check pods; label selectors are filled in per run.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	// PodRunAsNonRoot sets runAsNonRoot on the check pod security context when non-nil.
+	PodRunAsNonRoot *bool
+	// PodRunAsUser sets runAsUser on the check pod security context when non-nil.
+	PodRunAsUser *int64
+	// PodRunAsGroup sets runAsGroup on the check pod security context when non-nil.
+	PodRunAsGroup *int64
+	// PodFSGroup sets fsGroup on the check pod security context when non-nil.
+	PodFSGroup *int64
+	// PodSeccompProfile sets the seccomp profile on the check pod security context when non-nil.
+	PodSeccompProfile *corev1.SeccompProfile
+	// ContainerReadOnlyRootFilesystem mounts the check container root filesystem read-only.
+	ContainerReadOnlyRootFilesystem bool
+	// ContainerAllowPrivilegeEscalation sets allowPrivilegeEscalation on the check container when non-nil.
+	ContainerAllowPrivilegeEscalation *bool
+	// ContainerDropAllCapabilities drops every Linux capability from the check container.
+	ContainerDropAllCapabilities bool
+	// PSSProfile is the Pod Security Standards profile the check pods comply with, if any.
+	PSSProfile string
+	// AutopilotMode makes the check pods compliant with GKE Autopilot admission.
+	AutopilotMode bool
+	// CheckNodeAffinityRequirements are required node affinity terms for the check pods.
+	CheckNodeAffinityRequirements []corev1.NodeSelectorRequirement
+	// EndpointDiagnostics requests pods directly when the service fails to isolate kube-proxy issues.
+	EndpointDiagnostics bool
+	// PodIPVerify connects to every pod IP directly before the service is requested.
+	PodIPVerify bool
+	// ProberVerify requests the service from an auxiliary prober pod on another node.
+	ProberVerify bool
+	// ProberImage is the curl image the prober pod runs.
+	ProberImage string
+	// ProberHostNetwork runs the prober pod in the host network namespace.
+	ProberHostNetwork bool
+	// PodDNSVerify enables the in-pod DNS resolution step.
+	PodDNSVerify bool
+	// PodDNSName is the name resolved from inside the check pods.
+	PodDNSName string
+	// PodDNSPolicy is the DNS policy of the check pods, or empty for the cluster default.
+	PodDNSPolicy corev1.DNSPolicy
+	// PodDNSConfig holds extra nameservers, search domains, and ndots for the check pods, or nil.
+	PodDNSConfig *corev1.PodDNSConfig
+	// PodForceDeleteAfter force deletes check pods stuck terminating this long during cleanup; zero disables it.
+	PodForceDeleteAfter time.Duration
+	// DeletePropagationPolicy is the propagation policy for the deployment delete.
+	DeletePropagationPolicy metav1.DeletionPropagation
+	// DeleteTimeout fails a deployment delete that has waited this long, naming the pods stuck terminating; zero waits until the check deadline.
+	DeleteTimeout time.Duration
+	// PodTerminationGracePeriodSeconds is the check pods' termination grace period.
+	PodTerminationGracePeriodSeconds int64
+	// MinReadySeconds is how long a new pod must stay ready before the deployment counts it available.
+	MinReadySeconds int32
+	// ProgressDeadlineSeconds is how long a rollout may go without progress before it fails, or zero for the API default.
+	ProgressDeadlineSeconds int32
+	// RequestRetryTimeout caps the window for retrying an endpoint request.
+	RequestRetryTimeout time.Duration
+	// RequestMaxAttempts caps the number of endpoint request attempts.
+	RequestMaxAttempts int
+	// RetryBackoffInitial is the delay before the first HTTP or API retry.
+	RetryBackoffInitial time.Duration
+	// RetryBackoffMax caps the delay between retries.
+	RetryBackoffMax time.Duration
+	// RetryBackoffFactor multiplies the delay after each retry.
+	RetryBackoffFactor float64
+	// RetryBackoffJitter adds up to this fraction of each delay at random.
+	RetryBackoffJitter float64
+	// WatchTimeout bounds each deployment and service watch before it is re-established.
+	WatchTimeout time.Duration
+	// IngressVerify creates an ingress for the check service and validates traffic through it.
+	IngressVerify bool
+	// IngressClassName selects the ingress controller; empty uses the cluster default.
+	IngressClassName string
+	// IngressHost is the host rule and Host header used for the ingress request.
+	IngressHost string
+	// IngressPath is the path routed to the check service.
+	IngressPath string
+	// IngressAddress overrides the controller address requested instead of waiting for ingress status.
+	IngressAddress string
+	// IngressTimeout is the window for the controller to publish an ingress address.
+	IngressTimeout time.Duration
+	// GatewayName is the Gateway an HTTPRoute is attached to; setting it enables the Gateway API check.
+	GatewayName string
+	// GatewayNamespace is the namespace of the Gateway.
+	GatewayNamespace string
+	// GatewaySectionName pins the route to a single Gateway listener.
+	GatewaySectionName string
+	// GatewayHost is the route hostname and Host header used for the Gateway request.
+	GatewayHost string
+	// GatewayPath is the path prefix routed to the check service.
+	GatewayPath string
+	// GatewayAddress overrides the Gateway address requested instead of reading the Gateway status.
+	GatewayAddress string
+	// GatewayTimeout is the window for the route to be accepted with resolved references.
+	GatewayTimeout time.Duration
+	// OpenShiftRouteVerify creates an OpenShift Route for the check service and validates traffic through the router.
+	OpenShiftRouteVerify bool
+	// OpenShiftRouteHost is the Route host, or empty to let the router generate one.
+	OpenShiftRouteHost string
+	// OpenShiftRouteAddress overrides the router address requested instead of resolving the Route host.
+	OpenShiftRouteAddress string
+	// OpenShiftRouteTimeout is the window for a router to admit the Route.
+	OpenShiftRouteTimeout time.Duration
+	// EveryReplicaVerify requires every ready replica to serve a request.
+	EveryReplicaVerify bool
+	// BackendHeader is a response header naming the pod that served the request.
+	BackendHeader string
+	// SessionAffinityVerify creates the service with ClientIP session affinity and verifies requests stick to one pod.
+	SessionAffinityVerify bool
+	// EndpointSliceVerify requires the service's EndpointSlices to list every ready replica before requests are made.
+	EndpointSliceVerify bool
+	// HeadlessServiceVerify creates a headless service and verifies its DNS records.
+	HeadlessServiceVerify bool
+	// ClusterDomain is the cluster DNS domain used to build service names.
+	ClusterDomain string
+	// NetworkPolicyVerify checks that deny-all and allow network policies are enforced.
+	NetworkPolicyVerify bool
+	// NetworkPolicyTimeout is the window for each policy change to take effect.
+	NetworkPolicyTimeout time.Duration
+	// ScaleReplicas is the replica count to scale to and back from, or zero to skip.
+	ScaleReplicas int
+	// ScaleTimeout is the window for each scaling step to converge.
+	ScaleTimeout time.Duration
+	// HPAVerify checks that a horizontal pod autoscaler scales the deployment up and back down.
+	HPAVerify bool
+	// HPAMaxReplicas is the autoscaler replica ceiling.
+	HPAMaxReplicas int32
+	// HPATargetCPUUtilization is the autoscaler CPU target as a percentage of requests.
+	HPATargetCPUUtilization int32
+	// HPAGenerateLoad burns CPU in the check pods to trigger the scale up.
+	HPAGenerateLoad bool
+	// HPATimeout is the window for each scaling direction.
+	HPATimeout time.Duration
+	// PDBVerify checks that a pod disruption budget blocks and then allows an eviction.
+	PDBVerify bool
+	// PDBTimeout is the window for each disruption budget change and the eviction replacement.
+	PDBTimeout time.Duration
+	// ServiceDNSVerify resolves the service FQDN and compares it to the cluster IP.
+	ServiceDNSVerify bool
+	// ServiceDNSSlowThreshold fails service DNS verification when a lookup takes longer.
+	ServiceDNSSlowThreshold time.Duration
+	// SoakDuration keeps the deployment running under validation after success; zero disables it.
+	SoakDuration time.Duration
 }
 
 // parseConfig reads environment variables into a CheckConfig for the check runtime.
@@ -133,11 +667,46 @@ func parseConfig() (*CheckConfig, error) {
 		cfg.CheckImageURL = checkImageEnv
 		log.Infoln("Parsed CHECK_IMAGE:", cfg.CheckImageURL)
 	}
+
+	// Parse the initial image verification and digest pin.
+	imageVerifyEnv := os.Getenv("CHECK_IMAGE_VERIFY")
+	if len(imageVerifyEnv) != 0 {
+		imageVerifyValue, err := strconv.ParseBool(imageVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_IMAGE_VERIFY: %w", err)
+		}
+		cfg.ImageVerify = imageVerifyValue
+		log.Infoln("Parsed CHECK_IMAGE_VERIFY:", cfg.ImageVerify)
+	}
+	imageDigestEnv := strings.TrimSpace(os.Getenv("CHECK_IMAGE_DIGEST"))
+	if len(imageDigestEnv) != 0 {
+		hexDigest, found := strings.CutPrefix(imageDigestEnv, "sha256:")
+		_, decodeErr := hex.DecodeString(hexDigest)
+		if !found || len(hexDigest) != 64 || decodeErr != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_IMAGE_DIGEST: %q must be sha256: followed by 64 hex characters", imageDigestEnv)
+		}
+		referenceDigest := imageReferenceDigest(cfg.CheckImageURL)
+		if len(referenceDigest) != 0 && referenceDigest != imageDigestEnv {
+			return nil, fmt.Errorf("failed to parse CHECK_IMAGE_DIGEST: %s conflicts with the digest in CHECK_IMAGE", imageDigestEnv)
+		}
+		cfg.ImageDigest = imageDigestEnv
+		cfg.ImageVerify = true
+		log.Infoln("Parsed CHECK_IMAGE_DIGEST:", cfg.ImageDigest)
+	}
 	checkImageRollEnv := os.Getenv("CHECK_IMAGE_ROLL_TO")
 	if len(checkImageRollEnv) != 0 {
 		cfg.CheckImageURLRollTo = checkImageRollEnv
 		log.Infoln("Parsed CHECK_IMAGE_ROLL_TO:", cfg.CheckImageURLRollTo)
 	}
+	checkImageSequenceEnv := os.Getenv("CHECK_IMAGE_ROLL_SEQUENCE")
+	if len(checkImageSequenceEnv) != 0 {
+		sequence, err := parseImageRollSequence(cfg.CheckImageURL, checkImageSequenceEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CheckImageRollSequence = sequence
+		log.Infoln("Parsed CHECK_IMAGE_ROLL_SEQUENCE:", cfg.CheckImageRollSequence)
+	}
 
 	// Parse image pull secret.
 	cfg.CheckImagePullSecret = os.Getenv("CHECK_IMAGE_PULL_SECRET")
@@ -161,6 +730,18 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed CHECK_SERVICE_NAME:", cfg.CheckServiceName)
 	}
 
+	// Parse container name.
+	cfg.CheckContainerName = defaultCheckContainerName
+	checkContainerNameEnv := os.Getenv("CHECK_CONTAINER_NAME")
+	if len(checkContainerNameEnv) != 0 {
+		nameErrs := validation.IsDNS1123Label(checkContainerNameEnv)
+		if len(nameErrs) != 0 {
+			return nil, fmt.Errorf("CHECK_CONTAINER_NAME %q is not a valid container name: %s", checkContainerNameEnv, strings.Join(nameErrs, ", "))
+		}
+		cfg.CheckContainerName = checkContainerNameEnv
+		log.Infoln("Parsed CHECK_CONTAINER_NAME:", cfg.CheckContainerName)
+	}
+
 	// Parse container port.
 	cfg.CheckContainerPort = defaultCheckContainerPort
 	checkContainerPortEnv := os.Getenv("CHECK_CONTAINER_PORT")
@@ -185,6 +766,189 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed CHECK_LOAD_BALANCER_PORT:", cfg.CheckLoadBalancerPort)
 	}
 
+	// Parse additional container/service port pairs.
+	cfg.CheckAdditionalPorts = make([]checkPort, 0)
+	checkAdditionalPortsEnv := os.Getenv("CHECK_ADDITIONAL_PORTS")
+	if len(checkAdditionalPortsEnv) != 0 {
+		ports, err := parseAdditionalPorts(checkAdditionalPortsEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CheckAdditionalPorts = ports
+		log.Infoln("Parsed CHECK_ADDITIONAL_PORTS:", cfg.CheckAdditionalPorts)
+	}
+
+	// Parse the endpoint protocol.
+	cfg.Protocol = protocolHTTP
+	protocolEnv := os.Getenv("CHECK_PROTOCOL")
+	if len(protocolEnv) != 0 {
+		protocol := strings.ToLower(protocolEnv)
+		if protocol != protocolHTTP && protocol != protocolTCP {
+			return nil, fmt.Errorf("failed to parse CHECK_PROTOCOL: %q must be %s or %s", protocolEnv, protocolHTTP, protocolTCP)
+		}
+		cfg.Protocol = protocol
+		log.Infoln("Parsed CHECK_PROTOCOL:", cfg.Protocol)
+	}
+	tcpBannerEnv := os.Getenv("CHECK_TCP_BANNER")
+	if len(tcpBannerEnv) != 0 {
+		cfg.TCPBanner = tcpBannerEnv
+		log.Infoln("Parsed CHECK_TCP_BANNER:", cfg.TCPBanner)
+	}
+
+	// Parse the HTTP path and acceptable status codes.
+	cfg.HTTPPath = defaultHTTPPath
+	httpPathEnv := os.Getenv("CHECK_HTTP_PATH")
+	if len(httpPathEnv) != 0 {
+		cfg.HTTPPath = httpPathEnv
+		if !strings.HasPrefix(cfg.HTTPPath, "/") {
+			cfg.HTTPPath = "/" + cfg.HTTPPath
+		}
+		log.Infoln("Parsed CHECK_HTTP_PATH:", cfg.HTTPPath)
+	}
+	cfg.HTTPHeaders = make(http.Header)
+	httpHeadersEnv := os.Getenv("CHECK_HTTP_HEADERS")
+	if len(httpHeadersEnv) != 0 {
+		headers, err := parseHTTPHeaders(httpHeadersEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPHeaders = headers
+		log.Infoln("Parsed CHECK_HTTP_HEADERS:", sortedHeaderNames(cfg.HTTPHeaders))
+	}
+	cfg.HTTPExpectedCodes = expectedStatusCodes{{Min: http.StatusOK, Max: http.StatusOK}}
+	httpExpectedCodesEnv := os.Getenv("CHECK_HTTP_EXPECTED_CODES")
+	if len(httpExpectedCodesEnv) != 0 {
+		codes, err := parseExpectedStatusCodes(httpExpectedCodesEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPExpectedCodes = codes
+		log.Infoln("Parsed CHECK_HTTP_EXPECTED_CODES:", cfg.HTTPExpectedCodes)
+	}
+
+	// Parse the load test settings.
+	httpRequestCountEnv := os.Getenv("CHECK_HTTP_REQUEST_COUNT")
+	if len(httpRequestCountEnv) != 0 {
+		countValue, err := strconv.Atoi(httpRequestCountEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_REQUEST_COUNT: %w", err)
+		}
+		if countValue < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_REQUEST_COUNT: must be at least 1")
+		}
+		cfg.HTTPRequestCount = countValue
+		log.Infoln("Parsed CHECK_HTTP_REQUEST_COUNT:", cfg.HTTPRequestCount)
+	}
+	cfg.HTTPConcurrency = defaultHTTPConcurrency
+	httpConcurrencyEnv := os.Getenv("CHECK_HTTP_CONCURRENCY")
+	if len(httpConcurrencyEnv) != 0 {
+		concurrencyValue, err := strconv.Atoi(httpConcurrencyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_CONCURRENCY: %w", err)
+		}
+		if concurrencyValue < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_CONCURRENCY: must be at least 1")
+		}
+		if cfg.HTTPRequestCount == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_CONCURRENCY: CHECK_HTTP_REQUEST_COUNT must be set")
+		}
+		cfg.HTTPConcurrency = concurrencyValue
+		log.Infoln("Parsed CHECK_HTTP_CONCURRENCY:", cfg.HTTPConcurrency)
+	}
+	cfg.HTTPConcurrency = min(cfg.HTTPConcurrency, max(cfg.HTTPRequestCount, 1))
+	httpMaxErrorRateEnv := os.Getenv("CHECK_HTTP_MAX_ERROR_RATE")
+	if len(httpMaxErrorRateEnv) != 0 {
+		rateValue, err := strconv.ParseFloat(httpMaxErrorRateEnv, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_MAX_ERROR_RATE: %w", err)
+		}
+		if rateValue < 0 || rateValue > 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_MAX_ERROR_RATE: must be between 0 and 1")
+		}
+		if cfg.HTTPRequestCount == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_MAX_ERROR_RATE: CHECK_HTTP_REQUEST_COUNT must be set")
+		}
+		cfg.HTTPMaxErrorRate = rateValue
+		log.Infoln("Parsed CHECK_HTTP_MAX_ERROR_RATE:", cfg.HTTPMaxErrorRate)
+	}
+
+	// Parse the endpoint scheme and TLS settings.
+	cfg.EndpointScheme = endpointSchemeHTTP
+	endpointSchemeEnv := os.Getenv("CHECK_ENDPOINT_SCHEME")
+	if len(endpointSchemeEnv) != 0 {
+		scheme := strings.ToLower(endpointSchemeEnv)
+		if scheme != endpointSchemeHTTP && scheme != endpointSchemeHTTPS {
+			return nil, fmt.Errorf("failed to parse CHECK_ENDPOINT_SCHEME: %q must be %s or %s", endpointSchemeEnv, endpointSchemeHTTP, endpointSchemeHTTPS)
+		}
+		cfg.EndpointScheme = scheme
+		log.Infoln("Parsed CHECK_ENDPOINT_SCHEME:", cfg.EndpointScheme)
+	}
+	if cfg.EndpointScheme != endpointSchemeHTTPS && (len(os.Getenv("CHECK_TLS_CA_FILE")) != 0 || len(os.Getenv("CHECK_TLS_INSECURE_SKIP_VERIFY")) != 0 || len(os.Getenv("CHECK_TLS_MIN_VERSION")) != 0) {
+		return nil, fmt.Errorf("CHECK_TLS_CA_FILE, CHECK_TLS_INSECURE_SKIP_VERIFY, and CHECK_TLS_MIN_VERSION require CHECK_ENDPOINT_SCHEME=https")
+	}
+	if cfg.EndpointScheme == endpointSchemeHTTPS {
+		caFile := os.Getenv("CHECK_TLS_CA_FILE")
+		insecureSkipVerify := false
+		insecureSkipVerifyEnv := os.Getenv("CHECK_TLS_INSECURE_SKIP_VERIFY")
+		if len(insecureSkipVerifyEnv) != 0 {
+			skipValue, err := strconv.ParseBool(insecureSkipVerifyEnv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_TLS_INSECURE_SKIP_VERIFY: %w", err)
+			}
+			insecureSkipVerify = skipValue
+		}
+		minVersion := defaultTLSMinVersion
+		minVersionEnv := os.Getenv("CHECK_TLS_MIN_VERSION")
+		if len(minVersionEnv) != 0 {
+			minVersion = minVersionEnv
+		}
+		tlsConfig, err := buildEndpointTLSConfig(caFile, insecureSkipVerify, minVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TLS settings: %w", err)
+		}
+		cfg.EndpointTLSConfig = tlsConfig
+		log.Infoln("Parsed TLS settings: CA file:", caFile, "insecure skip verify:", insecureSkipVerify, "minimum version:", minVersion)
+	}
+
+	// Parse service type.
+	cfg.CheckServiceType = corev1.ServiceTypeClusterIP
+	checkServiceTypeEnv := os.Getenv("CHECK_SERVICE_TYPE")
+	if len(checkServiceTypeEnv) != 0 {
+		serviceType := corev1.ServiceType(checkServiceTypeEnv)
+		if serviceType != corev1.ServiceTypeClusterIP && serviceType != corev1.ServiceTypeNodePort && serviceType != corev1.ServiceTypeLoadBalancer {
+			return nil, fmt.Errorf("failed to parse CHECK_SERVICE_TYPE: %q must be %s, %s, or %s", checkServiceTypeEnv, corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+		}
+		cfg.CheckServiceType = serviceType
+		log.Infoln("Parsed CHECK_SERVICE_TYPE:", cfg.CheckServiceType)
+	}
+
+	// Parse how many nodes are requested on node ports.
+	cfg.NodePortNodeCount = defaultNodePortNodeCount
+	nodePortNodesEnv := os.Getenv("CHECK_NODE_PORT_NODES")
+	if len(nodePortNodesEnv) != 0 {
+		countValue, err := strconv.Atoi(nodePortNodesEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_NODE_PORT_NODES: %w", err)
+		}
+		if countValue < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_NODE_PORT_NODES: must be at least 1")
+		}
+		cfg.NodePortNodeCount = countValue
+		log.Infoln("Parsed CHECK_NODE_PORT_NODES:", cfg.NodePortNodeCount)
+	}
+
+	// Parse the load balancer provisioning timeout.
+	cfg.LoadBalancerTimeout = defaultLoadBalancerTimeout
+	loadBalancerTimeoutEnv := os.Getenv("CHECK_LOAD_BALANCER_TIMEOUT")
+	if len(loadBalancerTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(loadBalancerTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_LOAD_BALANCER_TIMEOUT: %w", err)
+		}
+		cfg.LoadBalancerTimeout = durationValue
+		log.Infoln("Parsed CHECK_LOAD_BALANCER_TIMEOUT:", cfg.LoadBalancerTimeout)
+	}
+
 	// Parse namespace with service account fallback.
 	cfg.CheckNamespace = defaultCheckNamespace
 	namespaceBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
@@ -286,6 +1050,107 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed CHECK_POD_MEM_LIMIT:", cfg.MemoryLimit)
 	}
 
+	// Parse ephemeral-storage requests and limits so LimitRanges do not reject or default them.
+	ephemeralStorageRequestEnv := os.Getenv("CHECK_POD_EPHEMERAL_STORAGE_REQUEST")
+	if len(ephemeralStorageRequestEnv) != 0 {
+		quantity, err := resource.ParseQuantity(ephemeralStorageRequestEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EPHEMERAL_STORAGE_REQUEST: %w", err)
+		}
+		if quantity.Sign() <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EPHEMERAL_STORAGE_REQUEST: must be greater than zero")
+		}
+		cfg.EphemeralStorageRequest = quantity
+		log.Infoln("Parsed CHECK_POD_EPHEMERAL_STORAGE_REQUEST:", cfg.EphemeralStorageRequest.String())
+	}
+
+	ephemeralStorageLimitEnv := os.Getenv("CHECK_POD_EPHEMERAL_STORAGE_LIMIT")
+	if len(ephemeralStorageLimitEnv) != 0 {
+		quantity, err := resource.ParseQuantity(ephemeralStorageLimitEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EPHEMERAL_STORAGE_LIMIT: %w", err)
+		}
+		if quantity.Sign() <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EPHEMERAL_STORAGE_LIMIT: must be greater than zero")
+		}
+		if !cfg.EphemeralStorageRequest.IsZero() && quantity.Cmp(cfg.EphemeralStorageRequest) < 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EPHEMERAL_STORAGE_LIMIT: must not be less than CHECK_POD_EPHEMERAL_STORAGE_REQUEST")
+		}
+		cfg.EphemeralStorageLimit = quantity
+		log.Infoln("Parsed CHECK_POD_EPHEMERAL_STORAGE_LIMIT:", cfg.EphemeralStorageLimit.String())
+	}
+
+	// Parse extended resources so device-backed node pools can be exercised.
+	extendedResourcesEnv := os.Getenv("CHECK_POD_EXTENDED_RESOURCES")
+	if len(extendedResourcesEnv) != 0 {
+		extendedResources, err := parseExtendedResources(extendedResourcesEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ExtendedResources = extendedResources
+		log.Infoln("Parsed CHECK_POD_EXTENDED_RESOURCES:", extendedResourcesEnv)
+	}
+
+	// Parse init containers so the init-container execution path is exercised.
+	initContainersEnv := os.Getenv("CHECK_INIT_CONTAINERS")
+	if len(initContainersEnv) != 0 {
+		initContainers, err := parseInitContainers(initContainersEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.InitContainers = initContainers
+		log.Infoln("Parsed CHECK_INIT_CONTAINERS:", cfg.InitContainers)
+	}
+
+	// Parse the optional sidecar container.
+	cfg.SidecarImage = strings.TrimSpace(os.Getenv("CHECK_SIDECAR_IMAGE"))
+	if len(cfg.SidecarImage) != 0 {
+		log.Infoln("Parsed CHECK_SIDECAR_IMAGE:", cfg.SidecarImage)
+	}
+
+	sidecarCommandEnv := os.Getenv("CHECK_SIDECAR_COMMAND")
+	if len(strings.TrimSpace(sidecarCommandEnv)) != 0 {
+		if len(cfg.SidecarImage) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_COMMAND: CHECK_SIDECAR_IMAGE must be set")
+		}
+		cfg.SidecarCommand = strings.Fields(sidecarCommandEnv)
+		log.Infoln("Parsed CHECK_SIDECAR_COMMAND:", cfg.SidecarCommand)
+	}
+
+	sidecarPortEnv := os.Getenv("CHECK_SIDECAR_PORT")
+	if len(sidecarPortEnv) != 0 {
+		if len(cfg.SidecarImage) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_PORT: CHECK_SIDECAR_IMAGE must be set")
+		}
+		portValue, err := strconv.ParseInt(sidecarPortEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_PORT: %w", err)
+		}
+		if portValue < 1 || portValue > 65535 {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_PORT: must be between 1 and 65535")
+		}
+		for _, port := range cfg.checkPorts() {
+			if port.ContainerPort == int32(portValue) {
+				return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_PORT: port %d is already used by the check container", portValue)
+			}
+		}
+		cfg.SidecarPort = int32(portValue)
+		log.Infoln("Parsed CHECK_SIDECAR_PORT:", cfg.SidecarPort)
+	}
+
+	sidecarNativeEnv := os.Getenv("CHECK_SIDECAR_NATIVE")
+	if len(sidecarNativeEnv) != 0 {
+		nativeValue, err := strconv.ParseBool(sidecarNativeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_NATIVE: %w", err)
+		}
+		if nativeValue && len(cfg.SidecarImage) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SIDECAR_NATIVE: CHECK_SIDECAR_IMAGE must be set")
+		}
+		cfg.SidecarNative = nativeValue
+		log.Infoln("Parsed CHECK_SIDECAR_NATIVE:", cfg.SidecarNative)
+	}
+
 	// Parse service account name.
 	cfg.CheckServiceAccount = defaultCheckServiceAccount
 	checkServiceAccountEnv := os.Getenv("CHECK_SERVICE_ACCOUNT")
@@ -294,6 +1159,44 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed CHECK_SERVICE_ACCOUNT:", cfg.CheckServiceAccount)
 	}
 
+	// Parse ephemeral namespace creation.
+	createNamespaceEnv := os.Getenv("CHECK_CREATE_NAMESPACE")
+	if len(createNamespaceEnv) != 0 {
+		createValue, err := strconv.ParseBool(createNamespaceEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_CREATE_NAMESPACE: %w", err)
+		}
+		cfg.CreateNamespace = createValue
+		log.Infoln("Parsed CHECK_CREATE_NAMESPACE:", cfg.CreateNamespace)
+	}
+	if cfg.CreateNamespace {
+		problems := validation.IsDNS1123Label(ephemeralNamespaceName(cfg.CheckDeploymentName, time.Now()))
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("CHECK_CREATE_NAMESPACE names namespaces after CHECK_DEPLOYMENT_NAME %q, which does not make a valid namespace name: %s", cfg.CheckDeploymentName, strings.Join(problems, "; "))
+		}
+		if cfg.CheckServiceAccount != defaultCheckServiceAccount || len(cfg.CheckImagePullSecret) != 0 {
+			return nil, fmt.Errorf("CHECK_CREATE_NAMESPACE cannot be combined with CHECK_SERVICE_ACCOUNT or CHECK_IMAGE_PULL_SECRET, which would not exist in the new namespace")
+		}
+	}
+
+	// Parse extra labels for the ephemeral namespace.
+	cfg.NamespaceLabels = make(map[string]string)
+	namespaceLabelsEnv := os.Getenv("CHECK_NAMESPACE_LABELS")
+	if len(namespaceLabelsEnv) != 0 {
+		if !cfg.CreateNamespace {
+			return nil, fmt.Errorf("CHECK_NAMESPACE_LABELS requires CHECK_CREATE_NAMESPACE")
+		}
+		labels, err := parseLabels("CHECK_NAMESPACE_LABELS", namespaceLabelsEnv)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := labels[namespaceOwnerLabelKey]; found {
+			return nil, fmt.Errorf("failed to parse CHECK_NAMESPACE_LABELS: label %q is managed by the check", namespaceOwnerLabelKey)
+		}
+		cfg.NamespaceLabels = labels
+		log.Infoln("Parsed CHECK_NAMESPACE_LABELS:", cfg.NamespaceLabels)
+	}
+
 	// Parse check deadline from injected env.
 	cfg.CheckTimeLimit = defaultCheckTimeLimit
 	deadlineTime, err := checkclient.GetDeadline()
@@ -315,7 +1218,10 @@ func parseConfig() (*CheckConfig, error) {
 		cfg.RollingUpdate = rollingValue
 	}
 	log.Infoln("Parsed CHECK_DEPLOYMENT_ROLLING_UPDATE:", cfg.RollingUpdate)
-	if cfg.RollingUpdate {
+	if len(cfg.CheckImageRollSequence) != 0 && !cfg.RollingUpdate {
+		return nil, fmt.Errorf("failed to parse CHECK_IMAGE_ROLL_SEQUENCE: a roll sequence requires CHECK_DEPLOYMENT_ROLLING_UPDATE")
+	}
+	if cfg.RollingUpdate && len(cfg.CheckImageRollSequence) == 0 {
 		if cfg.CheckImageURL == cfg.CheckImageURLRollTo {
 			log.Infoln("The same container image cannot be used for the rolling-update check. Using defaults.")
 			cfg.CheckImageURL = defaultCheckImageURL
@@ -323,7 +1229,24 @@ func parseConfig() (*CheckConfig, error) {
 			log.Infoln("Setting initial container image to:", cfg.CheckImageURL)
 			log.Infoln("Setting update container image to:", cfg.CheckImageURLRollTo)
 		}
-		log.Infoln("Check deployment image will be rolled from [" + cfg.CheckImageURL + "] to [" + cfg.CheckImageURLRollTo + "]")
+		cfg.CheckImageRollSequence = []string{cfg.CheckImageURLRollTo}
+	}
+	if cfg.RollingUpdate {
+		log.Infoln("Check deployment image will be rolled from [" + cfg.CheckImageURL + "] through [" + strings.Join(cfg.CheckImageRollSequence, " -> ") + "]")
+	}
+
+	// Parse the rollback verification toggle, which needs a rolling update to undo.
+	rollbackEnv := os.Getenv("CHECK_DEPLOYMENT_ROLLBACK")
+	if len(rollbackEnv) != 0 {
+		rollbackValue, err := strconv.ParseBool(rollbackEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_DEPLOYMENT_ROLLBACK: %w", err)
+		}
+		if rollbackValue && !cfg.RollingUpdate {
+			return nil, fmt.Errorf("failed to parse CHECK_DEPLOYMENT_ROLLBACK: rollback requires CHECK_DEPLOYMENT_ROLLING_UPDATE")
+		}
+		cfg.RollbackVerify = rollbackValue
+		log.Infoln("Parsed CHECK_DEPLOYMENT_ROLLBACK:", cfg.RollbackVerify)
 	}
 
 	// Parse additional env vars for the deployment.
@@ -338,6 +1261,98 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed ADDITIONAL_ENV_VARS:", cfg.AdditionalEnvVars)
 	}
 
+	// Parse extra labels for the created resources.
+	cfg.ExtraLabels = make(map[string]string)
+	extraLabelsEnv := os.Getenv("CHECK_LABELS")
+	if len(extraLabelsEnv) != 0 {
+		labels, err := parseLabels("CHECK_LABELS", extraLabelsEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ExtraLabels = labels
+		log.Infoln("Parsed CHECK_LABELS:", cfg.ExtraLabels)
+	}
+
+	// Parse extra annotations for the created resources.
+	cfg.DeploymentAnnotations = make(map[string]string)
+	deploymentAnnotationsEnv := os.Getenv("CHECK_DEPLOYMENT_ANNOTATIONS")
+	if len(deploymentAnnotationsEnv) != 0 {
+		annotations, err := parseAnnotations("CHECK_DEPLOYMENT_ANNOTATIONS", deploymentAnnotationsEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.DeploymentAnnotations = annotations
+		log.Infoln("Parsed CHECK_DEPLOYMENT_ANNOTATIONS:", cfg.DeploymentAnnotations)
+	}
+	cfg.PodAnnotations = make(map[string]string)
+	podAnnotationsEnv := os.Getenv("CHECK_POD_ANNOTATIONS")
+	if len(podAnnotationsEnv) != 0 {
+		annotations, err := parseAnnotations("CHECK_POD_ANNOTATIONS", podAnnotationsEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.PodAnnotations = annotations
+		log.Infoln("Parsed CHECK_POD_ANNOTATIONS:", cfg.PodAnnotations)
+	}
+	cfg.ServiceAnnotations = make(map[string]string)
+	serviceAnnotationsEnv := os.Getenv("CHECK_SERVICE_ANNOTATIONS")
+	if len(serviceAnnotationsEnv) != 0 {
+		annotations, err := parseAnnotations("CHECK_SERVICE_ANNOTATIONS", serviceAnnotationsEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ServiceAnnotations = annotations
+		log.Infoln("Parsed CHECK_SERVICE_ANNOTATIONS:", cfg.ServiceAnnotations)
+	}
+
+	// Parse the service mesh mode and its sidecar settings.
+	meshModeEnv := strings.ToLower(strings.TrimSpace(os.Getenv("CHECK_MESH_MODE")))
+	if len(meshModeEnv) != 0 {
+		if meshModeEnv != meshModeIstio {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_MODE: %q must be %s", meshModeEnv, meshModeIstio)
+		}
+		cfg.MeshMode = meshModeEnv
+		cfg.MeshInject = true
+		cfg.MeshHoldApplication = true
+		log.Infoln("Parsed CHECK_MESH_MODE:", cfg.MeshMode)
+	}
+	meshInjectEnv := os.Getenv("CHECK_MESH_INJECT")
+	if len(meshInjectEnv) != 0 {
+		if len(cfg.MeshMode) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_INJECT: CHECK_MESH_MODE must be set")
+		}
+		meshInjectValue, err := strconv.ParseBool(meshInjectEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_INJECT: %w", err)
+		}
+		cfg.MeshInject = meshInjectValue
+		log.Infoln("Parsed CHECK_MESH_INJECT:", cfg.MeshInject)
+	}
+	meshHoldApplicationEnv := os.Getenv("CHECK_MESH_HOLD_APPLICATION")
+	if len(meshHoldApplicationEnv) != 0 {
+		if len(cfg.MeshMode) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_HOLD_APPLICATION: CHECK_MESH_MODE must be set")
+		}
+		meshHoldApplicationValue, err := strconv.ParseBool(meshHoldApplicationEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_HOLD_APPLICATION: %w", err)
+		}
+		cfg.MeshHoldApplication = meshHoldApplicationValue
+		log.Infoln("Parsed CHECK_MESH_HOLD_APPLICATION:", cfg.MeshHoldApplication)
+	}
+	meshQuitOnCleanupEnv := os.Getenv("CHECK_MESH_QUIT_ON_CLEANUP")
+	if len(meshQuitOnCleanupEnv) != 0 {
+		if len(cfg.MeshMode) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_QUIT_ON_CLEANUP: CHECK_MESH_MODE must be set")
+		}
+		meshQuitOnCleanupValue, err := strconv.ParseBool(meshQuitOnCleanupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_MESH_QUIT_ON_CLEANUP: %w", err)
+		}
+		cfg.MeshQuitOnCleanup = meshQuitOnCleanupValue
+		log.Infoln("Parsed CHECK_MESH_QUIT_ON_CLEANUP:", cfg.MeshQuitOnCleanup)
+	}
+
 	// Parse shutdown grace period.
 	cfg.ShutdownGracePeriod = defaultShutdownGracePeriod
 	shutdownGracePeriodEnv := os.Getenv("SHUTDOWN_GRACE_PERIOD")
@@ -353,66 +1368,1795 @@ func parseConfig() (*CheckConfig, error) {
 		log.Infoln("Parsed SHUTDOWN_GRACE_PERIOD:", cfg.ShutdownGracePeriod)
 	}
 
-	// Ensure logrus and checkclient share debug state.
-	checkclient.Debug = cfg.Debug
-
-	return cfg, nil
-}
-
-// parseTolerations converts a comma-separated tolerations string into objects for the pod spec.
-func parseTolerations(raw string) ([]corev1.Toleration, error) {
-	// Split entries on commas for key/value pairs.
-	entries := strings.Split(raw, ",")
-	if len(entries) == 0 {
-		return nil, fmt.Errorf("no tolerations provided")
+	// Parse termination message policy.
+	terminationMessageFallbackEnv := os.Getenv("CHECK_TERMINATION_MESSAGE_FALLBACK_TO_LOGS")
+	if len(terminationMessageFallbackEnv) != 0 {
+		fallbackValue, err := strconv.ParseBool(terminationMessageFallbackEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_TERMINATION_MESSAGE_FALLBACK_TO_LOGS: %w", err)
+		}
+		cfg.TerminationMessageFallbackToLogs = fallbackValue
+		log.Infoln("Parsed CHECK_TERMINATION_MESSAGE_FALLBACK_TO_LOGS:", cfg.TerminationMessageFallbackToLogs)
 	}
 
-	// Build the tolerations slice.
-	tolerations := make([]corev1.Toleration, 0)
-	for _, entry := range entries {
-		// Split key and value/effect parts.
-		parts := strings.Split(entry, "=")
-		if len(parts) != 2 {
-			log.Warnln("Unable to parse key value pair:", entry)
-			log.Warnln("Setting operator to", corev1.TolerationOpExists)
-			toleration := corev1.Toleration{
-				Key:      parts[0],
-				Operator: corev1.TolerationOpExists,
-			}
-			log.Infoln("Adding toleration to deployment:", toleration)
-			tolerations = append(tolerations, toleration)
-			continue
+	// Parse the liveness and readiness probe timing.
+	cfg.ProbeInitialDelaySeconds = defaultProbeInitialDelaySeconds
+	probeInitialDelaySecondsEnv := os.Getenv("CHECK_PROBE_INITIAL_DELAY_SECONDS")
+	if len(probeInitialDelaySecondsEnv) != 0 {
+		initialDelayValue, err := strconv.ParseInt(probeInitialDelaySecondsEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_INITIAL_DELAY_SECONDS: %w", err)
 		}
-
-		// Split value/effect when present.
-		valueEffect := strings.Split(parts[1], ":")
-		if len(valueEffect) != 2 {
-			log.Warnln("Unable to parse complete toleration value and effect:", valueEffect)
-			toleration := corev1.Toleration{
-				Key:      parts[0],
-				Operator: corev1.TolerationOpEqual,
-				Value:    parts[1],
-			}
-			log.Infoln("Adding toleration to deployment:", toleration)
-			tolerations = append(tolerations, toleration)
-			continue
+		if initialDelayValue < 0 {
+			return nil, fmt.Errorf("CHECK_PROBE_INITIAL_DELAY_SECONDS must be >= 0, got %d", initialDelayValue)
 		}
-
-		// Build a full toleration when both value and effect are present.
-		toleration := corev1.Toleration{
-			Key:      parts[0],
-			Operator: corev1.TolerationOpEqual,
-			Value:    valueEffect[0],
-			Effect:   corev1.TaintEffect(valueEffect[1]),
+		cfg.ProbeInitialDelaySeconds = int32(initialDelayValue)
+		log.Infoln("Parsed CHECK_PROBE_INITIAL_DELAY_SECONDS:", cfg.ProbeInitialDelaySeconds)
+	}
+	cfg.ProbePeriodSeconds = defaultProbePeriodSeconds
+	probePeriodSecondsEnv := os.Getenv("CHECK_PROBE_PERIOD_SECONDS")
+	if len(probePeriodSecondsEnv) != 0 {
+		periodValue, err := strconv.ParseInt(probePeriodSecondsEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PERIOD_SECONDS: %w", err)
 		}
-		log.Infoln("Adding toleration to deployment:", toleration)
-		tolerations = append(tolerations, toleration)
+		if periodValue < 1 {
+			return nil, fmt.Errorf("CHECK_PROBE_PERIOD_SECONDS must be >= 1, got %d", periodValue)
+		}
+		cfg.ProbePeriodSeconds = int32(periodValue)
+		log.Infoln("Parsed CHECK_PROBE_PERIOD_SECONDS:", cfg.ProbePeriodSeconds)
 	}
-
-	return tolerations, nil
-}
-
-// parseNodeSelectors converts a comma-separated selector string into a map for the pod spec.
+	cfg.ProbeTimeoutSeconds = defaultProbeTimeoutSeconds
+	probeTimeoutSecondsEnv := os.Getenv("CHECK_PROBE_TIMEOUT_SECONDS")
+	if len(probeTimeoutSecondsEnv) != 0 {
+		timeoutValue, err := strconv.ParseInt(probeTimeoutSecondsEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_TIMEOUT_SECONDS: %w", err)
+		}
+		if timeoutValue < 1 {
+			return nil, fmt.Errorf("CHECK_PROBE_TIMEOUT_SECONDS must be >= 1, got %d", timeoutValue)
+		}
+		cfg.ProbeTimeoutSeconds = int32(timeoutValue)
+		log.Infoln("Parsed CHECK_PROBE_TIMEOUT_SECONDS:", cfg.ProbeTimeoutSeconds)
+	}
+	cfg.ProbeFailureThreshold = defaultProbeFailureThreshold
+	probeFailureThresholdEnv := os.Getenv("CHECK_PROBE_FAILURE_THRESHOLD")
+	if len(probeFailureThresholdEnv) != 0 {
+		thresholdValue, err := strconv.ParseInt(probeFailureThresholdEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_FAILURE_THRESHOLD: %w", err)
+		}
+		if thresholdValue < 1 {
+			return nil, fmt.Errorf("CHECK_PROBE_FAILURE_THRESHOLD must be >= 1, got %d", thresholdValue)
+		}
+		cfg.ProbeFailureThreshold = int32(thresholdValue)
+		log.Infoln("Parsed CHECK_PROBE_FAILURE_THRESHOLD:", cfg.ProbeFailureThreshold)
+	}
+
+	// Parse the startup probe and its thresholds.
+	startupProbeEnv := os.Getenv("CHECK_STARTUP_PROBE")
+	if len(startupProbeEnv) != 0 {
+		startupValue, err := strconv.ParseBool(startupProbeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_STARTUP_PROBE: %w", err)
+		}
+		cfg.StartupProbe = startupValue
+		log.Infoln("Parsed CHECK_STARTUP_PROBE:", cfg.StartupProbe)
+	}
+	cfg.StartupProbeFailureThreshold = defaultStartupProbeFailureThreshold
+	startupFailureThresholdEnv := os.Getenv("CHECK_STARTUP_PROBE_FAILURE_THRESHOLD")
+	if len(startupFailureThresholdEnv) != 0 {
+		thresholdValue, err := strconv.ParseInt(startupFailureThresholdEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_STARTUP_PROBE_FAILURE_THRESHOLD: %w", err)
+		}
+		if thresholdValue < 1 {
+			return nil, fmt.Errorf("CHECK_STARTUP_PROBE_FAILURE_THRESHOLD must be >= 1, got %d", thresholdValue)
+		}
+		if !cfg.StartupProbe {
+			return nil, fmt.Errorf("failed to parse CHECK_STARTUP_PROBE_FAILURE_THRESHOLD: CHECK_STARTUP_PROBE must be enabled")
+		}
+		cfg.StartupProbeFailureThreshold = int32(thresholdValue)
+		log.Infoln("Parsed CHECK_STARTUP_PROBE_FAILURE_THRESHOLD:", cfg.StartupProbeFailureThreshold)
+	}
+	cfg.StartupProbePeriodSeconds = defaultStartupProbePeriodSeconds
+	startupPeriodEnv := os.Getenv("CHECK_STARTUP_PROBE_PERIOD_SECONDS")
+	if len(startupPeriodEnv) != 0 {
+		periodValue, err := strconv.ParseInt(startupPeriodEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_STARTUP_PROBE_PERIOD_SECONDS: %w", err)
+		}
+		if periodValue < 1 {
+			return nil, fmt.Errorf("CHECK_STARTUP_PROBE_PERIOD_SECONDS must be >= 1, got %d", periodValue)
+		}
+		if !cfg.StartupProbe {
+			return nil, fmt.Errorf("failed to parse CHECK_STARTUP_PROBE_PERIOD_SECONDS: CHECK_STARTUP_PROBE must be enabled")
+		}
+		cfg.StartupProbePeriodSeconds = int32(periodValue)
+		log.Infoln("Parsed CHECK_STARTUP_PROBE_PERIOD_SECONDS:", cfg.StartupProbePeriodSeconds)
+	}
+
+	// Parse the probe handler type and its target.
+	cfg.ProbeType = probeTypeTCP
+	probeTypeEnv := os.Getenv("CHECK_PROBE_TYPE")
+	if len(probeTypeEnv) != 0 {
+		probeType := strings.ToLower(probeTypeEnv)
+		if probeType != probeTypeTCP && probeType != probeTypeHTTP && probeType != probeTypeExec {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_TYPE: %q must be %s, %s, or %s", probeTypeEnv, probeTypeTCP, probeTypeHTTP, probeTypeExec)
+		}
+		cfg.ProbeType = probeType
+		log.Infoln("Parsed CHECK_PROBE_TYPE:", cfg.ProbeType)
+	}
+	cfg.ProbePath = defaultHTTPPath
+	probePathEnv := os.Getenv("CHECK_PROBE_PATH")
+	if len(probePathEnv) != 0 {
+		if cfg.ProbeType != probeTypeHTTP {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PATH: CHECK_PROBE_TYPE must be %s", probeTypeHTTP)
+		}
+		if !strings.HasPrefix(probePathEnv, "/") {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PATH: %q must start with /", probePathEnv)
+		}
+		cfg.ProbePath = probePathEnv
+		log.Infoln("Parsed CHECK_PROBE_PATH:", cfg.ProbePath)
+	}
+	probePortEnv := os.Getenv("CHECK_PROBE_PORT")
+	if len(probePortEnv) != 0 {
+		portValue, err := strconv.ParseInt(probePortEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PORT: %w", err)
+		}
+		if portValue < 1 || portValue > 65535 {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PORT: %d is not a valid port", portValue)
+		}
+		if cfg.ProbeType == probeTypeExec {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_PORT: exec probes have no port")
+		}
+		cfg.ProbePort = int32(portValue)
+		log.Infoln("Parsed CHECK_PROBE_PORT:", cfg.ProbePort)
+	}
+	probeExecCommandEnv := os.Getenv("CHECK_PROBE_EXEC_COMMAND")
+	if len(probeExecCommandEnv) != 0 {
+		if cfg.ProbeType != probeTypeExec {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBE_EXEC_COMMAND: CHECK_PROBE_TYPE must be %s", probeTypeExec)
+		}
+		cfg.ProbeExecCommand = strings.Fields(probeExecCommandEnv)
+		log.Infoln("Parsed CHECK_PROBE_EXEC_COMMAND:", cfg.ProbeExecCommand)
+	}
+	if cfg.ProbeType == probeTypeExec && len(cfg.ProbeExecCommand) == 0 {
+		return nil, fmt.Errorf("failed to parse CHECK_PROBE_TYPE: CHECK_PROBE_EXEC_COMMAND must be set for %s probes", probeTypeExec)
+	}
+
+	// Parse crash loop restart threshold.
+	cfg.MaxContainerRestarts = defaultMaxContainerRestarts
+	maxContainerRestartsEnv := os.Getenv("CHECK_MAX_CONTAINER_RESTARTS")
+	if len(maxContainerRestartsEnv) != 0 {
+		restartValue, err := strconv.Atoi(maxContainerRestartsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_MAX_CONTAINER_RESTARTS: %w", err)
+		}
+		if restartValue < 0 {
+			return nil, fmt.Errorf("CHECK_MAX_CONTAINER_RESTARTS must be >= 0, got %d", restartValue)
+		}
+		cfg.MaxContainerRestarts = restartValue
+		log.Infoln("Parsed CHECK_MAX_CONTAINER_RESTARTS:", cfg.MaxContainerRestarts)
+	}
+
+	// Parse orphan handling policy.
+	cfg.OrphanPolicy = orphanPolicyClean
+	orphanPolicyEnv := os.Getenv("CHECK_ORPHAN_POLICY")
+	if len(orphanPolicyEnv) != 0 {
+		if orphanPolicyEnv != orphanPolicyClean && orphanPolicyEnv != orphanPolicyWarn && orphanPolicyEnv != orphanPolicyFail {
+			return nil, fmt.Errorf("CHECK_ORPHAN_POLICY must be one of %s, %s, or %s, got %s", orphanPolicyClean, orphanPolicyWarn, orphanPolicyFail, orphanPolicyEnv)
+		}
+		cfg.OrphanPolicy = orphanPolicyEnv
+		log.Infoln("Parsed CHECK_ORPHAN_POLICY:", cfg.OrphanPolicy)
+	}
+
+	// Parse the owner reference toggle.
+	ownerReferenceEnv := os.Getenv("CHECK_OWNER_REFERENCE")
+	if len(ownerReferenceEnv) != 0 {
+		ownerValue, err := strconv.ParseBool(ownerReferenceEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_OWNER_REFERENCE: %w", err)
+		}
+		cfg.OwnerReference = ownerValue
+		log.Infoln("Parsed CHECK_OWNER_REFERENCE:", cfg.OwnerReference)
+	}
+
+	// Parse the run lock.
+	runLockEnv := os.Getenv("CHECK_RUN_LOCK")
+	if len(runLockEnv) != 0 {
+		lockValue, err := strconv.ParseBool(runLockEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RUN_LOCK: %w", err)
+		}
+		cfg.RunLock = lockValue
+		log.Infoln("Parsed CHECK_RUN_LOCK:", cfg.RunLock)
+	}
+
+	// Parse whether a held run lock fails the check.
+	runLockFailEnv := os.Getenv("CHECK_RUN_LOCK_FAIL_WHEN_HELD")
+	if len(runLockFailEnv) != 0 {
+		failValue, err := strconv.ParseBool(runLockFailEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RUN_LOCK_FAIL_WHEN_HELD: %w", err)
+		}
+		if failValue && !cfg.RunLock {
+			return nil, fmt.Errorf("CHECK_RUN_LOCK_FAIL_WHEN_HELD requires CHECK_RUN_LOCK")
+		}
+		cfg.RunLockFailWhenHeld = failValue
+		log.Infoln("Parsed CHECK_RUN_LOCK_FAIL_WHEN_HELD:", cfg.RunLockFailWhenHeld)
+	}
+
+	// Parse the stale resource age threshold.
+	staleResourceAgeEnv := os.Getenv("CHECK_STALE_RESOURCE_AGE")
+	if len(staleResourceAgeEnv) != 0 {
+		durationValue, err := time.ParseDuration(staleResourceAgeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_STALE_RESOURCE_AGE: %w", err)
+		}
+		minimum := cfg.CheckTimeLimit + cfg.ShutdownGracePeriod
+		if durationValue < 0 || (durationValue > 0 && durationValue < minimum) {
+			return nil, fmt.Errorf("failed to parse CHECK_STALE_RESOURCE_AGE: %s must be at least the check time limit plus shutdown grace period of %s so a live run is never reclaimed", durationValue, minimum)
+		}
+		cfg.StaleResourceAge = durationValue
+		log.Infoln("Parsed CHECK_STALE_RESOURCE_AGE:", cfg.StaleResourceAge)
+	}
+
+	// Parse the resource TTL and its annotation.
+	resourceTTLEnv := os.Getenv("CHECK_RESOURCE_TTL")
+	if len(resourceTTLEnv) != 0 {
+		durationValue, err := time.ParseDuration(resourceTTLEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RESOURCE_TTL: %w", err)
+		}
+		if durationValue < 0 || (durationValue > 0 && durationValue <= cfg.CheckTimeLimit) {
+			return nil, fmt.Errorf("failed to parse CHECK_RESOURCE_TTL: %s must exceed the check time limit of %s so live resources never expire", durationValue, cfg.CheckTimeLimit)
+		}
+		cfg.ResourceTTL = durationValue
+		log.Infoln("Parsed CHECK_RESOURCE_TTL:", cfg.ResourceTTL)
+	}
+	cfg.ExpiryAnnotation = defaultExpiryAnnotation
+	expiryAnnotationEnv := os.Getenv("CHECK_EXPIRY_ANNOTATION")
+	if len(expiryAnnotationEnv) != 0 {
+		problems := validation.IsQualifiedName(expiryAnnotationEnv)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_EXPIRY_ANNOTATION: %s", strings.Join(problems, "; "))
+		}
+		cfg.ExpiryAnnotation = expiryAnnotationEnv
+		log.Infoln("Parsed CHECK_EXPIRY_ANNOTATION:", cfg.ExpiryAnnotation)
+	}
+
+	// Parse the server-side apply mode and field manager.
+	serverSideApplyEnv := os.Getenv("CHECK_SERVER_SIDE_APPLY")
+	if len(serverSideApplyEnv) != 0 {
+		serverSideApplyValue, err := strconv.ParseBool(serverSideApplyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SERVER_SIDE_APPLY: %w", err)
+		}
+		cfg.ServerSideApply = serverSideApplyValue
+		log.Infoln("Parsed CHECK_SERVER_SIDE_APPLY:", cfg.ServerSideApply)
+	}
+	cfg.FieldManager = defaultFieldManager
+	fieldManagerEnv := os.Getenv("CHECK_FIELD_MANAGER")
+	if len(fieldManagerEnv) != 0 {
+		if len(fieldManagerEnv) > 128 {
+			return nil, fmt.Errorf("failed to parse CHECK_FIELD_MANAGER: must be at most 128 characters")
+		}
+		cfg.FieldManager = fieldManagerEnv
+		log.Infoln("Parsed CHECK_FIELD_MANAGER:", cfg.FieldManager)
+	}
+
+	// Parse the cleanup-only mode.
+	cleanupOnlyEnv := os.Getenv("CLEANUP_ONLY")
+	if len(cleanupOnlyEnv) != 0 {
+		cleanupOnlyValue, err := strconv.ParseBool(cleanupOnlyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CLEANUP_ONLY: %w", err)
+		}
+		cfg.CleanupOnly = cleanupOnlyValue
+		log.Infoln("Parsed CLEANUP_ONLY:", cfg.CleanupOnly)
+	}
+
+	// Parse fatal container waiting reasons, where "none" disables fast-fail.
+	fatalWaitingReasons := defaultFatalWaitingReasons
+	fatalWaitingReasonsEnv := os.Getenv("CHECK_FATAL_WAITING_REASONS")
+	if len(fatalWaitingReasonsEnv) != 0 {
+		fatalWaitingReasons = fatalWaitingReasonsEnv
+		log.Infoln("Parsed CHECK_FATAL_WAITING_REASONS:", fatalWaitingReasons)
+	}
+	cfg.FatalWaitingReasons = make(map[string]bool)
+	if fatalWaitingReasons != "none" {
+		for _, waitingReason := range strings.Split(fatalWaitingReasons, ",") {
+			waitingReason = strings.TrimSpace(waitingReason)
+			if len(waitingReason) == 0 {
+				continue
+			}
+			cfg.FatalWaitingReasons[waitingReason] = true
+		}
+	}
+
+	// Parse the pod error grace, after which image pull failures always fail the check.
+	podErrorGraceEnv := os.Getenv("CHECK_POD_ERROR_GRACE")
+	if len(podErrorGraceEnv) != 0 {
+		durationValue, err := time.ParseDuration(podErrorGraceEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_ERROR_GRACE: %w", err)
+		}
+		if durationValue < 0 {
+			return nil, fmt.Errorf("CHECK_POD_ERROR_GRACE must be >= 0, got %s", durationValue)
+		}
+		cfg.PodErrorGrace = durationValue
+		log.Infoln("Parsed CHECK_POD_ERROR_GRACE:", cfg.PodErrorGrace)
+	}
+	if cfg.PodErrorGrace > 0 {
+		cfg.FatalWaitingReasons["ErrImagePull"] = true
+		cfg.FatalWaitingReasons["ImagePullBackOff"] = true
+	}
+
+	// Parse the resource quota preflight.
+	quotaPreflightEnv := os.Getenv("CHECK_QUOTA_PREFLIGHT")
+	if len(quotaPreflightEnv) != 0 {
+		preflightValue, err := strconv.ParseBool(quotaPreflightEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_QUOTA_PREFLIGHT: %w", err)
+		}
+		cfg.QuotaPreflight = preflightValue
+		log.Infoln("Parsed CHECK_QUOTA_PREFLIGHT:", cfg.QuotaPreflight)
+	}
+
+	// Parse how long pods may stay unschedulable before failing fast.
+	cfg.UnschedulableTimeout = defaultUnschedulableTimeout
+	unschedulableTimeoutEnv := os.Getenv("CHECK_UNSCHEDULABLE_TIMEOUT")
+	if len(unschedulableTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(unschedulableTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_UNSCHEDULABLE_TIMEOUT: %w", err)
+		}
+		if durationValue < 0 {
+			return nil, fmt.Errorf("CHECK_UNSCHEDULABLE_TIMEOUT must be >= 0, got %s", durationValue)
+		}
+		cfg.UnschedulableTimeout = durationValue
+		log.Infoln("Parsed CHECK_UNSCHEDULABLE_TIMEOUT:", cfg.UnschedulableTimeout)
+	}
+
+	// Parse cluster autoscaler validation settings.
+	autoscalerModeEnv := os.Getenv("CHECK_AUTOSCALER_MODE")
+	if len(autoscalerModeEnv) != 0 {
+		autoscalerValue, err := strconv.ParseBool(autoscalerModeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_AUTOSCALER_MODE: %w", err)
+		}
+		cfg.AutoscalerMode = autoscalerValue
+		log.Infoln("Parsed CHECK_AUTOSCALER_MODE:", cfg.AutoscalerMode)
+	}
+	cfg.AutoscalerTimeout = defaultAutoscalerTimeout
+	autoscalerTimeoutEnv := os.Getenv("CHECK_AUTOSCALER_TIMEOUT")
+	if len(autoscalerTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(autoscalerTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_AUTOSCALER_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_AUTOSCALER_TIMEOUT: must be greater than zero")
+		}
+		cfg.AutoscalerTimeout = durationValue
+		log.Infoln("Parsed CHECK_AUTOSCALER_TIMEOUT:", cfg.AutoscalerTimeout)
+	}
+	// Apply autoscaler placement and sizing only when the mode is enabled.
+	if cfg.AutoscalerMode {
+		autoscalerNodeSelectorEnv := os.Getenv("CHECK_AUTOSCALER_NODE_SELECTOR")
+		if len(autoscalerNodeSelectorEnv) != 0 {
+			selectors, err := parseNodeSelectors(autoscalerNodeSelectorEnv)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range selectors {
+				cfg.CheckDeploymentNodeSelectors[key] = value
+			}
+			log.Infoln("Parsed CHECK_AUTOSCALER_NODE_SELECTOR:", selectors)
+		}
+		autoscalerCPURequestEnv := os.Getenv("CHECK_AUTOSCALER_CPU_REQUEST")
+		if len(autoscalerCPURequestEnv) != 0 {
+			cpuValue, err := strconv.ParseInt(autoscalerCPURequestEnv, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_AUTOSCALER_CPU_REQUEST: %w", err)
+			}
+			cfg.MillicoreRequest = int(cpuValue)
+			if cfg.MillicoreLimit < cfg.MillicoreRequest {
+				cfg.MillicoreLimit = cfg.MillicoreRequest
+			}
+			log.Infoln("Parsed CHECK_AUTOSCALER_CPU_REQUEST:", cfg.MillicoreRequest)
+		}
+	}
+
+	// Parse Karpenter validation settings.
+	cfg.CheckNodeAffinityRequirements = make([]corev1.NodeSelectorRequirement, 0)
+	karpenterModeEnv := os.Getenv("CHECK_KARPENTER_MODE")
+	if len(karpenterModeEnv) != 0 {
+		karpenterValue, err := strconv.ParseBool(karpenterModeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_KARPENTER_MODE: %w", err)
+		}
+		cfg.KarpenterMode = karpenterValue
+		log.Infoln("Parsed CHECK_KARPENTER_MODE:", cfg.KarpenterMode)
+	}
+	cfg.KarpenterTimeout = defaultKarpenterTimeout
+	karpenterTimeoutEnv := os.Getenv("CHECK_KARPENTER_TIMEOUT")
+	if len(karpenterTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(karpenterTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_KARPENTER_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_KARPENTER_TIMEOUT: must be greater than zero")
+		}
+		cfg.KarpenterTimeout = durationValue
+		log.Infoln("Parsed CHECK_KARPENTER_TIMEOUT:", cfg.KarpenterTimeout)
+	}
+
+	// Apply Karpenter placement only when the mode is enabled.
+	if cfg.KarpenterMode {
+		karpenterNodeSelectorEnv := os.Getenv("CHECK_KARPENTER_NODE_SELECTOR")
+		if len(karpenterNodeSelectorEnv) != 0 {
+			selectors, err := parseNodeSelectors(karpenterNodeSelectorEnv)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range selectors {
+				cfg.CheckDeploymentNodeSelectors[key] = value
+			}
+			log.Infoln("Parsed CHECK_KARPENTER_NODE_SELECTOR:", selectors)
+		}
+		karpenterRequirementsEnv := os.Getenv("CHECK_KARPENTER_REQUIREMENTS")
+		if len(karpenterRequirementsEnv) != 0 {
+			requirements, err := parseNodeAffinityRequirements(karpenterRequirementsEnv)
+			if err != nil {
+				return nil, err
+			}
+			cfg.CheckNodeAffinityRequirements = requirements
+			log.Infoln("Parsed CHECK_KARPENTER_REQUIREMENTS:", cfg.CheckNodeAffinityRequirements)
+		}
+	}
+
+	// Parse the metrics endpoint settings.
+	metricsAddressEnv := os.Getenv("CHECK_METRICS_ADDRESS")
+	if len(metricsAddressEnv) != 0 {
+		cfg.MetricsAddress = metricsAddressEnv
+		log.Infoln("Parsed CHECK_METRICS_ADDRESS:", cfg.MetricsAddress)
+	}
+	cfg.MetricsLinger = defaultMetricsLinger
+	metricsLingerEnv := os.Getenv("CHECK_METRICS_LINGER")
+	if len(metricsLingerEnv) != 0 {
+		durationValue, err := time.ParseDuration(metricsLingerEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_METRICS_LINGER: %w", err)
+		}
+		cfg.MetricsLinger = durationValue
+		log.Infoln("Parsed CHECK_METRICS_LINGER:", cfg.MetricsLinger)
+	}
+
+	// Parse the result document output.
+	cfg.ResultOutputPath = os.Getenv("RESULT_OUTPUT_PATH")
+	if len(cfg.ResultOutputPath) != 0 {
+		log.Infoln("Parsed RESULT_OUTPUT_PATH:", cfg.ResultOutputPath)
+	}
+
+	// Parse the Pushgateway settings.
+	pushgatewayURLEnv := os.Getenv("CHECK_PUSHGATEWAY_URL")
+	if len(pushgatewayURLEnv) != 0 {
+		parsedURL, err := url.Parse(pushgatewayURLEnv)
+		if err != nil || len(parsedURL.Scheme) == 0 || len(parsedURL.Host) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_PUSHGATEWAY_URL: %q must be an absolute URL", pushgatewayURLEnv)
+		}
+		cfg.PushgatewayURL = pushgatewayURLEnv
+		log.Infoln("Parsed CHECK_PUSHGATEWAY_URL:", cfg.PushgatewayURL)
+	}
+	cfg.PushgatewayJob = defaultPushgatewayJob
+	pushgatewayJobEnv := os.Getenv("CHECK_PUSHGATEWAY_JOB")
+	if len(pushgatewayJobEnv) != 0 {
+		cfg.PushgatewayJob = pushgatewayJobEnv
+		log.Infoln("Parsed CHECK_PUSHGATEWAY_JOB:", cfg.PushgatewayJob)
+	}
+
+	// Parse the failure webhook.
+	failureWebhookURLEnv := os.Getenv("CHECK_FAILURE_WEBHOOK_URL")
+	if len(failureWebhookURLEnv) != 0 {
+		parsedURL, err := url.Parse(failureWebhookURLEnv)
+		if err != nil || len(parsedURL.Scheme) == 0 || len(parsedURL.Host) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_FAILURE_WEBHOOK_URL: %q must be an absolute URL", failureWebhookURLEnv)
+		}
+		cfg.FailureWebhookURL = failureWebhookURLEnv
+		log.Infoln("Parsed CHECK_FAILURE_WEBHOOK_URL:", parsedURL.Scheme+"://"+parsedURL.Host)
+	}
+
+	// Parse the node pool label for per-pool runs.
+	nodePoolLabelEnv := os.Getenv("CHECK_NODE_POOL_LABEL")
+	if len(nodePoolLabelEnv) != 0 {
+		_, pinned := cfg.CheckDeploymentNodeSelectors[nodePoolLabelEnv]
+		if pinned {
+			return nil, fmt.Errorf("CHECK_NODE_POOL_LABEL %s is already pinned by NODE_SELECTOR", nodePoolLabelEnv)
+		}
+		cfg.NodePoolLabel = nodePoolLabelEnv
+		log.Infoln("Parsed CHECK_NODE_POOL_LABEL:", cfg.NodePoolLabel)
+	}
+
+	// Parse the namespaces to run the check in.
+	checkNamespacesEnv := os.Getenv("CHECK_NAMESPACES")
+	if len(checkNamespacesEnv) != 0 {
+		namespaces, err := parseNamespaces(checkNamespacesEnv)
+		if err != nil {
+			return nil, err
+		}
+		if len(cfg.NodePoolLabel) != 0 || cfg.CreateNamespace {
+			return nil, fmt.Errorf("CHECK_NAMESPACES cannot be combined with CHECK_NODE_POOL_LABEL or CHECK_CREATE_NAMESPACE")
+		}
+		cfg.CheckNamespaces = namespaces
+		log.Infoln("Parsed CHECK_NAMESPACES:", cfg.CheckNamespaces)
+	}
+
+	// Parse whether the namespaces run in parallel.
+	namespacesParallelEnv := os.Getenv("CHECK_NAMESPACES_PARALLEL")
+	if len(namespacesParallelEnv) != 0 {
+		parallelValue, err := strconv.ParseBool(namespacesParallelEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_NAMESPACES_PARALLEL: %w", err)
+		}
+		if parallelValue && len(cfg.CheckNamespaces) == 0 {
+			return nil, fmt.Errorf("CHECK_NAMESPACES_PARALLEL requires CHECK_NAMESPACES")
+		}
+		cfg.NamespacesParallel = parallelValue
+		log.Infoln("Parsed CHECK_NAMESPACES_PARALLEL:", cfg.NamespacesParallel)
+	}
+
+	// Parse the priority class for the check pods.
+	priorityClassNameEnv := os.Getenv("CHECK_PRIORITY_CLASS_NAME")
+	if len(priorityClassNameEnv) != 0 {
+		cfg.PriorityClassName = priorityClassNameEnv
+		log.Infoln("Parsed CHECK_PRIORITY_CLASS_NAME:", cfg.PriorityClassName)
+	}
+
+	// Parse host aliases for names the check image resolves outside cluster DNS.
+	hostAliasesEnv := os.Getenv("CHECK_HOST_ALIASES")
+	if len(hostAliasesEnv) != 0 {
+		hostAliases, err := parseHostAliases(hostAliasesEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HostAliases = hostAliases
+		log.Infoln("Parsed CHECK_HOST_ALIASES:", cfg.HostAliases)
+	}
+
+	// Parse the readiness gate the checker opens on the check pods.
+	readinessGateEnv := os.Getenv("CHECK_READINESS_GATE")
+	if len(readinessGateEnv) != 0 {
+		problems := validation.IsQualifiedName(readinessGateEnv)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_READINESS_GATE: %s", strings.Join(problems, "; "))
+		}
+		cfg.ReadinessGate = readinessGateEnv
+		log.Infoln("Parsed CHECK_READINESS_GATE:", cfg.ReadinessGate)
+	}
+
+	// Parse topology spread constraints.
+	topologySpreadEnv := os.Getenv("CHECK_TOPOLOGY_SPREAD")
+	if len(topologySpreadEnv) != 0 {
+		constraints, err := parseTopologySpreadConstraints(topologySpreadEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TopologySpreadConstraints = constraints
+		log.Infoln("Parsed CHECK_TOPOLOGY_SPREAD:", topologySpreadEnv)
+	}
+
+	// Parse the minimum zone distribution.
+	minZonesEnv := os.Getenv("CHECK_MIN_ZONES")
+	if len(minZonesEnv) != 0 {
+		minZones, err := strconv.Atoi(minZonesEnv)
+		if err != nil || minZones < 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_MIN_ZONES: %q must be a non-negative integer", minZonesEnv)
+		}
+		if minZones > cfg.CheckDeploymentReplicas {
+			return nil, fmt.Errorf("CHECK_MIN_ZONES %d cannot exceed CHECK_DEPLOYMENT_REPLICAS %d", minZones, cfg.CheckDeploymentReplicas)
+		}
+		cfg.MinZones = minZones
+		log.Infoln("Parsed CHECK_MIN_ZONES:", cfg.MinZones)
+	}
+
+	// Parse GKE Autopilot compatibility mode and adjust resources to its rules.
+	autopilotModeEnv := os.Getenv("CHECK_AUTOPILOT_MODE")
+	if len(autopilotModeEnv) != 0 {
+		autopilotValue, err := strconv.ParseBool(autopilotModeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_AUTOPILOT_MODE: %w", err)
+		}
+		cfg.AutopilotMode = autopilotValue
+		log.Infoln("Parsed CHECK_AUTOPILOT_MODE:", cfg.AutopilotMode)
+	}
+	if cfg.AutopilotMode {
+		applyAutopilotResources(cfg)
+	}
+
+	// Parse the pod security context settings.
+	podRunAsNonRootEnv := os.Getenv("CHECK_POD_RUN_AS_NON_ROOT")
+	if len(podRunAsNonRootEnv) != 0 {
+		runAsNonRoot, err := strconv.ParseBool(podRunAsNonRootEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_RUN_AS_NON_ROOT: %w", err)
+		}
+		cfg.PodRunAsNonRoot = &runAsNonRoot
+		log.Infoln("Parsed CHECK_POD_RUN_AS_NON_ROOT:", runAsNonRoot)
+	}
+	podRunAsUserEnv := os.Getenv("CHECK_POD_RUN_AS_USER")
+	if len(podRunAsUserEnv) != 0 {
+		runAsUser, err := strconv.ParseInt(podRunAsUserEnv, 10, 64)
+		if err != nil || runAsUser < 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_RUN_AS_USER: %q is not a valid user ID", podRunAsUserEnv)
+		}
+		cfg.PodRunAsUser = &runAsUser
+		log.Infoln("Parsed CHECK_POD_RUN_AS_USER:", runAsUser)
+	}
+	podRunAsGroupEnv := os.Getenv("CHECK_POD_RUN_AS_GROUP")
+	if len(podRunAsGroupEnv) != 0 {
+		runAsGroup, err := strconv.ParseInt(podRunAsGroupEnv, 10, 64)
+		if err != nil || runAsGroup < 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_RUN_AS_GROUP: %q is not a valid group ID", podRunAsGroupEnv)
+		}
+		cfg.PodRunAsGroup = &runAsGroup
+		log.Infoln("Parsed CHECK_POD_RUN_AS_GROUP:", runAsGroup)
+	}
+	podFSGroupEnv := os.Getenv("CHECK_POD_FS_GROUP")
+	if len(podFSGroupEnv) != 0 {
+		fsGroup, err := strconv.ParseInt(podFSGroupEnv, 10, 64)
+		if err != nil || fsGroup < 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_FS_GROUP: %q is not a valid group ID", podFSGroupEnv)
+		}
+		cfg.PodFSGroup = &fsGroup
+		log.Infoln("Parsed CHECK_POD_FS_GROUP:", fsGroup)
+	}
+	podSeccompProfileEnv := os.Getenv("CHECK_POD_SECCOMP_PROFILE")
+	if len(podSeccompProfileEnv) != 0 {
+		profile, err := parseSeccompProfile(podSeccompProfileEnv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.PodSeccompProfile = profile
+		log.Infoln("Parsed CHECK_POD_SECCOMP_PROFILE:", podSeccompProfileEnv)
+	}
+	if cfg.PodRunAsNonRoot != nil && *cfg.PodRunAsNonRoot && cfg.PodRunAsUser != nil && *cfg.PodRunAsUser == 0 {
+		return nil, fmt.Errorf("CHECK_POD_RUN_AS_NON_ROOT=true conflicts with CHECK_POD_RUN_AS_USER=0")
+	}
+
+	// Parse the container security context settings.
+	readOnlyRootFilesystemEnv := os.Getenv("CHECK_CONTAINER_READ_ONLY_ROOT_FILESYSTEM")
+	if len(readOnlyRootFilesystemEnv) != 0 {
+		readOnlyValue, err := strconv.ParseBool(readOnlyRootFilesystemEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_CONTAINER_READ_ONLY_ROOT_FILESYSTEM: %w", err)
+		}
+		cfg.ContainerReadOnlyRootFilesystem = readOnlyValue
+		log.Infoln("Parsed CHECK_CONTAINER_READ_ONLY_ROOT_FILESYSTEM:", cfg.ContainerReadOnlyRootFilesystem)
+	}
+	allowPrivilegeEscalationEnv := os.Getenv("CHECK_CONTAINER_ALLOW_PRIVILEGE_ESCALATION")
+	if len(allowPrivilegeEscalationEnv) != 0 {
+		allowValue, err := strconv.ParseBool(allowPrivilegeEscalationEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_CONTAINER_ALLOW_PRIVILEGE_ESCALATION: %w", err)
+		}
+		cfg.ContainerAllowPrivilegeEscalation = &allowValue
+		log.Infoln("Parsed CHECK_CONTAINER_ALLOW_PRIVILEGE_ESCALATION:", allowValue)
+	}
+	dropAllCapabilitiesEnv := os.Getenv("CHECK_CONTAINER_DROP_ALL_CAPABILITIES")
+	if len(dropAllCapabilitiesEnv) != 0 {
+		dropValue, err := strconv.ParseBool(dropAllCapabilitiesEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_CONTAINER_DROP_ALL_CAPABILITIES: %w", err)
+		}
+		cfg.ContainerDropAllCapabilities = dropValue
+		log.Infoln("Parsed CHECK_CONTAINER_DROP_ALL_CAPABILITIES:", cfg.ContainerDropAllCapabilities)
+	}
+
+	// Parse the volumes mounted in the check container.
+	mountPaths := map[string]string{}
+	if cfg.ContainerReadOnlyRootFilesystem {
+		mountPaths[scratchVolumeMountPath] = "the read-only root filesystem scratch volume"
+	}
+	emptyDirMountPathEnv := os.Getenv("CHECK_EMPTYDIR_MOUNT_PATH")
+	if len(emptyDirMountPathEnv) != 0 {
+		err := validateMountPath("CHECK_EMPTYDIR_MOUNT_PATH", emptyDirMountPathEnv, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		cfg.EmptyDirMountPath = emptyDirMountPathEnv
+		log.Infoln("Parsed CHECK_EMPTYDIR_MOUNT_PATH:", cfg.EmptyDirMountPath)
+	}
+
+	configMapMountPathEnv := os.Getenv("CHECK_CONFIGMAP_MOUNT_PATH")
+	if len(configMapMountPathEnv) != 0 {
+		err := validateMountPath("CHECK_CONFIGMAP_MOUNT_PATH", configMapMountPathEnv, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ConfigMapMountPath = configMapMountPathEnv
+		log.Infoln("Parsed CHECK_CONFIGMAP_MOUNT_PATH:", cfg.ConfigMapMountPath)
+	}
+
+	secretNameEnv := os.Getenv("CHECK_SECRET_NAME")
+	secretMountPathEnv := os.Getenv("CHECK_SECRET_MOUNT_PATH")
+	if len(secretNameEnv) != 0 || len(secretMountPathEnv) != 0 {
+		if len(secretNameEnv) == 0 || len(secretMountPathEnv) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SECRET_NAME: CHECK_SECRET_NAME and CHECK_SECRET_MOUNT_PATH must be set together")
+		}
+		problems := validation.IsDNS1123Subdomain(secretNameEnv)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SECRET_NAME: %s", strings.Join(problems, "; "))
+		}
+		err := validateMountPath("CHECK_SECRET_MOUNT_PATH", secretMountPathEnv, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SecretName = secretNameEnv
+		cfg.SecretMountPath = secretMountPathEnv
+		log.Infoln("Parsed CHECK_SECRET_NAME:", cfg.SecretName)
+		log.Infoln("Parsed CHECK_SECRET_MOUNT_PATH:", cfg.SecretMountPath)
+	}
+
+	// Parse the persistent volume claim used to exercise dynamic provisioning.
+	cfg.PVCStorageClass = strings.TrimSpace(os.Getenv("CHECK_PVC_STORAGE_CLASS"))
+	cfg.PVCSize = resource.MustParse(defaultPVCSize)
+	cfg.PVCAccessMode = corev1.ReadWriteOnce
+	if len(cfg.PVCStorageClass) != 0 {
+		problems := validation.IsDNS1123Subdomain(cfg.PVCStorageClass)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_PVC_STORAGE_CLASS: %s", strings.Join(problems, "; "))
+		}
+		log.Infoln("Parsed CHECK_PVC_STORAGE_CLASS:", cfg.PVCStorageClass)
+
+		pvcSizeEnv := os.Getenv("CHECK_PVC_SIZE")
+		if len(pvcSizeEnv) != 0 {
+			quantity, err := resource.ParseQuantity(pvcSizeEnv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_PVC_SIZE: %w", err)
+			}
+			if quantity.Sign() <= 0 {
+				return nil, fmt.Errorf("failed to parse CHECK_PVC_SIZE: must be greater than zero")
+			}
+			cfg.PVCSize = quantity
+			log.Infoln("Parsed CHECK_PVC_SIZE:", cfg.PVCSize.String())
+		}
+
+		pvcAccessModeEnv := os.Getenv("CHECK_PVC_ACCESS_MODE")
+		if len(pvcAccessModeEnv) != 0 {
+			accessMode := corev1.PersistentVolumeAccessMode(strings.TrimSpace(pvcAccessModeEnv))
+			if accessMode != corev1.ReadWriteOnce && accessMode != corev1.ReadWriteMany && accessMode != corev1.ReadWriteOncePod {
+				return nil, fmt.Errorf("failed to parse CHECK_PVC_ACCESS_MODE: %q must be %s, %s, or %s", pvcAccessModeEnv, corev1.ReadWriteOnce, corev1.ReadWriteMany, corev1.ReadWriteOncePod)
+			}
+			cfg.PVCAccessMode = accessMode
+			log.Infoln("Parsed CHECK_PVC_ACCESS_MODE:", cfg.PVCAccessMode)
+		}
+
+		cfg.PVCMountPath = defaultPVCMountPath
+		pvcMountPathEnv := os.Getenv("CHECK_PVC_MOUNT_PATH")
+		if len(pvcMountPathEnv) != 0 {
+			cfg.PVCMountPath = pvcMountPathEnv
+		}
+		err := validateMountPath("CHECK_PVC_MOUNT_PATH", cfg.PVCMountPath, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		log.Infoln("Parsed CHECK_PVC_MOUNT_PATH:", cfg.PVCMountPath)
+	}
+
+	// Parse the projected service account token to mount and verify.
+	cfg.ProjectedTokenAudience = strings.TrimSpace(os.Getenv("CHECK_PROJECTED_TOKEN_AUDIENCE"))
+	cfg.ProjectedTokenExpirationSeconds = defaultProjectedTokenExpirationSeconds
+	if len(cfg.ProjectedTokenAudience) != 0 {
+		log.Infoln("Parsed CHECK_PROJECTED_TOKEN_AUDIENCE:", cfg.ProjectedTokenAudience)
+
+		tokenExpirationEnv := os.Getenv("CHECK_PROJECTED_TOKEN_EXPIRATION")
+		if len(tokenExpirationEnv) != 0 {
+			duration, err := time.ParseDuration(tokenExpirationEnv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_PROJECTED_TOKEN_EXPIRATION: %w", err)
+			}
+			if duration < minProjectedTokenExpirationSeconds*time.Second {
+				return nil, fmt.Errorf("failed to parse CHECK_PROJECTED_TOKEN_EXPIRATION: must be at least %s", minProjectedTokenExpirationSeconds*time.Second)
+			}
+			cfg.ProjectedTokenExpirationSeconds = int64(duration / time.Second)
+			log.Infoln("Parsed CHECK_PROJECTED_TOKEN_EXPIRATION:", duration)
+		}
+
+		cfg.ProjectedTokenMountPath = defaultProjectedTokenMountPath
+		tokenMountPathEnv := os.Getenv("CHECK_PROJECTED_TOKEN_MOUNT_PATH")
+		if len(tokenMountPathEnv) != 0 {
+			cfg.ProjectedTokenMountPath = tokenMountPathEnv
+		}
+		err := validateMountPath("CHECK_PROJECTED_TOKEN_MOUNT_PATH", cfg.ProjectedTokenMountPath, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		log.Infoln("Parsed CHECK_PROJECTED_TOKEN_MOUNT_PATH:", cfg.ProjectedTokenMountPath)
+	}
+
+	// Parse the cert-manager issuer to request, mount, and serve a certificate from.
+	cfg.CertManagerIssuer = strings.TrimSpace(os.Getenv("CHECK_CERT_MANAGER_ISSUER"))
+	if len(cfg.CertManagerIssuer) != 0 {
+		log.Infoln("Parsed CHECK_CERT_MANAGER_ISSUER:", cfg.CertManagerIssuer)
+
+		cfg.CertManagerIssuerKind = certManagerIssuerKindIssuer
+		issuerKindEnv := os.Getenv("CHECK_CERT_MANAGER_ISSUER_KIND")
+		if len(issuerKindEnv) != 0 {
+			if !validCertManagerIssuerKind(issuerKindEnv) {
+				return nil, fmt.Errorf("failed to parse CHECK_CERT_MANAGER_ISSUER_KIND: %q must be %s or %s", issuerKindEnv, certManagerIssuerKindIssuer, certManagerIssuerKindClusterIssuer)
+			}
+			cfg.CertManagerIssuerKind = issuerKindEnv
+			log.Infoln("Parsed CHECK_CERT_MANAGER_ISSUER_KIND:", cfg.CertManagerIssuerKind)
+		}
+
+		cfg.CertManagerMountPath = defaultCertManagerMountPath
+		certMountPathEnv := os.Getenv("CHECK_CERT_MANAGER_MOUNT_PATH")
+		if len(certMountPathEnv) != 0 {
+			cfg.CertManagerMountPath = certMountPathEnv
+		}
+		err := validateMountPath("CHECK_CERT_MANAGER_MOUNT_PATH", cfg.CertManagerMountPath, mountPaths)
+		if err != nil {
+			return nil, err
+		}
+		log.Infoln("Parsed CHECK_CERT_MANAGER_MOUNT_PATH:", cfg.CertManagerMountPath)
+
+		// Require the HTTPS port explicitly, since the primary port serves plain HTTP.
+		certPortEnv := os.Getenv("CHECK_CERT_MANAGER_PORT")
+		if len(certPortEnv) == 0 {
+			return nil, fmt.Errorf("CHECK_CERT_MANAGER_PORT is required with CHECK_CERT_MANAGER_ISSUER so the issued certificate is requested on a port serving TLS")
+		}
+		portValue, err := strconv.ParseInt(certPortEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_CERT_MANAGER_PORT: %w", err)
+		}
+		served := false
+		for _, port := range cfg.checkPorts() {
+			if port.ServicePort == int32(portValue) {
+				served = true
+			}
+		}
+		if !served {
+			return nil, fmt.Errorf("failed to parse CHECK_CERT_MANAGER_PORT: %d is not a check service port", portValue)
+		}
+		cfg.CertManagerPort = int32(portValue)
+		log.Infoln("Parsed CHECK_CERT_MANAGER_PORT:", cfg.CertManagerPort)
+
+		cfg.CertManagerTimeout = defaultCertManagerTimeout
+		certTimeoutEnv := os.Getenv("CHECK_CERT_MANAGER_TIMEOUT")
+		if len(certTimeoutEnv) != 0 {
+			durationValue, err := time.ParseDuration(certTimeoutEnv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_CERT_MANAGER_TIMEOUT: %w", err)
+			}
+			if durationValue <= 0 {
+				return nil, fmt.Errorf("failed to parse CHECK_CERT_MANAGER_TIMEOUT: must be positive")
+			}
+			cfg.CertManagerTimeout = durationValue
+			log.Infoln("Parsed CHECK_CERT_MANAGER_TIMEOUT:", cfg.CertManagerTimeout)
+		}
+	}
+
+	volumeVerifyEnv := os.Getenv("CHECK_VOLUME_VERIFY")
+	if len(volumeVerifyEnv) != 0 {
+		volumeVerifyValue, err := strconv.ParseBool(volumeVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_VOLUME_VERIFY: %w", err)
+		}
+		if volumeVerifyValue && !cfg.volumesConfigured() {
+			return nil, fmt.Errorf("failed to parse CHECK_VOLUME_VERIFY: no volumes are configured")
+		}
+		cfg.VolumeVerify = volumeVerifyValue
+		log.Infoln("Parsed CHECK_VOLUME_VERIFY:", cfg.VolumeVerify)
+	}
+
+	// Parse the Pod Security Standards profile and reject settings it forbids.
+	pssProfileEnv := os.Getenv("CHECK_PSS_PROFILE")
+	if len(pssProfileEnv) != 0 {
+		profile := strings.ToLower(strings.TrimSpace(pssProfileEnv))
+		if profile != pssProfileRestricted {
+			return nil, fmt.Errorf("failed to parse CHECK_PSS_PROFILE: %q must be %s", pssProfileEnv, pssProfileRestricted)
+		}
+		cfg.PSSProfile = profile
+		log.Infoln("Parsed CHECK_PSS_PROFILE:", cfg.PSSProfile)
+	}
+	if cfg.PSSProfile == pssProfileRestricted {
+		if cfg.PodRunAsNonRoot != nil && !*cfg.PodRunAsNonRoot {
+			return nil, fmt.Errorf("CHECK_PSS_PROFILE=restricted conflicts with CHECK_POD_RUN_AS_NON_ROOT=false")
+		}
+		if cfg.PodRunAsUser != nil && *cfg.PodRunAsUser == 0 {
+			return nil, fmt.Errorf("CHECK_PSS_PROFILE=restricted conflicts with CHECK_POD_RUN_AS_USER=0")
+		}
+		if cfg.PodSeccompProfile != nil && cfg.PodSeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			return nil, fmt.Errorf("CHECK_PSS_PROFILE=restricted conflicts with CHECK_POD_SECCOMP_PROFILE=Unconfined")
+		}
+		if cfg.ContainerAllowPrivilegeEscalation != nil && *cfg.ContainerAllowPrivilegeEscalation {
+			return nil, fmt.Errorf("CHECK_PSS_PROFILE=restricted conflicts with CHECK_CONTAINER_ALLOW_PRIVILEGE_ESCALATION=true")
+		}
+	}
+
+	// Parse direct endpoint diagnostics setting.
+	endpointDiagnosticsEnv := os.Getenv("CHECK_ENDPOINT_DIAGNOSTICS")
+	if len(endpointDiagnosticsEnv) != 0 {
+		diagnosticsValue, err := strconv.ParseBool(endpointDiagnosticsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_ENDPOINT_DIAGNOSTICS: %w", err)
+		}
+		cfg.EndpointDiagnostics = diagnosticsValue
+		log.Infoln("Parsed CHECK_ENDPOINT_DIAGNOSTICS:", cfg.EndpointDiagnostics)
+	}
+	podIPVerifyEnv := os.Getenv("CHECK_POD_IP_VERIFY")
+	if len(podIPVerifyEnv) != 0 {
+		podIPVerifyValue, err := strconv.ParseBool(podIPVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_IP_VERIFY: %w", err)
+		}
+		cfg.PodIPVerify = podIPVerifyValue
+		log.Infoln("Parsed CHECK_POD_IP_VERIFY:", cfg.PodIPVerify)
+	}
+
+	// Parse the auxiliary prober pod settings.
+	proberVerifyEnv := os.Getenv("CHECK_PROBER_VERIFY")
+	if len(proberVerifyEnv) != 0 {
+		proberVerifyValue, err := strconv.ParseBool(proberVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBER_VERIFY: %w", err)
+		}
+		cfg.ProberVerify = proberVerifyValue
+		log.Infoln("Parsed CHECK_PROBER_VERIFY:", cfg.ProberVerify)
+	}
+	cfg.ProberImage = defaultProberImage
+	proberImageEnv := strings.TrimSpace(os.Getenv("CHECK_PROBER_IMAGE"))
+	if len(proberImageEnv) != 0 {
+		cfg.ProberImage = proberImageEnv
+		log.Infoln("Parsed CHECK_PROBER_IMAGE:", cfg.ProberImage)
+	}
+	proberHostNetworkEnv := os.Getenv("CHECK_PROBER_HOST_NETWORK")
+	if len(proberHostNetworkEnv) != 0 {
+		hostNetworkValue, err := strconv.ParseBool(proberHostNetworkEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBER_HOST_NETWORK: %w", err)
+		}
+		if hostNetworkValue && (len(cfg.PSSProfile) != 0 || cfg.AutopilotMode) {
+			return nil, fmt.Errorf("failed to parse CHECK_PROBER_HOST_NETWORK: host networking is forbidden by CHECK_PSS_PROFILE and CHECK_AUTOPILOT_MODE")
+		}
+		cfg.ProberHostNetwork = hostNetworkValue
+		log.Infoln("Parsed CHECK_PROBER_HOST_NETWORK:", cfg.ProberHostNetwork)
+	}
+
+	// Parse in-pod DNS verification settings.
+	podDNSVerifyEnv := os.Getenv("CHECK_POD_DNS_VERIFY")
+	if len(podDNSVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(podDNSVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_VERIFY: %w", err)
+		}
+		cfg.PodDNSVerify = verifyValue
+		log.Infoln("Parsed CHECK_POD_DNS_VERIFY:", cfg.PodDNSVerify)
+	}
+	cfg.PodDNSName = defaultPodDNSName
+	podDNSNameEnv := os.Getenv("CHECK_POD_DNS_NAME")
+	if len(podDNSNameEnv) != 0 {
+		cfg.PodDNSName = podDNSNameEnv
+		log.Infoln("Parsed CHECK_POD_DNS_NAME:", cfg.PodDNSName)
+	}
+
+	// Parse the pod DNS policy and resolver configuration.
+	podDNSPolicyEnv := os.Getenv("CHECK_POD_DNS_POLICY")
+	if len(podDNSPolicyEnv) != 0 {
+		policy := corev1.DNSPolicy(podDNSPolicyEnv)
+		if policy != corev1.DNSClusterFirst && policy != corev1.DNSClusterFirstWithHostNet && policy != corev1.DNSDefault && policy != corev1.DNSNone {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_POLICY: %q must be %s, %s, %s, or %s", podDNSPolicyEnv, corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault, corev1.DNSNone)
+		}
+		cfg.PodDNSPolicy = policy
+		log.Infoln("Parsed CHECK_POD_DNS_POLICY:", cfg.PodDNSPolicy)
+	}
+	podDNSConfig := &corev1.PodDNSConfig{}
+	podDNSNameserversEnv := os.Getenv("CHECK_POD_DNS_NAMESERVERS")
+	if len(podDNSNameserversEnv) != 0 {
+		for _, nameserver := range strings.Split(podDNSNameserversEnv, ",") {
+			nameserver = strings.TrimSpace(nameserver)
+			if net.ParseIP(nameserver) == nil {
+				return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_NAMESERVERS: %q is not an IP address", nameserver)
+			}
+			podDNSConfig.Nameservers = append(podDNSConfig.Nameservers, nameserver)
+		}
+		if len(podDNSConfig.Nameservers) > 3 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_NAMESERVERS: at most 3 nameservers are allowed, got %d", len(podDNSConfig.Nameservers))
+		}
+		log.Infoln("Parsed CHECK_POD_DNS_NAMESERVERS:", podDNSConfig.Nameservers)
+	}
+	podDNSSearchesEnv := os.Getenv("CHECK_POD_DNS_SEARCHES")
+	if len(podDNSSearchesEnv) != 0 {
+		for _, search := range strings.Split(podDNSSearchesEnv, ",") {
+			search = strings.TrimSpace(search)
+			problems := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, "."))
+			if len(problems) != 0 {
+				return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_SEARCHES: invalid search domain %q: %s", search, strings.Join(problems, "; "))
+			}
+			podDNSConfig.Searches = append(podDNSConfig.Searches, search)
+		}
+		if len(podDNSConfig.Searches) > 32 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_SEARCHES: at most 32 search domains are allowed, got %d", len(podDNSConfig.Searches))
+		}
+		log.Infoln("Parsed CHECK_POD_DNS_SEARCHES:", podDNSConfig.Searches)
+	}
+	podDNSNdotsEnv := os.Getenv("CHECK_POD_DNS_NDOTS")
+	if len(podDNSNdotsEnv) != 0 {
+		ndotsValue, err := strconv.Atoi(podDNSNdotsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_NDOTS: %w", err)
+		}
+		if ndotsValue < 0 || ndotsValue > 15 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_NDOTS: %d must be between 0 and 15", ndotsValue)
+		}
+		ndots := strconv.Itoa(ndotsValue)
+		podDNSConfig.Options = append(podDNSConfig.Options, corev1.PodDNSConfigOption{Name: "ndots", Value: &ndots})
+		log.Infoln("Parsed CHECK_POD_DNS_NDOTS:", ndots)
+	}
+	if len(podDNSConfig.Nameservers) != 0 || len(podDNSConfig.Searches) != 0 || len(podDNSConfig.Options) != 0 {
+		cfg.PodDNSConfig = podDNSConfig
+	}
+	if cfg.PodDNSPolicy == corev1.DNSNone && (cfg.PodDNSConfig == nil || len(cfg.PodDNSConfig.Nameservers) == 0) {
+		return nil, fmt.Errorf("failed to parse CHECK_POD_DNS_POLICY: %s requires CHECK_POD_DNS_NAMESERVERS", corev1.DNSNone)
+	}
+
+	// Parse the stuck terminating pod threshold.
+	cfg.PodForceDeleteAfter = defaultPodForceDeleteAfter
+	podForceDeleteAfterEnv := os.Getenv("CHECK_POD_FORCE_DELETE_AFTER")
+	if len(podForceDeleteAfterEnv) != 0 {
+		durationValue, err := time.ParseDuration(podForceDeleteAfterEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_FORCE_DELETE_AFTER: %w", err)
+		}
+		cfg.PodForceDeleteAfter = durationValue
+		log.Infoln("Parsed CHECK_POD_FORCE_DELETE_AFTER:", cfg.PodForceDeleteAfter)
+	}
+
+	// Parse the pod termination grace period and deployment minReadySeconds.
+	cfg.PodTerminationGracePeriodSeconds = defaultPodTerminationGracePeriodSeconds
+	gracePeriodEnv := os.Getenv("CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS")
+	if len(gracePeriodEnv) != 0 {
+		graceValue, err := strconv.ParseInt(gracePeriodEnv, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS: %w", err)
+		}
+		if graceValue < 0 {
+			return nil, fmt.Errorf("CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS must be >= 0, got %d", graceValue)
+		}
+		if cfg.PodForceDeleteAfter > 0 && cfg.PodForceDeleteAfter <= time.Duration(graceValue)*time.Second {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS: %ds must be shorter than CHECK_POD_FORCE_DELETE_AFTER (%s) so pods are not force deleted within their grace period", graceValue, cfg.PodForceDeleteAfter)
+		}
+		cfg.PodTerminationGracePeriodSeconds = graceValue
+		log.Infoln("Parsed CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS:", cfg.PodTerminationGracePeriodSeconds)
+	}
+	cfg.MinReadySeconds = defaultMinReadySeconds
+	minReadySecondsEnv := os.Getenv("CHECK_MIN_READY_SECONDS")
+	if len(minReadySecondsEnv) != 0 {
+		minReadyValue, err := strconv.ParseInt(minReadySecondsEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_MIN_READY_SECONDS: %w", err)
+		}
+		if minReadyValue < 0 {
+			return nil, fmt.Errorf("CHECK_MIN_READY_SECONDS must be >= 0, got %d", minReadyValue)
+		}
+		cfg.MinReadySeconds = int32(minReadyValue)
+		log.Infoln("Parsed CHECK_MIN_READY_SECONDS:", cfg.MinReadySeconds)
+	}
+	progressDeadlineEnv := os.Getenv("CHECK_PROGRESS_DEADLINE_SECONDS")
+	if len(progressDeadlineEnv) != 0 {
+		deadlineValue, err := strconv.ParseInt(progressDeadlineEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PROGRESS_DEADLINE_SECONDS: %w", err)
+		}
+		if deadlineValue <= int64(cfg.MinReadySeconds) {
+			return nil, fmt.Errorf("failed to parse CHECK_PROGRESS_DEADLINE_SECONDS: %d must be greater than CHECK_MIN_READY_SECONDS (%d)", deadlineValue, cfg.MinReadySeconds)
+		}
+		cfg.ProgressDeadlineSeconds = int32(deadlineValue)
+		log.Infoln("Parsed CHECK_PROGRESS_DEADLINE_SECONDS:", cfg.ProgressDeadlineSeconds)
+	}
+
+	// Parse the deployment delete propagation policy and how long the delete may wait.
+	cfg.DeletePropagationPolicy = metav1.DeletePropagationBackground
+	deletePropagationEnv := os.Getenv("CHECK_DELETE_PROPAGATION_POLICY")
+	if len(deletePropagationEnv) != 0 {
+		policy := metav1.DeletionPropagation(deletePropagationEnv)
+		if policy != metav1.DeletePropagationBackground && policy != metav1.DeletePropagationForeground && policy != metav1.DeletePropagationOrphan {
+			return nil, fmt.Errorf("failed to parse CHECK_DELETE_PROPAGATION_POLICY: %q must be %s, %s, or %s", deletePropagationEnv, metav1.DeletePropagationBackground, metav1.DeletePropagationForeground, metav1.DeletePropagationOrphan)
+		}
+		cfg.DeletePropagationPolicy = policy
+		log.Infoln("Parsed CHECK_DELETE_PROPAGATION_POLICY:", cfg.DeletePropagationPolicy)
+	}
+	cfg.DeleteTimeout = defaultDeleteTimeout
+	deleteTimeoutEnv := os.Getenv("CHECK_DELETE_TIMEOUT")
+	if len(deleteTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(deleteTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_DELETE_TIMEOUT: %w", err)
+		}
+		if durationValue < 0 {
+			return nil, fmt.Errorf("CHECK_DELETE_TIMEOUT must be >= 0, got %s", durationValue)
+		}
+		cfg.DeleteTimeout = durationValue
+		log.Infoln("Parsed CHECK_DELETE_TIMEOUT:", cfg.DeleteTimeout)
+	}
+
+	// Parse the endpoint request retry caps.
+	cfg.RequestRetryTimeout = defaultRequestRetryTimeout
+	requestRetryTimeoutEnv := os.Getenv("CHECK_REQUEST_RETRY_TIMEOUT")
+	if len(requestRetryTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(requestRetryTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_REQUEST_RETRY_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_REQUEST_RETRY_TIMEOUT: must be greater than zero")
+		}
+		cfg.RequestRetryTimeout = durationValue
+		log.Infoln("Parsed CHECK_REQUEST_RETRY_TIMEOUT:", cfg.RequestRetryTimeout)
+	}
+	cfg.RequestMaxAttempts = defaultRequestMaxAttempts
+	requestMaxAttemptsEnv := os.Getenv("CHECK_REQUEST_MAX_ATTEMPTS")
+	if len(requestMaxAttemptsEnv) != 0 {
+		attempts, err := strconv.Atoi(requestMaxAttemptsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_REQUEST_MAX_ATTEMPTS: %w", err)
+		}
+		if attempts < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_REQUEST_MAX_ATTEMPTS: must be at least 1")
+		}
+		cfg.RequestMaxAttempts = attempts
+		log.Infoln("Parsed CHECK_REQUEST_MAX_ATTEMPTS:", cfg.RequestMaxAttempts)
+	}
+
+	// Parse the retry backoff shared by endpoint requests and API retries.
+	cfg.RetryBackoffInitial = defaultRetryBackoffInitial
+	retryBackoffInitialEnv := os.Getenv("CHECK_RETRY_BACKOFF_INITIAL")
+	if len(retryBackoffInitialEnv) != 0 {
+		durationValue, err := time.ParseDuration(retryBackoffInitialEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_INITIAL: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_INITIAL: must be greater than zero")
+		}
+		cfg.RetryBackoffInitial = durationValue
+		log.Infoln("Parsed CHECK_RETRY_BACKOFF_INITIAL:", cfg.RetryBackoffInitial)
+	}
+	cfg.RetryBackoffMax = defaultRetryBackoffMax
+	retryBackoffMaxEnv := os.Getenv("CHECK_RETRY_BACKOFF_MAX")
+	if len(retryBackoffMaxEnv) != 0 {
+		durationValue, err := time.ParseDuration(retryBackoffMaxEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_MAX: %w", err)
+		}
+		cfg.RetryBackoffMax = durationValue
+		log.Infoln("Parsed CHECK_RETRY_BACKOFF_MAX:", cfg.RetryBackoffMax)
+	}
+	if cfg.RetryBackoffMax < cfg.RetryBackoffInitial {
+		return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_MAX: %s is below the initial backoff of %s", cfg.RetryBackoffMax, cfg.RetryBackoffInitial)
+	}
+	cfg.RetryBackoffFactor = defaultRetryBackoffFactor
+	retryBackoffFactorEnv := os.Getenv("CHECK_RETRY_BACKOFF_FACTOR")
+	if len(retryBackoffFactorEnv) != 0 {
+		factor, err := strconv.ParseFloat(retryBackoffFactorEnv, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_FACTOR: %w", err)
+		}
+		if factor < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_FACTOR: must be at least 1")
+		}
+		cfg.RetryBackoffFactor = factor
+		log.Infoln("Parsed CHECK_RETRY_BACKOFF_FACTOR:", cfg.RetryBackoffFactor)
+	}
+	cfg.RetryBackoffJitter = defaultRetryBackoffJitter
+	retryBackoffJitterEnv := os.Getenv("CHECK_RETRY_BACKOFF_JITTER")
+	if len(retryBackoffJitterEnv) != 0 {
+		jitter, err := strconv.ParseFloat(retryBackoffJitterEnv, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_JITTER: %w", err)
+		}
+		if jitter < 0 || jitter > 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_RETRY_BACKOFF_JITTER: must be between 0 and 1")
+		}
+		cfg.RetryBackoffJitter = jitter
+		log.Infoln("Parsed CHECK_RETRY_BACKOFF_JITTER:", cfg.RetryBackoffJitter)
+	}
+
+	// Parse the watch timeout.
+	cfg.WatchTimeout = defaultWatchTimeout
+	watchTimeoutEnv := os.Getenv("CHECK_WATCH_TIMEOUT")
+	if len(watchTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(watchTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_WATCH_TIMEOUT: %w", err)
+		}
+		if durationValue < time.Second {
+			return nil, fmt.Errorf("failed to parse CHECK_WATCH_TIMEOUT: must be at least 1s")
+		}
+		cfg.WatchTimeout = durationValue
+		log.Infoln("Parsed CHECK_WATCH_TIMEOUT:", cfg.WatchTimeout)
+	}
+
+	// Parse explicit cluster targeting.
+	cfg.KubeContext = os.Getenv("KUBE_CONTEXT")
+	if len(cfg.KubeContext) != 0 {
+		log.Infoln("Parsed KUBE_CONTEXT:", cfg.KubeContext)
+	}
+	cfg.KubeAPIServer = os.Getenv("KUBE_API_SERVER")
+	if len(cfg.KubeAPIServer) != 0 {
+		serverURL, err := url.Parse(cfg.KubeAPIServer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KUBE_API_SERVER: %w", err)
+		}
+		if serverURL.Scheme != "https" || len(serverURL.Host) == 0 {
+			return nil, fmt.Errorf("failed to parse KUBE_API_SERVER: %s is not an https URL", cfg.KubeAPIServer)
+		}
+		if len(cfg.KubeContext) != 0 {
+			return nil, fmt.Errorf("failed to parse KUBE_API_SERVER: cannot be combined with KUBE_CONTEXT")
+		}
+		log.Infoln("Parsed KUBE_API_SERVER:", cfg.KubeAPIServer)
+	}
+	cfg.KubeTokenFile = os.Getenv("KUBE_TOKEN_FILE")
+	cfg.KubeCAFile = os.Getenv("KUBE_CA_FILE")
+	if len(cfg.KubeAPIServer) == 0 && (len(cfg.KubeTokenFile) != 0 || len(cfg.KubeCAFile) != 0) {
+		return nil, fmt.Errorf("failed to parse KUBE_TOKEN_FILE and KUBE_CA_FILE: they require KUBE_API_SERVER")
+	}
+	if len(cfg.KubeAPIServer) != 0 && len(cfg.KubeTokenFile) == 0 {
+		return nil, fmt.Errorf("failed to parse KUBE_TOKEN_FILE: required with KUBE_API_SERVER")
+	}
+	if len(cfg.KubeTokenFile) != 0 {
+		log.Infoln("Parsed KUBE_TOKEN_FILE:", cfg.KubeTokenFile)
+	}
+	if len(cfg.KubeCAFile) != 0 {
+		log.Infoln("Parsed KUBE_CA_FILE:", cfg.KubeCAFile)
+	}
+
+	// The checker pod lives in this cluster, so it cannot own resources created in another one.
+	if cfg.OwnerReference && cfg.targetsRemoteCluster() {
+		return nil, fmt.Errorf("failed to parse CHECK_OWNER_REFERENCE: the checker pod cannot own resources in a cluster targeted by KUBE_CONTEXT or KUBE_API_SERVER")
+	}
+
+	// Parse the identity to impersonate.
+	cfg.ImpersonateUser = os.Getenv("CHECK_IMPERSONATE_USER")
+	if len(cfg.ImpersonateUser) != 0 {
+		log.Infoln("Parsed CHECK_IMPERSONATE_USER:", cfg.ImpersonateUser)
+	}
+	impersonateGroupsEnv := os.Getenv("CHECK_IMPERSONATE_GROUPS")
+	if len(impersonateGroupsEnv) != 0 {
+		if len(cfg.ImpersonateUser) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_IMPERSONATE_GROUPS: requires CHECK_IMPERSONATE_USER")
+		}
+		for _, group := range strings.Split(impersonateGroupsEnv, ",") {
+			group = strings.TrimSpace(group)
+			if len(group) == 0 {
+				return nil, fmt.Errorf("failed to parse CHECK_IMPERSONATE_GROUPS: empty group in %q", impersonateGroupsEnv)
+			}
+			cfg.ImpersonateGroups = append(cfg.ImpersonateGroups, group)
+		}
+		log.Infoln("Parsed CHECK_IMPERSONATE_GROUPS:", strings.Join(cfg.ImpersonateGroups, ","))
+	}
+
+	// Parse the Kubernetes client rate limits and request timeout.
+	kubeClientQPSEnv := os.Getenv("KUBE_CLIENT_QPS")
+	if len(kubeClientQPSEnv) != 0 {
+		qps, err := strconv.ParseFloat(kubeClientQPSEnv, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_QPS: %w", err)
+		}
+		if qps <= 0 {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_QPS: must be greater than zero")
+		}
+		cfg.KubeClientQPS = float32(qps)
+		log.Infoln("Parsed KUBE_CLIENT_QPS:", cfg.KubeClientQPS)
+	}
+	kubeClientBurstEnv := os.Getenv("KUBE_CLIENT_BURST")
+	if len(kubeClientBurstEnv) != 0 {
+		burst, err := strconv.Atoi(kubeClientBurstEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_BURST: %w", err)
+		}
+		if burst < 1 {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_BURST: must be at least 1")
+		}
+		cfg.KubeClientBurst = burst
+		log.Infoln("Parsed KUBE_CLIENT_BURST:", cfg.KubeClientBurst)
+	}
+	kubeClientTimeoutEnv := os.Getenv("KUBE_CLIENT_TIMEOUT")
+	if len(kubeClientTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(kubeClientTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_TIMEOUT: %w", err)
+		}
+		// The timeout also applies to watches, so it must outlast the server-side watch timeout.
+		if durationValue <= cfg.WatchTimeout {
+			return nil, fmt.Errorf("failed to parse KUBE_CLIENT_TIMEOUT: %s must exceed CHECK_WATCH_TIMEOUT of %s", durationValue, cfg.WatchTimeout)
+		}
+		cfg.KubeClientTimeout = durationValue
+		log.Infoln("Parsed KUBE_CLIENT_TIMEOUT:", cfg.KubeClientTimeout)
+	}
+
+	// Parse ingress verification settings.
+	ingressVerifyEnv := os.Getenv("CHECK_INGRESS_VERIFY")
+	if len(ingressVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(ingressVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_INGRESS_VERIFY: %w", err)
+		}
+		cfg.IngressVerify = verifyValue
+		log.Infoln("Parsed CHECK_INGRESS_VERIFY:", cfg.IngressVerify)
+	}
+	ingressClassNameEnv := os.Getenv("CHECK_INGRESS_CLASS_NAME")
+	if len(ingressClassNameEnv) != 0 {
+		cfg.IngressClassName = ingressClassNameEnv
+		log.Infoln("Parsed CHECK_INGRESS_CLASS_NAME:", cfg.IngressClassName)
+	}
+	ingressHostEnv := os.Getenv("CHECK_INGRESS_HOST")
+	if len(ingressHostEnv) != 0 {
+		cfg.IngressHost = ingressHostEnv
+		log.Infoln("Parsed CHECK_INGRESS_HOST:", cfg.IngressHost)
+	}
+	cfg.IngressPath = defaultIngressPath
+	ingressPathEnv := os.Getenv("CHECK_INGRESS_PATH")
+	if len(ingressPathEnv) != 0 {
+		cfg.IngressPath = ingressPathEnv
+		log.Infoln("Parsed CHECK_INGRESS_PATH:", cfg.IngressPath)
+	}
+	ingressAddressEnv := os.Getenv("CHECK_INGRESS_ADDRESS")
+	if len(ingressAddressEnv) != 0 {
+		cfg.IngressAddress = ingressAddressEnv
+		log.Infoln("Parsed CHECK_INGRESS_ADDRESS:", cfg.IngressAddress)
+	}
+	cfg.IngressTimeout = defaultIngressTimeout
+	ingressTimeoutEnv := os.Getenv("CHECK_INGRESS_TIMEOUT")
+	if len(ingressTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(ingressTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_INGRESS_TIMEOUT: %w", err)
+		}
+		cfg.IngressTimeout = durationValue
+		log.Infoln("Parsed CHECK_INGRESS_TIMEOUT:", cfg.IngressTimeout)
+	}
+
+	// Parse Gateway API HTTPRoute settings.
+	gatewayNameEnv := os.Getenv("CHECK_GATEWAY_NAME")
+	if len(gatewayNameEnv) != 0 {
+		cfg.GatewayName = gatewayNameEnv
+		log.Infoln("Parsed CHECK_GATEWAY_NAME:", cfg.GatewayName)
+	}
+	gatewayNamespaceEnv := os.Getenv("CHECK_GATEWAY_NAMESPACE")
+	if len(gatewayNamespaceEnv) != 0 {
+		cfg.GatewayNamespace = gatewayNamespaceEnv
+		log.Infoln("Parsed CHECK_GATEWAY_NAMESPACE:", cfg.GatewayNamespace)
+	}
+	if len(cfg.GatewayName) != 0 && len(cfg.GatewayNamespace) == 0 {
+		return nil, fmt.Errorf("CHECK_GATEWAY_NAMESPACE is required with CHECK_GATEWAY_NAME, since shared Gateways usually live outside the check namespace")
+	}
+	gatewaySectionNameEnv := os.Getenv("CHECK_GATEWAY_SECTION_NAME")
+	if len(gatewaySectionNameEnv) != 0 {
+		cfg.GatewaySectionName = gatewaySectionNameEnv
+		log.Infoln("Parsed CHECK_GATEWAY_SECTION_NAME:", cfg.GatewaySectionName)
+	}
+	gatewayHostEnv := os.Getenv("CHECK_GATEWAY_HOST")
+	if len(gatewayHostEnv) != 0 {
+		cfg.GatewayHost = gatewayHostEnv
+		log.Infoln("Parsed CHECK_GATEWAY_HOST:", cfg.GatewayHost)
+	}
+	cfg.GatewayPath = defaultGatewayPath
+	gatewayPathEnv := os.Getenv("CHECK_GATEWAY_PATH")
+	if len(gatewayPathEnv) != 0 {
+		cfg.GatewayPath = gatewayPathEnv
+		log.Infoln("Parsed CHECK_GATEWAY_PATH:", cfg.GatewayPath)
+	}
+	gatewayAddressEnv := os.Getenv("CHECK_GATEWAY_ADDRESS")
+	if len(gatewayAddressEnv) != 0 {
+		cfg.GatewayAddress = gatewayAddressEnv
+		log.Infoln("Parsed CHECK_GATEWAY_ADDRESS:", cfg.GatewayAddress)
+	}
+	cfg.GatewayTimeout = defaultGatewayTimeout
+	gatewayTimeoutEnv := os.Getenv("CHECK_GATEWAY_TIMEOUT")
+	if len(gatewayTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(gatewayTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_GATEWAY_TIMEOUT: %w", err)
+		}
+		cfg.GatewayTimeout = durationValue
+		log.Infoln("Parsed CHECK_GATEWAY_TIMEOUT:", cfg.GatewayTimeout)
+	}
+
+	// Parse OpenShift Route settings.
+	openShiftRouteVerifyEnv := os.Getenv("CHECK_OPENSHIFT_ROUTE_VERIFY")
+	if len(openShiftRouteVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(openShiftRouteVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_OPENSHIFT_ROUTE_VERIFY: %w", err)
+		}
+		cfg.OpenShiftRouteVerify = verifyValue
+		log.Infoln("Parsed CHECK_OPENSHIFT_ROUTE_VERIFY:", cfg.OpenShiftRouteVerify)
+	}
+	openShiftRouteHostEnv := os.Getenv("CHECK_OPENSHIFT_ROUTE_HOST")
+	if len(openShiftRouteHostEnv) != 0 {
+		cfg.OpenShiftRouteHost = openShiftRouteHostEnv
+		log.Infoln("Parsed CHECK_OPENSHIFT_ROUTE_HOST:", cfg.OpenShiftRouteHost)
+	}
+	openShiftRouteAddressEnv := os.Getenv("CHECK_OPENSHIFT_ROUTE_ADDRESS")
+	if len(openShiftRouteAddressEnv) != 0 {
+		cfg.OpenShiftRouteAddress = openShiftRouteAddressEnv
+		log.Infoln("Parsed CHECK_OPENSHIFT_ROUTE_ADDRESS:", cfg.OpenShiftRouteAddress)
+	}
+	cfg.OpenShiftRouteTimeout = defaultOpenShiftRouteTimeout
+	openShiftRouteTimeoutEnv := os.Getenv("CHECK_OPENSHIFT_ROUTE_TIMEOUT")
+	if len(openShiftRouteTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(openShiftRouteTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_OPENSHIFT_ROUTE_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_OPENSHIFT_ROUTE_TIMEOUT: must be positive")
+		}
+		cfg.OpenShiftRouteTimeout = durationValue
+		log.Infoln("Parsed CHECK_OPENSHIFT_ROUTE_TIMEOUT:", cfg.OpenShiftRouteTimeout)
+	}
+	if !cfg.OpenShiftRouteVerify && (len(cfg.OpenShiftRouteHost) != 0 || len(cfg.OpenShiftRouteAddress) != 0) {
+		return nil, fmt.Errorf("CHECK_OPENSHIFT_ROUTE_HOST and CHECK_OPENSHIFT_ROUTE_ADDRESS require CHECK_OPENSHIFT_ROUTE_VERIFY")
+	}
+
+	// Parse the settings that identify and cover every backend replica.
+	backendHeaderEnv := strings.TrimSpace(os.Getenv("CHECK_BACKEND_HEADER"))
+	if len(backendHeaderEnv) != 0 {
+		cfg.BackendHeader = http.CanonicalHeaderKey(backendHeaderEnv)
+		log.Infoln("Parsed CHECK_BACKEND_HEADER:", cfg.BackendHeader)
+	}
+	everyReplicaVerifyEnv := os.Getenv("CHECK_EVERY_REPLICA_VERIFY")
+	if len(everyReplicaVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(everyReplicaVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_EVERY_REPLICA_VERIFY: %w", err)
+		}
+		cfg.EveryReplicaVerify = verifyValue
+		log.Infoln("Parsed CHECK_EVERY_REPLICA_VERIFY:", cfg.EveryReplicaVerify)
+	}
+	sessionAffinityVerifyEnv := os.Getenv("CHECK_SESSION_AFFINITY_VERIFY")
+	if len(sessionAffinityVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(sessionAffinityVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SESSION_AFFINITY_VERIFY: %w", err)
+		}
+		if verifyValue && len(cfg.BackendHeader) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SESSION_AFFINITY_VERIFY: CHECK_BACKEND_HEADER must be set")
+		}
+		if verifyValue && cfg.CheckDeploymentReplicas < 2 {
+			return nil, fmt.Errorf("failed to parse CHECK_SESSION_AFFINITY_VERIFY: CHECK_DEPLOYMENT_REPLICAS must be at least 2")
+		}
+		if verifyValue && cfg.EveryReplicaVerify {
+			return nil, fmt.Errorf("failed to parse CHECK_SESSION_AFFINITY_VERIFY: ClientIP affinity keeps the checker on one pod, so CHECK_EVERY_REPLICA_VERIFY cannot be enabled")
+		}
+		cfg.SessionAffinityVerify = verifyValue
+		log.Infoln("Parsed CHECK_SESSION_AFFINITY_VERIFY:", cfg.SessionAffinityVerify)
+	}
+
+	// Parse EndpointSlice verification settings.
+	endpointSliceVerifyEnv := os.Getenv("CHECK_ENDPOINT_SLICE_VERIFY")
+	if len(endpointSliceVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(endpointSliceVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_ENDPOINT_SLICE_VERIFY: %w", err)
+		}
+		cfg.EndpointSliceVerify = verifyValue
+		log.Infoln("Parsed CHECK_ENDPOINT_SLICE_VERIFY:", cfg.EndpointSliceVerify)
+	}
+
+	// Parse headless service DNS verification settings.
+	headlessServiceVerifyEnv := os.Getenv("CHECK_HEADLESS_SERVICE_VERIFY")
+	if len(headlessServiceVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(headlessServiceVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HEADLESS_SERVICE_VERIFY: %w", err)
+		}
+		cfg.HeadlessServiceVerify = verifyValue
+		log.Infoln("Parsed CHECK_HEADLESS_SERVICE_VERIFY:", cfg.HeadlessServiceVerify)
+	}
+	cfg.ClusterDomain = defaultClusterDomain
+	clusterDomainEnv := os.Getenv("CHECK_CLUSTER_DOMAIN")
+	if len(clusterDomainEnv) != 0 {
+		cfg.ClusterDomain = strings.Trim(clusterDomainEnv, ".")
+		log.Infoln("Parsed CHECK_CLUSTER_DOMAIN:", cfg.ClusterDomain)
+	}
+
+	// Parse service DNS verification settings.
+	serviceDNSVerifyEnv := os.Getenv("CHECK_SERVICE_DNS_VERIFY")
+	if len(serviceDNSVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(serviceDNSVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SERVICE_DNS_VERIFY: %w", err)
+		}
+		cfg.ServiceDNSVerify = verifyValue
+		log.Infoln("Parsed CHECK_SERVICE_DNS_VERIFY:", cfg.ServiceDNSVerify)
+	}
+	cfg.ServiceDNSSlowThreshold = defaultServiceDNSSlowThreshold
+	serviceDNSSlowThresholdEnv := os.Getenv("CHECK_SERVICE_DNS_SLOW_THRESHOLD")
+	if len(serviceDNSSlowThresholdEnv) != 0 {
+		durationValue, err := time.ParseDuration(serviceDNSSlowThresholdEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SERVICE_DNS_SLOW_THRESHOLD: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SERVICE_DNS_SLOW_THRESHOLD: must be greater than zero")
+		}
+		cfg.ServiceDNSSlowThreshold = durationValue
+		log.Infoln("Parsed CHECK_SERVICE_DNS_SLOW_THRESHOLD:", cfg.ServiceDNSSlowThreshold)
+	}
+
+	// Parse network policy verification settings.
+	networkPolicyVerifyEnv := os.Getenv("CHECK_NETWORK_POLICY_VERIFY")
+	if len(networkPolicyVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(networkPolicyVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_NETWORK_POLICY_VERIFY: %w", err)
+		}
+		cfg.NetworkPolicyVerify = verifyValue
+		log.Infoln("Parsed CHECK_NETWORK_POLICY_VERIFY:", cfg.NetworkPolicyVerify)
+	}
+	cfg.NetworkPolicyTimeout = defaultNetworkPolicyTimeout
+	networkPolicyTimeoutEnv := os.Getenv("CHECK_NETWORK_POLICY_TIMEOUT")
+	if len(networkPolicyTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(networkPolicyTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_NETWORK_POLICY_TIMEOUT: %w", err)
+		}
+		cfg.NetworkPolicyTimeout = durationValue
+		log.Infoln("Parsed CHECK_NETWORK_POLICY_TIMEOUT:", cfg.NetworkPolicyTimeout)
+	}
+
+	// Parse scale verification settings.
+	scaleReplicasEnv := os.Getenv("CHECK_SCALE_REPLICAS")
+	if len(scaleReplicasEnv) != 0 {
+		scaleValue, err := strconv.Atoi(scaleReplicasEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SCALE_REPLICAS: %w", err)
+		}
+		if scaleValue < 0 || scaleValue == cfg.CheckDeploymentReplicas {
+			return nil, fmt.Errorf("failed to parse CHECK_SCALE_REPLICAS: %d must be a positive count other than CHECK_DEPLOYMENT_REPLICAS (%d)", scaleValue, cfg.CheckDeploymentReplicas)
+		}
+		cfg.ScaleReplicas = scaleValue
+		log.Infoln("Parsed CHECK_SCALE_REPLICAS:", cfg.ScaleReplicas)
+	}
+	cfg.ScaleTimeout = defaultScaleTimeout
+	scaleTimeoutEnv := os.Getenv("CHECK_SCALE_TIMEOUT")
+	if len(scaleTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(scaleTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SCALE_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_SCALE_TIMEOUT: must be greater than zero")
+		}
+		cfg.ScaleTimeout = durationValue
+		log.Infoln("Parsed CHECK_SCALE_TIMEOUT:", cfg.ScaleTimeout)
+	}
+
+	// Parse horizontal pod autoscaler verification settings.
+	hpaVerifyEnv := os.Getenv("CHECK_HPA_VERIFY")
+	if len(hpaVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(hpaVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_VERIFY: %w", err)
+		}
+		cfg.HPAVerify = verifyValue
+		log.Infoln("Parsed CHECK_HPA_VERIFY:", cfg.HPAVerify)
+	}
+	cfg.HPAMaxReplicas = int32(cfg.CheckDeploymentReplicas * 2)
+	hpaMaxReplicasEnv := os.Getenv("CHECK_HPA_MAX_REPLICAS")
+	if len(hpaMaxReplicasEnv) != 0 {
+		maxReplicas, err := strconv.ParseInt(hpaMaxReplicasEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_MAX_REPLICAS: %w", err)
+		}
+		cfg.HPAMaxReplicas = int32(maxReplicas)
+		log.Infoln("Parsed CHECK_HPA_MAX_REPLICAS:", cfg.HPAMaxReplicas)
+	}
+	if cfg.HPAVerify && int(cfg.HPAMaxReplicas) <= cfg.CheckDeploymentReplicas {
+		return nil, fmt.Errorf("failed to parse CHECK_HPA_MAX_REPLICAS: %d must be above CHECK_DEPLOYMENT_REPLICAS (%d) to leave room to scale up", cfg.HPAMaxReplicas, cfg.CheckDeploymentReplicas)
+	}
+	cfg.HPATargetCPUUtilization = defaultHPATargetCPUUtilization
+	hpaTargetEnv := os.Getenv("CHECK_HPA_TARGET_CPU_UTILIZATION")
+	if len(hpaTargetEnv) != 0 {
+		target, err := strconv.ParseInt(hpaTargetEnv, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_TARGET_CPU_UTILIZATION: %w", err)
+		}
+		if target < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_TARGET_CPU_UTILIZATION: must be at least 1")
+		}
+		cfg.HPATargetCPUUtilization = int32(target)
+		log.Infoln("Parsed CHECK_HPA_TARGET_CPU_UTILIZATION:", cfg.HPATargetCPUUtilization)
+	}
+	cfg.HPAGenerateLoad = true
+	hpaGenerateLoadEnv := os.Getenv("CHECK_HPA_GENERATE_LOAD")
+	if len(hpaGenerateLoadEnv) != 0 {
+		loadValue, err := strconv.ParseBool(hpaGenerateLoadEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_GENERATE_LOAD: %w", err)
+		}
+		cfg.HPAGenerateLoad = loadValue
+		log.Infoln("Parsed CHECK_HPA_GENERATE_LOAD:", cfg.HPAGenerateLoad)
+	}
+	cfg.HPATimeout = defaultHPATimeout
+	hpaTimeoutEnv := os.Getenv("CHECK_HPA_TIMEOUT")
+	if len(hpaTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(hpaTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_HPA_TIMEOUT: must be greater than zero")
+		}
+		cfg.HPATimeout = durationValue
+		log.Infoln("Parsed CHECK_HPA_TIMEOUT:", cfg.HPATimeout)
+	}
+
+	// Parse pod disruption budget verification settings.
+	pdbVerifyEnv := os.Getenv("CHECK_PDB_VERIFY")
+	if len(pdbVerifyEnv) != 0 {
+		verifyValue, err := strconv.ParseBool(pdbVerifyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PDB_VERIFY: %w", err)
+		}
+		cfg.PDBVerify = verifyValue
+		log.Infoln("Parsed CHECK_PDB_VERIFY:", cfg.PDBVerify)
+	}
+	cfg.PDBTimeout = defaultPDBTimeout
+	pdbTimeoutEnv := os.Getenv("CHECK_PDB_TIMEOUT")
+	if len(pdbTimeoutEnv) != 0 {
+		durationValue, err := time.ParseDuration(pdbTimeoutEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_PDB_TIMEOUT: %w", err)
+		}
+		if durationValue <= 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_PDB_TIMEOUT: must be greater than zero")
+		}
+		cfg.PDBTimeout = durationValue
+		log.Infoln("Parsed CHECK_PDB_TIMEOUT:", cfg.PDBTimeout)
+	}
+
+	// Parse soak duration.
+	soakDurationEnv := os.Getenv("CHECK_SOAK_DURATION")
+	if len(soakDurationEnv) != 0 {
+		durationValue, err := time.ParseDuration(soakDurationEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_SOAK_DURATION: %w", err)
+		}
+		cfg.SoakDuration = durationValue
+		log.Infoln("Parsed CHECK_SOAK_DURATION:", cfg.SoakDuration)
+	}
+
+	// The prober pod requests the service with curl.
+	if cfg.Protocol == protocolTCP && cfg.ProberVerify {
+		return nil, fmt.Errorf("CHECK_PROTOCOL=tcp cannot be combined with CHECK_PROBER_VERIFY")
+	}
+
+	// Backends can only identify themselves in an HTTP response.
+	if cfg.Protocol == protocolTCP && len(cfg.BackendHeader) != 0 {
+		return nil, fmt.Errorf("CHECK_PROTOCOL=tcp cannot be combined with CHECK_BACKEND_HEADER")
+	}
+
+	// Ingress, Gateway, and Route validation only speak HTTP.
+	if cfg.Protocol == protocolTCP && (cfg.IngressVerify || len(cfg.GatewayName) != 0 || cfg.OpenShiftRouteVerify) {
+		return nil, fmt.Errorf("CHECK_PROTOCOL=tcp cannot be combined with ingress, Gateway, or OpenShift Route validation")
+	}
+
+	// A single-writer volume can only follow one pod, so rule out replicas, rolling updates, and scaling that need two.
+	if len(cfg.PVCStorageClass) != 0 && cfg.PVCAccessMode != corev1.ReadWriteMany && (cfg.CheckDeploymentReplicas > 1 || cfg.RollingUpdate || cfg.ScaleReplicas > 1 || cfg.HPAVerify) {
+		return nil, fmt.Errorf("failed to parse CHECK_PVC_ACCESS_MODE: %s needs CHECK_DEPLOYMENT_REPLICAS=1 without CHECK_DEPLOYMENT_ROLLING_UPDATE, CHECK_SCALE_REPLICAS above 1, or CHECK_HPA_VERIFY; use %s otherwise", cfg.PVCAccessMode, corev1.ReadWriteMany)
+	}
+
+	// A remote cluster is only reached through its API server, so nothing may connect to its pod, node, or service addresses or use its DNS.
+	if cfg.targetsRemoteCluster() {
+		remoteConflicts := make([]string, 0)
+		if cfg.Protocol == protocolTCP {
+			remoteConflicts = append(remoteConflicts, "CHECK_PROTOCOL=tcp")
+		}
+		if cfg.CheckServiceType == corev1.ServiceTypeNodePort {
+			remoteConflicts = append(remoteConflicts, "CHECK_SERVICE_TYPE=NodePort")
+		}
+		if cfg.EndpointDiagnostics {
+			remoteConflicts = append(remoteConflicts, "CHECK_ENDPOINT_DIAGNOSTICS")
+		}
+		if cfg.PodIPVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_POD_IP_VERIFY")
+		}
+		if cfg.EveryReplicaVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_EVERY_REPLICA_VERIFY")
+		}
+		if cfg.SessionAffinityVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_SESSION_AFFINITY_VERIFY")
+		}
+		if cfg.HTTPRequestCount > 0 {
+			remoteConflicts = append(remoteConflicts, "CHECK_HTTP_REQUEST_COUNT")
+		}
+		if cfg.ServiceDNSVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_SERVICE_DNS_VERIFY")
+		}
+		if cfg.HeadlessServiceVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_HEADLESS_SERVICE_VERIFY")
+		}
+		if cfg.NetworkPolicyVerify {
+			remoteConflicts = append(remoteConflicts, "CHECK_NETWORK_POLICY_VERIFY")
+		}
+		if cfg.SoakDuration > 0 {
+			remoteConflicts = append(remoteConflicts, "CHECK_SOAK_DURATION")
+		}
+		if len(cfg.CertManagerIssuer) != 0 {
+			remoteConflicts = append(remoteConflicts, "CHECK_CERT_MANAGER_ISSUER")
+		}
+		if len(remoteConflicts) != 0 {
+			return nil, fmt.Errorf("KUBE_CONTEXT and KUBE_API_SERVER cannot be combined with %s: the checker cannot reach the target cluster's pod, node, or service network", strings.Join(remoteConflicts, ", "))
+		}
+	}
+
+	// Ensure logrus and checkclient share debug state.
+	checkclient.Debug = cfg.Debug
+
+	return cfg, nil
+}
+
+// parseTolerations converts a comma-separated tolerations string into objects for the pod spec.
+func parseTolerations(raw string) ([]corev1.Toleration, error) {
+	// Split entries on commas for key/value pairs.
+	entries := strings.Split(raw, ",")
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no tolerations provided")
+	}
+
+	// Build the tolerations slice.
+	tolerations := make([]corev1.Toleration, 0)
+	for _, entry := range entries {
+		// Split key and value/effect parts.
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			log.Warnln("Unable to parse key value pair:", entry)
+			log.Warnln("Setting operator to", corev1.TolerationOpExists)
+			toleration := corev1.Toleration{
+				Key:      parts[0],
+				Operator: corev1.TolerationOpExists,
+			}
+			log.Infoln("Adding toleration to deployment:", toleration)
+			tolerations = append(tolerations, toleration)
+			continue
+		}
+
+		// Split value/effect when present.
+		valueEffect := strings.Split(parts[1], ":")
+		if len(valueEffect) != 2 {
+			log.Warnln("Unable to parse complete toleration value and effect:", valueEffect)
+			toleration := corev1.Toleration{
+				Key:      parts[0],
+				Operator: corev1.TolerationOpEqual,
+				Value:    parts[1],
+			}
+			log.Infoln("Adding toleration to deployment:", toleration)
+			tolerations = append(tolerations, toleration)
+			continue
+		}
+
+		// Build a full toleration when both value and effect are present.
+		toleration := corev1.Toleration{
+			Key:      parts[0],
+			Operator: corev1.TolerationOpEqual,
+			Value:    valueEffect[0],
+			Effect:   corev1.TaintEffect(valueEffect[1]),
+		}
+		log.Infoln("Adding toleration to deployment:", toleration)
+		tolerations = append(tolerations, toleration)
+	}
+
+	return tolerations, nil
+}
+
+// parseNodeSelectors converts a comma-separated selector string into a map for the pod spec.
 func parseNodeSelectors(raw string) (map[string]string, error) {
 	// Split entries into key/value pairs.
 	entries := strings.Split(raw, ",")
@@ -438,6 +3182,29 @@ func parseNodeSelectors(raw string) (map[string]string, error) {
 	return selectors, nil
 }
 
+// parseNodeAffinityRequirements converts key=value1|value2 entries into required node affinity terms.
+func parseNodeAffinityRequirements(raw string) ([]corev1.NodeSelectorRequirement, error) {
+	// Split entries into key/values pairs.
+	entries := strings.Split(raw, ",")
+
+	// Build an In requirement for each entry.
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(strings.TrimSpace(entry), "=")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("failed to parse node affinity requirement %q: expected key=value1|value2", entry)
+		}
+		requirement := corev1.NodeSelectorRequirement{
+			Key:      parts[0],
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   strings.Split(parts[1], "|"),
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
 // parseAdditionalEnvVars parses key=value pairs into a map for container env vars.
 func parseAdditionalEnvVars(raw string) (map[string]string, error) {
 	// Split entries into key/value pairs.
@@ -463,3 +3230,358 @@ func parseAdditionalEnvVars(raw string) (map[string]string, error) {
 
 	return vars, nil
 }
+
+// parseLabels parses comma-separated key=value labels, rejecting keys the check manages itself.
+func parseLabels(name string, raw string) (map[string]string, error) {
+	// Split entries and then each entry on its equals sign.
+	labels := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found || len(key) == 0 {
+			return nil, fmt.Errorf("failed to parse %s: entry %q must be key=value", name, entry)
+		}
+		if key == deploymentLabelKey || key == sourceLabelKey {
+			return nil, fmt.Errorf("failed to parse %s: label %q is managed by the check", name, key)
+		}
+		problems := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse %s: invalid label %q: %s", name, entry, strings.Join(problems, "; "))
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// parseAnnotations parses comma-separated key=value annotations, allowing equals signs in values.
+func parseAnnotations(name string, raw string) (map[string]string, error) {
+	// Split entries and then each entry on its first equals sign.
+	annotations := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		key = strings.TrimSpace(key)
+		if !found || len(key) == 0 {
+			return nil, fmt.Errorf("failed to parse %s: entry %q must be key=value", name, entry)
+		}
+		problems := validation.IsQualifiedName(key)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse %s: invalid key %q: %s", name, key, strings.Join(problems, "; "))
+		}
+		annotations[key] = strings.TrimSpace(value)
+	}
+
+	return annotations, nil
+}
+
+// parseHTTPHeaders converts a comma-separated list of Name=Value pairs into request headers.
+func parseHTTPHeaders(raw string) (http.Header, error) {
+	// Split entries on commas and names from values on the first equals sign.
+	headers := make(http.Header)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_HEADERS entry %q: expected Name=Value", entry)
+		}
+		headers.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	return headers, nil
+}
+
+// sortedHeaderNames returns header names in sorted order so values such as tokens are not logged.
+func sortedHeaderNames(headers http.Header) []string {
+	// Collect and sort the names.
+	names := make(map[string]bool)
+	for name := range headers {
+		names[name] = true
+	}
+
+	return sortedKeys(names)
+}
+
+// parseExpectedStatusCodes converts a comma-separated list of codes and ranges such as "200,204,300-399".
+func parseExpectedStatusCodes(raw string) (expectedStatusCodes, error) {
+	// Parse each entry as a single code or an inclusive range.
+	codes := make(expectedStatusCodes, 0)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		bounds := strings.SplitN(entry, "-", 2)
+		minCode, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_EXPECTED_CODES entry %q: %w", entry, err)
+		}
+		maxCode := minCode
+		if len(bounds) == 2 {
+			maxCode, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CHECK_HTTP_EXPECTED_CODES entry %q: %w", entry, err)
+			}
+		}
+
+		// Keep codes within the valid HTTP range and ranges in order.
+		if minCode < 100 || maxCode > 599 || minCode > maxCode {
+			return nil, fmt.Errorf("failed to parse CHECK_HTTP_EXPECTED_CODES entry %q: codes must be ascending between 100 and 599", entry)
+		}
+		codes = append(codes, statusCodeRange{Min: minCode, Max: maxCode})
+	}
+
+	// Require at least one code.
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("failed to parse CHECK_HTTP_EXPECTED_CODES: no status codes provided")
+	}
+
+	return codes, nil
+}
+
+// parseTopologySpreadConstraints parses comma-separated topologyKey:maxSkew[:whenUnsatisfiable] entries.
+func parseTopologySpreadConstraints(raw string) ([]corev1.TopologySpreadConstraint, error) {
+	// Split entries and then each entry into its fields.
+	constraints := make([]corev1.TopologySpreadConstraint, 0)
+	for _, entry := range strings.Split(raw, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 || len(parts) > 3 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_TOPOLOGY_SPREAD: entry %q must be topologyKey:maxSkew[:whenUnsatisfiable]", entry)
+		}
+		maxSkew, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil || maxSkew < 1 {
+			return nil, fmt.Errorf("failed to parse CHECK_TOPOLOGY_SPREAD: max skew %q in %q must be a positive integer", parts[1], entry)
+		}
+
+		// Default to hard constraints, which the verification mirrors.
+		whenUnsatisfiable := corev1.DoNotSchedule
+		if len(parts) == 3 {
+			whenUnsatisfiable = corev1.UnsatisfiableConstraintAction(parts[2])
+			if whenUnsatisfiable != corev1.DoNotSchedule && whenUnsatisfiable != corev1.ScheduleAnyway {
+				return nil, fmt.Errorf("failed to parse CHECK_TOPOLOGY_SPREAD: %q in %q must be DoNotSchedule or ScheduleAnyway", parts[2], entry)
+			}
+		}
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			TopologyKey:       parts[0],
+			MaxSkew:           int32(maxSkew),
+			WhenUnsatisfiable: whenUnsatisfiable,
+		})
+	}
+
+	return constraints, nil
+}
+
+// parseSeccompProfile parses RuntimeDefault, Unconfined, or Localhost/<profile> into a seccomp profile.
+func parseSeccompProfile(raw string) (*corev1.SeccompProfile, error) {
+	// Match the profile type, splitting off a localhost profile path.
+	profileType, localhostProfile, hasPath := strings.Cut(strings.TrimSpace(raw), "/")
+	switch {
+	case strings.EqualFold(profileType, string(corev1.SeccompProfileTypeRuntimeDefault)) && !hasPath:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}, nil
+	case strings.EqualFold(profileType, string(corev1.SeccompProfileTypeUnconfined)) && !hasPath:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}, nil
+	case strings.EqualFold(profileType, string(corev1.SeccompProfileTypeLocalhost)) && len(localhostProfile) != 0:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}, nil
+	}
+
+	return nil, fmt.Errorf("failed to parse CHECK_POD_SECCOMP_PROFILE: %q must be RuntimeDefault, Unconfined, or Localhost/<profile>", raw)
+}
+
+// parseImageRollSequence parses the rolling update images, rejecting steps that would not change the image.
+func parseImageRollSequence(initialImage string, raw string) ([]string, error) {
+	// Split entries on commas and walk them from the initial image.
+	sequence := make([]string, 0)
+	previous := initialImage
+	for _, entry := range strings.Split(raw, ",") {
+		image := strings.TrimSpace(entry)
+		if len(image) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_IMAGE_ROLL_SEQUENCE: empty image entry in %q", raw)
+		}
+		if image == previous {
+			return nil, fmt.Errorf("failed to parse CHECK_IMAGE_ROLL_SEQUENCE: step %d repeats the previous image %s", len(sequence)+1, image)
+		}
+		sequence = append(sequence, image)
+		previous = image
+	}
+
+	return sequence, nil
+}
+
+// parseAdditionalPorts converts containerPort:servicePort pairs into port definitions.
+func parseAdditionalPorts(raw string) ([]checkPort, error) {
+	// Split entries on commas for port pairs.
+	entries := strings.Split(raw, ",")
+
+	// Build the port slice.
+	ports := make([]checkPort, 0, len(entries))
+	for _, entry := range entries {
+		// Split the container and service ports.
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("failed to parse CHECK_ADDITIONAL_PORTS entry %q: expected containerPort:servicePort", entry)
+		}
+		containerPort, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_ADDITIONAL_PORTS container port %q: %w", parts[0], err)
+		}
+		servicePort, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_ADDITIONAL_PORTS service port %q: %w", parts[1], err)
+		}
+		ports = append(ports, checkPort{ContainerPort: int32(containerPort), ServicePort: int32(servicePort)})
+	}
+
+	return ports, nil
+}
+
+// parseExtendedResources parses comma-separated name=count extended resources such as nvidia.com/gpu=1.
+func parseExtendedResources(raw string) (map[corev1.ResourceName]resource.Quantity, error) {
+	// Split entries and then each entry on its equals sign.
+	extendedResources := make(map[corev1.ResourceName]resource.Quantity)
+	for _, entry := range strings.Split(raw, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		name = strings.TrimSpace(name)
+		if !found || len(name) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EXTENDED_RESOURCES: entry %q must be name=count", entry)
+		}
+
+		// Extended resources live outside the kubernetes.io domain and carry a vendor prefix.
+		domain, _, prefixed := strings.Cut(name, "/")
+		if !prefixed || domain == "kubernetes.io" || strings.HasSuffix(domain, ".kubernetes.io") {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EXTENDED_RESOURCES: %q is not an extended resource name", name)
+		}
+		problems := validation.IsQualifiedName(name)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EXTENDED_RESOURCES: invalid name %q: %s", name, strings.Join(problems, "; "))
+		}
+
+		// Extended resources cannot be overcommitted or split, so require a positive whole count.
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EXTENDED_RESOURCES: invalid count for %q: %w", name, err)
+		}
+		if quantity.Sign() <= 0 || quantity.MilliValue()%1000 != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_POD_EXTENDED_RESOURCES: count for %q must be a positive whole number", name)
+		}
+		extendedResources[corev1.ResourceName(name)] = quantity
+	}
+
+	return extendedResources, nil
+}
+
+// parseNamespaces parses a comma-separated list of distinct namespace names.
+func parseNamespaces(raw string) ([]string, error) {
+	// Split entries and validate each name.
+	namespaces := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(raw, ",") {
+		namespace := strings.TrimSpace(entry)
+		problems := validation.IsDNS1123Label(namespace)
+		if len(problems) != 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_NAMESPACES: invalid namespace %q: %s", namespace, strings.Join(problems, "; "))
+		}
+		if seen[namespace] {
+			return nil, fmt.Errorf("failed to parse CHECK_NAMESPACES: namespace %q is listed more than once", namespace)
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+
+	return namespaces, nil
+}
+
+// parseInitContainers parses semicolon-separated image=command entries, where the command is split on whitespace.
+func parseInitContainers(raw string) ([]initContainerSpec, error) {
+	// Split entries on semicolons since commands may contain commas.
+	initContainers := make([]initContainerSpec, 0)
+	for _, entry := range strings.Split(raw, ";") {
+		image, command, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		image = strings.TrimSpace(image)
+		if len(image) == 0 {
+			return nil, fmt.Errorf("failed to parse CHECK_INIT_CONTAINERS: entry %q must be image or image=command", entry)
+		}
+		if strings.ContainsAny(image, " \t") {
+			return nil, fmt.Errorf("failed to parse CHECK_INIT_CONTAINERS: image %q must not contain whitespace", image)
+		}
+		spec := initContainerSpec{Image: image}
+		if len(strings.TrimSpace(command)) != 0 {
+			spec.Command = strings.Fields(command)
+		}
+		initContainers = append(initContainers, spec)
+	}
+
+	return initContainers, nil
+}
+
+// parseHostAliases parses semicolon-separated ip=hostname,hostname entries into host aliases.
+func parseHostAliases(raw string) ([]corev1.HostAlias, error) {
+	// Split entries on semicolons since each entry lists hostnames with commas.
+	hostAliases := make([]corev1.HostAlias, 0)
+	for _, entry := range strings.Split(raw, ";") {
+		ip, hostnames, found := strings.Cut(strings.TrimSpace(entry), "=")
+		ip = strings.TrimSpace(ip)
+		if !found || net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("failed to parse CHECK_HOST_ALIASES: entry %q must be ip=hostname[,hostname]", entry)
+		}
+
+		// Validate each hostname the way the API server does.
+		alias := corev1.HostAlias{IP: ip}
+		for _, hostname := range strings.Split(hostnames, ",") {
+			hostname = strings.TrimSpace(hostname)
+			problems := validation.IsDNS1123Subdomain(hostname)
+			if len(problems) != 0 {
+				return nil, fmt.Errorf("failed to parse CHECK_HOST_ALIASES: invalid hostname %q for %s: %s", hostname, ip, strings.Join(problems, "; "))
+			}
+			alias.Hostnames = append(alias.Hostnames, hostname)
+		}
+		hostAliases = append(hostAliases, alias)
+	}
+
+	return hostAliases, nil
+}
+
+// validateMountPath requires an absolute, clean mount path not already used by another check volume.
+func validateMountPath(name string, mountPath string, used map[string]string) error {
+	// Reject relative or unclean paths the API server would refuse or rewrite.
+	if !strings.HasPrefix(mountPath, "/") || path.Clean(mountPath) != mountPath || mountPath == "/" {
+		return fmt.Errorf("failed to parse %s: %q must be a clean absolute path below /", name, mountPath)
+	}
+
+	// Two volumes cannot share a mount path.
+	owner, found := used[mountPath]
+	if found {
+		return fmt.Errorf("failed to parse %s: %q is already used by %s", name, mountPath, owner)
+	}
+	used[mountPath] = name
+
+	return nil
+}
+
+// checkPorts returns the primary port pair followed by any additional pairs.
+func (cfg *CheckConfig) checkPorts() []checkPort {
+	// Lead with the primary HTTP port.
+	ports := []checkPort{{ContainerPort: cfg.CheckContainerPort, ServicePort: cfg.CheckLoadBalancerPort}}
+	ports = append(ports, cfg.CheckAdditionalPorts...)
+
+	return ports
+}
+
+// headlessServiceName returns the name of the headless service used for DNS record verification.
+func (cfg *CheckConfig) headlessServiceName() string {
+	// Derive the name from the main service so both are cleaned up together.
+	return cfg.CheckServiceName + "-headless"
+}
+
+// resourceLabels returns a fresh copy of the user labels for a created resource.
+func (cfg *CheckConfig) resourceLabels() map[string]string {
+	// Copy so callers can add their own labels safely.
+	labels := make(map[string]string, len(cfg.ExtraLabels))
+	for key, value := range cfg.ExtraLabels {
+		labels[key] = value
+	}
+
+	return labels
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestParseAdditionalPorts validates port pair parsing for multi-port checks.
+func TestParseAdditionalPorts(t *testing.T) {
+	// Parse a pair of valid port definitions.
+	ports, err := parseAdditionalPorts("8081:81, 8443:443")
+	if err != nil {
+		t.Fatalf("unexpected error parsing additional ports: %v", err)
+	}
+
+	if len(ports) != 2 {
+		t.Fatalf("expected 2 ports but got %d", len(ports))
+	}
+
+	if ports[1].ContainerPort != 8443 || ports[1].ServicePort != 443 {
+		t.Fatalf("expected 8443:443 but got %d:%d", ports[1].ContainerPort, ports[1].ServicePort)
+	}
+
+	// Reject entries that are missing the service port.
+	_, err = parseAdditionalPorts("8081")
+	if err == nil {
+		t.Fatalf("expected an error for a port entry without a service port")
+	}
+}
+
+// TestParseNodeAffinityRequirements validates node affinity requirement parsing.
+func TestParseNodeAffinityRequirements(t *testing.T) {
+	// Parse a requirement with multiple values.
+	requirements, err := parseNodeAffinityRequirements("karpenter.sh/capacity-type=spot|on-demand")
+	if err != nil {
+		t.Fatalf("unexpected error parsing requirements: %v", err)
+	}
+
+	if len(requirements) != 1 || len(requirements[0].Values) != 2 {
+		t.Fatalf("expected one requirement with two values but got: %v", requirements)
+	}
+
+	// Reject entries without values.
+	_, err = parseNodeAffinityRequirements("karpenter.sh/nodepool")
+	if err == nil {
+		t.Fatalf("expected an error for a requirement without values")
+	}
+}
+
+// TestApplyAutopilotResources validates Autopilot minimums, ratios, and pinned limits.
+func TestApplyAutopilotResources(t *testing.T) {
+	// Start from the small default requests.
+	cfg := &CheckConfig{
+		MillicoreRequest: defaultMillicoreRequest,
+		MemoryRequest:    defaultMemoryRequest,
+		MillicoreLimit:   defaultMillicoreLimit,
+		MemoryLimit:      defaultMemoryLimit,
+	}
+	applyAutopilotResources(cfg)
+
+	if cfg.MillicoreRequest != autopilotMinMillicores {
+		t.Fatalf("expected %d millicores but got %d", autopilotMinMillicores, cfg.MillicoreRequest)
+	}
+
+	if cfg.MemoryRequest < autopilotMinMemoryBytes {
+		t.Fatalf("expected memory of at least %d but got %d", autopilotMinMemoryBytes, cfg.MemoryRequest)
+	}
+
+	if cfg.MillicoreLimit != cfg.MillicoreRequest || cfg.MemoryLimit != cfg.MemoryRequest {
+		t.Fatalf("expected limits to equal requests but got %d/%d", cfg.MillicoreLimit, cfg.MemoryLimit)
+	}
+}
+
+// TestParseExpectedStatusCodes validates single codes and ranges.
+func TestParseExpectedStatusCodes(t *testing.T) {
+	// Parse a mix of codes and ranges.
+	codes, err := parseExpectedStatusCodes("200-299, 301,404")
+	if err != nil {
+		t.Fatalf("unexpected error parsing expected codes: %v", err)
+	}
+
+	for _, code := range []int{200, 250, 299, 301, 404} {
+		if !codes.matches(code) {
+			t.Fatalf("expected %d to match %s", code, codes)
+		}
+	}
+
+	if codes.matches(302) || codes.matches(500) {
+		t.Fatalf("expected 302 and 500 not to match %s", codes)
+	}
+
+	// Reject inverted and out-of-range entries.
+	for _, raw := range []string{"299-200", "99", "200-600", "abc"} {
+		_, err = parseExpectedStatusCodes(raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseHTTPHeaders validates header parsing including values containing equals signs.
+func TestParseHTTPHeaders(t *testing.T) {
+	// Parse a host override and a token header.
+	headers, err := parseHTTPHeaders("host=app.example.com, X-Token=abc==")
+	if err != nil {
+		t.Fatalf("unexpected error parsing headers: %v", err)
+	}
+
+	if headers.Get("Host") != "app.example.com" {
+		t.Fatalf("expected canonical Host header but got %v", headers)
+	}
+
+	if headers.Get("X-Token") != "abc==" {
+		t.Fatalf("expected token value to keep equals signs but got %q", headers.Get("X-Token"))
+	}
+
+	_, err = parseHTTPHeaders("novalue")
+	if err == nil {
+		t.Fatalf("expected an error for an entry without a value")
+	}
+}
+
+// TestParseSeccompProfile validates seccomp profile types and localhost paths.
+func TestParseSeccompProfile(t *testing.T) {
+	// Parse the runtime default case-insensitively.
+	profile, err := parseSeccompProfile("runtimedefault")
+	if err != nil || profile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected RuntimeDefault but got %v, %v", profile, err)
+	}
+
+	// Parse a localhost profile with a nested path.
+	profile, err = parseSeccompProfile("Localhost/profiles/check.json")
+	if err != nil || profile.Type != corev1.SeccompProfileTypeLocalhost || *profile.LocalhostProfile != "profiles/check.json" {
+		t.Fatalf("expected Localhost profile profiles/check.json but got %v, %v", profile, err)
+	}
+
+	// Reject unknown types and a localhost profile without a path.
+	for _, raw := range []string{"Localhost", "RuntimeDefault/x", "strict"} {
+		_, err = parseSeccompProfile(raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseAnnotations validates annotation parsing, including values with equals signs.
+func TestParseAnnotations(t *testing.T) {
+	// Parse a sidecar toggle and a value containing an equals sign.
+	annotations, err := parseAnnotations("CHECK_POD_ANNOTATIONS", "sidecar.istio.io/inject=false, cost-center=team=platform")
+	if err != nil {
+		t.Fatalf("unexpected error parsing annotations: %v", err)
+	}
+
+	if annotations["sidecar.istio.io/inject"] != "false" || annotations["cost-center"] != "team=platform" {
+		t.Fatalf("unexpected annotations: %v", annotations)
+	}
+
+	// Reject entries without a value and invalid keys.
+	for _, raw := range []string{"novalue", "bad key=value"} {
+		_, err = parseAnnotations("CHECK_POD_ANNOTATIONS", raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseLabels validates label parsing and rejection of check-managed keys.
+func TestParseLabels(t *testing.T) {
+	// Parse team and app labels.
+	labels, err := parseLabels("CHECK_LABELS", "team=platform, app.kubernetes.io/name=deployment-check")
+	if err != nil {
+		t.Fatalf("unexpected error parsing labels: %v", err)
+	}
+
+	if labels["team"] != "platform" || labels["app.kubernetes.io/name"] != "deployment-check" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+
+	// Reject managed keys, invalid values, and entries without values.
+	for _, raw := range []string{deploymentLabelKey + "=x", "source=other", "team=has space", "novalue"} {
+		_, err = parseLabels("CHECK_LABELS", raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseTopologySpreadConstraints validates constraint parsing and defaults.
+func TestParseTopologySpreadConstraints(t *testing.T) {
+	// Parse a zone constraint with the default action and a soft hostname constraint.
+	constraints, err := parseTopologySpreadConstraints("topology.kubernetes.io/zone:1, kubernetes.io/hostname:2:ScheduleAnyway")
+	if err != nil {
+		t.Fatalf("unexpected error parsing constraints: %v", err)
+	}
+
+	if len(constraints) != 2 || constraints[0].WhenUnsatisfiable != corev1.DoNotSchedule || constraints[1].MaxSkew != 2 || constraints[1].WhenUnsatisfiable != corev1.ScheduleAnyway {
+		t.Fatalf("unexpected constraints: %+v", constraints)
+	}
+
+	// Reject missing skews, zero skews, and unknown actions.
+	for _, raw := range []string{"topology.kubernetes.io/zone", "zone:0", "zone:1:Sometimes"} {
+		_, err = parseTopologySpreadConstraints(raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseImageRollSequence validates roll sequences and rejection of no-op steps.
+func TestParseImageRollSequence(t *testing.T) {
+	// Parse an A to B to A release train.
+	sequence, err := parseImageRollSequence("app:a", "app:b, app:a")
+	if err != nil {
+		t.Fatalf("unexpected error parsing roll sequence: %v", err)
+	}
+
+	if len(sequence) != 2 || sequence[0] != "app:b" || sequence[1] != "app:a" {
+		t.Fatalf("unexpected roll sequence: %v", sequence)
+	}
+
+	// Reject steps that repeat the image before them and empty entries.
+	for _, raw := range []string{"app:a", "app:b,app:b", "app:b,,app:a"} {
+		_, err = parseImageRollSequence("app:a", raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseExtendedResources validates extended resource parsing for device node pools.
+func TestParseExtendedResources(t *testing.T) {
+	// Parse a GPU and a vendor device.
+	extendedResources, err := parseExtendedResources("nvidia.com/gpu=1, example.com/fpga=2")
+	if err != nil {
+		t.Fatalf("unexpected error parsing extended resources: %v", err)
+	}
+
+	gpus := extendedResources["nvidia.com/gpu"]
+	if len(extendedResources) != 2 || gpus.Value() != 1 {
+		t.Fatalf("expected two extended resources with one GPU but got %v", extendedResources)
+	}
+
+	// Reject native resources, unprefixed names, and fractional counts.
+	for _, raw := range []string{"cpu=1", "kubernetes.io/foo=1", "nvidia.com/gpu=500m", "nvidia.com/gpu=0", "nvidia.com/gpu"} {
+		_, err = parseExtendedResources(raw)
+		if err == nil {
+			t.Fatalf("expected an error for extended resources %q", raw)
+		}
+	}
+}
+
+// TestParseInitContainers validates init container parsing with and without commands.
+func TestParseInitContainers(t *testing.T) {
+	// Parse one entry with a command and one using the image entrypoint.
+	initContainers, err := parseInitContainers("busybox:1.36=sleep 2; registry.example.com/init@sha256:abc")
+	if err != nil {
+		t.Fatalf("unexpected error parsing init containers: %v", err)
+	}
+
+	if len(initContainers) != 2 || initContainers[0].Image != "busybox:1.36" || len(initContainers[0].Command) != 2 {
+		t.Fatalf("expected busybox with a two-part command first but got %v", initContainers)
+	}
+
+	if initContainers[1].Command != nil {
+		t.Fatalf("expected no command override for the second entry but got %v", initContainers[1].Command)
+	}
+
+	// Reject entries without an image.
+	_, err = parseInitContainers("busybox:1.36;=true")
+	if err == nil {
+		t.Fatalf("expected an error for an init container without an image")
+	}
+}
+
+// TestParseNamespaces validates namespace lists are trimmed and invalid or repeated names are rejected.
+func TestParseNamespaces(t *testing.T) {
+	// Parse a list with spacing.
+	namespaces, err := parseNamespaces("tenant-a, tenant-b")
+	if err != nil {
+		t.Fatalf("unexpected error parsing namespaces: %v", err)
+	}
+	if len(namespaces) != 2 || namespaces[0] != "tenant-a" || namespaces[1] != "tenant-b" {
+		t.Fatalf("unexpected namespaces %v", namespaces)
+	}
+
+	// Reject malformed lists.
+	for _, raw := range []string{"tenant-a,", "Tenant", "tenant-a,tenant-a"} {
+		_, err = parseNamespaces(raw)
+		if err == nil {
+			t.Fatalf("expected an error parsing %q", raw)
+		}
+	}
+}
+
+// TestParseHostAliases validates host alias entries and their rejection rules.
+func TestParseHostAliases(t *testing.T) {
+	// Parse an IPv4 entry with two hostnames and an IPv6 entry.
+	hostAliases, err := parseHostAliases("10.0.0.5=registry.internal, license.internal; fd00::5=mirror.internal")
+	if err != nil {
+		t.Fatalf("unexpected error parsing host aliases: %v", err)
+	}
+
+	if len(hostAliases) != 2 || hostAliases[0].IP != "10.0.0.5" || len(hostAliases[0].Hostnames) != 2 || hostAliases[0].Hostnames[1] != "license.internal" {
+		t.Fatalf("expected two hostnames for 10.0.0.5 first but got %v", hostAliases)
+	}
+
+	if hostAliases[1].IP != "fd00::5" || hostAliases[1].Hostnames[0] != "mirror.internal" {
+		t.Fatalf("expected mirror.internal on fd00::5 but got %v", hostAliases[1])
+	}
+
+	// Reject invalid addresses, missing hostnames, and invalid hostnames.
+	for _, raw := range []string{"registry.internal=10.0.0.5", "10.0.0.5", "10.0.0.5=", "10.0.0.5=Bad_Name"} {
+		_, err = parseHostAliases(raw)
+		if err == nil {
+			t.Fatalf("expected an error for host aliases %q", raw)
+		}
+	}
+}
+
+// TestParseDeletePropagationPolicy validates the deployment delete policy defaults to Background and rejects unknown values.
+func TestParseDeletePropagationPolicy(t *testing.T) {
+	// Default to a background delete.
+	cfg, err := parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing the default config: %v", err)
+	}
+	if cfg.DeletePropagationPolicy != metav1.DeletePropagationBackground {
+		t.Fatalf("expected a Background delete by default but got %s", cfg.DeletePropagationPolicy)
+	}
+
+	// Accept each policy by name.
+	for _, policy := range []metav1.DeletionPropagation{metav1.DeletePropagationForeground, metav1.DeletePropagationOrphan} {
+		t.Setenv("CHECK_DELETE_PROPAGATION_POLICY", string(policy))
+		cfg, err = parseConfig()
+		if err != nil || cfg.DeletePropagationPolicy != policy {
+			t.Fatalf("expected %s to parse but got %v", policy, err)
+		}
+	}
+
+	// Reject names that differ from the API's spelling.
+	t.Setenv("CHECK_DELETE_PROPAGATION_POLICY", "foreground")
+	_, err = parseConfig()
+	if err == nil {
+		t.Fatalf("expected an error for a lowercase policy")
+	}
+}
+
+// TestParseScaleVerification validates the scale target and timeout are parsed and bad values rejected.
+func TestParseScaleVerification(t *testing.T) {
+	// Parse a scale target and timeout.
+	t.Setenv("CHECK_SCALE_REPLICAS", "5")
+	t.Setenv("CHECK_SCALE_TIMEOUT", "30s")
+	cfg, err := parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing scale settings: %v", err)
+	}
+	if cfg.ScaleReplicas != 5 || cfg.ScaleTimeout != time.Second*30 {
+		t.Fatalf("expected 5 replicas within 30s but got %d within %s", cfg.ScaleReplicas, cfg.ScaleTimeout)
+	}
+
+	// Reject a non-positive timeout.
+	for _, raw := range []string{"0s", "-1m"} {
+		t.Setenv("CHECK_SCALE_TIMEOUT", raw)
+		_, err = parseConfig()
+		if err == nil {
+			t.Fatalf("expected an error for CHECK_SCALE_TIMEOUT %q", raw)
+		}
+	}
+
+	// Reject a target equal to the starting replica count.
+	t.Setenv("CHECK_SCALE_TIMEOUT", "30s")
+	t.Setenv("CHECK_SCALE_REPLICAS", strconv.Itoa(cfg.CheckDeploymentReplicas))
+	_, err = parseConfig()
+	if err == nil {
+		t.Fatalf("expected an error for a scale target equal to CHECK_DEPLOYMENT_REPLICAS")
+	}
+}
+
+// TestParseFatalWaitingReasons validates fast-fail is opt-in and a pod error grace adds the pull failures.
+func TestParseFatalWaitingReasons(t *testing.T) {
+	// Nothing is fatal on first sight by default.
+	cfg, err := parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing config: %v", err)
+	}
+	if len(cfg.FatalWaitingReasons) != 0 {
+		t.Fatalf("expected no fatal waiting reasons by default but got %v", cfg.FatalWaitingReasons)
+	}
+
+	// Configured reasons are fatal, and a grace makes pull failures fatal once it passes.
+	t.Setenv("CHECK_FATAL_WAITING_REASONS", "InvalidImageName, CreateContainerConfigError")
+	t.Setenv("CHECK_POD_ERROR_GRACE", "2m")
+	cfg, err = parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing config: %v", err)
+	}
+	for _, reason := range []string{"InvalidImageName", "CreateContainerConfigError", "ErrImagePull", "ImagePullBackOff"} {
+		if !cfg.FatalWaitingReasons[reason] {
+			t.Fatalf("expected %s to be fatal but got %v", reason, cfg.FatalWaitingReasons)
+		}
+	}
+}
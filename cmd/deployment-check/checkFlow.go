@@ -3,23 +3,138 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// cleanup removes the deployment and service created by the check.
+const (
+	// deleteWatchFallbackTimeout bounds each wait for a delete event before re-checking the cached object.
+	deleteWatchFallbackTimeout = time.Second * 30
+	// cleanupTimeout bounds pre-check cleanup and post-cleanup verification.
+	cleanupTimeout = time.Minute * 2
+	// deletePollInterval is the pause between lookups while waiting for a deleted object to disappear.
+	deletePollInterval = time.Second * 2
+)
+
+// cleanup removes everything the run created and confirms it is gone, on failure paths as well as after a pass.
 func (r *CheckRunner) cleanup(ctx context.Context) error {
-	// Track aggregated errors for cleanup.
+	// Delete the resources, reporting deletes that failed before looking for leftovers.
+	err := r.deleteResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Confirm nothing from this run was left behind.
+	r.phases.begin("cleanup_verify")
+	return r.verifyCleanup(ctx)
+}
+
+// deleteResources removes the deployment, service, and supporting objects created by the check.
+func (r *CheckRunner) deleteResources(ctx context.Context) error {
+	// Track aggregated errors and time the cleanup.
 	resultErr := ""
+	r.phases.begin("cleanup")
+	cleanupStart := time.Now()
+	defer func() { r.metrics.observe(metricCleanupDuration, time.Since(cleanupStart)) }()
 
-	// Delete the service first.
+	// Delete the ingress and route before the service they point to.
 	log.Infoln("Cleaning up deployment and service.")
-	serviceErr := r.deleteServiceAndWait(ctx)
-	if serviceErr != nil {
-		log.Errorln("Error cleaning up service:", serviceErr.Error())
-		resultErr = resultErr + "error cleaning up service: " + serviceErr.Error()
+	r.timeline.record("cleanup started")
+	if len(r.ephemeralNamespace) != 0 {
+		return r.cleanupNamespace(ctx)
+	}
+	if r.cfg.IngressVerify {
+		ingressErr := r.deleteIngressAndWait(ctx)
+		if ingressErr != nil {
+			log.Errorln("Error cleaning up ingress:", ingressErr.Error())
+			resultErr = resultErr + "error cleaning up ingress: " + ingressErr.Error()
+		}
+	}
+
+	if len(r.cfg.GatewayName) != 0 {
+		routeErr := r.deleteHTTPRouteAndWait(ctx)
+		if routeErr != nil {
+			log.Errorln("Error cleaning up HTTPRoute:", routeErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up HTTPRoute: " + routeErr.Error()
+		}
+	}
+
+	if r.cfg.ProberVerify {
+		proberErr := r.deleteProberPodAndWait(ctx)
+		if proberErr != nil {
+			log.Errorln("Error cleaning up prober pod:", proberErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up prober pod: " + proberErr.Error()
+		}
+	}
+
+	if r.cfg.OpenShiftRouteVerify {
+		routeErr := r.deleteOpenShiftRouteAndWait(ctx)
+		if routeErr != nil {
+			log.Errorln("Error cleaning up OpenShift Route:", routeErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up OpenShift Route: " + routeErr.Error()
+		}
+	}
+
+	// Delete the network policies alongside the other networking objects.
+	if r.cfg.NetworkPolicyVerify {
+		policyErr := r.deleteNetworkPoliciesAndWait(ctx)
+		if policyErr != nil {
+			log.Errorln("Error cleaning up network policies:", policyErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up network policies: " + policyErr.Error()
+		}
+	}
+
+	// Delete the autoscaler before it can react to the deployment going away.
+	if r.cfg.HPAVerify {
+		hpaErr := r.deleteHPAAndWait(ctx)
+		if hpaErr != nil {
+			log.Errorln("Error cleaning up horizontal pod autoscaler:", hpaErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up horizontal pod autoscaler: " + hpaErr.Error()
+		}
+	}
+
+	// Delete the disruption budget so it cannot hold up pod deletion.
+	if r.cfg.PDBVerify {
+		pdbErr := r.deletePDBAndWait(ctx)
+		if pdbErr != nil {
+			log.Errorln("Error cleaning up pod disruption budget:", pdbErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up pod disruption budget: " + pdbErr.Error()
+		}
+	}
+
+	// Delete the services next.
+	for _, name := range r.checkServiceNames() {
+		serviceErr := r.deleteServiceAndWait(ctx, name)
+		if serviceErr != nil {
+			log.Errorln("Error cleaning up service", name+":", serviceErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up service " + name + ": " + serviceErr.Error()
+		}
 	}
 
 	// Delete the deployment second.
@@ -32,19 +147,82 @@ func (r *CheckRunner) cleanup(ctx context.Context) error {
 		resultErr = resultErr + "error cleaning up deployment: " + deploymentErr.Error()
 	}
 
+	// Stop the mesh proxies so the terminating pods are not held open by them.
+	if r.cfg.MeshQuitOnCleanup {
+		r.quitMeshProxies(ctx)
+	}
+
+	// Make sure no pods are left stuck terminating on a bad node.
+	if deploymentErr == nil {
+		r.waitForPodsGone(ctx)
+	}
+
+	// Delete the claim once no pods mount it so its volume is reclaimed.
+	if len(r.cfg.PVCStorageClass) != 0 {
+		pvcErr := r.deletePVCAndWait(ctx)
+		if pvcErr != nil {
+			log.Errorln("Error cleaning up persistent volume claim:", pvcErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up persistent volume claim: " + pvcErr.Error()
+		}
+	}
+
+	// Delete the Certificate and its issued Secret once no pods mount it.
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		certificateErr := r.deleteCertificateAndWait(ctx)
+		if certificateErr != nil {
+			log.Errorln("Error cleaning up certificate:", certificateErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up certificate: " + certificateErr.Error()
+		}
+	}
+
+	// Delete the ConfigMap once no pods mount it.
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		configMapErr := r.deleteConfigMapAndWait(ctx)
+		if configMapErr != nil {
+			log.Errorln("Error cleaning up configmap:", configMapErr.Error())
+			if len(resultErr) != 0 {
+				resultErr = resultErr + " | "
+			}
+			resultErr = resultErr + "error cleaning up configmap: " + configMapErr.Error()
+		}
+	}
+
 	// Return a combined error if needed.
 	if len(resultErr) != 0 {
-		return fmt.Errorf("%s", resultErr)
+		r.timeline.record("cleanup failed: " + resultErr)
+		return classify(failureClassCleanup, fmt.Errorf("%s", resultErr))
 	}
 
 	log.Infoln("Finished clean up process.")
+	r.timeline.record("cleanup finished")
+	return nil
+}
+
+// cleanupNamespace removes everything the run created by deleting its ephemeral namespace.
+func (r *CheckRunner) cleanupNamespace(ctx context.Context) error {
+	// Deleting the namespace takes every resource in it along.
+	err := r.deleteNamespaceAndWait(ctx, r.ephemeralNamespace)
+	if err != nil {
+		log.Errorln("Error cleaning up namespace:", err.Error())
+		r.timeline.record("cleanup failed: " + err.Error())
+		return classify(failureClassCleanup, fmt.Errorf("error cleaning up namespace %s: %w", r.ephemeralNamespace, err))
+	}
+
+	log.Infoln("Finished clean up process.")
+	r.timeline.record("cleanup finished")
 	return nil
 }
 
 // cleanupOrphans removes stale resources before starting a new run.
 func (r *CheckRunner) cleanupOrphans(ctx context.Context) error {
 	// Bound the cleanup with a timeout to avoid hanging.
-	cleanupTimeout := time.After(time.Minute * 2)
+	cleanupDeadline := time.After(cleanupTimeout)
 
 	// Find any previous resources created by this check.
 	serviceExists, err := r.findPreviousService(ctx)
@@ -61,20 +239,164 @@ func (r *CheckRunner) cleanupOrphans(ctx context.Context) error {
 	if deploymentExists {
 		log.Infoln("Found previous deployment.")
 	}
+	ingressFound := false
+	if r.cfg.IngressVerify {
+		ingressFound, err = r.ingressExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous ingress:", err.Error())
+		}
+		if ingressFound {
+			log.Infoln("Found previous ingress.")
+		}
+	}
+	routeFound := false
+	if len(r.cfg.GatewayName) != 0 {
+		routeFound, err = r.httpRouteExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous HTTPRoute:", err.Error())
+		}
+		if routeFound {
+			log.Infoln("Found previous HTTPRoute.")
+		}
+	}
+	openShiftRouteFound := false
+	if r.cfg.OpenShiftRouteVerify {
+		openShiftRouteFound, err = r.openShiftRouteExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous OpenShift Route:", err.Error())
+		}
+		if openShiftRouteFound {
+			log.Infoln("Found previous OpenShift Route.")
+		}
+	}
+	policyFound := false
+	if r.cfg.NetworkPolicyVerify {
+		policyFound, err = r.networkPoliciesExist(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous network policies:", err.Error())
+		}
+		if policyFound {
+			log.Infoln("Found previous network policies.")
+		}
+	}
+	hpaFound := false
+	if r.cfg.HPAVerify {
+		hpaFound, err = r.hpaExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous horizontal pod autoscaler:", err.Error())
+		}
+		if hpaFound {
+			log.Infoln("Found previous horizontal pod autoscaler.")
+		}
+	}
+	pdbFound := false
+	if r.cfg.PDBVerify {
+		pdbFound, err = r.pdbExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous pod disruption budget:", err.Error())
+		}
+		if pdbFound {
+			log.Infoln("Found previous pod disruption budget.")
+		}
+	}
+	configMapFound := false
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		configMapFound, err = r.configMapExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous configmap:", err.Error())
+		}
+		if configMapFound {
+			log.Infoln("Found previous configmap.")
+		}
+	}
+	pvcFound := false
+	if len(r.cfg.PVCStorageClass) != 0 {
+		pvcFound, err = r.pvcExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous persistent volume claim:", err.Error())
+		}
+		if pvcFound {
+			log.Infoln("Found previous persistent volume claim.")
+		}
+	}
+	proberFound := false
+	if r.cfg.ProberVerify {
+		proberFound, err = r.proberPodExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous prober pod:", err.Error())
+		}
+		if proberFound {
+			log.Infoln("Found previous prober pod.")
+		}
+	}
+	certificateFound := false
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		certificateFound, err = r.certificateExists(ctx)
+		if err != nil {
+			log.Warnln("Failed to find previous certificate:", err.Error())
+		}
+		if certificateFound {
+			log.Infoln("Found previous certificate.")
+		}
+	}
 
 	// Clean up if anything was found.
-	if serviceExists || deploymentExists {
+	if serviceExists || deploymentExists || ingressFound || routeFound || openShiftRouteFound || policyFound || hpaFound || pdbFound || configMapFound || pvcFound || certificateFound || proberFound {
+		// Surface the orphans according to the configured policy.
+		orphans := fmt.Sprintf("deployment found: %t, service found: %t", deploymentExists, serviceExists)
+		if r.cfg.IngressVerify {
+			orphans = orphans + fmt.Sprintf(", ingress found: %t", ingressFound)
+		}
+		if len(r.cfg.GatewayName) != 0 {
+			orphans = orphans + fmt.Sprintf(", httproute found: %t", routeFound)
+		}
+		if r.cfg.OpenShiftRouteVerify {
+			orphans = orphans + fmt.Sprintf(", openshift route found: %t", openShiftRouteFound)
+		}
+		if r.cfg.NetworkPolicyVerify {
+			orphans = orphans + fmt.Sprintf(", network policy found: %t", policyFound)
+		}
+		if r.cfg.HPAVerify {
+			orphans = orphans + fmt.Sprintf(", horizontal pod autoscaler found: %t", hpaFound)
+		}
+		if r.cfg.PDBVerify {
+			orphans = orphans + fmt.Sprintf(", pod disruption budget found: %t", pdbFound)
+		}
+		if len(r.cfg.ConfigMapMountPath) != 0 {
+			orphans = orphans + fmt.Sprintf(", configmap found: %t", configMapFound)
+		}
+		if len(r.cfg.PVCStorageClass) != 0 {
+			orphans = orphans + fmt.Sprintf(", persistent volume claim found: %t", pvcFound)
+		}
+		if len(r.cfg.CertManagerIssuer) != 0 {
+			orphans = orphans + fmt.Sprintf(", certificate found: %t", certificateFound)
+		}
+		if r.cfg.ProberVerify {
+			orphans = orphans + fmt.Sprintf(", prober pod found: %t", proberFound)
+		}
+		r.timeline.record("found orphaned resources from a previous run: " + orphans)
+		if r.cfg.OrphanPolicy == orphanPolicyWarn || r.cfg.OrphanPolicy == orphanPolicyFail {
+			log.Warnln("Found orphaned resources from a previous run, which suggests it did not finish cleanly:", orphans)
+		}
+
 		log.Infoln("Wiping all found orphaned resources belonging to this check.")
 		cleanupDone := make(chan error, 1)
 		go r.runCleanupAsync(ctx, cleanupDone)
 
 		select {
 		case cleanupErr := <-cleanupDone:
-			return cleanupErr
+			if cleanupErr != nil {
+				return cleanupErr
+			}
 		case <-ctx.Done():
-			return fmt.Errorf("failed to perform pre-check cleanup within timeout")
-		case <-cleanupTimeout:
-			return fmt.Errorf("failed to perform pre-check cleanup within timeout")
+			return classify(failureClassCleanup, fmt.Errorf("failed to perform pre-check cleanup within timeout"))
+		case <-cleanupDeadline:
+			return classify(failureClassCleanup, fmt.Errorf("failed to perform pre-check cleanup within timeout"))
+		}
+
+		// Treat the orphans as a failure of the previous run when requested.
+		if r.cfg.OrphanPolicy == orphanPolicyFail {
+			return classify(failureClassCleanup, fmt.Errorf("found orphaned resources from a previous run (%s); they were removed but the previous run did not clean up", orphans))
 		}
 	}
 
@@ -82,6 +404,101 @@ func (r *CheckRunner) cleanupOrphans(ctx context.Context) error {
 	return nil
 }
 
+// cleanupOnly reports and removes check resources left behind by earlier runs, then confirms they are gone.
+func (r *CheckRunner) cleanupOnly(ctx context.Context) error {
+	// Ephemeral namespaces hold everything the check created, so deleting them is the whole cleanup.
+	if r.cfg.CreateNamespace {
+		return r.cleanupCheckNamespaces(ctx)
+	}
+
+	// Report what was left behind before removing it.
+	deploymentExists, err := r.findPreviousDeployment(ctx)
+	if err != nil {
+		log.Warnln("Failed to find previous deployment:", err.Error())
+	}
+	serviceExists, err := r.findPreviousService(ctx)
+	if err != nil {
+		log.Warnln("Failed to find previous service:", err.Error())
+	}
+	log.Infoln("Cleanup-only mode in namespace", r.cfg.CheckNamespace+":", fmt.Sprintf("deployment found: %t, service found: %t", deploymentExists, serviceExists))
+
+	// Remove and verify everything the configuration would have created.
+	err = r.cleanup(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Reclaim leftovers from runs under other names too.
+	if r.cfg.StaleResourceAge > 0 || r.cfg.ResourceTTL > 0 {
+		r.cleanupStaleResources(ctx)
+	}
+
+	return nil
+}
+
+// cleanupCheckNamespaces deletes every ephemeral namespace created for this check and waits for them to go away.
+func (r *CheckRunner) cleanupCheckNamespaces(ctx context.Context) error {
+	// Find the namespaces by label.
+	names, err := r.listCheckNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Cleanup-only mode found", len(names), "check namespace(s).")
+
+	// Delete each one, collecting failures.
+	failures := make([]string, 0)
+	for _, name := range names {
+		err = r.deleteNamespaceAndWait(ctx, name)
+		if err != nil {
+			log.Errorln("Error cleaning up namespace", name+":", err.Error())
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) != 0 {
+		return classify(failureClassCleanup, fmt.Errorf("%s", strings.Join(failures, " | ")))
+	}
+
+	return nil
+}
+
+// deleteAndWait issues a delete, tolerating an object that was never created, and polls until it is gone.
+func deleteAndWait(ctx context.Context, kind string, remove func(context.Context) error, exists func(context.Context) (bool, error)) error {
+	// Issue the delete.
+	err := remove(ctx)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s: %w", kind, err)
+	}
+
+	// Poll until the object is gone, surfacing lookups that fail for any other reason.
+	for {
+		found, err := exists(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s while waiting for it to delete: %w", kind, err)
+		}
+		if !found {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out while waiting for %s to delete", kind)
+		case <-time.After(deletePollInterval):
+		}
+	}
+}
+
+// objectFound turns the error from a Get into whether the object is present.
+func objectFound(err error) (bool, error) {
+	// A missing object is not an error.
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // runCleanupAsync performs cleanup work in a goroutine.
 func (r *CheckRunner) runCleanupAsync(ctx context.Context, resultChan chan<- error) {
 	// Run cleanup and forward the result.
@@ -89,17 +506,45 @@ func (r *CheckRunner) runCleanupAsync(ctx context.Context, resultChan chan<- err
 	resultChan <- cleanupErr
 }
 
-// rollDeploymentAndVerify performs the rolling update and validates the service again.
+// rollDeploymentAndVerify walks the deployment through each roll image and validates the service after every step.
 func (r *CheckRunner) rollDeploymentAndVerify(ctx context.Context) error {
 	// Compute the deadline for rollout operations.
 	deadline := time.Now().Add(r.cfg.CheckTimeLimit)
 
-	// Update the deployment with the new image.
-	updatedDeployment, err := r.updateDeploymentAndWait(ctx, deadline)
+	// Roll to each image in turn, verifying before moving on.
+	rolloutStart := time.Now()
+	steps := len(r.cfg.CheckImageRollSequence)
+	for i, image := range r.cfg.CheckImageRollSequence {
+		updatedDeployment, err := r.updateDeploymentAndWait(ctx, deadline, image)
+		if err == nil {
+			log.Infoln("Rolled deployment in", updatedDeployment.Namespace, "namespace:", updatedDeployment.Name)
+			err = r.verifyRolledDeployment(ctx, updatedDeployment, image)
+		}
+		if err != nil && steps > 1 {
+			return fmt.Errorf("rolling update step %d of %d to %s failed: %w", i+1, steps, image, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	r.metrics.observe(metricRolloutDuration, time.Since(rolloutStart))
+
+	return nil
+}
+
+// verifyRolledDeployment confirms a finished rollout runs the image, drained old pods, and still serves traffic.
+func (r *CheckRunner) verifyRolledDeployment(ctx context.Context, deployment *appsv1.Deployment, image string) error {
+	// Confirm the pods actually run the new image rather than trusting status counters.
+	err := r.verifyRolledPodImages(ctx, image, "")
 	if err != nil {
-		return err
+		return classify(failureClassRollout, err)
+	}
+
+	// Confirm the previous ReplicaSet fully drained.
+	err = r.verifyOldReplicaSetsDrained(ctx, deployment)
+	if err != nil {
+		return classify(failureClassRollout, err)
 	}
-	log.Infoln("Rolled deployment in", updatedDeployment.Namespace, "namespace:", updatedDeployment.Name)
 
 	// Fetch the service cluster IP.
 	service, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
@@ -111,7 +556,7 @@ func (r *CheckRunner) rollDeploymentAndVerify(ctx context.Context) error {
 		return err
 	}
 
-	// Validate the service endpoint after rolling update.
-	log.Infoln("Rolling update completed. Validating service endpoint again.")
-	return r.requestServiceEndpoint(ctx, serviceIP)
+	// Validate the service endpoint after the rollout.
+	log.Infoln("Rollout completed. Validating service endpoint again.")
+	return classify(failureClassNetworking, r.validateServicePorts(ctx, serviceIP))
 }
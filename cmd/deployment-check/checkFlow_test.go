@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// TestDeleteAndWait validates the delete removes the object and the wait finishes once it is gone.
+func TestDeleteAndWait(t *testing.T) {
+	// Seed the generated ConfigMap.
+	runner := buildTestRunner()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.checkConfigMapName(), Namespace: runner.cfg.CheckNamespace}}
+	runner.client = fake.NewClientset(configMap)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.deleteConfigMapAndWait(ctx)
+	if err != nil {
+		t.Fatalf("expected the wait to finish but got %v", err)
+	}
+
+	// A second call tolerates the missing object.
+	err = runner.deleteConfigMapAndWait(ctx)
+	if err != nil {
+		t.Fatalf("expected a missing object to finish the wait but got %v", err)
+	}
+}
+
+// TestDeleteAndWaitLookupError validates a failing lookup is returned instead of polled until the deadline.
+func TestDeleteAndWaitLookupError(t *testing.T) {
+	// Fail every ConfigMap lookup.
+	runner := buildTestRunner()
+	client := fake.NewClientset()
+	client.PrependReactor("get", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	runner.client = client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.deleteConfigMapAndWait(ctx)
+	if err == nil || !strings.Contains(err.Error(), "forbidden") || ctx.Err() != nil {
+		t.Fatalf("expected the lookup error before the deadline but got %v", err)
+	}
+}
+
+// TestCleanupOrphansPolicy validates orphans are always removed and only fail the run under the fail policy.
+func TestCleanupOrphansPolicy(t *testing.T) {
+	for _, policy := range []string{orphanPolicyClean, orphanPolicyWarn, orphanPolicyFail} {
+		// Seed a service left behind by a previous run.
+		runner := buildTestRunner()
+		runner.cfg.OrphanPolicy = policy
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckServiceName, Namespace: runner.cfg.CheckNamespace}}
+		runner.client = fake.NewClientset(service)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		err := runner.cleanupOrphans(ctx)
+		cancel()
+		if policy == orphanPolicyFail {
+			if err == nil || !strings.Contains(err.Error(), "found orphaned resources from a previous run") || classifyFailure(err) != failureClassCleanup {
+				t.Fatalf("expected a cleanup failure under the %s policy but got %v", policy, err)
+			}
+		}
+		if policy != orphanPolicyFail && err != nil {
+			t.Fatalf("expected the run to continue under the %s policy but got %v", policy, err)
+		}
+
+		// The orphan is removed under every policy.
+		services, listErr := runner.client.CoreV1().Services(runner.cfg.CheckNamespace).List(context.Background(), metav1.ListOptions{})
+		if listErr != nil || len(services.Items) != 0 {
+			t.Fatalf("expected the orphaned service to be removed under the %s policy but got %v (%v)", policy, services, listErr)
+		}
+	}
+}
+
+// TestCleanupOnly validates leftover check resources are removed without starting a check.
+func TestCleanupOnly(t *testing.T) {
+	// Seed the deployment and service an interrupted run left behind.
+	runner := buildTestRunner()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckDeploymentName, Namespace: runner.cfg.CheckNamespace}}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckServiceName, Namespace: runner.cfg.CheckNamespace}}
+	client := fake.NewClientset(deployment, service)
+	runner.client = client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	err := runner.cleanupOnly(ctx)
+	if err != nil {
+		t.Fatalf("expected the leftovers to be cleaned up but got %v", err)
+	}
+
+	// Both are gone and nothing new was created.
+	deployments, err := client.AppsV1().Deployments(runner.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil || len(deployments.Items) != 0 {
+		t.Fatalf("expected no deployments to remain but got %v (%v)", deployments, err)
+	}
+	services, err := client.CoreV1().Services(runner.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil || len(services.Items) != 0 {
+		t.Fatalf("expected no services to remain but got %v (%v)", services, err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" {
+			t.Fatalf("expected cleanup-only mode to create nothing but it created a %s", action.GetResource().Resource)
+		}
+	}
+}
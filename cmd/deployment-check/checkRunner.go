@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 // CheckRunner bundles dependencies and configuration for running the deployment check.
@@ -13,62 +18,364 @@ type CheckRunner struct {
 	// cfg stores the parsed check configuration.
 	cfg *CheckConfig
 	// client provides typed Kubernetes API access.
-	client *kubernetes.Clientset
+	client kubernetes.Interface
+	// restConfig is the client configuration used for streaming requests such as exec.
+	restConfig *rest.Config
 	// now pins a timestamp for resource labeling during a run.
 	now time.Time
+	// timeline records notable observations for failure reports.
+	timeline *runTimeline
+	// transport carries every HTTP request made to the check endpoints.
+	transport http.RoundTripper
+	// metrics records phase durations for the metrics endpoint.
+	metrics *runMetrics
+	// phases tracks how long each phase of the run took.
+	phases *phaseTimer
+	// owner is the checker pod that owns created resources, or nil.
+	owner *metav1.OwnerReference
+	// pods caches the current run's pods for waits that would otherwise poll.
+	pods podInformer
+	// podSummary holds the pod status captured when a deployment failure was decorated.
+	podSummary string
+	// ephemeralNamespace is the namespace created for this run, or empty.
+	ephemeralNamespace string
 }
 
 // newCheckRunner builds a runner with configuration and Kubernetes access.
-func newCheckRunner(cfg *CheckConfig, client *kubernetes.Clientset, now time.Time) *CheckRunner {
+func newCheckRunner(cfg *CheckConfig, client kubernetes.Interface, restConfig *rest.Config, now time.Time) *CheckRunner {
 	// Assemble the runner that will execute the check steps.
 	return &CheckRunner{
-		cfg:    cfg,
-		client: client,
-		now:    now,
+		cfg:        cfg,
+		client:     client,
+		restConfig: restConfig,
+		now:        now,
+		timeline:   newRunTimeline(now),
+		transport:  newEndpointTransport(cfg),
+		metrics:    newRunMetrics(),
+		phases:     &phaseTimer{},
 	}
 }
 
 // run executes the full deployment check flow and reports back to Kuberhealthy.
 func (r *CheckRunner) run(ctx context.Context) error {
+	// Stop any informers the waits started once the run ends.
+	defer r.stopInformers()
+
 	// Wait for Kuberhealthy to accept reports before doing any work.
+	r.phases.begin("kuberhealthy_wait")
 	err := r.waitForKuberhealthyReady(ctx)
 	if err != nil {
 		return err
 	}
 
+	// Move the run into a namespace of its own when requested.
+	if r.cfg.CreateNamespace {
+		r.phases.begin("namespace_create")
+		err = r.createEphemeralNamespace(ctx)
+		if err != nil {
+			return classify(failureClassAdmission, fmt.Errorf("namespace create failed: %w", err))
+		}
+	}
+
 	// Clear any leftovers from prior runs.
+	r.phases.begin("orphan_check")
 	err = r.cleanupOrphans(ctx)
 	if err != nil {
 		return err
 	}
 
+	// Reclaim leftovers from runs under any name once they are old enough or expired.
+	if r.cfg.StaleResourceAge > 0 || r.cfg.ResourceTTL > 0 {
+		r.cleanupStaleResources(ctx)
+	}
+
+	// Let garbage collection remove the resources if the checker pod dies before cleanup.
+	if r.cfg.OwnerReference {
+		err = r.resolveCheckerOwner(ctx)
+		if err != nil {
+			log.Warnln("Creating check resources without an owner reference:", err.Error())
+		}
+	}
+
 	// Capture the run deadline for create/update monitoring.
 	deadline := time.Now().Add(r.cfg.CheckTimeLimit)
 
+	// Snapshot existing nodes so newly provisioned capacity can be identified.
+	var existingNodes map[string]bool
+	if r.cfg.AutoscalerMode || r.cfg.KarpenterMode {
+		existingNodes, err = r.snapshotNodeNames(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Confirm the namespace quota has room for the run before creating anything.
+	if r.cfg.QuotaPreflight {
+		r.phases.begin("quota_preflight")
+		err = r.verifyQuotaHeadroom(ctx)
+		if err != nil {
+			return classify(failureClassAdmission, fmt.Errorf("resource quota preflight failed: %w", err))
+		}
+	}
+
+	// Follow the check pod logs in debug mode so startup failures are visible before cleanup.
+	if r.cfg.Debug {
+		streamCtx, stopStreaming := context.WithCancel(ctx)
+		defer stopStreaming()
+		go r.streamPodLogs(streamCtx)
+	}
+
+	// Open the readiness gate on check pods for the whole run, since rollouts and scaling create new pods.
+	var gate *readinessGateController
+	if len(r.cfg.ReadinessGate) != 0 {
+		gateCtx, stopGate := context.WithCancel(ctx)
+		defer stopGate()
+		gate = newReadinessGateController()
+		go r.runReadinessGateController(gateCtx, gate)
+	}
+
+	// Create the ConfigMap the check pods mount before they are scheduled.
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		r.phases.begin("configmap_create")
+		err = r.createConfigMap(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("configmap creation failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("configmap creation failed: %w", err)
+		}
+	}
+
+	// Create the claim the check pods mount so provisioning is exercised.
+	pvcCreated := time.Now()
+	if len(r.cfg.PVCStorageClass) != 0 {
+		r.phases.begin("pvc_create")
+		err = r.createPVC(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassStorage, fmt.Errorf("persistent volume claim creation failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassStorage, fmt.Errorf("persistent volume claim creation failed: %w", err))
+		}
+	}
+
+	// Issue the certificate the check pods mount before they are scheduled.
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		r.phases.begin("certificate_issue")
+		err = r.issueCertificate(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassCertificate, fmt.Errorf("certificate issuance failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassCertificate, fmt.Errorf("certificate issuance failed: %w", err))
+		}
+	}
+
 	// Create a deployment for the check.
+	r.phases.begin("deployment_create")
+	createStart := time.Now()
 	deploymentResult, err := r.createDeploymentAndWait(ctx, deadline)
 	if err != nil {
 		return err
 	}
+	r.metrics.observe(metricDeploymentCreateDuration, time.Since(createStart))
+
+	// Confirm the pods only turned Ready once the checker opened their readiness gate.
+	if gate != nil {
+		r.phases.begin("readiness_gate_verify")
+		err = r.verifyReadinessGate(ctx, gate)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassRollout, fmt.Errorf("readiness gate verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassRollout, fmt.Errorf("readiness gate verification failed: %w", err))
+		}
+	}
+
+	// Confirm the pods run the configured image and pinned digest rather than a rewritten one.
+	if r.cfg.ImageVerify {
+		r.phases.begin("image_verify")
+		err = r.verifyRolledPodImages(ctx, r.cfg.CheckImageURL, r.cfg.ImageDigest)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassImage, fmt.Errorf("image verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassImage, fmt.Errorf("image verification failed: %w", err))
+		}
+	}
+
+	// Confirm the mesh proxy was injected, or left out, as configured.
+	if len(r.cfg.MeshMode) != 0 {
+		r.phases.begin("mesh_verify")
+		err = r.verifyMeshInjection(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassAdmission, fmt.Errorf("mesh verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassAdmission, fmt.Errorf("mesh verification failed: %w", err))
+		}
+	}
+
+	// Confirm the sidecar runs ready next to the check container.
+	if len(r.cfg.SidecarImage) != 0 {
+		r.phases.begin("sidecar_verify")
+		err = r.verifySidecarReady(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassRollout, fmt.Errorf("sidecar verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassRollout, fmt.Errorf("sidecar verification failed: %w", err))
+		}
+	}
+
+	// Confirm the claim bound to a dynamically provisioned volume.
+	if len(r.cfg.PVCStorageClass) != 0 {
+		r.phases.begin("pvc_verify")
+		err = r.verifyPVCBound(ctx, pvcCreated)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassStorage, fmt.Errorf("persistent volume claim verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassStorage, fmt.Errorf("persistent volume claim verification failed: %w", err))
+		}
+	}
+
+	// Confirm the mounted volumes are usable from inside the pods.
+	if r.cfg.VolumeVerify {
+		r.phases.begin("volume_verify")
+		err = r.verifyVolumes(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassRollout, fmt.Errorf("volume verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassRollout, fmt.Errorf("volume verification failed: %w", err))
+		}
+	}
+
+	// Confirm the projected token reached the pods for the configured audience.
+	if len(r.cfg.ProjectedTokenAudience) != 0 {
+		r.phases.begin("projected_token_verify")
+		err = r.verifyProjectedToken(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassAdmission, fmt.Errorf("projected token verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassAdmission, fmt.Errorf("projected token verification failed: %w", err))
+		}
+	}
+
+	// Confirm the pods landed on freshly provisioned capacity.
+	if r.cfg.AutoscalerMode || r.cfg.KarpenterMode {
+		r.phases.begin("provisioning_verify")
+		err = r.verifyProvisioning(ctx, existingNodes, createStart)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassScheduling, fmt.Errorf("node provisioning verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassScheduling, fmt.Errorf("node provisioning verification failed: %w", err))
+		}
+	}
+
+	// Confirm the pods landed across the configured topology domains.
+	if len(r.cfg.TopologySpreadConstraints) != 0 {
+		r.phases.begin("topology_spread_verify")
+		err = r.verifyTopologySpread(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassScheduling, fmt.Errorf("topology spread verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassScheduling, fmt.Errorf("topology spread verification failed: %w", err))
+		}
+	}
+
+	// Confirm the pods did not all land in too few zones.
+	if r.cfg.MinZones > 0 {
+		r.phases.begin("zone_verify")
+		err = r.verifyZoneDistribution(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassScheduling, fmt.Errorf("zone distribution verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassScheduling, fmt.Errorf("zone distribution verification failed: %w", err))
+		}
+	}
+
+	// Confirm the workload itself can resolve cluster DNS when requested.
+	if r.cfg.PodDNSVerify {
+		r.phases.begin("pod_dns_verify")
+		err = r.verifyPodDNS(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return classify(failureClassNetworking, fmt.Errorf("in-pod DNS verification failed: %w; cleanup error: %w", err, cleanupErr))
+			}
+			return classify(failureClassNetworking, fmt.Errorf("in-pod DNS verification failed: %w", err))
+		}
+	}
 
 	// Create a service for the deployment.
-	serviceResult, err := r.createServiceAndWait(ctx, deploymentResult.Spec.Template.Labels)
+	r.phases.begin("service_create")
+	serviceStart := time.Now()
+	serviceResult, err := r.createServiceAndWait(ctx, deploymentResult.Spec.Selector.MatchLabels)
 	if err != nil {
 		cleanupErr := r.cleanup(ctx)
 		if cleanupErr != nil {
-			return fmt.Errorf("service creation failed: %w; cleanup error: %w", err, cleanupErr)
+			return classify(failureClassNetworking, fmt.Errorf("service creation failed: %w; cleanup error: %w", err, cleanupErr))
 		}
-		return fmt.Errorf("service creation failed: %w", err)
+		return classify(failureClassNetworking, fmt.Errorf("service creation failed: %w", err))
 	}
 
 	// Fetch the service IP that will be used for HTTP checks.
 	serviceIP, err := r.getServiceClusterIP(ctx, serviceResult)
 	if err != nil {
-		return fmt.Errorf("service lookup failed: %w", err)
+		return classify(failureClassNetworking, fmt.Errorf("service lookup failed: %w", err))
 	}
 
-	// Validate a 200 response from the service.
-	err = r.requestServiceEndpoint(ctx, serviceIP)
+	// Reach every pod on its IP first so pod network failures are not blamed on the service.
+	if r.cfg.PodIPVerify {
+		r.phases.begin("pod_ip_verify")
+		err = classify(failureClassNetworking, r.verifyPodIPs(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("pod IP connectivity failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("pod IP connectivity failed: %w", err)
+		}
+	}
+
+	// Require the endpoint controller to publish every ready replica before the retries can mask a lag.
+	if r.cfg.EndpointSliceVerify {
+		r.phases.begin("endpoint_slice_verify")
+		err = classify(failureClassNetworking, r.verifyEndpointSlices(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("endpoint slice verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("endpoint slice verification failed: %w", err)
+		}
+	}
+
+	// Validate a 200 response from every service port.
+	r.phases.begin("service_validate")
+	err = classify(failureClassNetworking, r.validateServicePorts(ctx, serviceIP))
+	if err != nil && r.cfg.EndpointDiagnostics {
+		err = r.diagnoseServiceFailure(ctx, err)
+	}
 	if err != nil {
 		cleanupErr := r.cleanup(ctx)
 		if cleanupErr != nil {
@@ -76,20 +383,256 @@ func (r *CheckRunner) run(ctx context.Context) error {
 		}
 		return fmt.Errorf("service request failed: %w", err)
 	}
+	r.metrics.observe(metricHTTPFirstSuccess, time.Since(serviceStart))
+
+	// Require every ready replica to serve traffic, not just whichever one answered first.
+	if r.cfg.EveryReplicaVerify {
+		r.phases.begin("replica_traffic_verify")
+		err = classify(failureClassNetworking, r.verifyEveryReplicaServes(ctx, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("replica traffic verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("replica traffic verification failed: %w", err)
+		}
+	}
+
+	// Verify kube-proxy keeps a client on one backend.
+	if r.cfg.SessionAffinityVerify {
+		r.phases.begin("session_affinity_verify")
+		err = classify(failureClassNetworking, r.verifySessionAffinity(ctx, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("session affinity verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("session affinity verification failed: %w", err)
+		}
+	}
+
+	// Load the service to catch intermittent data-plane failures a single request never sees.
+	if r.cfg.HTTPRequestCount > 0 {
+		r.phases.begin("load_test")
+		err = classify(failureClassNetworking, r.runLoadTest(ctx, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("load test failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("load test failed: %w", err)
+		}
+	}
+
+	// Request the service from another node for cross-node reachability.
+	if r.cfg.ProberVerify {
+		r.phases.begin("prober_verify")
+		err = classify(failureClassNetworking, r.verifyFromProber(ctx, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("prober verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("prober verification failed: %w", err)
+		}
+	}
+
+	// Require the service to serve the issued certificate over HTTPS.
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		r.phases.begin("certificate_verify")
+		err = classify(failureClassCertificate, r.verifyCertificate(ctx, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("certificate verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("certificate verification failed: %w", err)
+		}
+	}
+
+	// Verify the service name resolves to its cluster IP through cluster DNS.
+	if r.cfg.ServiceDNSVerify {
+		r.phases.begin("service_dns_verify")
+		err = classify(failureClassDNS, r.verifyServiceDNS(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("service DNS verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("service DNS verification failed: %w", err)
+		}
+	}
+
+	// Verify CoreDNS publishes per-pod records for a headless service.
+	if r.cfg.HeadlessServiceVerify {
+		r.phases.begin("headless_dns_verify")
+		err = classify(failureClassNetworking, r.verifyHeadlessDNS(ctx, deploymentResult.Spec.Selector.MatchLabels))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("headless service DNS verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("headless service DNS verification failed: %w", err)
+		}
+	}
+
+	// Validate the allocated node ports from outside the pod network.
+	if r.cfg.CheckServiceType == corev1.ServiceTypeNodePort {
+		r.phases.begin("node_port_validate")
+		err = classify(failureClassNetworking, r.validateNodePorts(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("node port request failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("node port request failed: %w", err)
+		}
+	}
+
+	// Validate the cloud load balancer end to end.
+	if r.cfg.CheckServiceType == corev1.ServiceTypeLoadBalancer {
+		r.phases.begin("load_balancer_validate")
+		err = classify(failureClassNetworking, r.validateLoadBalancer(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("load balancer request failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("load balancer request failed: %w", err)
+		}
+	}
+
+	// Validate traffic through the ingress controller.
+	if r.cfg.IngressVerify {
+		r.phases.begin("ingress_verify")
+		err = classify(failureClassNetworking, r.verifyIngress(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("ingress verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("ingress verification failed: %w", err)
+		}
+	}
+
+	// Validate traffic through a Gateway API HTTPRoute.
+	if len(r.cfg.GatewayName) != 0 {
+		r.phases.begin("gateway_route_verify")
+		err = classify(failureClassNetworking, r.verifyGatewayRoute(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("gateway route verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("gateway route verification failed: %w", err)
+		}
+	}
+
+	// Validate traffic through the OpenShift router.
+	if r.cfg.OpenShiftRouteVerify {
+		r.phases.begin("openshift_route_verify")
+		err = classify(failureClassNetworking, r.verifyOpenShiftRoute(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("openshift route verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("openshift route verification failed: %w", err)
+		}
+	}
+
+	// Verify the CNI enforces network policies for the check pods.
+	if r.cfg.NetworkPolicyVerify {
+		r.phases.begin("network_policy_verify")
+		err = classify(failureClassNetworking, r.verifyNetworkPolicy(ctx, deploymentResult.Spec.Selector.MatchLabels, serviceIP))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("network policy verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("network policy verification failed: %w", err)
+		}
+	}
+
+	// Verify the deployment controller's scaling path.
+	if r.cfg.ScaleReplicas > 0 {
+		r.phases.begin("scale_verify")
+		err = classify(failureClassRollout, r.verifyScaling(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("scale verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("scale verification failed: %w", err)
+		}
+	}
+
+	// Verify the metrics pipeline drives horizontal pod autoscaling.
+	if r.cfg.HPAVerify {
+		r.phases.begin("hpa_verify")
+		err = classify(failureClassRollout, r.verifyHPA(ctx))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("horizontal pod autoscaler verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("horizontal pod autoscaler verification failed: %w", err)
+		}
+	}
+
+	// Verify the eviction API honors a pod disruption budget.
+	if r.cfg.PDBVerify {
+		r.phases.begin("pdb_verify")
+		err = classify(failureClassRollout, r.verifyPodDisruption(ctx, deploymentResult.Spec.Selector.MatchLabels))
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("pod disruption budget verification failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("pod disruption budget verification failed: %w", err)
+		}
+	}
 
 	// Handle optional rolling updates.
 	if r.cfg.RollingUpdate {
+		r.phases.begin("rolling_update")
 		err = r.rollDeploymentAndVerify(ctx)
 		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("rolling update failed: %w; cleanup error: %w", err, cleanupErr)
+			}
 			return err
 		}
 	}
 
-	// Clean up resources after a successful run.
-	err = r.cleanup(ctx)
-	if err != nil {
-		return err
+	// Roll back to the original revision and confirm it serves again.
+	if r.cfg.RollbackVerify {
+		r.phases.begin("rollback")
+		err = r.rollbackDeploymentAndVerify(ctx)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("rollback failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+	}
+
+	// Keep the deployment up under validation to catch delayed degradation.
+	if r.cfg.SoakDuration > 0 {
+		r.phases.begin("soak")
+		err = r.soakDeployment(ctx, serviceIP)
+		if err != nil {
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return fmt.Errorf("soak failed: %w; cleanup error: %w", err, cleanupErr)
+			}
+			return fmt.Errorf("soak failed: %w", err)
+		}
 	}
 
-	return nil
+	// Clean up and verify resources after a successful run.
+	r.phases.markPassed()
+	return r.cleanup(ctx)
 }
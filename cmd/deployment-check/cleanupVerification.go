@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// verifyCleanup confirms every resource created by this run is gone, reporting whatever lingers.
+func (r *CheckRunner) verifyCleanup(ctx context.Context) error {
+	// Poll until nothing remains or the cleanup timeout passes.
+	deadline := time.Now().Add(cleanupTimeout)
+	var lingering []string
+	for {
+		var err error
+		lingering, err = r.lingeringResources(ctx)
+		if err != nil {
+			log.WithError(err).Warnln("Failed to look up resources while verifying cleanup.")
+		}
+		if err == nil && len(lingering) == 0 {
+			log.Infoln("Verified that all check resources are gone.")
+			r.timeline.record("verified cleanup")
+			return nil
+		}
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			break
+		}
+
+		// Sleep briefly to avoid hammering the API.
+		time.Sleep(time.Second * 2)
+	}
+
+	// Report what is still present.
+	if len(lingering) == 0 {
+		return classify(failureClassCleanup, fmt.Errorf("could not verify cleanup within %s", cleanupTimeout))
+	}
+	return classify(failureClassCleanup, fmt.Errorf("resources still present %s after cleanup: %s", cleanupTimeout, strings.Join(lingering, ", ")))
+}
+
+// lingeringResources lists the resources from this run that still exist.
+func (r *CheckRunner) lingeringResources(ctx context.Context) ([]string, error) {
+	// An ephemeral namespace takes everything in it along, so only the namespace itself can linger.
+	lingering := make([]string, 0)
+	if len(r.ephemeralNamespace) != 0 {
+		namespaceFound, err := r.namespaceExists(ctx, r.ephemeralNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace: %w", err)
+		}
+		if namespaceFound {
+			lingering = append(lingering, "namespace "+r.ephemeralNamespace)
+		}
+		return lingering, nil
+	}
+
+	// Look for the deployment.
+	_, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	if err == nil {
+		lingering = append(lingering, "deployment "+r.cfg.CheckDeploymentName)
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	// Look for replica sets and pods from this run.
+	runSelector := r.runSelector()
+	replicaSets, err := r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{LabelSelector: runSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets: %w", err)
+	}
+	for _, replicaSet := range replicaSets.Items {
+		lingering = append(lingering, "replicaset "+replicaSet.Name)
+	}
+	pods, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		lingering = append(lingering, "pod "+pod.Name+" on node "+pod.Spec.NodeName)
+	}
+
+	// Look for the ingress.
+	if r.cfg.IngressVerify {
+		ingressFound, err := r.ingressExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingress: %w", err)
+		}
+		if ingressFound {
+			lingering = append(lingering, "ingress "+r.cfg.CheckServiceName)
+		}
+	}
+
+	// Look for the HTTPRoute.
+	if len(r.cfg.GatewayName) != 0 {
+		routeFound, err := r.httpRouteExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HTTPRoute: %w", err)
+		}
+		if routeFound {
+			lingering = append(lingering, "httproute "+r.cfg.CheckServiceName)
+		}
+	}
+
+	// Look for the OpenShift Route.
+	if r.cfg.OpenShiftRouteVerify {
+		routeFound, err := r.openShiftRouteExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OpenShift Route: %w", err)
+		}
+		if routeFound {
+			lingering = append(lingering, "route "+r.cfg.CheckServiceName)
+		}
+	}
+
+	// Look for the network policies.
+	if r.cfg.NetworkPolicyVerify {
+		for _, name := range r.networkPolicyNames() {
+			_, err = r.client.NetworkingV1().NetworkPolicies(r.cfg.CheckNamespace).Get(ctx, name, metav1.GetOptions{})
+			if err == nil {
+				lingering = append(lingering, "networkpolicy "+name)
+			}
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get network policy %s: %w", name, err)
+			}
+		}
+	}
+
+	// Look for the autoscaler.
+	if r.cfg.HPAVerify {
+		hpaFound, err := r.hpaExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get horizontal pod autoscaler: %w", err)
+		}
+		if hpaFound {
+			lingering = append(lingering, "horizontalpodautoscaler "+r.cfg.CheckDeploymentName)
+		}
+	}
+
+	// Look for the disruption budget.
+	if r.cfg.PDBVerify {
+		pdbFound, err := r.pdbExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod disruption budget: %w", err)
+		}
+		if pdbFound {
+			lingering = append(lingering, "poddisruptionbudget "+r.cfg.CheckDeploymentName)
+		}
+	}
+
+	// Look for the generated ConfigMap.
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		configMapFound, err := r.configMapExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap: %w", err)
+		}
+		if configMapFound {
+			lingering = append(lingering, "configmap "+r.cfg.checkConfigMapName())
+		}
+	}
+
+	// Look for the claim.
+	if len(r.cfg.PVCStorageClass) != 0 {
+		pvcFound, err := r.pvcExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get persistent volume claim: %w", err)
+		}
+		if pvcFound {
+			lingering = append(lingering, "persistentvolumeclaim "+r.cfg.checkPVCName())
+		}
+	}
+
+	// Look for the prober pod.
+	if r.cfg.ProberVerify {
+		proberFound, err := r.proberPodExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prober pod: %w", err)
+		}
+		if proberFound {
+			lingering = append(lingering, "pod "+r.cfg.proberPodName())
+		}
+	}
+
+	// Look for the Certificate and its issued Secret.
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		certificateFound, err := r.certificateExists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get certificate: %w", err)
+		}
+		if certificateFound {
+			lingering = append(lingering, "certificate "+r.cfg.checkCertificateName())
+		}
+	}
+
+	// Look for the services and their endpoint slices.
+	for _, name := range r.checkServiceNames() {
+		_, err = r.client.CoreV1().Services(r.cfg.CheckNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			lingering = append(lingering, "service "+name)
+		}
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get service %s: %w", name, err)
+		}
+		endpointSlices, err := r.client.DiscoveryV1().EndpointSlices(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list endpoint slices for service %s: %w", name, err)
+		}
+		for _, endpointSlice := range endpointSlices.Items {
+			lingering = append(lingering, "endpointslice "+endpointSlice.Name)
+		}
+	}
+
+	return lingering, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"strconv"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,9 +19,8 @@ const (
 	deploymentLabelKey = "deployment-timestamp"
 	// deploymentLabelValueBase is combined with the run timestamp.
 	deploymentLabelValueBase = "unix-"
-	// deploymentMinReadySeconds sets the minimum ready time for replicas.
-	deploymentMinReadySeconds = 5
-
+	// sourceLabelKey marks resources as created by kuberhealthy.
+	sourceLabelKey = "source"
 	// deploymentMaxSurgeDefault is a fallback for max surge.
 	deploymentMaxSurgeDefault = 2
 	// deploymentMaxUnavailableDefault is a fallback for max unavailable.
@@ -29,16 +29,20 @@ const (
 	// deploymentImagePullPolicy sets a sane default for the check image.
 	deploymentImagePullPolicy = "IfNotPresent"
 
-	// probeFailureThreshold sets readiness and liveness thresholds.
-	probeFailureThreshold = 5
+	// scratchVolumeName names the writable volume mounted over /tmp when the root filesystem is read-only.
+	scratchVolumeName = "tmp"
+	// scratchVolumeMountPath is where the scratch volume is mounted.
+	scratchVolumeMountPath = "/tmp"
+
 	// probeSuccessThreshold sets readiness and liveness thresholds.
 	probeSuccessThreshold = 1
-	// probeInitialDelaySeconds controls probe startup delay.
-	probeInitialDelaySeconds = 2
-	// probeTimeoutSeconds controls probe timeouts.
-	probeTimeoutSeconds = 2
-	// probePeriodSeconds controls probe check cadence.
-	probePeriodSeconds = 15
+
+	// probeTypeTCP probes by opening a TCP connection to the probe port.
+	probeTypeTCP = "tcp"
+	// probeTypeHTTP probes with an HTTP GET against the probe path and port.
+	probeTypeHTTP = "http"
+	// probeTypeExec probes by running a command in the container.
+	probeTypeExec = "exec"
 )
 
 // createDeploymentConfig builds a deployment manifest for the check image.
@@ -60,6 +64,18 @@ func (r *CheckRunner) createDeploymentConfig(image string) *appsv1.Deployment {
 	// Build the container spec for the deployment.
 	container := r.createContainerConfig(checkImage)
 	containers := []corev1.Container{container}
+	initContainers := r.createInitContainerConfigs()
+
+	// Add the sidecar, leading the init containers in native mode so it runs throughout.
+	if len(r.cfg.SidecarImage) != 0 {
+		sidecar := r.createSidecarContainerConfig()
+		if r.cfg.SidecarNative {
+			initContainers = append([]corev1.Container{sidecar}, initContainers...)
+		}
+		if !r.cfg.SidecarNative {
+			containers = append(containers, sidecar)
+		}
+	}
 
 	// Ensure node selector map is nil when empty.
 	nodeSelectors := r.cfg.CheckDeploymentNodeSelectors
@@ -67,17 +83,50 @@ func (r *CheckRunner) createDeploymentConfig(image string) *appsv1.Deployment {
 		nodeSelectors = nil
 	}
 
-	// Configure a short pod termination grace period.
-	graceSeconds := int64(1)
+	// Use the configured pod termination grace period.
+	graceSeconds := r.cfg.PodTerminationGracePeriodSeconds
 
 	// Assemble the pod spec for the deployment.
 	podSpec := corev1.PodSpec{
+		InitContainers:                initContainers,
 		Containers:                    containers,
 		NodeSelector:                  nodeSelectors,
 		RestartPolicy:                 corev1.RestartPolicyAlways,
 		TerminationGracePeriodSeconds: &graceSeconds,
 		ServiceAccountName:            r.cfg.CheckServiceAccount,
 		Tolerations:                   r.cfg.CheckDeploymentTolerations,
+		PriorityClassName:             r.cfg.PriorityClassName,
+		HostAliases:                   r.cfg.HostAliases,
+		DNSPolicy:                     r.cfg.PodDNSPolicy,
+		DNSConfig:                     r.cfg.PodDNSConfig,
+	}
+
+	// Give a read-only container somewhere to write its pid and cache files.
+	if r.cfg.ContainerReadOnlyRootFilesystem {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         scratchVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	// Add the configured scratch, ConfigMap, and Secret volumes.
+	volumes, _ := r.createCheckVolumes()
+	podSpec.Volumes = append(podSpec.Volumes, volumes...)
+
+	// Require node affinity terms when configured.
+	if len(r.cfg.CheckNodeAffinityRequirements) != 0 {
+		podSpec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: r.cfg.CheckNodeAffinityRequirements}},
+				},
+			},
+		}
+	}
+
+	// Hold the pods unready until the checker sets the readiness gate condition.
+	if len(r.cfg.ReadinessGate) != 0 {
+		podSpec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: corev1.PodConditionType(r.cfg.ReadinessGate)}}
 	}
 
 	// Attach image pull secrets if configured.
@@ -86,18 +135,38 @@ func (r *CheckRunner) createDeploymentConfig(image string) *appsv1.Deployment {
 		podSpec.ImagePullSecrets = secrets
 	}
 
-	// Build labels for the deployment and pod template.
+	// Apply the configured pod security context.
+	podSpec.SecurityContext = r.createPodSecurityContext()
+
+	// Make the pod spec Autopilot-compliant when requested.
+	if r.cfg.AutopilotMode {
+		applyAutopilotPodSpec(&podSpec)
+	}
+
+	// Apply the Pod Security Standards profile last so it covers every container.
+	if r.cfg.PSSProfile == pssProfileRestricted {
+		applyRestrictedProfile(&podSpec)
+	}
+
+	// Build the selector labels, then add the user labels for the pod template and the deployment itself.
 	labels := make(map[string]string)
 	labels[deploymentLabelKey] = deploymentLabelValueBase + strconv.Itoa(int(r.now.Unix()))
-	labels["source"] = "kuberhealthy"
+	labels[sourceLabelKey] = "kuberhealthy"
+	podLabels := r.cfg.resourceLabels()
+	for key, value := range labels {
+		podLabels[key] = value
+	}
 
 	// Assemble the pod template.
+	podSpec.TopologySpreadConstraints = r.createTopologySpreadConstraints(labels)
 	podTemplateSpec := corev1.PodTemplateSpec{
 		Spec: podSpec,
 	}
-	podTemplateSpec.ObjectMeta.Labels = labels
+	podTemplateSpec.ObjectMeta.Labels = podLabels
+	podTemplateSpec.ObjectMeta.Annotations = copyStringMap(r.cfg.PodAnnotations)
 	podTemplateSpec.ObjectMeta.Name = r.cfg.CheckDeploymentName
 	podTemplateSpec.ObjectMeta.Namespace = r.cfg.CheckNamespace
+	r.applyMeshPodMetadata(&podTemplateSpec.ObjectMeta)
 
 	// Build the selector from the labels.
 	labelSelector := metav1.LabelSelector{
@@ -134,46 +203,62 @@ func (r *CheckRunner) createDeploymentConfig(image string) *appsv1.Deployment {
 	replicas := int32(r.cfg.CheckDeploymentReplicas)
 	deploySpec := appsv1.DeploymentSpec{
 		Strategy:        deployStrategy,
-		MinReadySeconds: deploymentMinReadySeconds,
+		MinReadySeconds: r.cfg.MinReadySeconds,
 		Replicas:        &replicas,
 		Selector:        &labelSelector,
 		Template:        podTemplateSpec,
 	}
 
+	// Let the deployment controller report a stalled rollout sooner than the API default.
+	if r.cfg.ProgressDeadlineSeconds > 0 {
+		progressDeadline := r.cfg.ProgressDeadlineSeconds
+		deploySpec.ProgressDeadlineSeconds = &progressDeadline
+	}
+
 	// Populate the deployment metadata and spec.
 	deployment.ObjectMeta.Name = r.cfg.CheckDeploymentName
 	deployment.ObjectMeta.Namespace = r.cfg.CheckNamespace
+	deployment.ObjectMeta.Labels = copyStringMap(podLabels)
+	deployment.ObjectMeta.Annotations = r.resourceAnnotations(r.cfg.DeploymentAnnotations)
+	deployment.ObjectMeta.OwnerReferences = r.ownerReferences()
 	deployment.Spec = deploySpec
 
 	return deployment
 }
 
+// createPodSecurityContext builds the pod security context from the configured fields, or nil when none are set.
+func (r *CheckRunner) createPodSecurityContext() *corev1.PodSecurityContext {
+	// Leave the security context unset when nothing is configured.
+	if r.cfg.PodRunAsNonRoot == nil && r.cfg.PodRunAsUser == nil && r.cfg.PodRunAsGroup == nil && r.cfg.PodFSGroup == nil && r.cfg.PodSeccompProfile == nil {
+		return nil
+	}
+
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:   r.cfg.PodRunAsNonRoot,
+		RunAsUser:      r.cfg.PodRunAsUser,
+		RunAsGroup:     r.cfg.PodRunAsGroup,
+		FSGroup:        r.cfg.PodFSGroup,
+		SeccompProfile: r.cfg.PodSeccompProfile,
+	}
+}
+
 // createContainerConfig builds the main container spec for the deployment.
 func (r *CheckRunner) createContainerConfig(imageURL string) corev1.Container {
 	// Emit configuration details to the logs.
 	log.Infoln("Creating container using image ["+imageURL+"] with environment variables:", r.cfg.AdditionalEnvVars)
 
-	// Configure the container port.
-	basicPort := corev1.ContainerPort{
-		ContainerPort: r.cfg.CheckContainerPort,
+	// Configure the container ports.
+	containerPorts := make([]corev1.ContainerPort, 0)
+	for _, port := range r.cfg.checkPorts() {
+		containerPort := corev1.ContainerPort{
+			ContainerPort: port.ContainerPort,
+			Protocol:      corev1.ProtocolTCP,
+		}
+		containerPorts = append(containerPorts, containerPort)
 	}
-	containerPorts := []corev1.ContainerPort{basicPort}
 
-	// Build resource requests.
-	requests := make(map[corev1.ResourceName]resource.Quantity)
-	requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.cfg.MillicoreRequest), resource.DecimalSI)
-	requests[corev1.ResourceMemory] = *resource.NewQuantity(int64(r.cfg.MemoryRequest), resource.BinarySI)
-
-	// Build resource limits.
-	limits := make(map[corev1.ResourceName]resource.Quantity)
-	limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.cfg.MillicoreLimit), resource.DecimalSI)
-	limits[corev1.ResourceMemory] = *resource.NewQuantity(int64(r.cfg.MemoryLimit), resource.BinarySI)
-
-	// Assemble resource requirements.
-	resources := corev1.ResourceRequirements{
-		Requests: requests,
-		Limits:   limits,
-	}
+	// Share the configured resource requirements.
+	resources := r.createResourceRequirements()
 
 	// Build environment variable list from config.
 	envs := make([]corev1.EnvVar, 0)
@@ -185,41 +270,44 @@ func (r *CheckRunner) createContainerConfig(imageURL string) corev1.Container {
 		envs = append(envs, envVar)
 	}
 
+	// Share one probe handler so every probe exercises the same kubelet probing path.
+	handler := r.createProbeHandler()
+
 	// Assemble the liveness probe.
 	liveProbe := corev1.Probe{
-		InitialDelaySeconds: probeInitialDelaySeconds,
-		TimeoutSeconds:      probeTimeoutSeconds,
-		PeriodSeconds:       probePeriodSeconds,
+		ProbeHandler:        handler,
+		InitialDelaySeconds: r.cfg.ProbeInitialDelaySeconds,
+		TimeoutSeconds:      r.cfg.ProbeTimeoutSeconds,
+		PeriodSeconds:       r.cfg.ProbePeriodSeconds,
 		SuccessThreshold:    probeSuccessThreshold,
-		FailureThreshold:    probeFailureThreshold,
-	}
-	// Configure the probe handler to use a TCP socket on the container port.
-	liveProbe.TCPSocket = &corev1.TCPSocketAction{
-		Port: intstr.IntOrString{
-			IntVal: r.cfg.CheckContainerPort,
-			StrVal: strconv.Itoa(int(r.cfg.CheckContainerPort)),
-		},
+		FailureThreshold:    r.cfg.ProbeFailureThreshold,
 	}
 
 	// Assemble the readiness probe.
 	readyProbe := corev1.Probe{
-		InitialDelaySeconds: probeInitialDelaySeconds,
-		TimeoutSeconds:      probeTimeoutSeconds,
-		PeriodSeconds:       probePeriodSeconds,
+		ProbeHandler:        handler,
+		InitialDelaySeconds: r.cfg.ProbeInitialDelaySeconds,
+		TimeoutSeconds:      r.cfg.ProbeTimeoutSeconds,
+		PeriodSeconds:       r.cfg.ProbePeriodSeconds,
 		SuccessThreshold:    probeSuccessThreshold,
-		FailureThreshold:    probeFailureThreshold,
+		FailureThreshold:    r.cfg.ProbeFailureThreshold,
 	}
-	// Configure the probe handler to use a TCP socket on the container port.
-	readyProbe.TCPSocket = &corev1.TCPSocketAction{
-		Port: intstr.IntOrString{
-			IntVal: r.cfg.CheckContainerPort,
-			StrVal: strconv.Itoa(int(r.cfg.CheckContainerPort)),
-		},
+
+	// Assemble the startup probe, which holds back the other probes until the container has started.
+	var startupProbe *corev1.Probe
+	if r.cfg.StartupProbe {
+		startupProbe = &corev1.Probe{
+			ProbeHandler:     handler,
+			TimeoutSeconds:   r.cfg.ProbeTimeoutSeconds,
+			PeriodSeconds:    r.cfg.StartupProbePeriodSeconds,
+			SuccessThreshold: probeSuccessThreshold,
+			FailureThreshold: r.cfg.StartupProbeFailureThreshold,
+		}
 	}
 
 	// Build the container spec.
 	container := corev1.Container{
-		Name:            "deployment-container",
+		Name:            r.cfg.CheckContainerName,
 		Image:           imageURL,
 		ImagePullPolicy: deploymentImagePullPolicy,
 		Ports:           containerPorts,
@@ -227,7 +315,165 @@ func (r *CheckRunner) createContainerConfig(imageURL string) corev1.Container {
 		Env:             envs,
 		LivenessProbe:   &liveProbe,
 		ReadinessProbe:  &readyProbe,
+		StartupProbe:    startupProbe,
+	}
+
+	// Harden the container security context when configured.
+	container.SecurityContext = r.createContainerSecurityContext()
+	if r.cfg.ContainerReadOnlyRootFilesystem {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: scratchVolumeName, MountPath: scratchVolumeMountPath})
+	}
+
+	// Mount the configured scratch, ConfigMap, and Secret volumes.
+	_, mounts := r.createCheckVolumes()
+	container.VolumeMounts = append(container.VolumeMounts, mounts...)
+
+	// Fall back to container logs for termination messages when requested.
+	if r.cfg.TerminationMessageFallbackToLogs {
+		container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
 	}
 
 	return container
 }
+
+// createProbeHandler builds the configured probe handler: a TCP connect, an HTTP GET, or a command.
+func (r *CheckRunner) createProbeHandler() corev1.ProbeHandler {
+	// Run the configured command inside the container.
+	if r.cfg.ProbeType == probeTypeExec {
+		return corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: r.cfg.ProbeExecCommand}}
+	}
+
+	// Target the configured probe port, falling back to the container port.
+	port := r.cfg.ProbePort
+	if port == 0 {
+		port = r.cfg.CheckContainerPort
+	}
+	portValue := intstr.FromInt32(port)
+
+	// Request the probe path, over HTTPS when the endpoints serve TLS.
+	if r.cfg.ProbeType == probeTypeHTTP {
+		scheme := corev1.URISchemeHTTP
+		if r.cfg.EndpointScheme == endpointSchemeHTTPS {
+			scheme = corev1.URISchemeHTTPS
+		}
+		return corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: r.cfg.ProbePath, Port: portValue, Scheme: scheme}}
+	}
+
+	return corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: portValue}}
+}
+
+// createInitContainerConfigs builds the configured init containers that must finish before the check container starts.
+func (r *CheckRunner) createInitContainerConfigs() []corev1.Container {
+	// Leave init containers out of the spec unless configured.
+	if len(r.cfg.InitContainers) == 0 {
+		return nil
+	}
+
+	// Give each init container the check container's resources and hardening so admission treats them alike.
+	initContainers := make([]corev1.Container, 0, len(r.cfg.InitContainers))
+	for i, spec := range r.cfg.InitContainers {
+		container := corev1.Container{
+			Name:            r.cfg.CheckContainerName + "-init-" + strconv.Itoa(i),
+			Image:           spec.Image,
+			Command:         spec.Command,
+			ImagePullPolicy: deploymentImagePullPolicy,
+			Resources:       r.createResourceRequirements(),
+			SecurityContext: r.createContainerSecurityContext(),
+		}
+		if r.cfg.ContainerReadOnlyRootFilesystem {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: scratchVolumeName, MountPath: scratchVolumeMountPath})
+		}
+		if r.cfg.TerminationMessageFallbackToLogs {
+			container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+		}
+		initContainers = append(initContainers, container)
+	}
+
+	return initContainers
+}
+
+// createResourceRequirements builds the configured requests and limits shared by every check container.
+func (r *CheckRunner) createResourceRequirements() corev1.ResourceRequirements {
+	// Build resource requests.
+	requests := make(map[corev1.ResourceName]resource.Quantity)
+	requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.cfg.MillicoreRequest), resource.DecimalSI)
+	requests[corev1.ResourceMemory] = *resource.NewQuantity(int64(r.cfg.MemoryRequest), resource.BinarySI)
+
+	// Build resource limits.
+	limits := make(map[corev1.ResourceName]resource.Quantity)
+	limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.cfg.MillicoreLimit), resource.DecimalSI)
+	limits[corev1.ResourceMemory] = *resource.NewQuantity(int64(r.cfg.MemoryLimit), resource.BinarySI)
+
+	// Add ephemeral-storage only when configured so the cluster defaults still apply otherwise.
+	if !r.cfg.EphemeralStorageRequest.IsZero() {
+		requests[corev1.ResourceEphemeralStorage] = r.cfg.EphemeralStorageRequest.DeepCopy()
+	}
+	if !r.cfg.EphemeralStorageLimit.IsZero() {
+		limits[corev1.ResourceEphemeralStorage] = r.cfg.EphemeralStorageLimit.DeepCopy()
+	}
+
+	// Request and limit extended resources equally since the scheduler does not overcommit them.
+	for name, quantity := range r.cfg.ExtendedResources {
+		requests[name] = quantity.DeepCopy()
+		limits[name] = quantity.DeepCopy()
+	}
+
+	return corev1.ResourceRequirements{
+		Requests: requests,
+		Limits:   limits,
+	}
+}
+
+// createContainerSecurityContext builds the container security context from the configured hardening, or nil when none is set.
+func (r *CheckRunner) createContainerSecurityContext() *corev1.SecurityContext {
+	// Leave the security context unset when nothing is configured.
+	if !r.cfg.ContainerReadOnlyRootFilesystem && r.cfg.ContainerAllowPrivilegeEscalation == nil && !r.cfg.ContainerDropAllCapabilities {
+		return nil
+	}
+
+	// Apply each configured setting.
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: r.cfg.ContainerAllowPrivilegeEscalation,
+	}
+	if r.cfg.ContainerReadOnlyRootFilesystem {
+		readOnly := true
+		securityContext.ReadOnlyRootFilesystem = &readOnly
+	}
+	if r.cfg.ContainerDropAllCapabilities {
+		securityContext.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	}
+
+	return securityContext
+}
+
+// resourceAnnotations copies the base annotations and adds the expiry stamp when a resource TTL is set.
+func (r *CheckRunner) resourceAnnotations(base map[string]string) map[string]string {
+	// Leave the annotations untouched without a TTL.
+	annotations := copyStringMap(base)
+	if r.cfg.ResourceTTL <= 0 {
+		return annotations
+	}
+
+	// Stamp when the resource may be deleted by any run or janitor.
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[r.cfg.ExpiryAnnotation] = r.now.Add(r.cfg.ResourceTTL).UTC().Format(time.RFC3339)
+
+	return annotations
+}
+
+// copyStringMap copies a string map so generated objects never share the config's map, returning nil when empty.
+func copyStringMap(source map[string]string) map[string]string {
+	// Avoid empty metadata maps in generated manifests.
+	if len(source) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]string, len(source))
+	for key, value := range source {
+		copied[key] = value
+	}
+
+	return copied
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TestCreateContainerConfig validates container fields used by the deployment check.
@@ -91,23 +95,360 @@ func TestCreateDeploymentConfig(t *testing.T) {
 func buildTestRunner() *CheckRunner {
 	// Build a minimal config with defaults needed for generation functions.
 	cfg := &CheckConfig{
-		CheckDeploymentName:          defaultCheckDeploymentName,
-		CheckServiceName:             defaultCheckServiceName,
-		CheckContainerPort:           defaultCheckContainerPort,
-		CheckLoadBalancerPort:        defaultCheckLoadBalancerPort,
-		CheckNamespace:               defaultCheckNamespace,
-		CheckDeploymentReplicas:      defaultCheckDeploymentReplicas,
-		CheckServiceAccount:          defaultCheckServiceAccount,
-		MillicoreRequest:             defaultMillicoreRequest,
-		MillicoreLimit:               defaultMillicoreLimit,
-		MemoryRequest:                defaultMemoryRequest,
-		MemoryLimit:                  defaultMemoryLimit,
-		AdditionalEnvVars:            map[string]string{},
-		CheckDeploymentNodeSelectors: map[string]string{},
-		CheckDeploymentTolerations:   []corev1.Toleration{},
+		CheckDeploymentName:              defaultCheckDeploymentName,
+		CheckServiceName:                 defaultCheckServiceName,
+		CheckContainerName:               defaultCheckContainerName,
+		CheckContainerPort:               defaultCheckContainerPort,
+		CheckLoadBalancerPort:            defaultCheckLoadBalancerPort,
+		CheckServiceType:                 corev1.ServiceTypeClusterIP,
+		Protocol:                         protocolHTTP,
+		EndpointScheme:                   endpointSchemeHTTP,
+		HTTPPath:                         defaultHTTPPath,
+		HTTPExpectedCodes:                expectedStatusCodes{{Min: 200, Max: 200}},
+		CheckNamespace:                   defaultCheckNamespace,
+		CheckDeploymentReplicas:          defaultCheckDeploymentReplicas,
+		CheckServiceAccount:              defaultCheckServiceAccount,
+		MillicoreRequest:                 defaultMillicoreRequest,
+		MillicoreLimit:                   defaultMillicoreLimit,
+		MemoryRequest:                    defaultMemoryRequest,
+		MemoryLimit:                      defaultMemoryLimit,
+		AdditionalEnvVars:                map[string]string{},
+		CheckDeploymentNodeSelectors:     map[string]string{},
+		CheckDeploymentTolerations:       []corev1.Toleration{},
+		ProbeInitialDelaySeconds:         defaultProbeInitialDelaySeconds,
+		ProbePeriodSeconds:               defaultProbePeriodSeconds,
+		ProbeTimeoutSeconds:              defaultProbeTimeoutSeconds,
+		ProbeFailureThreshold:            defaultProbeFailureThreshold,
+		PodTerminationGracePeriodSeconds: defaultPodTerminationGracePeriodSeconds,
+		MinReadySeconds:                  defaultMinReadySeconds,
 	}
 
 	// Create the runner with a fixed timestamp.
-	runner := newCheckRunner(cfg, nil, time.Now())
+	runner := newCheckRunner(cfg, nil, nil, time.Now())
 	return runner
 }
+
+// TestPodErrorGraceElapsed validates pod errors wait out the grace and are never held back without one.
+func TestPodErrorGraceElapsed(t *testing.T) {
+	// Measure from a fixed start.
+	started := time.Now()
+
+	if podErrorGraceElapsed(time.Minute, started, started.Add(time.Second*30)) {
+		t.Fatalf("expected pod errors to be held back within the grace")
+	}
+
+	if !podErrorGraceElapsed(time.Minute, started, started.Add(time.Minute)) {
+		t.Fatalf("expected pod errors to count once the grace passed")
+	}
+
+	if !podErrorGraceElapsed(0, started, started) {
+		t.Fatalf("expected pod errors to count immediately without a grace")
+	}
+}
+
+// TestCreatePodSecurityContext validates the pod security context is only set when configured.
+func TestCreatePodSecurityContext(t *testing.T) {
+	// Leave the security context unset by default.
+	runner := buildTestRunner()
+	deployment := runner.createDeploymentConfig("nginx:test")
+	if deployment.Spec.Template.Spec.SecurityContext != nil {
+		t.Fatalf("expected no pod security context by default but got: %+v", deployment.Spec.Template.Spec.SecurityContext)
+	}
+
+	// Apply the configured fields.
+	runAsNonRoot := true
+	runAsUser := int64(101)
+	fsGroup := int64(2000)
+	runner.cfg.PodRunAsNonRoot = &runAsNonRoot
+	runner.cfg.PodRunAsUser = &runAsUser
+	runner.cfg.PodFSGroup = &fsGroup
+	runner.cfg.PodSeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	securityContext := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec.SecurityContext
+	if securityContext == nil || !*securityContext.RunAsNonRoot || *securityContext.RunAsUser != 101 || *securityContext.FSGroup != 2000 {
+		t.Fatalf("expected configured pod security context but got: %+v", securityContext)
+	}
+	if securityContext.RunAsGroup != nil {
+		t.Fatalf("expected runAsGroup to stay unset but got %d", *securityContext.RunAsGroup)
+	}
+	if securityContext.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected RuntimeDefault seccomp profile but got %s", securityContext.SeccompProfile.Type)
+	}
+}
+
+// TestCreateContainerSecurityContext validates container hardening and the scratch volume for read-only roots.
+func TestCreateContainerSecurityContext(t *testing.T) {
+	// Leave the security context unset by default.
+	runner := buildTestRunner()
+	container := runner.createContainerConfig("nginx:test")
+	if container.SecurityContext != nil {
+		t.Fatalf("expected no container security context by default but got: %+v", container.SecurityContext)
+	}
+
+	// Apply every hardening option.
+	allowPrivilegeEscalation := false
+	runner.cfg.ContainerReadOnlyRootFilesystem = true
+	runner.cfg.ContainerAllowPrivilegeEscalation = &allowPrivilegeEscalation
+	runner.cfg.ContainerDropAllCapabilities = true
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	securityContext := podSpec.Containers[0].SecurityContext
+	if securityContext == nil || !*securityContext.ReadOnlyRootFilesystem || *securityContext.AllowPrivilegeEscalation {
+		t.Fatalf("expected a read-only root without privilege escalation but got: %+v", securityContext)
+	}
+	if len(securityContext.Capabilities.Drop) != 1 || securityContext.Capabilities.Drop[0] != "ALL" {
+		t.Fatalf("expected ALL capabilities dropped but got: %v", securityContext.Capabilities.Drop)
+	}
+
+	// The read-only root gets a writable /tmp.
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].EmptyDir == nil {
+		t.Fatalf("expected an emptyDir scratch volume but got: %+v", podSpec.Volumes)
+	}
+	if len(podSpec.Containers[0].VolumeMounts) != 1 || podSpec.Containers[0].VolumeMounts[0].MountPath != scratchVolumeMountPath {
+		t.Fatalf("expected the scratch volume mounted at %s but got: %+v", scratchVolumeMountPath, podSpec.Containers[0].VolumeMounts)
+	}
+}
+
+// TestCreateDeploymentConfigLabels validates user labels reach the resources without entering the selectors.
+func TestCreateDeploymentConfigLabels(t *testing.T) {
+	// Configure a team label.
+	runner := buildTestRunner()
+	runner.cfg.ExtraLabels = map[string]string{"team": "platform"}
+	deployment := runner.createDeploymentConfig("nginx:test")
+
+	// The deployment and pods carry the label.
+	if deployment.Labels["team"] != "platform" || deployment.Spec.Template.Labels["team"] != "platform" {
+		t.Fatalf("expected the team label on the deployment and pods but got %v and %v", deployment.Labels, deployment.Spec.Template.Labels)
+	}
+	if deployment.Labels[sourceLabelKey] != "kuberhealthy" || deployment.Labels[deploymentLabelKey] != deployment.Spec.Template.Labels[deploymentLabelKey] {
+		t.Fatalf("expected the check labels on the deployment but got %v", deployment.Labels)
+	}
+
+	// The selector only uses the check's own labels.
+	_, found := deployment.Spec.Selector.MatchLabels["team"]
+	if found || len(deployment.Spec.Selector.MatchLabels[deploymentLabelKey]) == 0 {
+		t.Fatalf("expected the selector to hold only check labels but got %v", deployment.Spec.Selector.MatchLabels)
+	}
+
+	// The service carries the label too.
+	service := runner.createServiceConfig(deployment.Spec.Selector.MatchLabels)
+	if service.Labels["team"] != "platform" {
+		t.Fatalf("expected the team label on the service but got %v", service.Labels)
+	}
+}
+
+// TestOwnerReferences validates resources are only owned once the checker pod is resolved.
+func TestOwnerReferences(t *testing.T) {
+	// Leave resources unowned by default.
+	runner := buildTestRunner()
+	deployment := runner.createDeploymentConfig("nginx:test")
+	if len(deployment.OwnerReferences) != 0 {
+		t.Fatalf("expected no owner references by default but got %v", deployment.OwnerReferences)
+	}
+
+	// Point both the deployment and service at the checker pod.
+	pod := &corev1.Pod{}
+	pod.Name = "deployment-checker"
+	pod.UID = "1234"
+	runner.owner = checkerOwnerReference(pod)
+	deployment = runner.createDeploymentConfig("nginx:test")
+	service := runner.createServiceConfig(deployment.Spec.Selector.MatchLabels)
+	for _, owners := range [][]metav1.OwnerReference{deployment.OwnerReferences, service.OwnerReferences} {
+		if len(owners) != 1 || owners[0].Kind != "Pod" || owners[0].UID != "1234" {
+			t.Fatalf("expected the checker pod as owner but got %v", owners)
+		}
+	}
+}
+
+// TestEphemeralStorageResources validates ephemeral-storage is only set when configured.
+func TestEphemeralStorageResources(t *testing.T) {
+	// Leave ephemeral-storage to the cluster defaults when unset.
+	runner := buildTestRunner()
+	container := runner.createContainerConfig("nginx:test")
+	_, found := container.Resources.Requests[corev1.ResourceEphemeralStorage]
+	if found {
+		t.Fatalf("expected no ephemeral-storage request by default but got %v", container.Resources.Requests)
+	}
+
+	// Apply the configured request and limit.
+	runner.cfg.EphemeralStorageRequest = resource.MustParse("100Mi")
+	runner.cfg.EphemeralStorageLimit = resource.MustParse("1Gi")
+	container = runner.createContainerConfig("nginx:test")
+	request := container.Resources.Requests[corev1.ResourceEphemeralStorage]
+	limit := container.Resources.Limits[corev1.ResourceEphemeralStorage]
+	if request.String() != "100Mi" || limit.String() != "1Gi" {
+		t.Fatalf("expected 100Mi and 1Gi ephemeral-storage but got %s and %s", request.String(), limit.String())
+	}
+}
+
+// TestInitContainers validates init containers share the check container's resources and hardening.
+func TestInitContainers(t *testing.T) {
+	// Leave init containers out by default.
+	runner := buildTestRunner()
+	deployment := runner.createDeploymentConfig("nginx:test")
+	if len(deployment.Spec.Template.Spec.InitContainers) != 0 {
+		t.Fatalf("expected no init containers by default but got %v", deployment.Spec.Template.Spec.InitContainers)
+	}
+
+	// Add an init container to a read-only pod.
+	runner.cfg.InitContainers = []initContainerSpec{{Image: "busybox:1.36", Command: []string{"sleep", "2"}}}
+	runner.cfg.ContainerReadOnlyRootFilesystem = true
+	deployment = runner.createDeploymentConfig("nginx:test")
+	initContainers := deployment.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 1 || initContainers[0].Image != "busybox:1.36" || initContainers[0].Name == runner.cfg.CheckContainerName {
+		t.Fatalf("expected one uniquely named busybox init container but got %v", initContainers)
+	}
+
+	if initContainers[0].Resources.Limits.Memory().Value() != int64(runner.cfg.MemoryLimit) {
+		t.Fatalf("expected the check memory limit on the init container but got %v", initContainers[0].Resources.Limits)
+	}
+
+	if len(initContainers[0].VolumeMounts) != 1 || initContainers[0].SecurityContext == nil {
+		t.Fatalf("expected the scratch mount and security context on the init container but got %v", initContainers[0])
+	}
+}
+
+// TestStartupProbe validates the startup probe is only added when enabled and carries its own thresholds.
+func TestStartupProbe(t *testing.T) {
+	// Build the default container without a startup probe.
+	runner := buildTestRunner()
+	if runner.createContainerConfig("nginx:test").StartupProbe != nil {
+		t.Fatalf("expected no startup probe by default")
+	}
+
+	// Enable the startup probe with custom thresholds.
+	runner.cfg.StartupProbe = true
+	runner.cfg.StartupProbeFailureThreshold = 60
+	runner.cfg.StartupProbePeriodSeconds = 5
+	probe := runner.createContainerConfig("nginx:test").StartupProbe
+	if probe == nil || probe.FailureThreshold != 60 || probe.PeriodSeconds != 5 || probe.TCPSocket == nil {
+		t.Fatalf("expected a TCP startup probe with threshold 60 and period 5 but got %v", probe)
+	}
+}
+
+// TestCreateProbeHandler validates each probe type builds its handler against the right target.
+func TestCreateProbeHandler(t *testing.T) {
+	// Default to a TCP connect on the container port.
+	runner := buildTestRunner()
+	handler := runner.createProbeHandler()
+	if handler.TCPSocket == nil || handler.TCPSocket.Port.IntVal != runner.cfg.CheckContainerPort {
+		t.Fatalf("expected a TCP probe on the container port but got %v", handler)
+	}
+
+	// Request the probe path over HTTPS on an explicit port.
+	runner.cfg.ProbeType = probeTypeHTTP
+	runner.cfg.ProbePath = "/healthz"
+	runner.cfg.ProbePort = 9090
+	runner.cfg.EndpointScheme = endpointSchemeHTTPS
+	handler = runner.createProbeHandler()
+	if handler.HTTPGet == nil || handler.HTTPGet.Path != "/healthz" || handler.HTTPGet.Port.IntVal != 9090 || handler.HTTPGet.Scheme != corev1.URISchemeHTTPS {
+		t.Fatalf("expected an HTTPS GET of /healthz on 9090 but got %v", handler.HTTPGet)
+	}
+
+	// Run the configured command, shared by every probe on the container.
+	runner.cfg.ProbeType = probeTypeExec
+	runner.cfg.ProbeExecCommand = []string{"cat", "/tmp/ready"}
+	container := runner.createContainerConfig("nginx:test")
+	if container.LivenessProbe.Exec == nil || container.ReadinessProbe.Exec == nil || container.LivenessProbe.Exec.Command[1] != "/tmp/ready" {
+		t.Fatalf("expected exec probes running cat /tmp/ready but got %v", container.LivenessProbe)
+	}
+}
+
+// TestProbeTiming validates the configured timing reaches the liveness and readiness probes.
+func TestProbeTiming(t *testing.T) {
+	// Slow the probes down as a constrained cluster would.
+	runner := buildTestRunner()
+	runner.cfg.ProbeInitialDelaySeconds = 30
+	runner.cfg.ProbePeriodSeconds = 20
+	runner.cfg.ProbeTimeoutSeconds = 10
+	runner.cfg.ProbeFailureThreshold = 8
+	container := runner.createContainerConfig("nginx:test")
+
+	for _, probe := range []*corev1.Probe{container.LivenessProbe, container.ReadinessProbe} {
+		if probe.InitialDelaySeconds != 30 || probe.PeriodSeconds != 20 || probe.TimeoutSeconds != 10 || probe.FailureThreshold != 8 {
+			t.Fatalf("expected delay 30 period 20 timeout 10 threshold 8 but got %v", probe)
+		}
+	}
+}
+
+// TestPodDNSSettings validates the DNS policy and resolver options reach the pod template.
+func TestPodDNSSettings(t *testing.T) {
+	// Configure a None policy with its own resolver.
+	runner := buildTestRunner()
+	ndots := "2"
+	runner.cfg.PodDNSPolicy = corev1.DNSNone
+	runner.cfg.PodDNSConfig = &corev1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.10"},
+		Searches:    []string{"svc.cluster.local"},
+		Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
+	}
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+
+	if podSpec.DNSPolicy != corev1.DNSNone || podSpec.DNSConfig == nil || podSpec.DNSConfig.Nameservers[0] != "10.0.0.10" {
+		t.Fatalf("expected the None policy with nameserver 10.0.0.10 but got %s %v", podSpec.DNSPolicy, podSpec.DNSConfig)
+	}
+}
+
+// TestGracePeriodAndMinReady validates the configured grace period and minReadySeconds reach the deployment.
+func TestGracePeriodAndMinReady(t *testing.T) {
+	// Slow pod shutdown and rollout pacing.
+	runner := buildTestRunner()
+	runner.cfg.PodTerminationGracePeriodSeconds = 30
+	runner.cfg.MinReadySeconds = 20
+	deployment := runner.createDeploymentConfig("nginx:test")
+
+	if *deployment.Spec.Template.Spec.TerminationGracePeriodSeconds != 30 || deployment.Spec.MinReadySeconds != 20 {
+		t.Fatalf("expected grace 30 and minReadySeconds 20 but got %d and %d", *deployment.Spec.Template.Spec.TerminationGracePeriodSeconds, deployment.Spec.MinReadySeconds)
+	}
+}
+
+// TestPriorityClassName validates the priority class reaches the pod spec only when configured.
+func TestPriorityClassName(t *testing.T) {
+	// Leave the priority class to the cluster default.
+	runner := buildTestRunner()
+	deployment := runner.createDeploymentConfig("nginx:test")
+	if len(deployment.Spec.Template.Spec.PriorityClassName) != 0 {
+		t.Fatalf("expected no priority class by default but got %s", deployment.Spec.Template.Spec.PriorityClassName)
+	}
+
+	// Run the check pods at a high priority.
+	t.Setenv("CHECK_PRIORITY_CLASS_NAME", "system-cluster-critical")
+	cfg, err := parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing config: %v", err)
+	}
+	runner.cfg.PriorityClassName = cfg.PriorityClassName
+	deployment = runner.createDeploymentConfig("nginx:test")
+	if deployment.Spec.Template.Spec.PriorityClassName != "system-cluster-critical" {
+		t.Fatalf("expected the system-cluster-critical priority class but got %q", deployment.Spec.Template.Spec.PriorityClassName)
+	}
+}
+
+// TestProgressDeadlineExceeded validates a stalled rollout is detected only for the current generation.
+func TestProgressDeadlineExceeded(t *testing.T) {
+	// Build a deployment the controller gave up on.
+	progressDeadline := int32(60)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-check", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{ProgressDeadlineSeconds: &progressDeadline},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  corev1.ConditionFalse,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: `ReplicaSet "deployment-check-abc" has timed out progressing.`,
+			}},
+		},
+	}
+
+	stalled, err := progressDeadlineExceeded(deployment)
+	if !stalled || err == nil || !strings.Contains(err.Error(), "60s") || !strings.Contains(err.Error(), "timed out progressing") {
+		t.Fatalf("expected a stalled rollout naming the 60s deadline but got %t %v", stalled, err)
+	}
+
+	// Ignore the condition while the controller has not observed the latest spec.
+	deployment.Generation = 3
+	stalled, _ = progressDeadlineExceeded(deployment)
+	if stalled {
+		t.Fatalf("expected a stale condition to be ignored")
+	}
+}
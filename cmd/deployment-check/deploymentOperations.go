@@ -12,8 +12,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
 	corev1typed "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
@@ -30,7 +32,7 @@ func (r *CheckRunner) createDeploymentAndWait(ctx context.Context, deadline time
 	log.Infoln("Created deployment resource.")
 
 	// Create the deployment.
-	deployment, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Create(ctx, deploymentConfig, metav1.CreateOptions{})
+	deployment, err := r.createDeployment(ctx, deploymentConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create deployment: %w", err)
 	}
@@ -38,6 +40,7 @@ func (r *CheckRunner) createDeploymentAndWait(ctx context.Context, deadline time
 		return nil, fmt.Errorf("deployment creation returned nil")
 	}
 	log.Infoln("Created deployment in", deployment.Namespace, "namespace:", deployment.Name)
+	r.timeline.recordf("created deployment %s with image %s", deployment.Name, r.cfg.CheckImageURL)
 
 	// Watch for pod errors in a background goroutine.
 	ctxCreate, cancel := context.WithCancel(context.Background())
@@ -46,166 +49,236 @@ func (r *CheckRunner) createDeploymentAndWait(ctx context.Context, deadline time
 	go r.monitorDeploymentPodErrors(ctxCreate, deadline, 2, errDeploymentCreatePod, podErrorChan)
 
 	// Wait for the deployment to become available.
-	watcher, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Watch(ctx, metav1.ListOptions{
-		Watch:         true,
-		FieldSelector: "metadata.name=" + deployment.Name,
-	})
+	watcher, err := r.watchDeployment(ctx, deployment.Name, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to watch deployment: %w", err)
 	}
 	defer watcher.Stop()
+	events := watcher.ResultChan()
+
+	// Bound the wait by the provisioning window when a provisioning mode is enabled.
+	provisioningTimeout := r.provisioningWaitTimeout()
 
 	for {
 		// Handle events, errors, or context cancellation.
 		select {
-		case event := <-watcher.ResultChan():
+		case event, open := <-events:
+			if !open {
+				// The resumable watch only closes once ctx ends.
+				events = nil
+				continue
+			}
 			deploymentEvent, ok := event.Object.(*appsv1.Deployment)
 			if !ok {
 				log.Infoln("Got a watch event for a non-deployment object -- ignoring.")
 				continue
 			}
 			log.Debugln("Received an event watching for deployment changes:", deploymentEvent.Name, "got event", event.Type)
+			r.observeDeploymentConditions(deploymentEvent)
+			stalled, stalledErr := progressDeadlineExceeded(deploymentEvent)
+			if stalled {
+				// Describe the pods before cleanup removes them.
+				stalledErr = r.decorateDeploymentError(ctx, "deployment create", stalledErr)
+				cleanupErr := r.cleanup(ctx)
+				if cleanupErr != nil {
+					return nil, classify(failureClassCleanup, fmt.Errorf("failed to clean up after deployment create: %w", cleanupErr))
+				}
+				return nil, classify(failureClassRollout, stalledErr)
+			}
 			if deploymentAvailable(deploymentEvent, r.cfg.CheckDeploymentReplicas) {
+				r.timeline.recordf("deployment %s available with %d ready replica(s)", deploymentEvent.Name, deploymentEvent.Status.ReadyReplicas)
 				return deploymentEvent, nil
 			}
 		case podErr := <-podErrorChan:
 			if podErr != nil {
-				return nil, r.decorateDeploymentError(ctx, "deployment create", podErr)
+				// Describe the pods before cleanup removes them.
+				podErr = r.decorateDeploymentError(ctx, "deployment create", podErr)
+				cleanupErr := r.cleanup(ctx)
+				if cleanupErr != nil {
+					return nil, classify(failureClassCleanup, fmt.Errorf("failed to clean up after deployment create: %w", cleanupErr))
+				}
+				return nil, podErr
+			}
+		case <-provisioningTimeout:
+			cleanupErr := r.cleanup(ctx)
+			if cleanupErr != nil {
+				return nil, classify(failureClassCleanup, fmt.Errorf("failed to clean up after node provisioning timeout: %w", cleanupErr))
 			}
+			return nil, classify(failureClassScheduling, r.decorateDeploymentError(ctx, "deployment create", fmt.Errorf("pods did not become ready on provisioned capacity within the provisioning window")))
 		case <-ctx.Done():
 			cleanupErr := r.cleanup(ctx)
 			if cleanupErr != nil {
-				return nil, fmt.Errorf("failed to clean up after deployment create: %w", cleanupErr)
+				return nil, classify(failureClassCleanup, fmt.Errorf("failed to clean up after deployment create: %w", cleanupErr))
 			}
-			return nil, r.decorateDeploymentError(ctx, "deployment create", fmt.Errorf("context expired while waiting for deployment to create"))
+			return nil, classify(failureClassRollout, r.decorateDeploymentError(ctx, "deployment create", fmt.Errorf("context expired while waiting for deployment to create")))
 		}
 	}
 }
 
-// updateDeploymentAndWait performs a rolling update and waits for completion.
-func (r *CheckRunner) updateDeploymentAndWait(ctx context.Context, deadline time.Time) (*appsv1.Deployment, error) {
-	// Fetch the current deployment to preserve resourceVersion.
-	current, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch deployment for update: %w", err)
-	}
-	if current == nil {
-		return nil, fmt.Errorf("deployment lookup returned nil")
-	}
-
+// updateDeploymentAndWait performs a rolling update to an image and waits for completion.
+func (r *CheckRunner) updateDeploymentAndWait(ctx context.Context, deadline time.Time, image string) (*appsv1.Deployment, error) {
 	// Create the updated spec and apply the new image.
-	updatedConfig := r.createDeploymentConfig(r.cfg.CheckImageURLRollTo)
+	updatedConfig := r.createDeploymentConfig(image)
 	if len(updatedConfig.Spec.Template.Spec.Containers) == 0 {
 		return nil, fmt.Errorf("updated deployment config did not include containers")
 	}
+	log.Infoln("Performing rolling-update on deployment", r.cfg.CheckDeploymentName, "to ["+image+"]")
 
-	// Copy the new template into the existing deployment to keep metadata intact.
-	current.Spec.Template = updatedConfig.Spec.Template
-	current.Spec.Replicas = updatedConfig.Spec.Replicas
-	current.Spec.Strategy = updatedConfig.Spec.Strategy
-	current.Spec.MinReadySeconds = updatedConfig.Spec.MinReadySeconds
+	// Apply the desired state directly when server-side apply is enabled.
+	if r.cfg.ServerSideApply {
+		deployment, err := r.applyDeployment(ctx, updatedConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply deployment update: %w", err)
+		}
+		r.timeline.recordf("applied rolling update of deployment %s to image %s", deployment.Name, image)
+		return r.waitForRollout(ctx, deadline, deployment, "deployment update")
+	}
 
-	log.Infoln("Performing rolling-update on deployment", current.Name, "to ["+r.cfg.CheckImageURLRollTo+"]")
+	// Submit the update, re-reading the deployment whenever a concurrent writer such as a webhook or autoscaler wins the race.
+	var deployment *appsv1.Deployment
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Fetch the current deployment to preserve resourceVersion.
+		current, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to fetch deployment for update: %w", err)
+		}
 
-	// Submit the update.
-	deployment, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Update(ctx, current, metav1.UpdateOptions{})
+		// Copy the new template into the existing deployment to keep metadata intact.
+		current.Spec.Template = updatedConfig.Spec.Template
+		current.Spec.Replicas = updatedConfig.Spec.Replicas
+		current.Spec.Strategy = updatedConfig.Spec.Strategy
+		current.Spec.MinReadySeconds = updatedConfig.Spec.MinReadySeconds
+		current.Spec.ProgressDeadlineSeconds = updatedConfig.Spec.ProgressDeadlineSeconds
+		deployment, err = r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Update(ctx, current, metav1.UpdateOptions{})
+		if k8serrors.IsConflict(err) {
+			log.Infoln("Deployment changed while updating it; retrying with the latest version.")
+		}
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update deployment: %w", err)
 	}
+	r.timeline.recordf("submitted rolling update of deployment %s to image %s", deployment.Name, image)
+
+	return r.waitForRollout(ctx, deadline, deployment, "deployment update")
+}
 
+// waitForRollout waits for a submitted template change to roll out to ready pods.
+func (r *CheckRunner) waitForRollout(ctx context.Context, deadline time.Time, deployment *appsv1.Deployment, stage string) (*appsv1.Deployment, error) {
 	// Watch for pod errors in a background goroutine.
 	ctxUpdate, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	podErrorChan := make(chan error, 1)
 	go r.monitorDeploymentPodErrors(ctxUpdate, deadline, 3, errDeploymentUpdatePod, podErrorChan)
 
-	// Watch for the rolling update to complete.
-	watcher, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Watch(ctx, metav1.ListOptions{
-		Watch:         true,
-		FieldSelector: "metadata.name=" + deployment.Name,
-	})
+	// Watch for the rollout to complete.
+	watcher, err := r.watchDeployment(ctx, deployment.Name, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to watch deployment update: %w", err)
+		return nil, fmt.Errorf("failed to watch %s: %w", stage, err)
 	}
 	defer watcher.Stop()
+	events := watcher.ResultChan()
 
 	for {
 		// Wait for deployment status updates.
 		select {
-		case event := <-watcher.ResultChan():
+		case event, open := <-events:
+			if !open {
+				// The resumable watch only closes once ctx ends.
+				events = nil
+				continue
+			}
 			deploymentEvent, ok := event.Object.(*appsv1.Deployment)
 			if !ok {
 				log.Infoln("Got a watch event for a non-deployment object -- ignoring.")
 				continue
 			}
 			log.Debugln("Received an event watching for deployment changes:", deploymentEvent.Name, "got event", event.Type)
+			r.observeDeploymentConditions(deploymentEvent)
+			stalled, stalledErr := progressDeadlineExceeded(deploymentEvent)
+			if stalled {
+				return nil, classify(failureClassRollout, r.decorateDeploymentError(ctx, stage, stalledErr))
+			}
 			if rolledPodsAreReady(deploymentEvent, r.cfg.CheckDeploymentReplicas) {
+				r.timeline.recordf("%s complete for deployment %s with %d updated replica(s)", stage, deploymentEvent.Name, deploymentEvent.Status.UpdatedReplicas)
 				return deploymentEvent, nil
 			}
 		case podErr := <-podErrorChan:
 			if podErr != nil {
-				return nil, r.decorateDeploymentError(ctx, "deployment update", podErr)
+				return nil, r.decorateDeploymentError(ctx, stage, podErr)
 			}
 		case <-ctx.Done():
 			cleanupErr := r.cleanup(ctx)
 			if cleanupErr != nil {
-				return nil, fmt.Errorf("failed to clean up after deployment update: %w", cleanupErr)
+				return nil, classify(failureClassCleanup, fmt.Errorf("failed to clean up after %s: %w", stage, cleanupErr))
 			}
-			return nil, r.decorateDeploymentError(ctx, "deployment update", fmt.Errorf("context expired while waiting for deployment to update"))
+			return nil, classify(failureClassRollout, r.decorateDeploymentError(ctx, stage, fmt.Errorf("context expired while waiting for %s", stage)))
 		}
 	}
 }
 
 // deleteDeploymentAndWait deletes the deployment and waits for removal.
 func (r *CheckRunner) deleteDeploymentAndWait(ctx context.Context) error {
-	// Attempt a foreground delete with a short grace period.
+	// Attempt a delete with a short grace period.
 	err := r.deleteDeployment(ctx)
-	if err != nil {
+	if err != nil && !k8serrors.IsNotFound(err) {
 		log.Infoln("Could not delete deployment:", r.cfg.CheckDeploymentName)
 	}
 
-	// Loop until the deployment is no longer present.
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("timed out while waiting for deployment to delete")
-		default:
-			log.Debugln("Delete deployment and wait has not yet timed out.")
-		}
+	// Force delete wedged pods while waiting, since a foreground delete waits on them.
+	if r.cfg.PodForceDeleteAfter > 0 {
+		forceCtx, stopForce := context.WithCancel(ctx)
+		defer stopForce()
+		go r.forceDeleteDuringDelete(forceCtx)
+	}
 
-		log.Debugln("Waiting 5 seconds before trying again.")
-		time.Sleep(time.Second * 5)
+	// Wait for the informer cache to drop the deployment, giving up after the delete timeout.
+	waitCtx := ctx
+	if r.cfg.DeleteTimeout > 0 {
+		var cancelWait context.CancelFunc
+		waitCtx, cancelWait = context.WithTimeout(ctx, r.cfg.DeleteTimeout)
+		defer cancelWait()
+	}
+	err = r.waitForObjectDeleted(waitCtx, "deployment", r.cfg.CheckDeploymentName, func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Apps().V1().Deployments().Informer()
+	}, r.deleteDeployment)
+	if err != nil {
+		return r.decorateStuckDeletion(err)
+	}
 
-		deploymentList, listErr := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
-			FieldSelector: "metadata.name=" + r.cfg.CheckDeploymentName,
-		})
-		if listErr != nil {
-			log.Errorln("Error listing deployments:", listErr.Error())
-			continue
-		}
+	// An orphaning delete leaves the replica sets behind, so remove them directly.
+	if r.cfg.DeletePropagationPolicy == metav1.DeletePropagationOrphan {
+		return r.deleteOrphanedReplicaSets(ctx)
+	}
 
-		deploymentExists := false
-		for _, deploy := range deploymentList.Items {
-			if deploy.GetName() == r.cfg.CheckDeploymentName {
-				deploymentExists = true
-				deleteErr := r.deleteDeployment(ctx)
-				if deleteErr != nil {
-					log.Errorln("Error deleting deployment", r.cfg.CheckDeploymentName+":", deleteErr.Error())
-				}
-				break
-			}
-		}
+	return nil
+}
+
+// deleteOrphanedReplicaSets deletes the replica sets of this run that an orphaning deployment delete left behind.
+func (r *CheckRunner) deleteOrphanedReplicaSets(ctx context.Context) error {
+	// Find the run's replica sets by their pod template labels.
+	replicaSets, err := r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{LabelSelector: r.runSelector()})
+	if err != nil {
+		return fmt.Errorf("failed to list orphaned replica sets: %w", err)
+	}
 
-		if !deploymentExists {
-			return nil
+	// Delete each one along with its pods.
+	background := metav1.DeletePropagationBackground
+	for _, replicaSet := range replicaSets.Items {
+		err = r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).Delete(ctx, replicaSet.Name, metav1.DeleteOptions{PropagationPolicy: &background})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete orphaned replica set %s: %w", replicaSet.Name, err)
 		}
+		log.Infoln("Deleted orphaned replica set", replicaSet.Name+".")
 	}
+
+	return nil
 }
 
 // deleteDeployment issues the delete call for the deployment resource.
 func (r *CheckRunner) deleteDeployment(ctx context.Context) error {
-	// Prepare background delete options to avoid foreground finalizer stalls.
-	deletePolicy := metav1.DeletePropagationBackground
+	// Prepare delete options with the configured propagation policy.
+	deletePolicy := r.cfg.DeletePropagationPolicy
 	graceSeconds := int64(1)
 	deleteOpts := metav1.DeleteOptions{
 		GracePeriodSeconds: &graceSeconds,
@@ -244,6 +317,11 @@ func (r *CheckRunner) findPreviousDeployment(ctx context.Context) (bool, error)
 
 // monitorDeploymentPodErrors inspects pod states and events to surface deployment issues.
 func (r *CheckRunner) monitorDeploymentPodErrors(ctx context.Context, deadline time.Time, divisor int, reason error, resultChan chan<- error) {
+	// Note when monitoring began so an explicit pod error grace can be measured from it.
+	started := time.Now()
+	podChanges, unsubscribe := r.pods.subscribe()
+	defer unsubscribe()
+
 	// Loop until the context is canceled or an error is detected.
 	for {
 		select {
@@ -253,35 +331,194 @@ func (r *CheckRunner) monitorDeploymentPodErrors(ctx context.Context, deadline t
 		default:
 		}
 
-		// Only start evaluating errors later in the run to allow for startup.
-		if divisor > 0 && time.Until(deadline) < r.cfg.CheckTimeLimit/time.Duration(divisor) {
+		// Read pods for the current deployment run from the informer cache and note phase transitions.
+		pods, listErr := r.cachedDeploymentPods(ctx)
+		if listErr != nil {
+			log.WithError(listErr).Errorln("Error listing deployment pods while waiting for readiness.")
+		}
+		if listErr == nil {
+			r.observePodPhases(pods)
+
+			// Fail early on crash loops and fatal waiting reasons regardless of how much of the deadline remains.
+			crashErr := r.checkContainerRestarts(pods, reason)
+			if crashErr != nil {
+				resultChan <- crashErr
+				return
+			}
+			if podErrorGraceElapsed(r.cfg.PodErrorGrace, started, time.Now()) {
+				fatalErr := r.checkFatalWaitingReasons(pods, reason)
+				if fatalErr != nil {
+					resultChan <- fatalErr
+					return
+				}
+			}
+			unschedulableErr := r.checkUnschedulablePods(pods, reason, time.Now())
+			if unschedulableErr != nil {
+				resultChan <- unschedulableErr
+				return
+			}
+		}
+
+		// Only start evaluating errors after the pod error grace, or later in the run without one, to allow for startup.
+		evaluate := divisor > 0 && time.Until(deadline) < r.cfg.CheckTimeLimit/time.Duration(divisor)
+		if r.cfg.PodErrorGrace > 0 {
+			evaluate = podErrorGraceElapsed(r.cfg.PodErrorGrace, started, time.Now())
+		}
+		if evaluate {
 			log.Infoln("Capturing possible pod errors while deployment is in progress.")
-			podErr := r.checkDeploymentPodEvent(ctx, reason)
+			if listErr != nil {
+				resultChan <- listErr
+				return
+			}
+			podErr := r.checkDeploymentPodEvent(pods, reason)
 			if podErr != nil {
 				resultChan <- podErr
 				return
 			}
 		}
 
-		// Sleep briefly to avoid hammering the API.
-		time.Sleep(time.Second * 2)
+		// Wake on pod changes, re-evaluating periodically as the deadline approaches.
+		select {
+		case <-ctx.Done():
+		case <-podChanges:
+		case <-time.After(time.Second * 2):
+		}
 	}
 }
 
-// checkDeploymentPodEvent inspects pod and event states for deployment errors.
-func (r *CheckRunner) checkDeploymentPodEvent(ctx context.Context, reason error) error {
-	// List pods for the current deployment run.
-	podList, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
-		LabelSelector: deploymentLabelKey + "=" + deploymentLabelValueBase + fmt.Sprint(r.now.Unix()),
+// podErrorGraceElapsed reports whether pod errors may fail the check yet; without a grace they always may.
+func podErrorGraceElapsed(grace time.Duration, started time.Time, now time.Time) bool {
+	return grace <= 0 || now.Sub(started) >= grace
+}
+
+// listDeploymentPods lists the pods created for the current deployment run.
+func (r *CheckRunner) listDeploymentPods(ctx context.Context) (*corev1.PodList, error) {
+	// Select pods by the run timestamp label.
+	return r.client.CoreV1().Pods(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: r.runSelector(),
 	})
-	if err != nil {
-		log.WithError(err).Errorln("Error listing deployment pods while waiting for readiness.")
-		return err
+}
+
+// podContainerStatuses returns the init container statuses followed by the regular container statuses.
+func podContainerStatuses(pod corev1.Pod) []corev1.ContainerStatus {
+	// Include init containers so hung or crashing ones are reported like any other.
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// checkContainerRestarts reports containers whose restart count has reached the configured threshold.
+func (r *CheckRunner) checkContainerRestarts(pods []corev1.Pod, reason error) error {
+	// Skip the check when the threshold is disabled.
+	if r.cfg.MaxContainerRestarts < 1 {
+		return nil
+	}
+
+	// Inspect each container status for repeated restarts.
+	for _, pod := range pods {
+		for _, containerStat := range podContainerStatuses(pod) {
+			if containerStat.RestartCount < int32(r.cfg.MaxContainerRestarts) {
+				continue
+			}
+
+			// Include why and how the last run ended.
+			lastReason := "unknown"
+			lastExitCode := int32(0)
+			lastMessage := ""
+			if containerStat.LastTerminationState.Terminated != nil {
+				lastReason = containerStat.LastTerminationState.Terminated.Reason
+				lastExitCode = containerStat.LastTerminationState.Terminated.ExitCode
+				lastMessage = compactTerminationMessage(containerStat.LastTerminationState.Terminated.Message)
+			}
+			err := fmt.Errorf("pod: %s node: %s container: %s restarts: %d threshold: %d last termination reason: %s exit code: %d msg: %s",
+				pod.Name,
+				pod.Spec.NodeName,
+				containerStat.Name,
+				containerStat.RestartCount,
+				r.cfg.MaxContainerRestarts,
+				lastReason,
+				lastExitCode,
+				lastMessage,
+			)
+			log.WithError(err).Errorln("Container is crash looping.")
+			return classify(failureClassRollout, fmt.Errorf("crash loop detected: %s; stage: %w", err.Error(), reason))
+		}
+	}
+
+	return nil
+}
+
+// checkFatalWaitingReasons reports containers waiting for a reason configured as immediately fatal.
+func (r *CheckRunner) checkFatalWaitingReasons(pods []corev1.Pod, reason error) error {
+	// Skip the check when no fatal reasons are configured.
+	if len(r.cfg.FatalWaitingReasons) == 0 {
+		return nil
+	}
+
+	// Inspect each waiting container.
+	for _, pod := range pods {
+		for _, containerStat := range podContainerStatuses(pod) {
+			if containerStat.State.Waiting == nil {
+				continue
+			}
+			if !r.cfg.FatalWaitingReasons[containerStat.State.Waiting.Reason] {
+				continue
+			}
+
+			err := fmt.Errorf("pod: %s node: %s container: %s reason: %s msg: %s",
+				pod.Name,
+				pod.Spec.NodeName,
+				containerStat.Name,
+				containerStat.State.Waiting.Reason,
+				containerStat.State.Waiting.Message,
+			)
+			log.WithError(err).Errorln("Container is waiting for a fatal reason.")
+			return classify(podFailureClass(containerStat.State.Waiting.Reason), fmt.Errorf("fatal container state: %s; stage: %w", err.Error(), reason))
+		}
 	}
 
+	return nil
+}
+
+// checkUnschedulablePods reports the first pod the scheduler has been unable to place for longer than the configured window.
+func (r *CheckRunner) checkUnschedulablePods(pods []corev1.Pod, reason error, now time.Time) error {
+	// Skip the check when disabled or when pods are expected to wait for provisioned capacity.
+	if r.cfg.UnschedulableTimeout <= 0 || r.cfg.AutoscalerMode || r.cfg.KarpenterMode {
+		return nil
+	}
+
+	// Inspect the scheduled condition of each pod still waiting for a node.
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) != 0 || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type != corev1.PodScheduled || condition.Status != corev1.ConditionFalse || condition.Reason != corev1.PodReasonUnschedulable {
+				continue
+			}
+			unschedulableFor := now.Sub(pod.CreationTimestamp.Time)
+			if unschedulableFor < r.cfg.UnschedulableTimeout {
+				continue
+			}
+
+			err := fmt.Errorf("pod: %s unschedulable for %s: %s", pod.Name, unschedulableFor.Round(time.Second), condition.Message)
+			log.WithError(err).Errorln("Pod could not be scheduled.")
+			return classify(failureClassScheduling, fmt.Errorf("pod unschedulable: %s; stage: %w", err.Error(), reason))
+		}
+	}
+
+	return nil
+}
+
+// checkDeploymentPodEvent inspects pod and event states for deployment errors.
+func (r *CheckRunner) checkDeploymentPodEvent(pods []corev1.Pod, reason error) error {
+	// Track the most recent error for the caller.
+	var err error
+
 	// Inspect each pod and container status.
-	for _, pod := range podList.Items {
-		for _, containerStat := range pod.Status.ContainerStatuses {
+	for _, pod := range pods {
+		for _, containerStat := range podContainerStatuses(pod) {
 			if containerStat.State.Waiting == nil {
 				continue
 			}
@@ -289,17 +526,18 @@ func (r *CheckRunner) checkDeploymentPodEvent(ctx context.Context, reason error)
 				continue
 			}
 
-			// Capture waiting errors that are not the normal ContainerCreating state.
-			if !containerStat.Ready && containerStat.State.Waiting.Reason != "ContainerCreating" {
-				err = fmt.Errorf("pod: %s node: %s container: %s reason: %s msg: %s",
+			// Capture waiting errors that are not the normal ContainerCreating or PodInitializing states.
+			if !containerStat.Ready && containerStat.State.Waiting.Reason != "ContainerCreating" && containerStat.State.Waiting.Reason != "PodInitializing" {
+				err = fmt.Errorf("pod: %s node: %s container: %s reason: %s msg: %s state: %s",
 					pod.Name,
 					pod.Spec.NodeName,
 					containerStat.Name,
 					containerStat.State.Waiting.Reason,
 					containerStat.State.Waiting.Message,
+					describeContainerState(containerStat),
 				)
 				log.WithError(err).Errorln("Capturing unexpected container error.")
-				return fmt.Errorf("pod state error: %s; stage: %w", err.Error(), reason)
+				return classify(podFailureClass(containerStat.State.Waiting.Reason), fmt.Errorf("pod state error: %s; stage: %w", err.Error(), reason))
 			}
 
 			// Inspect events associated with the pod for errors.
@@ -336,7 +574,7 @@ func (r *CheckRunner) checkDeploymentPodEvent(ctx context.Context, reason error)
 					eventMsg,
 				)
 				log.WithError(err).Errorln("Capturing unexpected pod event.")
-				return fmt.Errorf("pod event error: %s; stage: %w", err.Error(), reason)
+				return classify(podFailureClass(eventReason), fmt.Errorf("pod event error: %s; stage: %w", err.Error(), reason))
 			}
 		}
 
@@ -349,7 +587,7 @@ func (r *CheckRunner) checkDeploymentPodEvent(ctx context.Context, reason error)
 				pod.Status.Message,
 			)
 			log.WithError(err).Errorln("Pod in failed status.")
-			return fmt.Errorf("pod failed: %s; stage: %w", err.Error(), reason)
+			return classify(podFailureClass(pod.Status.Reason), fmt.Errorf("pod failed: %s; stage: %w", err.Error(), reason))
 		}
 	}
 
@@ -382,7 +620,8 @@ func deploymentAvailable(deployment *appsv1.Deployment, replicas int) bool {
 		if deployment.Status.ReadyReplicas != int32(replicas) {
 			continue
 		}
-		if deployment.Status.ObservedGeneration != 1 {
+		// Compare against the current generation since admission may mutate the spec.
+		if deployment.Status.ObservedGeneration < deployment.Generation {
 			continue
 		}
 
@@ -394,6 +633,28 @@ func deploymentAvailable(deployment *appsv1.Deployment, replicas int) bool {
 	return false
 }
 
+// progressDeadlineExceeded reports whether the deployment controller gave up on the current rollout, with the controller's message.
+func progressDeadlineExceeded(deployment *appsv1.Deployment) (bool, error) {
+	// Guard against nil inputs and status that predates the latest spec.
+	if deployment == nil || deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, nil
+	}
+
+	// Look for Progressing=False with the deadline reason.
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type != appsv1.DeploymentProgressing || condition.Status != corev1.ConditionFalse || condition.Reason != "ProgressDeadlineExceeded" {
+			continue
+		}
+		deadline := "its"
+		if deployment.Spec.ProgressDeadlineSeconds != nil {
+			deadline = fmt.Sprintf("its %ds", *deployment.Spec.ProgressDeadlineSeconds)
+		}
+		return true, fmt.Errorf("deployment %s exceeded %s progress deadline (ProgressDeadlineExceeded): %s", deployment.Name, deadline, condition.Message)
+	}
+
+	return false, nil
+}
+
 // rolledPodsAreReady checks if updated pods are available after a rolling update.
 func rolledPodsAreReady(deployment *appsv1.Deployment, replicas int) bool {
 	// Guard against nil inputs.
@@ -417,38 +678,9 @@ func rolledPodsAreReady(deployment *appsv1.Deployment, replicas int) bool {
 	if deployment.Status.UnavailableReplicas >= 1 {
 		return false
 	}
-	if deployment.Status.ObservedGeneration <= 1 {
+	if deployment.Generation <= 1 || deployment.Status.ObservedGeneration < deployment.Generation {
 		return false
 	}
 
 	return true
 }
-
-// waitForDeploymentDelete watches for a deployment delete event.
-func (r *CheckRunner) waitForDeploymentDelete(ctx context.Context) error {
-	// Start a watch for deletion events.
-	watcher, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Watch(ctx, metav1.ListOptions{
-		Watch:         true,
-		FieldSelector: "metadata.name=" + r.cfg.CheckDeploymentName,
-	})
-	if err != nil {
-		return err
-	}
-	defer watcher.Stop()
-
-	// Consume watch events until deleted.
-	for event := range watcher.ResultChan() {
-		deployment, ok := event.Object.(*appsv1.Deployment)
-		if !ok {
-			log.Infoln("Got a watch event for a non-deployment object -- ignoring.")
-			continue
-		}
-		log.Debugln("Received an event watching for deployment changes:", deployment.Name, "got event", event.Type)
-		if event.Type == watch.Deleted {
-			log.Infoln("Received", event.Type, "while watching for deployment", deployment.Name, "to be deleted")
-			return nil
-		}
-	}
-
-	return fmt.Errorf("deployment watch channel closed without delete event")
-}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// TestCheckContainerRestarts validates crash loop detection against the restart threshold.
+func TestCheckContainerRestarts(t *testing.T) {
+	// Build a runner with a restart threshold of three.
+	runner := buildTestRunner()
+	runner.cfg.MaxContainerRestarts = 3
+
+	// Build a pod whose container has restarted below and then at the threshold.
+	pod := corev1.Pod{}
+	pod.Name = "deployment-pod"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         defaultCheckContainerName,
+		RestartCount: 2,
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 137},
+		},
+	}}
+
+	err := runner.checkContainerRestarts([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err != nil {
+		t.Fatalf("expected no crash loop below the threshold but got: %v", err)
+	}
+
+	pod.Status.ContainerStatuses[0].RestartCount = 3
+	err = runner.checkContainerRestarts([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err == nil {
+		t.Fatalf("expected a crash loop error at the threshold")
+	}
+
+	if !strings.Contains(err.Error(), "exit code: 137") {
+		t.Fatalf("expected the last exit code in the error but got: %s", err.Error())
+	}
+}
+
+// TestCheckContainerRestartsDisabledByDefault validates restarts are left to the check deadline unless a threshold is set.
+func TestCheckContainerRestartsDisabledByDefault(t *testing.T) {
+	// Leave the threshold at its default.
+	runner := buildTestRunner()
+	runner.cfg.MaxContainerRestarts = defaultMaxContainerRestarts
+	pod := corev1.Pod{}
+	pod.Name = "deployment-pod"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: defaultCheckContainerName, RestartCount: 10}}
+
+	err := runner.checkContainerRestarts([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err != nil {
+		t.Fatalf("expected the check to be off by default but got: %v", err)
+	}
+}
+
+// TestCheckFatalWaitingReasons validates only configured waiting reasons fail the check and are classed by reason.
+func TestCheckFatalWaitingReasons(t *testing.T) {
+	// Configure the default fatal reasons and a container waiting on a slow start.
+	runner := buildTestRunner()
+	runner.cfg.FatalWaitingReasons = map[string]bool{"ImagePullBackOff": true, "ErrImagePull": true}
+	pod := corev1.Pod{}
+	pod.Name = "deployment-pod"
+	pod.Spec.NodeName = "node-a"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  defaultCheckContainerName,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+	}}
+
+	err := runner.checkFatalWaitingReasons([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err != nil {
+		t.Fatalf("expected no failure for a reason that is not fatal but got: %v", err)
+	}
+
+	// A bad image fails immediately as an image failure.
+	pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "manifest unknown"}
+	err = runner.checkFatalWaitingReasons([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err == nil || !strings.Contains(err.Error(), "reason: ImagePullBackOff msg: manifest unknown") {
+		t.Fatalf("expected a fatal ImagePullBackOff error but got: %v", err)
+	}
+	if classifyFailure(err) != failureClassImage {
+		t.Fatalf("expected an image failure class but got %s", classifyFailure(err))
+	}
+
+	// Fast-fail is off when no reasons are configured.
+	runner.cfg.FatalWaitingReasons = map[string]bool{}
+	err = runner.checkFatalWaitingReasons([]corev1.Pod{pod}, errDeploymentCreatePod)
+	if err != nil {
+		t.Fatalf("expected no failure with fast-fail disabled but got: %v", err)
+	}
+}
+
+// TestCheckUnschedulablePods validates unschedulable pods fail only after the window and outside provisioning modes.
+func TestCheckUnschedulablePods(t *testing.T) {
+	// Build a runner with a two minute window.
+	runner := buildTestRunner()
+	runner.cfg.UnschedulableTimeout = time.Minute * 2
+
+	// Build a pod the scheduler could not place.
+	created := time.Now()
+	pod := corev1.Pod{}
+	pod.Name = "deployment-pod"
+	pod.CreationTimestamp = metav1.NewTime(created)
+	pod.Status.Conditions = []corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient cpu.",
+	}}
+
+	err := runner.checkUnschedulablePods([]corev1.Pod{pod}, errDeploymentCreatePod, created.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no failure within the window but got: %v", err)
+	}
+
+	err = runner.checkUnschedulablePods([]corev1.Pod{pod}, errDeploymentCreatePod, created.Add(time.Minute*3))
+	if err == nil || !strings.Contains(err.Error(), "Insufficient cpu") || classifyFailure(err) != failureClassScheduling {
+		t.Fatalf("expected a scheduling failure quoting the scheduler but got: %v", err)
+	}
+
+	// Pods waiting for provisioned capacity are left to the provisioning window.
+	runner.cfg.AutoscalerMode = true
+	err = runner.checkUnschedulablePods([]corev1.Pod{pod}, errDeploymentCreatePod, created.Add(time.Minute*3))
+	if err != nil {
+		t.Fatalf("expected autoscaler mode to skip the check but got: %v", err)
+	}
+}
+
+// TestCheckUnschedulablePodsDisabledByDefault validates unschedulable pods wait for the check deadline unless a window is set.
+func TestCheckUnschedulablePodsDisabledByDefault(t *testing.T) {
+	// Leave the window at its default.
+	runner := buildTestRunner()
+	runner.cfg.UnschedulableTimeout = defaultUnschedulableTimeout
+	pod := corev1.Pod{}
+	pod.Name = "deployment-pod"
+	pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}}
+
+	err := runner.checkUnschedulablePods([]corev1.Pod{pod}, errDeploymentCreatePod, time.Now())
+	if err != nil {
+		t.Fatalf("expected the check to be off by default but got: %v", err)
+	}
+}
+
+// TestDeleteOrphanedReplicaSets validates only the current run's replica sets are removed after an orphaning delete.
+func TestDeleteOrphanedReplicaSets(t *testing.T) {
+	// Seed a replica set from this run and one from another run.
+	runner := buildTestRunner()
+	current := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:      "current",
+		Namespace: runner.cfg.CheckNamespace,
+		Labels:    map[string]string{deploymentLabelKey: deploymentLabelValueBase + strconv.FormatInt(runner.now.Unix(), 10)},
+	}}
+	other := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:      "other",
+		Namespace: runner.cfg.CheckNamespace,
+		Labels:    map[string]string{deploymentLabelKey: deploymentLabelValueBase + "1"},
+	}}
+	runner.client = fake.NewClientset(current, other)
+
+	err := runner.deleteOrphanedReplicaSets(context.Background())
+	if err != nil {
+		t.Fatalf("expected the orphaned replica sets to delete but got %v", err)
+	}
+	remaining, err := runner.client.AppsV1().ReplicaSets(runner.cfg.CheckNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list replica sets: %v", err)
+	}
+	if len(remaining.Items) != 1 || remaining.Items[0].Name != "other" {
+		t.Fatalf("expected only the other run's replica set to remain but got %v", remaining.Items)
+	}
+}
+
+// TestDeleteDeploymentAndWait validates the wait finishes once the deployment is gone and gives up after the delete timeout.
+func TestDeleteDeploymentAndWait(t *testing.T) {
+	// Seed the check deployment.
+	runner := buildTestRunner()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckDeploymentName, Namespace: runner.cfg.CheckNamespace}}
+	runner.client = fake.NewClientset(deployment)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.deleteDeploymentAndWait(ctx)
+	if err != nil {
+		t.Fatalf("expected the deployment to delete but got %v", err)
+	}
+
+	// A deployment held by a finalizer times out after the delete timeout instead of the run deadline.
+	deleted := metav1.NewTime(time.Now())
+	deployment.DeletionTimestamp = &deleted
+	deployment.Finalizers = []string{"example.com/hold"}
+	client := fake.NewClientset(deployment)
+	client.PrependReactor("delete", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	runner.client = client
+	runner.cfg.DeleteTimeout = time.Millisecond * 200
+	err = runner.deleteDeploymentAndWait(ctx)
+	if err == nil || !strings.Contains(err.Error(), "timed out while waiting for deployment to delete") || ctx.Err() != nil {
+		t.Fatalf("expected the delete timeout before the deadline but got %v", err)
+	}
+}
+
+// TestCreateDeploymentAndWaitCleansUpOnPodError validates a fail-fast pod error removes the deployment before returning.
+func TestCreateDeploymentAndWaitCleansUpOnPodError(t *testing.T) {
+	// Seed a run pod stuck on a bad image.
+	runner := buildTestRunner()
+	runner.cfg.CheckImageURL = "nginx:missing"
+	runner.cfg.CheckTimeLimit = time.Minute
+	runner.cfg.FatalWaitingReasons = map[string]bool{"ImagePullBackOff": true}
+	pod := probeTestPod(runner, "bad-image", false)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  defaultCheckContainerName,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+	}}
+	client := fake.NewClientset(pod)
+	runner.client = client
+
+	// Garbage collect the pod with its deployment, as the cluster would.
+	client.PrependReactor("delete", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		_ = client.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), pod.Namespace, pod.Name)
+		return false, nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	_, err := runner.createDeploymentAndWait(ctx, time.Now().Add(runner.cfg.CheckTimeLimit))
+	if err == nil || !strings.Contains(err.Error(), "reason: ImagePullBackOff") {
+		t.Fatalf("expected the fatal waiting reason but got %v", err)
+	}
+
+	// The failed deployment is not left behind for the next run.
+	deployments, err := client.AppsV1().Deployments(runner.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil || len(deployments.Items) != 0 {
+		t.Fatalf("expected the deployment to be cleaned up but got %v (%v)", deployments, err)
+	}
+}
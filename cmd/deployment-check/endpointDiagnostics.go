@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// podProbeTimeout bounds each direct request to a pod IP.
+	podProbeTimeout = time.Second * 5
+)
+
+// podProbeResult is the outcome of a direct request to a single pod.
+type podProbeResult struct {
+	// pod is the name of the probed pod.
+	pod string
+	// node is the node hosting the pod.
+	node string
+	// address is the pod IP that was requested.
+	address string
+	// err joins the failures of every port, or is nil when the pod responded on all of them.
+	err error
+}
+
+// probePodEndpoints requests every ready check pod directly on each container port.
+func (r *CheckRunner) probePodEndpoints(ctx context.Context) ([]podProbeResult, error) {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment pods for direct requests: %w", err)
+	}
+
+	// Request each ready pod that backs the service.
+	results := make([]podProbeResult, 0)
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.transport}
+	for _, pod := range podList.Items {
+		if len(pod.Status.PodIP) == 0 || pod.DeletionTimestamp != nil || !podIsReady(pod) {
+			continue
+		}
+		result := podProbeResult{pod: pod.Name, node: pod.Spec.NodeName, address: pod.Status.PodIP}
+		portErrors := make([]string, 0)
+		for _, port := range r.cfg.checkPorts() {
+			address := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port.ContainerPort)))
+			err := r.requestPodOnce(ctx, client, address)
+			if err != nil {
+				portErrors = append(portErrors, fmt.Sprintf("%s: %s", address, err.Error()))
+			}
+		}
+		if len(portErrors) != 0 {
+			result.err = errors.New(strings.Join(portErrors, ", "))
+			log.Warnln("Direct request to pod", pod.Name, "on node", pod.Spec.NodeName, "failed:", result.err.Error())
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// requestPodOnce issues a single GET to an address with the configured scheme and path and expects an acceptable status.
+func (r *CheckRunner) requestPodOnce(ctx context.Context, client *http.Client, address string) error {
+	// Connect over TCP instead when the workload is not HTTP.
+	if r.cfg.Protocol == protocolTCP {
+		return r.connectOnce(ctx, address)
+	}
+
+	// Build the request bound to the caller context.
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.cfg.EndpointScheme+"://"+address+r.cfg.HTTPPath, nil)
+	if err != nil {
+		return err
+	}
+	r.applyRequestHeaders(request)
+
+	// Perform the request and validate the status.
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	closeErr := response.Body.Close()
+	if closeErr != nil {
+		log.Debugln("Failed to close response body:", closeErr.Error())
+	}
+	if !r.cfg.HTTPExpectedCodes.matches(response.StatusCode) {
+		return fmt.Errorf("received %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// diagnoseServiceFailure requests pods directly after a service failure to separate workload from service routing problems.
+func (r *CheckRunner) diagnoseServiceFailure(ctx context.Context, serviceErr error) error {
+	// Bound the diagnosis so it cannot eat the remaining deadline.
+	diagnoseCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	// Request every pod directly.
+	log.Infoln("Requesting check pods directly to isolate the service failure.")
+	results, err := r.probePodEndpoints(diagnoseCtx)
+	if err != nil {
+		return fmt.Errorf("%w; direct pod requests: %s", serviceErr, err.Error())
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("%w; direct pod requests: no ready pods to request", serviceErr)
+	}
+
+	// Summarize the per-pod outcomes.
+	summaries := make([]string, 0, len(results))
+	responding := 0
+	for _, result := range results {
+		if result.err == nil {
+			responding++
+			summaries = append(summaries, fmt.Sprintf("pod: %s node: %s address: %s ok", result.pod, result.node, result.address))
+			continue
+		}
+		summaries = append(summaries, fmt.Sprintf("pod: %s node: %s address: %s error: %s", result.pod, result.node, result.address, result.err.Error()))
+	}
+	r.timeline.recordf("%d of %d pod(s) responded to direct requests after the service failed", responding, len(results))
+
+	// Pods answering directly while the service does not points at kube-proxy or CNI service programming.
+	if responding == len(results) {
+		return classify(failureClassNetworking, fmt.Errorf("%w; all %d pod(s) respond directly, so the service path (kube-proxy/CNI) is failing: %s", serviceErr, len(results), strings.Join(summaries, "; ")))
+	}
+	if responding == 0 {
+		return classify(failureClassRollout, fmt.Errorf("%w; no pods respond directly, so the workload itself is failing: %s", serviceErr, strings.Join(summaries, "; ")))
+	}
+
+	return classify(failureClassNetworking, fmt.Errorf("%w; %d of %d pod(s) respond directly: %s", serviceErr, responding, len(results), strings.Join(summaries, "; ")))
+}
+
+// podIsReady reports whether the pod's Ready condition is true.
+func podIsReady(pod corev1.Pod) bool {
+	// Scan pod conditions for readiness.
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// probeTestPod builds a run pod on the loopback address with the given readiness.
+func probeTestPod(runner *CheckRunner, name string, ready bool) *corev1.Pod {
+	// Label the pod for the current run and report the requested readiness.
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: runner.cfg.CheckNamespace,
+			Labels:    map[string]string{deploymentLabelKey: deploymentLabelValueBase + strconv.FormatInt(runner.now.Unix(), 10)},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-" + name},
+		Status: corev1.PodStatus{
+			PodIP:      "127.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+// probeTestServer starts a server answering with a fixed status and returns its port.
+func probeTestServer(t *testing.T, status int) int32 {
+	// Answer every request with the status.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("failed to split server address: %v", err)
+	}
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	return int32(value)
+}
+
+// TestProbePodEndpointsCoversEveryPort validates each ready pod is requested on every container port.
+func TestProbePodEndpointsCoversEveryPort(t *testing.T) {
+	// Serve the primary port and fail the additional one.
+	runner := buildTestRunner()
+	runner.cfg.CheckContainerPort = probeTestServer(t, http.StatusOK)
+	failingPort := probeTestServer(t, http.StatusInternalServerError)
+	runner.cfg.CheckAdditionalPorts = []checkPort{{ContainerPort: failingPort, ServicePort: 9090}}
+	runner.client = fake.NewClientset(probeTestPod(runner, "ready", true), probeTestPod(runner, "starting", false))
+
+	// Only the ready pod is requested, and the failing port is named.
+	results, err := runner.probePodEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("expected the probe to run but got %v", err)
+	}
+	if len(results) != 1 || results[0].pod != "ready" || results[0].node != "node-ready" {
+		t.Fatalf("expected one result for the ready pod but got %+v", results)
+	}
+	if results[0].err == nil || !strings.Contains(results[0].err.Error(), strconv.Itoa(int(failingPort))) {
+		t.Fatalf("expected the additional port failure to be reported but got %v", results[0].err)
+	}
+	if strings.Contains(results[0].err.Error(), strconv.Itoa(int(runner.cfg.CheckContainerPort))) {
+		t.Fatalf("expected the primary port to pass but got %v", results[0].err)
+	}
+}
+
+// TestDiagnoseServiceFailure validates the service failure is blamed on the service path or the workload.
+func TestDiagnoseServiceFailure(t *testing.T) {
+	// Pods answering directly point at the service path.
+	serviceErr := errors.New("service request failed")
+	runner := buildTestRunner()
+	runner.cfg.CheckContainerPort = probeTestServer(t, http.StatusOK)
+	runner.client = fake.NewClientset(probeTestPod(runner, "a", true), probeTestPod(runner, "b", true))
+	err := runner.diagnoseServiceFailure(context.Background(), serviceErr)
+	if !errors.Is(err, serviceErr) || classifyFailure(err) != failureClassNetworking || !strings.Contains(err.Error(), "kube-proxy/CNI") {
+		t.Fatalf("expected a networking failure on the service path but got %v", err)
+	}
+
+	// Pods failing directly point at the workload.
+	runner = buildTestRunner()
+	runner.cfg.CheckContainerPort = probeTestServer(t, http.StatusServiceUnavailable)
+	runner.client = fake.NewClientset(probeTestPod(runner, "a", true))
+	err = runner.diagnoseServiceFailure(context.Background(), serviceErr)
+	if !errors.Is(err, serviceErr) || classifyFailure(err) != failureClassRollout {
+		t.Fatalf("expected a rollout failure but got %v", err)
+	}
+
+	// No ready pods leaves the original error with a note.
+	runner = buildTestRunner()
+	runner.client = fake.NewClientset(probeTestPod(runner, "a", false))
+	err = runner.diagnoseServiceFailure(context.Background(), serviceErr)
+	if !errors.Is(err, serviceErr) || !strings.Contains(err.Error(), "no ready pods") {
+		t.Fatalf("expected the missing pods to be noted but got %v", err)
+	}
+}
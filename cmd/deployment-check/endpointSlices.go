@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// endpointSliceTimeout bounds the wait for the endpoint controller to publish every ready replica.
+	endpointSliceTimeout = time.Minute * 2
+)
+
+// verifyEndpointSlices waits for the service's EndpointSlices to list exactly the ready replicas as ready endpoints.
+func (r *CheckRunner) verifyEndpointSlices(ctx context.Context) error {
+	// Poll until the slices match or the wait runs out, keeping the last mismatch for the report.
+	started := time.Now()
+	deadline := started.Add(endpointSliceTimeout)
+	for {
+		err := r.checkEndpointSlices(ctx)
+		if err == nil {
+			lag := time.Since(started).Round(time.Second)
+			log.Infoln("EndpointSlices list all", r.cfg.CheckDeploymentReplicas, "ready replica(s) after", lag)
+			r.timeline.recordf("endpoint slices listed %d ready endpoint(s) within %s", r.cfg.CheckDeploymentReplicas, lag)
+			return nil
+		}
+		log.Debugln("EndpointSlices do not match the ready pods yet:", err.Error())
+		if time.Now().After(deadline) {
+			return fmt.Errorf("endpoint slices did not match the ready pods within %s: %w", endpointSliceTimeout, err)
+		}
+
+		// Wait before listing again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while verifying endpoint slices: %w", err)
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+// checkEndpointSlices lists the service's EndpointSlices once and compares their ready endpoints with the ready pods.
+func (r *CheckRunner) checkEndpointSlices(ctx context.Context) error {
+	// Collect the names of ready pods for this run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	expected := make(map[string]bool)
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && podIsReady(pod) {
+			expected[pod.Name] = true
+		}
+	}
+	if len(expected) != r.cfg.CheckDeploymentReplicas {
+		return fmt.Errorf("expected %d ready pod(s) but found %d", r.cfg.CheckDeploymentReplicas, len(expected))
+	}
+
+	// Collect the ready endpoints across every slice of the service.
+	endpointSlices, err := r.client.DiscoveryV1().EndpointSlices(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + r.cfg.CheckServiceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list endpoint slices for service %s: %w", r.cfg.CheckServiceName, err)
+	}
+	ready, notReady := endpointSlicePods(endpointSlices.Items)
+	if !sameKeys(expected, ready) {
+		detail := fmt.Sprintf("%d endpoint slice(s) list ready pods [%s] but ready pods are [%s]", len(endpointSlices.Items), strings.Join(sortedKeys(ready), ", "), strings.Join(sortedKeys(expected), ", "))
+		if len(notReady) != 0 {
+			detail = detail + "; not ready endpoints [" + strings.Join(sortedKeys(notReady), ", ") + "]"
+		}
+		return fmt.Errorf("%s", detail)
+	}
+
+	return nil
+}
+
+// endpointSlicePods returns the pods behind ready and not-ready endpoints, merging address families.
+func endpointSlicePods(endpointSlices []discoveryv1.EndpointSlice) (map[string]bool, map[string]bool) {
+	// Key endpoints by pod so dual-stack slices do not count a pod twice.
+	ready := make(map[string]bool)
+	notReady := make(map[string]bool)
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			name := strings.Join(endpoint.Addresses, ",")
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				name = endpoint.TargetRef.Name
+			}
+
+			// An unset ready condition means unknown, which consumers treat as ready.
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready[name] = true
+				continue
+			}
+			notReady[name] = true
+		}
+	}
+
+	return ready, notReady
+}
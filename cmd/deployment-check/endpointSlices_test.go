@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// TestEndpointSlicePods validates ready endpoints are keyed by pod across address families.
+func TestEndpointSlicePods(t *testing.T) {
+	// Build dual-stack slices with one ready, one unknown, and one not-ready endpoint.
+	ready := true
+	notReady := false
+	endpointSlices := []discoveryv1.EndpointSlice{
+		{Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-a"}},
+			{Addresses: []string{"10.0.0.2"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-b"}},
+			{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-c"}},
+		}},
+		{Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"fd00::1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-a"}},
+		}},
+	}
+
+	readyPods, notReadyPods := endpointSlicePods(endpointSlices)
+	if !sameKeys(readyPods, map[string]bool{"pod-a": true, "pod-b": true}) {
+		t.Fatalf("expected pod-a and pod-b ready but got %v", sortedKeys(readyPods))
+	}
+
+	if !sameKeys(notReadyPods, map[string]bool{"pod-c": true}) {
+		t.Fatalf("expected pod-c not ready but got %v", sortedKeys(notReadyPods))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const (
+	// endpointSchemeHTTP requests check endpoints over plain HTTP.
+	endpointSchemeHTTP = "http"
+	// endpointSchemeHTTPS requests check endpoints over TLS.
+	endpointSchemeHTTPS = "https"
+)
+
+// tlsVersions maps configured minimum TLS versions to their crypto/tls values.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// buildEndpointTLSConfig assembles the TLS settings used for HTTPS endpoint requests.
+func buildEndpointTLSConfig(caFile string, insecureSkipVerify bool, minVersion string) (*tls.Config, error) {
+	// Resolve the minimum version.
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported minimum TLS version %q; use 1.0, 1.1, 1.2, or 1.3", minVersion)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         version,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	// Trust only the mounted CA bundle when one is configured.
+	if len(caFile) != 0 {
+		caBytes, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %s: %w", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// newEndpointTransport builds the HTTP transport used for every endpoint request.
+func newEndpointTransport(cfg *CheckConfig) http.RoundTripper {
+	// Start from the default transport and apply the TLS settings when configured.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.EndpointTLSConfig != nil {
+		transport.TLSClientConfig = cfg.EndpointTLSConfig
+	}
+
+	return transport
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+)
+
+// TestBuildEndpointTLSConfig validates minimum version parsing and CA bundle errors.
+func TestBuildEndpointTLSConfig(t *testing.T) {
+	// Build a config without a CA bundle.
+	tlsConfig, err := buildEndpointTLSConfig("", true, "1.3")
+	if err != nil {
+		t.Fatalf("unexpected error building TLS config: %v", err)
+	}
+
+	if tlsConfig.MinVersion != tls.VersionTLS13 || !tlsConfig.InsecureSkipVerify {
+		t.Fatalf("expected TLS 1.3 with verification skipped but got %x/%t", tlsConfig.MinVersion, tlsConfig.InsecureSkipVerify)
+	}
+
+	// Reject unknown versions and missing CA bundles.
+	_, err = buildEndpointTLSConfig("", false, "1.4")
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported TLS version")
+	}
+
+	_, err = buildEndpointTLSConfig("/nonexistent/ca.crt", false, "1.2")
+	if err == nil {
+		t.Fatalf("expected an error for a missing CA bundle")
+	}
+}
+
+// TestParseTLSSettingsRequireHTTPS validates TLS settings are rejected rather than ignored without the https scheme.
+func TestParseTLSSettingsRequireHTTPS(t *testing.T) {
+	// Skip verification against a plain http endpoint.
+	t.Setenv("CHECK_TLS_INSECURE_SKIP_VERIFY", "true")
+	_, err := parseConfig()
+	if err == nil || !strings.Contains(err.Error(), "require CHECK_ENDPOINT_SCHEME=https") {
+		t.Fatalf("expected TLS settings without https to fail but got %v", err)
+	}
+
+	// The same settings apply to https endpoints.
+	t.Setenv("CHECK_ENDPOINT_SCHEME", "https")
+	cfg, err := parseConfig()
+	if err != nil {
+		t.Fatalf("unexpected error parsing config: %v", err)
+	}
+	if cfg.EndpointTLSConfig == nil || !cfg.EndpointTLSConfig.InsecureSkipVerify {
+		t.Fatalf("expected verification to be skipped for https endpoints but got %+v", cfg.EndpointTLSConfig)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// failureClass groups failures by the team most likely to own them.
+type failureClass string
+
+const (
+	// failureClassScheduling covers pods that could not be placed due to capacity or constraints.
+	failureClassScheduling failureClass = "scheduling"
+	// failureClassAdmission covers RBAC, quota, and admission webhook rejections.
+	failureClassAdmission failureClass = "admission"
+	// failureClassImage covers registry and image pull failures.
+	failureClassImage failureClass = "image"
+	// failureClassNetworking covers service and data-path failures.
+	failureClassNetworking failureClass = "networking"
+	// failureClassDNS covers service names that do not resolve, resolve wrongly, or resolve slowly.
+	failureClassDNS failureClass = "dns"
+	// failureClassRollout covers deployments and pods that never became ready.
+	failureClassRollout failureClass = "rollout"
+	// failureClassStorage covers claims that never bind and volumes that fail to provision, attach, or mount.
+	failureClassStorage failureClass = "storage"
+	// failureClassCertificate covers certificates that are never issued or not served as issued.
+	failureClassCertificate failureClass = "certificate"
+	// failureClassCleanup covers failures removing check resources.
+	failureClassCleanup failureClass = "cleanup"
+	// failureClassUnknown is used when no better class can be determined.
+	failureClassUnknown failureClass = "unknown"
+)
+
+// classifiedError tags an error with the class of failure it represents.
+type classifiedError struct {
+	// class is the failure class assigned where the error was produced.
+	class failureClass
+	// err is the underlying error.
+	err error
+}
+
+// Error returns the underlying error message.
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap exposes the underlying error to errors.Is and errors.As.
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// classify tags err with a failure class, passing nil errors through.
+func classify(class failureClass, err error) error {
+	// Guard against nil errors.
+	if err == nil {
+		return nil
+	}
+
+	return &classifiedError{class: class, err: err}
+}
+
+// classifyFailure determines the failure class for an error returned by a run.
+func classifyFailure(err error) failureClass {
+	// Guard against nil errors.
+	if err == nil {
+		return failureClassUnknown
+	}
+
+	// API rejections point at RBAC, quota, or admission regardless of where they happened.
+	if k8serrors.IsForbidden(err) || k8serrors.IsInvalid(err) || k8serrors.IsUnauthorized(err) {
+		return failureClassAdmission
+	}
+
+	// Prefer the class assigned where the error was produced.
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+
+	// Fall back to matching well-known reasons in the message.
+	return classifyPodReason(err.Error())
+}
+
+// classifyPodReason maps a pod or event reason to a failure class.
+func classifyPodReason(reason string) failureClass {
+	// Normalize the reason for matching.
+	lowered := strings.ToLower(reason)
+
+	// Match image and registry failures.
+	if strings.Contains(lowered, "imagepull") || strings.Contains(lowered, "errimage") || strings.Contains(lowered, "invalidimagename") {
+		return failureClassImage
+	}
+
+	// Match volume failures, including pods that cannot schedule because their claim never bound.
+	if strings.Contains(lowered, "persistentvolumeclaim") || strings.Contains(lowered, "failedmount") || strings.Contains(lowered, "failedattachvolume") || strings.Contains(lowered, "provisioningfailed") {
+		return failureClassStorage
+	}
+
+	// Match scheduling and capacity failures.
+	if strings.Contains(lowered, "failedscheduling") || strings.Contains(lowered, "unschedulable") || strings.Contains(lowered, "insufficient") {
+		return failureClassScheduling
+	}
+
+	// Match admission and quota failures surfaced through events.
+	if strings.Contains(lowered, "exceeded quota") || strings.Contains(lowered, "forbidden") || strings.Contains(lowered, "admission webhook") {
+		return failureClassAdmission
+	}
+
+	return failureClassUnknown
+}
+
+// podFailureClass maps a pod or event reason to a failure class, defaulting to rollout.
+func podFailureClass(reason string) failureClass {
+	// Use the reason-specific class when one matches.
+	class := classifyPodReason(reason)
+	if class == failureClassUnknown {
+		return failureClassRollout
+	}
+
+	return class
+}
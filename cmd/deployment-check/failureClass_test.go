@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestClassifyFailure validates failure classes for tagged, API, and reason-based errors.
+func TestClassifyFailure(t *testing.T) {
+	// Build an API forbidden error like a quota rejection.
+	forbidden := k8serrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "check", errors.New("exceeded quota"))
+
+	// Define errors and the class expected for each.
+	cases := map[error]failureClass{
+		classify(failureClassNetworking, errors.New("service request failed")):                              failureClassNetworking,
+		fmt.Errorf("outer: %w", classify(failureClassDNS, errors.New("lookup failed"))):                     failureClassDNS,
+		fmt.Errorf("outer: %w", classify(failureClassCleanup, errors.New("cleanup failed"))):                failureClassCleanup,
+		classify(failureClassNetworking, fmt.Errorf("failed to create service: %w", forbidden)):             failureClassAdmission,
+		errors.New("pod: a reason: ImagePullBackOff"):                                                       failureClassImage,
+		errors.New("pod: a reason: FailedScheduling msg: 0/3 nodes are available"):                          failureClassScheduling,
+		errors.New("pod: a reason: FailedScheduling msg: pod has unbound immediate PersistentVolumeClaims"): failureClassStorage,
+		errors.New("pod: a reason: FailedMount msg: secret \"check\" not found"):                            failureClassStorage,
+		errors.New("something unexpected"):                                                                  failureClassUnknown,
+	}
+
+	// Validate the class for each error.
+	for err, expected := range cases {
+		class := classifyFailure(err)
+		if class != expected {
+			t.Fatalf("expected class %s for error %q but got %s", expected, err.Error(), class)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// configSetting describes an environment variable that can also be set with a command-line flag.
+type configSetting struct {
+	// env is the environment variable name read by parseConfig.
+	env string
+	// usage is the flag help text.
+	usage string
+	// boolean allows the flag to be passed without a value.
+	boolean bool
+}
+
+// configSettings lists every environment variable the check reads; each is mirrored by a flag.
+var configSettings = []configSetting{
+	{env: "DEBUG", usage: "enable debug logging", boolean: true},
+	{env: "CHECK_IMAGE", usage: "container image for the check deployment"},
+	{env: "CHECK_IMAGE_VERIFY", usage: "confirm the initial pods run the configured image", boolean: true},
+	{env: "CHECK_IMAGE_DIGEST", usage: "sha256 digest the initial pods must report for their image"},
+	{env: "CHECK_IMAGE_ROLL_TO", usage: "container image used for the rolling update"},
+	{env: "CHECK_IMAGE_ROLL_SEQUENCE", usage: "comma-separated images the rolling update walks through in order"},
+	{env: "CHECK_IMAGE_PULL_SECRET", usage: "image pull secret for the check pods"},
+	{env: "CHECK_DEPLOYMENT_NAME", usage: "name of the check deployment"},
+	{env: "CHECK_SERVICE_NAME", usage: "name of the check service"},
+	{env: "CHECK_CONTAINER_NAME", usage: "name of the check container"},
+	{env: "CHECK_CONTAINER_PORT", usage: "container port served by the check image"},
+	{env: "CHECK_LOAD_BALANCER_PORT", usage: "service port requested by the check"},
+	{env: "CHECK_PROTOCOL", usage: "how endpoints are validated: http or tcp"},
+	{env: "CHECK_TCP_BANNER", usage: "text expected in the server banner in tcp mode"},
+	{env: "CHECK_HTTP_PATH", usage: "path requested on check endpoints"},
+	{env: "CHECK_HTTP_HEADERS", usage: "Name=Value request headers, including Host"},
+	{env: "CHECK_HTTP_EXPECTED_CODES", usage: "acceptable status codes and ranges, such as 200-299"},
+	{env: "CHECK_HTTP_REQUEST_COUNT", usage: "number of load test requests sent to the service"},
+	{env: "CHECK_HTTP_CONCURRENCY", usage: "number of load test requests in flight at once"},
+	{env: "CHECK_HTTP_MAX_ERROR_RATE", usage: "fraction of load test requests allowed to fail"},
+	{env: "CHECK_ENDPOINT_SCHEME", usage: "scheme used to request check endpoints: http or https"},
+	{env: "CHECK_TLS_CA_FILE", usage: "CA bundle trusted for https endpoints"},
+	{env: "CHECK_TLS_INSECURE_SKIP_VERIFY", usage: "skip certificate verification for https endpoints", boolean: true},
+	{env: "CHECK_TLS_MIN_VERSION", usage: "minimum TLS version for https endpoints"},
+	{env: "CHECK_SERVICE_TYPE", usage: "service type to create: ClusterIP, NodePort, or LoadBalancer"},
+	{env: "CHECK_LOAD_BALANCER_TIMEOUT", usage: "window for cloud load balancer provisioning"},
+	{env: "CHECK_NODE_PORT_NODES", usage: "number of nodes requested on each node port"},
+	{env: "CHECK_ADDITIONAL_PORTS", usage: "extra containerPort:servicePort pairs to validate"},
+	{env: "CHECK_NAMESPACE", usage: "namespace for the check resources"},
+	{env: "CHECK_NAMESPACES", usage: "comma-separated namespaces to run the full check in, one after another"},
+	{env: "CHECK_NAMESPACES_PARALLEL", usage: "run the CHECK_NAMESPACES checks in parallel", boolean: true},
+	{env: "CHECK_DEPLOYMENT_REPLICAS", usage: "number of check pods"},
+	{env: "TOLERATIONS", usage: "tolerations for the check pods"},
+	{env: "NODE_SELECTOR", usage: "key=value node selectors for the check pods"},
+	{env: "CHECK_POD_CPU_REQUEST", usage: "CPU request in millicores"},
+	{env: "CHECK_POD_CPU_LIMIT", usage: "CPU limit in millicores"},
+	{env: "CHECK_POD_MEM_REQUEST", usage: "memory request in Mi"},
+	{env: "CHECK_POD_MEM_LIMIT", usage: "memory limit in Mi"},
+	{env: "CHECK_POD_EPHEMERAL_STORAGE_REQUEST", usage: "ephemeral-storage request as a quantity such as 100Mi"},
+	{env: "CHECK_POD_EPHEMERAL_STORAGE_LIMIT", usage: "ephemeral-storage limit as a quantity such as 1Gi"},
+	{env: "CHECK_POD_EXTENDED_RESOURCES", usage: "comma-separated name=count extended resources such as nvidia.com/gpu=1"},
+	{env: "CHECK_INIT_CONTAINERS", usage: "semicolon-separated image=command init containers to run before the check container"},
+	{env: "CHECK_SIDECAR_IMAGE", usage: "image for a sidecar container in the check pods"},
+	{env: "CHECK_SIDECAR_COMMAND", usage: "whitespace-separated command overriding the sidecar entrypoint"},
+	{env: "CHECK_SIDECAR_PORT", usage: "sidecar port probed for readiness"},
+	{env: "CHECK_SIDECAR_NATIVE", usage: "run the sidecar as a native restartable init container", boolean: true},
+	{env: "CHECK_EMPTYDIR_MOUNT_PATH", usage: "mount path for a scratch emptyDir volume"},
+	{env: "CHECK_CONFIGMAP_MOUNT_PATH", usage: "mount path for a ConfigMap generated by the check"},
+	{env: "CHECK_SECRET_NAME", usage: "existing Secret to mount in the check container"},
+	{env: "CHECK_SECRET_MOUNT_PATH", usage: "mount path for CHECK_SECRET_NAME"},
+	{env: "CHECK_PVC_STORAGE_CLASS", usage: "StorageClass for a persistent volume claim mounted in the check pods"},
+	{env: "CHECK_PVC_SIZE", usage: "storage requested by the check claim"},
+	{env: "CHECK_PVC_ACCESS_MODE", usage: "access mode of the check claim: ReadWriteOnce, ReadWriteMany, or ReadWriteOncePod"},
+	{env: "CHECK_PVC_MOUNT_PATH", usage: "mount path for the check claim"},
+	{env: "CHECK_PROJECTED_TOKEN_AUDIENCE", usage: "audience of a projected service account token to mount and verify"},
+	{env: "CHECK_PROJECTED_TOKEN_EXPIRATION", usage: "requested lifetime of the projected token, at least 10m"},
+	{env: "CHECK_PROJECTED_TOKEN_MOUNT_PATH", usage: "mount path for the projected token"},
+	{env: "CHECK_CERT_MANAGER_ISSUER", usage: "cert-manager issuer to request a certificate from; enables the cert-manager stage"},
+	{env: "CHECK_CERT_MANAGER_ISSUER_KIND", usage: "cert-manager issuer kind, Issuer or ClusterIssuer"},
+	{env: "CHECK_CERT_MANAGER_MOUNT_PATH", usage: "where the issued certificate is mounted in the check container"},
+	{env: "CHECK_CERT_MANAGER_PORT", usage: "service port that serves the issued certificate over HTTPS"},
+	{env: "CHECK_CERT_MANAGER_TIMEOUT", usage: "window for the certificate to be issued"},
+	{env: "CHECK_VOLUME_VERIFY", usage: "exec into the check pods to confirm the mounted volumes are usable", boolean: true},
+	{env: "CHECK_SERVICE_ACCOUNT", usage: "service account for the check pods"},
+	{env: "CHECK_CREATE_NAMESPACE", usage: "run each check in an ephemeral namespace that is deleted afterward", boolean: true},
+	{env: "CHECK_NAMESPACE_LABELS", usage: "extra key=value labels on the ephemeral namespace"},
+	{env: "CHECK_DEPLOYMENT_ROLLING_UPDATE", usage: "perform a rolling update after the initial rollout", boolean: true},
+	{env: "CHECK_DEPLOYMENT_ROLLBACK", usage: "roll back to the original image after the rolling update and validate again", boolean: true},
+	{env: "CHECK_LABELS", usage: "extra key=value labels on every resource the check creates"},
+	{env: "CHECK_DEPLOYMENT_ANNOTATIONS", usage: "extra key=value annotations on the check deployment"},
+	{env: "CHECK_POD_ANNOTATIONS", usage: "extra key=value annotations on the check pods"},
+	{env: "CHECK_MESH_MODE", usage: "service mesh handling for the check pods: istio"},
+	{env: "CHECK_MESH_INJECT", usage: "request sidecar injection in mesh mode", boolean: true},
+	{env: "CHECK_MESH_HOLD_APPLICATION", usage: "start the check container only after the mesh proxy", boolean: true},
+	{env: "CHECK_MESH_QUIT_ON_CLEANUP", usage: "ask mesh proxies to exit during cleanup", boolean: true},
+	{env: "CHECK_SERVICE_ANNOTATIONS", usage: "extra key=value annotations on the check services"},
+	{env: "ADDITIONAL_ENV_VARS", usage: "extra key=value environment variables for the check container"},
+	{env: "SHUTDOWN_GRACE_PERIOD", usage: "time allowed for cleanup after an interrupt"},
+	{env: "CHECK_TERMINATION_MESSAGE_FALLBACK_TO_LOGS", usage: "use container logs as the termination message when none is written", boolean: true},
+	{env: "CHECK_PROBE_INITIAL_DELAY_SECONDS", usage: "seconds before the first liveness and readiness probe"},
+	{env: "CHECK_PROBE_PERIOD_SECONDS", usage: "seconds between liveness and readiness probes"},
+	{env: "CHECK_PROBE_TIMEOUT_SECONDS", usage: "seconds each probe may take"},
+	{env: "CHECK_PROBE_FAILURE_THRESHOLD", usage: "failed liveness or readiness probes tolerated"},
+	{env: "CHECK_STARTUP_PROBE", usage: "add a startup probe that gates liveness and readiness probing", boolean: true},
+	{env: "CHECK_STARTUP_PROBE_FAILURE_THRESHOLD", usage: "failed startup probes tolerated before the container restarts"},
+	{env: "CHECK_STARTUP_PROBE_PERIOD_SECONDS", usage: "seconds between startup probes"},
+	{env: "CHECK_PROBE_TYPE", usage: "probe handler for the check container: tcp, http, or exec"},
+	{env: "CHECK_PROBE_PATH", usage: "path requested by http probes"},
+	{env: "CHECK_PROBE_PORT", usage: "port targeted by tcp and http probes"},
+	{env: "CHECK_PROBE_EXEC_COMMAND", usage: "command run by exec probes"},
+	{env: "CHECK_MAX_CONTAINER_RESTARTS", usage: "container restarts that fail the check; 0 disables"},
+	{env: "CHECK_ORPHAN_POLICY", usage: "how to handle resources left by a previous run: clean, warn, or fail"},
+	{env: "CHECK_OWNER_REFERENCE", usage: "make the checker pod own the deployment and services so they are garbage collected with it", boolean: true},
+	{env: "CHECK_RUN_LOCK", usage: "hold a Lease named after the check deployment so overlapping runs skip", boolean: true},
+	{env: "CHECK_RUN_LOCK_FAIL_WHEN_HELD", usage: "fail instead of skipping when another checker holds the run lock", boolean: true},
+	{env: "POD_NAME", usage: "checker pod name for owner references, defaulting to the hostname"},
+	{env: "CHECK_STALE_RESOURCE_AGE", usage: "delete check deployments and services under any name once their run label is this old"},
+	{env: "CHECK_RESOURCE_TTL", usage: "stamp created resources to expire after this long and delete expired check resources from any run"},
+	{env: "CHECK_EXPIRY_ANNOTATION", usage: "annotation key carrying the resource expiry time"},
+	{env: "CHECK_SERVER_SIDE_APPLY", usage: "create and update the deployment and service with server-side apply", boolean: true},
+	{env: "CHECK_FIELD_MANAGER", usage: "field manager name used for server-side apply"},
+	{env: "CLEANUP_ONLY", usage: "remove leftover check resources and exit without running the check", boolean: true},
+	{env: "CHECK_FATAL_WAITING_REASONS", usage: "container waiting reasons that fail the check immediately"},
+	{env: "CHECK_POD_ERROR_GRACE", usage: "time after pod creation before image pull failures, fatal waiting reasons, and pod events fail the check"},
+	{env: "CHECK_QUOTA_PREFLIGHT", usage: "verify resource quotas have room for the check pods before creating them", boolean: true},
+	{env: "CHECK_UNSCHEDULABLE_TIMEOUT", usage: "how long a pod may stay unschedulable before the check fails; 0 disables"},
+	{env: "CHECK_AUTOSCALER_MODE", usage: "verify the cluster autoscaler provisions a node", boolean: true},
+	{env: "CHECK_AUTOSCALER_TIMEOUT", usage: "window for autoscaler provisioning"},
+	{env: "CHECK_AUTOSCALER_NODE_SELECTOR", usage: "node selectors targeting the scale-up node group"},
+	{env: "CHECK_AUTOSCALER_CPU_REQUEST", usage: "CPU request in millicores that forces a scale-up"},
+	{env: "CHECK_KARPENTER_MODE", usage: "verify Karpenter launches a NodeClaim", boolean: true},
+	{env: "CHECK_KARPENTER_TIMEOUT", usage: "window for Karpenter provisioning"},
+	{env: "CHECK_KARPENTER_NODE_SELECTOR", usage: "node selectors targeting a Karpenter node pool"},
+	{env: "CHECK_KARPENTER_REQUIREMENTS", usage: "required node affinity that forces a new NodeClaim"},
+	{env: "CHECK_POD_RUN_AS_NON_ROOT", usage: "set runAsNonRoot on the check pods"},
+	{env: "CHECK_POD_RUN_AS_USER", usage: "user ID the check pods run as"},
+	{env: "CHECK_POD_RUN_AS_GROUP", usage: "group ID the check pods run as"},
+	{env: "CHECK_POD_FS_GROUP", usage: "fsGroup for the check pods"},
+	{env: "CHECK_POD_SECCOMP_PROFILE", usage: "seccomp profile for the check pods: RuntimeDefault, Unconfined, or Localhost/<profile>"},
+	{env: "CHECK_CONTAINER_READ_ONLY_ROOT_FILESYSTEM", usage: "mount the check container root filesystem read-only", boolean: true},
+	{env: "CHECK_CONTAINER_ALLOW_PRIVILEGE_ESCALATION", usage: "set allowPrivilegeEscalation on the check container"},
+	{env: "CHECK_CONTAINER_DROP_ALL_CAPABILITIES", usage: "drop all Linux capabilities from the check container", boolean: true},
+	{env: "CHECK_METRICS_ADDRESS", usage: "listen address for the Prometheus /metrics endpoint, such as :9102"},
+	{env: "CHECK_METRICS_LINGER", usage: "how long final metrics stay scrapeable after the run"},
+	{env: "RESULT_OUTPUT_PATH", usage: "write a JSON result document to this file, or - for stdout"},
+	{env: "CHECK_PUSHGATEWAY_URL", usage: "Prometheus Pushgateway that receives the final metrics"},
+	{env: "CHECK_PUSHGATEWAY_JOB", usage: "job name metrics are pushed under"},
+	{env: "CHECK_FAILURE_WEBHOOK_URL", usage: "URL that receives a JSON notification when the check fails"},
+	{env: "CHECK_NODE_POOL_LABEL", usage: "run the check once per distinct value of this node label"},
+	{env: "CHECK_PRIORITY_CLASS_NAME", usage: "priority class for the check pods"},
+	{env: "CHECK_HOST_ALIASES", usage: "extra /etc/hosts entries on the check pods as ip=hostname[,hostname] entries separated by semicolons"},
+	{env: "CHECK_READINESS_GATE", usage: "pod condition type added as a readiness gate and set by the checker"},
+	{env: "CHECK_TOPOLOGY_SPREAD", usage: "topology spread constraints as topologyKey:maxSkew[:whenUnsatisfiable] entries"},
+	{env: "CHECK_MIN_ZONES", usage: "least number of zones the ready pods must span"},
+	{env: "CHECK_PSS_PROFILE", usage: "Pod Security Standards profile the check pods comply with: restricted"},
+	{env: "CHECK_AUTOPILOT_MODE", usage: "make the check pods GKE Autopilot compliant", boolean: true},
+	{env: "CHECK_ENDPOINT_DIAGNOSTICS", usage: "request pods directly when the service fails", boolean: true},
+	{env: "CHECK_POD_IP_VERIFY", usage: "connect to every pod IP directly before requesting the service", boolean: true},
+	{env: "CHECK_PROBER_VERIFY", usage: "request the service from a prober pod on another node", boolean: true},
+	{env: "CHECK_PROBER_IMAGE", usage: "curl image run by the prober pod"},
+	{env: "CHECK_PROBER_HOST_NETWORK", usage: "run the prober pod in the host network", boolean: true},
+	{env: "CHECK_POD_DNS_VERIFY", usage: "resolve a name from inside the check pods", boolean: true},
+	{env: "CHECK_POD_DNS_NAME", usage: "name resolved from inside the check pods"},
+	{env: "CHECK_POD_DNS_POLICY", usage: "DNS policy of the check pods: ClusterFirst, ClusterFirstWithHostNet, Default, or None"},
+	{env: "CHECK_POD_DNS_NAMESERVERS", usage: "comma-separated nameserver IPs added to the check pods' resolver"},
+	{env: "CHECK_POD_DNS_SEARCHES", usage: "comma-separated search domains added to the check pods' resolver"},
+	{env: "CHECK_POD_DNS_NDOTS", usage: "ndots option of the check pods' resolver"},
+	{env: "CHECK_INGRESS_VERIFY", usage: "create an ingress and validate traffic through the ingress controller", boolean: true},
+	{env: "CHECK_INGRESS_CLASS_NAME", usage: "ingress class for the check ingress"},
+	{env: "CHECK_INGRESS_HOST", usage: "host rule and Host header for the check ingress"},
+	{env: "CHECK_INGRESS_PATH", usage: "path routed through the check ingress"},
+	{env: "CHECK_INGRESS_ADDRESS", usage: "ingress controller address to request instead of the ingress status"},
+	{env: "CHECK_INGRESS_TIMEOUT", usage: "window for the ingress controller to publish an address"},
+	{env: "CHECK_GATEWAY_NAME", usage: "Gateway to attach an HTTPRoute to; enables the Gateway API check"},
+	{env: "CHECK_GATEWAY_NAMESPACE", usage: "namespace of the Gateway"},
+	{env: "CHECK_GATEWAY_SECTION_NAME", usage: "Gateway listener to attach the HTTPRoute to"},
+	{env: "CHECK_GATEWAY_HOST", usage: "HTTPRoute hostname and Host header"},
+	{env: "CHECK_GATEWAY_PATH", usage: "path prefix routed through the Gateway"},
+	{env: "CHECK_GATEWAY_ADDRESS", usage: "Gateway address to request instead of the Gateway status"},
+	{env: "CHECK_GATEWAY_TIMEOUT", usage: "window for the HTTPRoute to be accepted"},
+	{env: "CHECK_OPENSHIFT_ROUTE_VERIFY", usage: "create an OpenShift Route and validate traffic through the router", boolean: true},
+	{env: "CHECK_OPENSHIFT_ROUTE_HOST", usage: "OpenShift Route host"},
+	{env: "CHECK_OPENSHIFT_ROUTE_ADDRESS", usage: "router address to request instead of the Route host"},
+	{env: "CHECK_OPENSHIFT_ROUTE_TIMEOUT", usage: "window for a router to admit the OpenShift Route"},
+	{env: "CHECK_BACKEND_HEADER", usage: "response header naming the pod that served a request"},
+	{env: "CHECK_EVERY_REPLICA_VERIFY", usage: "require every ready replica to serve a request", boolean: true},
+	{env: "CHECK_SESSION_AFFINITY_VERIFY", usage: "use ClientIP session affinity and verify requests stick to one pod", boolean: true},
+	{env: "CHECK_ENDPOINT_SLICE_VERIFY", usage: "require the EndpointSlices to list every ready replica before requesting the service", boolean: true},
+	{env: "CHECK_HEADLESS_SERVICE_VERIFY", usage: "create a headless service and verify its DNS records", boolean: true},
+	{env: "CHECK_CLUSTER_DOMAIN", usage: "cluster DNS domain"},
+	{env: "CHECK_NETWORK_POLICY_VERIFY", usage: "verify deny-all and allow network policies are enforced", boolean: true},
+	{env: "CHECK_NETWORK_POLICY_TIMEOUT", usage: "window for each network policy change to take effect"},
+	{env: "CHECK_SCALE_REPLICAS", usage: "replica count to scale the deployment to and back from"},
+	{env: "CHECK_SCALE_TIMEOUT", usage: "window for each scaling step to converge"},
+	{env: "CHECK_HPA_VERIFY", usage: "verify a horizontal pod autoscaler scales the deployment up and back down", boolean: true},
+	{env: "CHECK_HPA_MAX_REPLICAS", usage: "replica ceiling for the horizontal pod autoscaler"},
+	{env: "CHECK_HPA_TARGET_CPU_UTILIZATION", usage: "horizontal pod autoscaler CPU target as a percentage of requests"},
+	{env: "CHECK_HPA_GENERATE_LOAD", usage: "burn CPU in the check pods to trigger the scale up", boolean: true},
+	{env: "CHECK_HPA_TIMEOUT", usage: "how long each horizontal pod autoscaler scaling direction may take"},
+	{env: "CHECK_PDB_VERIFY", usage: "verify a pod disruption budget blocks and then allows an eviction", boolean: true},
+	{env: "CHECK_PDB_TIMEOUT", usage: "window for each pod disruption budget change and the eviction replacement"},
+	{env: "CHECK_SERVICE_DNS_VERIFY", usage: "resolve the service FQDN and compare it to the cluster IP", boolean: true},
+	{env: "CHECK_SERVICE_DNS_SLOW_THRESHOLD", usage: "longest acceptable service name lookup"},
+	{env: "CHECK_POD_FORCE_DELETE_AFTER", usage: "force delete pods stuck terminating this long during cleanup"},
+	{env: "CHECK_POD_TERMINATION_GRACE_PERIOD_SECONDS", usage: "termination grace period of the check pods"},
+	{env: "CHECK_MIN_READY_SECONDS", usage: "seconds a new pod must stay ready before the deployment counts it available"},
+	{env: "CHECK_PROGRESS_DEADLINE_SECONDS", usage: "seconds a rollout may go without progress before the check fails"},
+	{env: "CHECK_DELETE_PROPAGATION_POLICY", usage: "propagation policy for deployment and service deletes: Background, Foreground, or Orphan"},
+	{env: "CHECK_DELETE_TIMEOUT", usage: "fail a deployment delete after this long, naming the pods stuck terminating; 0 waits until the check deadline"},
+	{env: "CHECK_REQUEST_RETRY_TIMEOUT", usage: "window for retrying each endpoint request"},
+	{env: "CHECK_REQUEST_MAX_ATTEMPTS", usage: "maximum attempts for each endpoint request"},
+	{env: "CHECK_RETRY_BACKOFF_INITIAL", usage: "delay before the first endpoint or API retry"},
+	{env: "CHECK_RETRY_BACKOFF_MAX", usage: "maximum delay between retries"},
+	{env: "CHECK_RETRY_BACKOFF_FACTOR", usage: "multiplier applied to the retry delay after each retry"},
+	{env: "CHECK_RETRY_BACKOFF_JITTER", usage: "fraction of each retry delay added at random"},
+	{env: "KUBE_CONTEXT", usage: "kubeconfig context of the cluster to check instead of the local cluster"},
+	{env: "KUBE_API_SERVER", usage: "https URL of a remote API server to check"},
+	{env: "KUBE_TOKEN_FILE", usage: "bearer token file for KUBE_API_SERVER"},
+	{env: "KUBE_CA_FILE", usage: "CA bundle for KUBE_API_SERVER"},
+	{env: "CHECK_IMPERSONATE_USER", usage: "user to impersonate for every Kubernetes API call"},
+	{env: "CHECK_IMPERSONATE_GROUPS", usage: "comma-separated groups for the impersonated user"},
+	{env: "KUBE_CLIENT_QPS", usage: "sustained Kubernetes API request rate"},
+	{env: "KUBE_CLIENT_BURST", usage: "Kubernetes API request burst"},
+	{env: "KUBE_CLIENT_TIMEOUT", usage: "timeout for each Kubernetes API request"},
+	{env: "CHECK_WATCH_TIMEOUT", usage: "server-side timeout for each watch"},
+	{env: "CHECK_SOAK_DURATION", usage: "keep the deployment running under validation this long after success"},
+	{env: "KH_REPORTING_URL", usage: "Kuberhealthy reporting URL"},
+	{env: "KH_RUN_UUID", usage: "Kuberhealthy run UUID"},
+	{env: "KH_CHECK_RUN_DEADLINE", usage: "check deadline as a unix timestamp"},
+}
+
+// settingValue is a flag value that records the raw string for an environment variable.
+type settingValue struct {
+	// value holds the raw flag value.
+	value string
+	// boolean allows the flag to be passed without a value.
+	boolean bool
+}
+
+// String returns the raw flag value.
+func (v *settingValue) String() string {
+	return v.value
+}
+
+// Set stores the raw flag value.
+func (v *settingValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+// IsBoolFlag lets boolean settings be passed as bare flags.
+func (v *settingValue) IsBoolFlag() bool {
+	return v.boolean
+}
+
+// flagName converts an environment variable name into its flag name.
+func flagName(env string) string {
+	// Lowercase and hyphenate, so CHECK_IMAGE becomes check-image.
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+// applyFlags parses command-line flags and exports each one that was set as its environment variable.
+func applyFlags(args []string) error {
+	// Register a flag for every setting.
+	flagSet := flag.NewFlagSet("deployment-check", flag.ContinueOnError)
+	values := make(map[string]*settingValue)
+	for _, setting := range configSettings {
+		value := &settingValue{boolean: setting.boolean}
+		values[flagName(setting.env)] = value
+		flagSet.Var(value, flagName(setting.env), setting.usage+" (env "+setting.env+")")
+	}
+
+	// Parse the arguments.
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flagSet.Args(), " "))
+	}
+
+	// Flags take precedence over the environment, so overwrite only what was passed.
+	var setErr error
+	flagSet.Visit(func(f *flag.Flag) {
+		for _, setting := range configSettings {
+			if flagName(setting.env) != f.Name {
+				continue
+			}
+			err := os.Setenv(setting.env, values[f.Name].value)
+			if err != nil && setErr == nil {
+				setErr = fmt.Errorf("failed to set %s from flag --%s: %w", setting.env, f.Name, err)
+			}
+		}
+	})
+
+	return setErr
+}
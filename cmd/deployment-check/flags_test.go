@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+// TestApplyFlagsOverridesEnvironment validates that flags take precedence over environment variables.
+func TestApplyFlagsOverridesEnvironment(t *testing.T) {
+	// Start with values from the environment.
+	t.Setenv("CHECK_NAMESPACE", "from-env")
+	t.Setenv("CHECK_SERVICE_NAME", "from-env")
+	t.Setenv("DEBUG", "false")
+
+	err := applyFlags([]string{"--check-namespace", "from-flag", "--debug"})
+	if err != nil {
+		t.Fatalf("unexpected error applying flags: %v", err)
+	}
+
+	if os.Getenv("CHECK_NAMESPACE") != "from-flag" {
+		t.Fatalf("expected flag to override CHECK_NAMESPACE but got %q", os.Getenv("CHECK_NAMESPACE"))
+	}
+
+	if os.Getenv("DEBUG") != "true" {
+		t.Fatalf("expected bare boolean flag to set DEBUG=true but got %q", os.Getenv("DEBUG"))
+	}
+
+	if os.Getenv("CHECK_SERVICE_NAME") != "from-env" {
+		t.Fatalf("expected unset flag to leave CHECK_SERVICE_NAME alone but got %q", os.Getenv("CHECK_SERVICE_NAME"))
+	}
+}
+
+// TestConfigSettingsCoverEnvironment validates that every environment variable read by parseConfig has a flag.
+func TestConfigSettingsCoverEnvironment(t *testing.T) {
+	// Collect the environment variables referenced by the config parser.
+	source, err := os.ReadFile("checkConfig.go")
+	if err != nil {
+		t.Fatalf("failed to read checkConfig.go: %v", err)
+	}
+	known := make(map[string]bool)
+	for _, setting := range configSettings {
+		known[setting.env] = true
+	}
+
+	for _, match := range regexp.MustCompile(`os\.Getenv\("([A-Z_]+)"\)`).FindAllStringSubmatch(string(source), -1) {
+		if match[1] == "HOME" {
+			continue
+		}
+		if !known[match[1]] {
+			t.Fatalf("environment variable %s has no matching flag", match[1])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// httpRouteResource identifies Gateway API HTTPRoutes.
+	httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	// gatewayResource identifies Gateway API Gateways.
+	gatewayResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+)
+
+// createHTTPRouteConfig builds an HTTPRoute attaching the check service to the configured Gateway.
+func (r *CheckRunner) createHTTPRouteConfig() *unstructured.Unstructured {
+	// Reference the parent Gateway, optionally pinning a listener.
+	parentRef := map[string]interface{}{
+		"name":      r.cfg.GatewayName,
+		"namespace": r.cfg.GatewayNamespace,
+	}
+	if len(r.cfg.GatewaySectionName) != 0 {
+		parentRef["sectionName"] = r.cfg.GatewaySectionName
+	}
+
+	// Route the configured path prefix to the primary service port.
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{"type": "PathPrefix", "value": r.cfg.GatewayPath},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": r.cfg.CheckServiceName, "port": int64(r.cfg.CheckLoadBalancerPort)},
+				},
+			},
+		},
+	}
+	if len(r.cfg.GatewayHost) != 0 {
+		spec["hostnames"] = []interface{}{r.cfg.GatewayHost}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      r.cfg.CheckServiceName,
+			"namespace": r.cfg.CheckNamespace,
+		},
+		"spec": spec,
+	}}
+	if len(r.cfg.ExtraLabels) != 0 {
+		route.SetLabels(r.cfg.resourceLabels())
+	}
+	annotations := r.resourceAnnotations(nil)
+	if len(annotations) != 0 {
+		route.SetAnnotations(annotations)
+	}
+
+	return route
+}
+
+// verifyGatewayRoute creates the HTTPRoute, waits for the Gateway to accept it, and validates traffic through the Gateway.
+func (r *CheckRunner) verifyGatewayRoute(ctx context.Context) error {
+	// Create the route through the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(httpRouteResource).Namespace(r.cfg.CheckNamespace).Create(ctx, r.createHTTPRouteConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create HTTPRoute: %w", err)
+	}
+	log.Infoln("Created HTTPRoute", r.cfg.CheckServiceName, "attached to Gateway", r.cfg.GatewayNamespace+"/"+r.cfg.GatewayName)
+	r.timeline.recordf("created httproute %s attached to gateway %s/%s", r.cfg.CheckServiceName, r.cfg.GatewayNamespace, r.cfg.GatewayName)
+
+	// Wait for the Gateway to accept the route and resolve its backend.
+	err = r.waitForRouteConditions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Use the configured Gateway address or read it from the Gateway status.
+	address := r.cfg.GatewayAddress
+	if len(address) == 0 {
+		address, err = r.gatewayAddress(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	r.timeline.recordf("requesting httproute through gateway address %s", address)
+
+	// Request the path through the Gateway with the route hostname.
+	path := r.cfg.GatewayPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	err = r.requestEndpoint(ctx, "http://"+address+path, r.cfg.GatewayHost)
+	if err != nil {
+		return fmt.Errorf("request through gateway address %s failed: %w", address, err)
+	}
+
+	return nil
+}
+
+// waitForRouteConditions polls the HTTPRoute until the parent Gateway reports Accepted and ResolvedRefs.
+func (r *CheckRunner) waitForRouteConditions(ctx context.Context) error {
+	// Poll the route status until both conditions are true or the wait runs out.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(r.cfg.GatewayTimeout)
+	lastConditions := "none reported"
+	for {
+		route, err := client.Resource(httpRouteResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+		if err != nil {
+			log.Debugln("Failed to fetch HTTPRoute:", err.Error())
+		}
+		if err == nil {
+			conditions := r.routeParentConditions(route)
+			if len(conditions) != 0 {
+				lastConditions = describeRouteConditions(conditions)
+				r.timeline.observe("httproute-conditions", lastConditions, "httproute conditions: "+lastConditions)
+			}
+			if conditions["Accepted"] == "True" && conditions["ResolvedRefs"] == "True" {
+				log.Infoln("HTTPRoute accepted by the Gateway with resolved references.")
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("HTTPRoute was not accepted with resolved references within %s; last conditions: %s", r.cfg.GatewayTimeout, lastConditions)
+		}
+
+		// Wait before polling again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for HTTPRoute conditions; last conditions: %s", lastConditions)
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// routeParentConditions returns the condition statuses and reasons reported for the configured Gateway.
+func (r *CheckRunner) routeParentConditions(route *unstructured.Unstructured) map[string]string {
+	// Find the parent status entry for the configured Gateway.
+	conditions := make(map[string]string)
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, parent := range parents {
+		parentMap, ok := parent.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(parentMap, "parentRef", "name")
+		if name != r.cfg.GatewayName {
+			continue
+		}
+
+		// Record each condition with its status, keeping reasons for non-true conditions.
+		parentConditions, _, _ := unstructured.NestedSlice(parentMap, "conditions")
+		for _, condition := range parentConditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _, _ := unstructured.NestedString(conditionMap, "type")
+			status, _, _ := unstructured.NestedString(conditionMap, "status")
+			conditions[conditionType] = status
+			if status != "True" {
+				reason, _, _ := unstructured.NestedString(conditionMap, "reason")
+				conditions[conditionType+"Reason"] = reason
+			}
+		}
+	}
+
+	return conditions
+}
+
+// describeRouteConditions renders route conditions in a stable order.
+func describeRouteConditions(conditions map[string]string) string {
+	// Sort the keys so the rendering only changes when conditions change.
+	keys := make(map[string]bool)
+	for key := range conditions {
+		keys[key] = true
+	}
+	parts := make([]string, 0, len(conditions))
+	for _, key := range sortedKeys(keys) {
+		parts = append(parts, key+"="+conditions[key])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// gatewayAddress reads the first address published in the Gateway status.
+func (r *CheckRunner) gatewayAddress(ctx context.Context) (string, error) {
+	// Fetch the Gateway through the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return "", err
+	}
+	gateway, err := client.Resource(gatewayResource).Namespace(r.cfg.GatewayNamespace).Get(ctx, r.cfg.GatewayName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get Gateway %s/%s: %w", r.cfg.GatewayNamespace, r.cfg.GatewayName, err)
+	}
+
+	// Use the first published address.
+	addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+	for _, address := range addresses {
+		addressMap, ok := address.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, _, _ := unstructured.NestedString(addressMap, "value")
+		if len(value) != 0 {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("gateway %s/%s has no published address", r.cfg.GatewayNamespace, r.cfg.GatewayName)
+}
+
+// deleteHTTPRouteAndWait deletes the HTTPRoute and waits for it to disappear.
+func (r *CheckRunner) deleteHTTPRouteAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "HTTPRoute", func(ctx context.Context) error {
+		client, err := r.dynamicClient()
+		if err != nil {
+			return err
+		}
+		return client.Resource(httpRouteResource).Namespace(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckServiceName, metav1.DeleteOptions{})
+	}, r.httpRouteExists)
+}
+
+// httpRouteExists reports whether the check HTTPRoute is present.
+func (r *CheckRunner) httpRouteExists(ctx context.Context) (bool, error) {
+	// Look up the route by name.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Resource(httpRouteResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+	return objectFound(err)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestRouteParentConditions validates that only the configured Gateway's conditions are read.
+func TestRouteParentConditions(t *testing.T) {
+	// Build a route accepted by the configured Gateway and rejected by another.
+	runner := buildTestRunner()
+	runner.cfg.GatewayName = "public"
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"parents": []interface{}{
+				map[string]interface{}{
+					"parentRef": map[string]interface{}{"name": "internal"},
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Accepted", "status": "False", "reason": "NotAllowedByListeners"},
+					},
+				},
+				map[string]interface{}{
+					"parentRef": map[string]interface{}{"name": "public"},
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Accepted", "status": "True"},
+						map[string]interface{}{"type": "ResolvedRefs", "status": "False", "reason": "BackendNotFound"},
+					},
+				},
+			},
+		},
+	}}
+
+	conditions := runner.routeParentConditions(route)
+	if conditions["Accepted"] != "True" {
+		t.Fatalf("expected Accepted=True from the configured gateway but got %q", conditions["Accepted"])
+	}
+
+	if conditions["ResolvedRefsReason"] != "BackendNotFound" {
+		t.Fatalf("expected the ResolvedRefs reason to be kept but got %q", conditions["ResolvedRefsReason"])
+	}
+}
+
+// TestParseGatewayNamespace validates the Gateway namespace must be given alongside the Gateway name.
+func TestParseGatewayNamespace(t *testing.T) {
+	// Reject a Gateway name without its namespace.
+	t.Setenv("CHECK_GATEWAY_NAME", "public")
+	_, err := parseConfig()
+	if err == nil {
+		t.Fatalf("expected an error without CHECK_GATEWAY_NAMESPACE")
+	}
+
+	// Accept the pair.
+	t.Setenv("CHECK_GATEWAY_NAMESPACE", "gateway-system")
+	cfg, err := parseConfig()
+	if err != nil || cfg.GatewayNamespace != "gateway-system" {
+		t.Fatalf("expected the gateway namespace to parse but got %v", err)
+	}
+}
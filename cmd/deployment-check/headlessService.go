@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// headlessDNSTimeout bounds the wait for CoreDNS to publish the headless service records.
+	headlessDNSTimeout = time.Minute * 2
+)
+
+// verifyHeadlessDNS creates a headless service and verifies its A, SRV, and per-pod records match the ready pods.
+func (r *CheckRunner) verifyHeadlessDNS(ctx context.Context, labels map[string]string) error {
+	// Create the headless service alongside the main one.
+	service := r.createServiceConfig(labels)
+	service.Name = r.cfg.headlessServiceName()
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	_, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Create(ctx, service, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create headless service: %w", err)
+	}
+	log.Infoln("Created headless service", service.Name, "in", r.cfg.CheckNamespace, "namespace.")
+	r.timeline.recordf("created headless service %s", service.Name)
+
+	// Poll until the records match or the wait runs out, keeping the last mismatch for the report.
+	deadline := time.Now().Add(headlessDNSTimeout)
+	for {
+		err = r.checkHeadlessRecords(ctx)
+		if err == nil {
+			log.Infoln("Headless service DNS records match the ready pods.")
+			r.timeline.record("headless service DNS records verified")
+			return nil
+		}
+		log.Debugln("Headless service DNS records not ready yet:", err.Error())
+		if time.Now().After(deadline) {
+			return fmt.Errorf("headless service DNS records did not match the ready pods within %s: %w", headlessDNSTimeout, err)
+		}
+
+		// Wait before resolving again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while verifying headless service DNS: %w", err)
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// checkHeadlessRecords resolves the headless service records once and compares them with the ready pod IPs.
+func (r *CheckRunner) checkHeadlessRecords(ctx context.Context) error {
+	// Collect the IPs of ready pods for this run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	expected := make(map[string]bool)
+	for _, pod := range podList.Items {
+		if len(pod.Status.PodIP) != 0 && pod.DeletionTimestamp == nil && podIsReady(pod) {
+			expected[pod.Status.PodIP] = true
+		}
+	}
+	if len(expected) != r.cfg.CheckDeploymentReplicas {
+		return fmt.Errorf("expected %d ready pod(s) but found %d", r.cfg.CheckDeploymentReplicas, len(expected))
+	}
+
+	// The service name resolves to one A record per ready pod.
+	fqdn := r.cfg.headlessServiceName() + "." + r.cfg.CheckNamespace + ".svc." + r.cfg.ClusterDomain
+	resolver := net.DefaultResolver
+	addresses, err := resolver.LookupHost(ctx, fqdn)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", fqdn, err)
+	}
+	resolved := make(map[string]bool)
+	for _, address := range addresses {
+		resolved[address] = true
+	}
+	if !sameKeys(expected, resolved) {
+		return fmt.Errorf("%s resolved to [%s] but ready pods are [%s]", fqdn, strings.Join(sortedKeys(resolved), ", "), strings.Join(sortedKeys(expected), ", "))
+	}
+
+	// The named port publishes one SRV record per ready pod.
+	portName := "tcp-" + strconv.Itoa(int(r.cfg.CheckLoadBalancerPort))
+	_, srvRecords, err := resolver.LookupSRV(ctx, portName, "tcp", fqdn)
+	if err != nil {
+		return fmt.Errorf("failed to resolve SRV records for _%s._tcp.%s: %w", portName, fqdn, err)
+	}
+	if len(srvRecords) != r.cfg.CheckDeploymentReplicas {
+		return fmt.Errorf("expected %d SRV record(s) for _%s._tcp.%s but got %d", r.cfg.CheckDeploymentReplicas, portName, fqdn, len(srvRecords))
+	}
+
+	// Each pod has its own record named after its dashed IP.
+	for _, podIP := range sortedKeys(expected) {
+		podName := strings.NewReplacer(".", "-", ":", "-").Replace(podIP) + "." + fqdn
+		podAddresses, err := resolver.LookupHost(ctx, podName)
+		if err != nil {
+			return fmt.Errorf("failed to resolve per-pod record %s: %w", podName, err)
+		}
+		sort.Strings(podAddresses)
+		if len(podAddresses) != 1 || podAddresses[0] != podIP {
+			return fmt.Errorf("per-pod record %s resolved to [%s] instead of %s", podName, strings.Join(podAddresses, ", "), podIP)
+		}
+	}
+
+	return nil
+}
+
+// sameKeys reports whether two sets contain the same keys.
+func sameKeys(a map[string]bool, b map[string]bool) bool {
+	// Compare sizes first, then membership.
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+
+	return true
+}
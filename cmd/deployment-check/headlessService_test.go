@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestCheckHeadlessRecordsReadyPods validates records are only compared once every replica is ready and not terminating.
+func TestCheckHeadlessRecordsReadyPods(t *testing.T) {
+	// Run one ready pod, one unready pod, and one ready pod that is terminating.
+	runner := buildTestRunner()
+	runner.cfg.CheckDeploymentReplicas = 2
+	ready := probeTestPod(runner, "ready", true)
+	ready.Status.PodIP = "10.0.0.1"
+	unready := probeTestPod(runner, "unready", false)
+	unready.Status.PodIP = "10.0.0.2"
+	terminating := probeTestPod(runner, "terminating", true)
+	terminating.Status.PodIP = "10.0.0.3"
+	deleted := metav1.NewTime(time.Now())
+	terminating.DeletionTimestamp = &deleted
+	runner.client = fake.NewClientset(ready, unready, terminating)
+
+	err := runner.checkHeadlessRecords(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "expected 2 ready pod(s) but found 1") {
+		t.Fatalf("expected a ready pod shortfall but got %v", err)
+	}
+}
+
+// TestSameKeys validates set comparison by size and membership.
+func TestSameKeys(t *testing.T) {
+	if !sameKeys(map[string]bool{"a": true, "b": true}, map[string]bool{"b": true, "a": true}) {
+		t.Fatalf("expected equal sets to match")
+	}
+	if sameKeys(map[string]bool{"a": true}, map[string]bool{"a": true, "b": true}) {
+		t.Fatalf("expected sets of different sizes not to match")
+	}
+	if sameKeys(map[string]bool{"a": true, "c": true}, map[string]bool{"a": true, "b": true}) {
+		t.Fatalf("expected sets with different members not to match")
+	}
+}
+
+// TestCheckServiceNamesHeadless validates the headless service is found and cleaned up with the main one when enabled.
+func TestCheckServiceNamesHeadless(t *testing.T) {
+	runner := buildTestRunner()
+	if slices.Contains(runner.checkServiceNames(), runner.cfg.headlessServiceName()) {
+		t.Fatalf("expected no headless service name when the verification is off")
+	}
+
+	runner.cfg.HeadlessServiceVerify = true
+	names := runner.checkServiceNames()
+	if !slices.Equal(names, []string{runner.cfg.CheckServiceName, runner.cfg.CheckServiceName + "-headless"}) {
+		t.Fatalf("expected the main and headless service names but got %v", names)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// hpaPollInterval is the pause between scaling observations.
+	hpaPollInterval = time.Second * 5
+	// hpaLoadPIDFile records the load loop so it can be stopped inside the pod.
+	hpaLoadPIDFile = "/tmp/deployment-check-load.pid"
+	// hpaIdleTargetUtilization is a CPU target idle pods sit far below, used to force a scale down without load.
+	hpaIdleTargetUtilization = int32(1000)
+)
+
+// createHPAConfig builds an autoscaler for the check deployment with scaling delays removed.
+func (r *CheckRunner) createHPAConfig(targetUtilization int32) *autoscalingv2.HorizontalPodAutoscaler {
+	// Scale between the configured replicas and the HPA ceiling on CPU utilization.
+	minReplicas := int32(r.cfg.CheckDeploymentReplicas)
+	stabilizationWindow := int32(0)
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       r.cfg.CheckDeploymentName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: r.cfg.HPAMaxReplicas,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: &targetUtilization,
+					},
+				},
+			}},
+			// Skip the default five minute scale down window so the check fits its timeout.
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleUp:   &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &stabilizationWindow},
+				ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &stabilizationWindow},
+			},
+		},
+	}
+	hpa.Name = r.cfg.CheckDeploymentName
+	hpa.Namespace = r.cfg.CheckNamespace
+	hpa.Labels = copyStringMap(r.cfg.ExtraLabels)
+	hpa.Annotations = r.resourceAnnotations(nil)
+
+	return hpa
+}
+
+// verifyHPA checks that an autoscaler scales the deployment up under load and back down once it stops.
+func (r *CheckRunner) verifyHPA(ctx context.Context) error {
+	// Create the autoscaler.
+	autoscalers := r.client.AutoscalingV2().HorizontalPodAutoscalers(r.cfg.CheckNamespace)
+	_, err := autoscalers.Create(ctx, r.createHPAConfig(r.cfg.HPATargetCPUUtilization), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create horizontal pod autoscaler: %w", err)
+	}
+	r.timeline.recordf("created horizontal pod autoscaler targeting %d%% CPU with up to %d replicas", r.cfg.HPATargetCPUUtilization, r.cfg.HPAMaxReplicas)
+
+	// Burn CPU in the check pods unless the target alone should trigger scaling.
+	stopLoad := func() {}
+	if r.cfg.HPAGenerateLoad {
+		stopLoad, err = r.startCPULoad(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	defer stopLoad()
+
+	// Wait for the autoscaler to add replicas.
+	minReplicas := int32(r.cfg.CheckDeploymentReplicas)
+	scaledUp := func(desired int32, ready int32) bool { return desired > minReplicas && ready >= desired }
+	err = r.waitForHPAScale(ctx, "scale up", scaledUp)
+	if err != nil {
+		return err
+	}
+
+	// Remove the pressure: stop the load, or raise the target when scaling was driven by the target alone.
+	stopLoad()
+	if !r.cfg.HPAGenerateLoad {
+		err = r.updateHPATarget(ctx, hpaIdleTargetUtilization)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Wait for the autoscaler to return to the configured replicas.
+	scaledDown := func(desired int32, ready int32) bool { return desired == minReplicas && ready == minReplicas }
+	err = r.waitForHPAScale(ctx, "scale down", scaledDown)
+	if err != nil {
+		return err
+	}
+
+	// Remove the autoscaler so later stages control the replica count.
+	err = r.deleteHPAAndWait(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Horizontal pod autoscaling verified.")
+
+	return nil
+}
+
+// startCPULoad runs a busy loop in every running check pod and returns a function that stops it.
+func (r *CheckRunner) startCPULoad(ctx context.Context) (func(), error) {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment pods for CPU load: %w", err)
+	}
+
+	// Bound the loop by the HPA timeout in case the stop exec never lands.
+	seconds := strconv.Itoa(int((2 * r.cfg.HPATimeout).Seconds()))
+	script := "echo $$ > " + hpaLoadPIDFile + "; end=$(($(date +%s)+" + seconds + ")); while [ \"$(date +%s)\" -lt \"$end\" ]; do :; done"
+	loadCtx, cancel := context.WithCancel(ctx)
+	loaded := make([]corev1.Pod, 0)
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		loaded = append(loaded, pod)
+		go func(pod corev1.Pod) {
+			_, stderr, execErr := r.execInPod(loadCtx, pod, r.cfg.CheckContainerName, []string{"sh", "-c", script})
+			if execErr != nil && loadCtx.Err() == nil {
+				log.Warnln("CPU load in pod", pod.Name, "ended early:", execErr.Error(), strings.TrimSpace(stderr))
+			}
+		}(pod)
+	}
+	log.Infoln("Generating CPU load in", len(loaded), "check pod(s).")
+	r.timeline.recordf("started CPU load in %d check pod(s)", len(loaded))
+
+	// Stop the loops once, killing them inside the pods since closing the stream may not.
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			for _, pod := range loaded {
+				killCtx, killCancel := context.WithTimeout(context.Background(), podDNSLookupTimeout)
+				_, _, killErr := r.execInPod(killCtx, pod, r.cfg.CheckContainerName, []string{"sh", "-c", "kill $(cat " + hpaLoadPIDFile + ")"})
+				killCancel()
+				if killErr != nil {
+					log.Warnln("Failed to stop CPU load in pod", pod.Name+":", killErr.Error())
+				}
+			}
+			r.timeline.record("stopped CPU load")
+		})
+	}
+
+	return stop, nil
+}
+
+// waitForHPAScale polls the deployment until the autoscaler moves it into the expected state.
+func (r *CheckRunner) waitForHPAScale(ctx context.Context, direction string, reached func(desired int32, ready int32) bool) error {
+	// Poll until the state is reached or the timeout passes.
+	log.Infoln("Waiting for the horizontal pod autoscaler to", direction+".")
+	deadline := time.Now().Add(r.cfg.HPATimeout)
+	for {
+		deployment, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			log.Warnln("Failed to fetch deployment while waiting for the autoscaler:", err.Error())
+		}
+		if err == nil && deployment.Spec.Replicas != nil {
+			desired := *deployment.Spec.Replicas
+			log.Debugln("Deployment wants", desired, "replica(s) with", deployment.Status.ReadyReplicas, "ready.")
+			if reached(desired, deployment.Status.ReadyReplicas) {
+				r.timeline.recordf("horizontal pod autoscaler finished %s at %d ready replica(s)", direction, deployment.Status.ReadyReplicas)
+				return nil
+			}
+		}
+
+		// Explain what the autoscaler saw once the timeout passes.
+		if time.Now().After(deadline) {
+			return fmt.Errorf("horizontal pod autoscaler did not %s within %s: %s", direction, r.cfg.HPATimeout, r.describeHPA(ctx))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for the horizontal pod autoscaler to %s", direction)
+		case <-time.After(hpaPollInterval):
+		}
+	}
+}
+
+// describeHPA summarizes autoscaler replicas, observed CPU, and any unhealthy conditions.
+func (r *CheckRunner) describeHPA(ctx context.Context) string {
+	// Fetch the autoscaler status.
+	hpa, err := r.client.AutoscalingV2().HorizontalPodAutoscalers(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return "failed to fetch horizontal pod autoscaler: " + err.Error()
+	}
+
+	return formatHPAStatus(hpa.Status)
+}
+
+// formatHPAStatus renders autoscaler replicas, observed CPU, and false conditions on one line.
+func formatHPAStatus(status autoscalingv2.HorizontalPodAutoscalerStatus) string {
+	// Lead with the replica counts.
+	parts := []string{fmt.Sprintf("current replicas: %d desired replicas: %d", status.CurrentReplicas, status.DesiredReplicas)}
+
+	// Include the observed CPU utilization when metrics arrived.
+	for _, metric := range status.CurrentMetrics {
+		if metric.Resource == nil || metric.Resource.Name != corev1.ResourceCPU || metric.Resource.Current.AverageUtilization == nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("cpu utilization: %d%%", *metric.Resource.Current.AverageUtilization))
+	}
+
+	// Surface conditions that explain a stall, such as missing metrics.
+	for _, condition := range status.Conditions {
+		if condition.Status != corev1.ConditionFalse {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=False (%s: %s)", condition.Type, condition.Reason, condition.Message))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// updateHPATarget changes the autoscaler CPU target.
+func (r *CheckRunner) updateHPATarget(ctx context.Context, targetUtilization int32) error {
+	// Fetch the current autoscaler to preserve resourceVersion.
+	autoscalers := r.client.AutoscalingV2().HorizontalPodAutoscalers(r.cfg.CheckNamespace)
+	hpa, err := autoscalers.Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch horizontal pod autoscaler: %w", err)
+	}
+
+	// Replace the metrics with the new target.
+	hpa.Spec.Metrics = r.createHPAConfig(targetUtilization).Spec.Metrics
+	_, err = autoscalers.Update(ctx, hpa, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update horizontal pod autoscaler target: %w", err)
+	}
+	r.timeline.recordf("raised horizontal pod autoscaler CPU target to %d%%", targetUtilization)
+
+	return nil
+}
+
+// deleteHPAAndWait removes the check autoscaler and waits for it to disappear.
+func (r *CheckRunner) deleteHPAAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "horizontal pod autoscaler", func(ctx context.Context) error {
+		return r.client.AutoscalingV2().HorizontalPodAutoscalers(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckDeploymentName, metav1.DeleteOptions{})
+	}, r.hpaExists)
+}
+
+// hpaExists reports whether the check autoscaler is present.
+func (r *CheckRunner) hpaExists(ctx context.Context) (bool, error) {
+	_, err := r.client.AutoscalingV2().HorizontalPodAutoscalers(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	return objectFound(err)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestCreateHPAConfig validates the autoscaler targets the deployment without scaling delays.
+func TestCreateHPAConfig(t *testing.T) {
+	// Build an autoscaler for the default deployment.
+	runner := buildTestRunner()
+	runner.cfg.HPAMaxReplicas = 4
+	hpa := runner.createHPAConfig(50)
+
+	if hpa.Spec.ScaleTargetRef.Kind != "Deployment" || hpa.Spec.ScaleTargetRef.Name != runner.cfg.CheckDeploymentName {
+		t.Fatalf("expected the autoscaler to target the check deployment but got %+v", hpa.Spec.ScaleTargetRef)
+	}
+	if *hpa.Spec.MinReplicas != int32(runner.cfg.CheckDeploymentReplicas) || hpa.Spec.MaxReplicas != 4 {
+		t.Fatalf("expected replicas %d to 4 but got %d to %d", runner.cfg.CheckDeploymentReplicas, *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	if *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 50 {
+		t.Fatalf("expected a 50%% CPU target but got %d", *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+	}
+	if *hpa.Spec.Behavior.ScaleDown.StabilizationWindowSeconds != 0 {
+		t.Fatalf("expected no scale down stabilization window")
+	}
+}
+
+// TestFormatHPAStatus validates replica counts, CPU, and failing conditions are summarized.
+func TestFormatHPAStatus(t *testing.T) {
+	// Build a status whose metrics are missing.
+	utilization := int32(12)
+	status := autoscalingv2.HorizontalPodAutoscalerStatus{
+		CurrentReplicas: 2,
+		DesiredReplicas: 2,
+		CurrentMetrics: []autoscalingv2.MetricStatus{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name:    corev1.ResourceCPU,
+				Current: autoscalingv2.MetricValueStatus{AverageUtilization: &utilization},
+			},
+		}},
+		Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+			{Type: autoscalingv2.AbleToScale, Status: corev1.ConditionTrue, Reason: "ReadyForNewScale"},
+			{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetResourceMetric", Message: "no metrics returned"},
+		},
+	}
+
+	summary := formatHPAStatus(status)
+	for _, expected := range []string{"desired replicas: 2", "cpu utilization: 12%", "ScalingActive=False (FailedGetResourceMetric: no metrics returned)"} {
+		if !strings.Contains(summary, expected) {
+			t.Fatalf("expected %q in %q", expected, summary)
+		}
+	}
+	if strings.Contains(summary, "AbleToScale") {
+		t.Fatalf("expected healthy conditions to be omitted but got %q", summary)
+	}
+}
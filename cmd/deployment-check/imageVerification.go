@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// verifyRolledPodImages confirms every live check pod runs the expected image, and the pinned digest if any, after a rollout.
+func (r *CheckRunner) verifyRolledPodImages(ctx context.Context, image string, digest string) error {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for image verification: %w", err)
+	}
+
+	// Compare the spec and status images of each live pod, pinning the digest from the reference unless one is given.
+	expected := normalizeImageReference(image)
+	if len(digest) == 0 {
+		digest = imageReferenceDigest(image)
+	}
+	mismatches := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.Name != r.cfg.CheckContainerName {
+				continue
+			}
+			if normalizeImageReference(container.Image) != expected {
+				mismatches = append(mismatches, fmt.Sprintf("pod: %s node: %s spec image: %s", pod.Name, pod.Spec.NodeName, container.Image))
+			}
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != r.cfg.CheckContainerName {
+				continue
+			}
+			// Some runtimes report only the image ID here, which cannot be compared to a tag.
+			if strings.HasPrefix(status.Image, "sha256:") {
+				continue
+			}
+			if normalizeImageReference(status.Image) != expected {
+				mismatches = append(mismatches, fmt.Sprintf("pod: %s node: %s running image: %s", pod.Name, pod.Spec.NodeName, status.Image))
+			}
+		}
+
+		// Require the runtime to report the pinned digest for the pulled image.
+		if len(digest) == 0 {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != r.cfg.CheckContainerName {
+				continue
+			}
+			// Runtimes that report only a config ID, or nothing yet, give no repo digest to compare.
+			reported := imageReferenceDigest(status.ImageID)
+			if len(reported) == 0 {
+				continue
+			}
+			if reported != digest {
+				mismatches = append(mismatches, fmt.Sprintf("pod: %s node: %s image ID: %s does not match digest %s", pod.Name, pod.Spec.NodeName, status.ImageID, digest))
+			}
+		}
+	}
+
+	// Fail when any pod still runs something other than the expected image.
+	if len(mismatches) != 0 {
+		return fmt.Errorf("rolled pods are not running %s: %s", image, strings.Join(mismatches, "; "))
+	}
+
+	log.Infoln("All rolled pods are running", image)
+	r.timeline.recordf("verified rolled pods run image %s", image)
+	if len(digest) != 0 {
+		log.Infoln("All rolled pods report digest", digest)
+		r.timeline.recordf("verified rolled pods report digest %s", digest)
+	}
+	return nil
+}
+
+// imageReferenceDigest returns the digest of a repo@digest reference or image ID, or an empty string without one.
+func imageReferenceDigest(reference string) string {
+	// Runtimes report bare config IDs as sha256:..., which are not repository digests.
+	_, digest, found := strings.Cut(reference, "@")
+	if !found {
+		return ""
+	}
+
+	return digest
+}
+
+// normalizeImageReference expands Docker Hub shorthand and implicit tags so references can be compared.
+func normalizeImageReference(image string) string {
+	// Trim the default registry and library namespace added by runtimes.
+	normalized := strings.TrimPrefix(image, "docker.io/")
+	normalized = strings.TrimPrefix(normalized, "index.docker.io/")
+	normalized = strings.TrimPrefix(normalized, "library/")
+
+	// Leave digest references alone.
+	if strings.Contains(normalized, "@") {
+		return normalized
+	}
+
+	// Add the implicit latest tag when no tag follows the last path segment.
+	lastSegment := normalized[strings.LastIndex(normalized, "/")+1:]
+	if !strings.Contains(lastSegment, ":") {
+		normalized = normalized + ":latest"
+	}
+
+	return normalized
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestNormalizeImageReference validates that runtime-expanded image names compare equal to configured ones.
+func TestNormalizeImageReference(t *testing.T) {
+	// Define configured and runtime-reported references that should match.
+	cases := map[string]string{
+		"nginxinc/nginx-unprivileged:1.17.9": "docker.io/nginxinc/nginx-unprivileged:1.17.9",
+		"nginx":                              "docker.io/library/nginx:latest",
+		"registry.local:5000/team/app":       "registry.local:5000/team/app:latest",
+	}
+
+	// Validate that each pair normalizes to the same reference.
+	for configured, reported := range cases {
+		if normalizeImageReference(configured) != normalizeImageReference(reported) {
+			t.Fatalf("expected %s and %s to normalize equally but got %s and %s", configured, reported, normalizeImageReference(configured), normalizeImageReference(reported))
+		}
+	}
+
+	// Validate that different tags do not match.
+	if normalizeImageReference("nginx:1.17.8") == normalizeImageReference("nginx:1.17.9") {
+		t.Fatalf("expected different tags to normalize differently")
+	}
+}
+
+// TestImageReferenceDigest validates digests are read from references and image IDs but not bare config IDs.
+func TestImageReferenceDigest(t *testing.T) {
+	// Define references and the digest expected for each.
+	digest := "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"
+	cases := map[string]string{
+		"nginx@" + digest: digest,
+		"docker-pullable://docker.io/library/nginx@" + digest: digest,
+		"nginx:1.17.8": "",
+		digest:         "",
+	}
+
+	// Validate the digest for each reference.
+	for reference, expected := range cases {
+		if imageReferenceDigest(reference) != expected {
+			t.Fatalf("expected digest %q for %s but got %q", expected, reference, imageReferenceDigest(reference))
+		}
+	}
+}
+
+// TestVerifyRolledPodImagesDigest validates a pinned digest is compared only against image IDs that carry a repo digest.
+func TestVerifyRolledPodImagesDigest(t *testing.T) {
+	// Run one pod reporting the pinned repo digest and one reporting only a config ID.
+	digest := "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"
+	runner := buildTestRunner()
+	pinned := probeTestPod(runner, "pinned", true)
+	pinned.Spec.Containers = []corev1.Container{{Name: runner.cfg.CheckContainerName, Image: "nginx:1.17.9"}}
+	pinned.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: runner.cfg.CheckContainerName, Image: "docker.io/library/nginx:1.17.9", ImageID: "docker-pullable://docker.io/library/nginx@" + digest}}
+	configID := pinned.DeepCopy()
+	configID.Name = "config-id"
+	configID.Status.ContainerStatuses[0].ImageID = "sha256:5a3221f0137beb960c34b9cf4455424b6210160fd618c5e79401a07d6e5a2ced"
+	runner.client = fake.NewClientset(pinned, configID)
+
+	err := runner.verifyRolledPodImages(context.Background(), "nginx:1.17.9", digest)
+	if err != nil {
+		t.Fatalf("expected the pinned digest to verify but got %v", err)
+	}
+
+	// A different repo digest still fails.
+	err = runner.verifyRolledPodImages(context.Background(), "nginx:1.17.9", "sha256:1111111111111111111111111111111111111111111111111111111111111111")
+	if err == nil || !strings.Contains(err.Error(), "pod: pinned") || strings.Contains(err.Error(), "pod: config-id") {
+		t.Fatalf("expected only the pinned pod to mismatch but got %v", err)
+	}
+}
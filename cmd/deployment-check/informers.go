@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// podInformer serves the current run's pods from a shared informer so waits do not poll the API.
+type podInformer struct {
+	// once guards starting the informer.
+	once sync.Once
+	// lister reads pods from the informer cache.
+	lister corelisters.PodNamespaceLister
+	// mu guards listeners.
+	mu sync.Mutex
+	// listeners are each signaled whenever a run pod is added, updated, or deleted.
+	listeners []chan struct{}
+	// stop ends the informer.
+	stop chan struct{}
+	// err records why the informer failed to start.
+	err error
+}
+
+// runSelector returns the label selector matching pods of the current run.
+func (r *CheckRunner) runSelector() string {
+	return deploymentLabelKey + "=" + deploymentLabelValueBase + fmt.Sprint(r.now.Unix())
+}
+
+// startPodInformer starts the run pod informer once and waits for its cache to fill.
+func (r *CheckRunner) startPodInformer(ctx context.Context) error {
+	r.pods.once.Do(func() {
+		// Scope the factory to the check namespace and this run's pods.
+		factory := informers.NewSharedInformerFactoryWithOptions(r.client, 0,
+			informers.WithNamespace(r.cfg.CheckNamespace),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = r.runSelector()
+			}),
+		)
+		podInformer := factory.Core().V1().Pods()
+		informer := podInformer.Informer()
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(any) { r.pods.notify() },
+			UpdateFunc: func(any, any) { r.pods.notify() },
+			DeleteFunc: func(any) { r.pods.notify() },
+		})
+		if err != nil {
+			r.pods.err = fmt.Errorf("failed to register pod informer handler: %w", err)
+			return
+		}
+		r.pods.lister = podInformer.Lister().Pods(r.cfg.CheckNamespace)
+
+		// Start the informer and wait for the first list to land.
+		r.pods.stop = make(chan struct{})
+		factory.Start(r.pods.stop)
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			r.pods.err = fmt.Errorf("pod informer cache did not sync")
+		}
+	})
+
+	return r.pods.err
+}
+
+// stopInformers stops the run pod informer if it was started.
+func (r *CheckRunner) stopInformers() {
+	if r.pods.stop != nil {
+		close(r.pods.stop)
+		r.pods.stop = nil
+	}
+}
+
+// cachedDeploymentPods returns the current run's pods from the informer cache, sorted by name.
+func (r *CheckRunner) cachedDeploymentPods(ctx context.Context) ([]corev1.Pod, error) {
+	// Start the informer on first use.
+	err := r.startPodInformer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the cached pods so callers cannot mutate the cache.
+	cached, err := r.pods.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached pods: %w", err)
+	}
+	pods := make([]corev1.Pod, 0, len(cached))
+	for _, pod := range cached {
+		pods = append(pods, *pod.DeepCopy())
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	return pods, nil
+}
+
+// subscribe returns a channel signaled on every run pod change, and a function that stops the signals.
+func (p *podInformer) subscribe() (<-chan struct{}, func()) {
+	// Give each waiter its own channel so one never consumes another's wakeup.
+	changed := make(chan struct{}, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, changed)
+
+	return changed, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.listeners = slices.DeleteFunc(p.listeners, func(listener chan struct{}) bool { return listener == changed })
+	}
+}
+
+// notify signals every subscribed waiter of a run pod change.
+func (p *podInformer) notify() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, listener := range p.listeners {
+		signalChange(listener)
+	}
+}
+
+// signalChange notifies a waiter without blocking when a notification is already pending.
+func signalChange(changed chan struct{}) {
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
+
+// waitForObjectDeleted runs an informer scoped to one named object and waits until its cache no longer holds it.
+func (r *CheckRunner) waitForObjectDeleted(ctx context.Context, kind string, name string, informerFor func(informers.SharedInformerFactory) cache.SharedIndexInformer, deleteAgain func(context.Context) error) error {
+	// Scope the factory to the single object so the cache mirrors its presence.
+	factory := informers.NewSharedInformerFactoryWithOptions(r.client, 0,
+		informers.WithNamespace(r.cfg.CheckNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = "metadata.name=" + name
+		}),
+	)
+	informer := informerFor(factory)
+	deleted := make(chan struct{}, 1)
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(any) { signalChange(deleted) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s %s for deletion: %w", kind, name, err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("timed out while waiting for %s to delete", kind)
+	}
+
+	for {
+		// Done once the cache no longer holds the object.
+		item, found, err := informer.GetStore().GetByKey(r.cfg.CheckNamespace + "/" + name)
+		if err == nil && !found {
+			return nil
+		}
+
+		// Only re-issue the delete when the previous one did not take effect.
+		object, ok := item.(metav1.Object)
+		if ok && object.GetDeletionTimestamp() == nil {
+			deleteErr := deleteAgain(ctx)
+			if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+				log.Errorln("Error deleting", kind, name+":", deleteErr.Error())
+			}
+		}
+
+		// Wake on the delete event, re-checking periodically in case a finalizer or lost delete stalls it.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out while waiting for %s to delete", kind)
+		case <-deleted:
+		case <-time.After(deleteWatchFallbackTimeout):
+			log.Debugln("Still waiting for", kind, name, "to delete.")
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+// serviceInformer selects the service informer from a factory.
+func serviceInformer(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	return factory.Core().V1().Services().Informer()
+}
+
+// TestWaitForObjectDeletedRedeletes validates a delete that did not take effect is issued again until the object is gone.
+func TestWaitForObjectDeletedRedeletes(t *testing.T) {
+	// Seed a service the first delete never reached.
+	runner := buildTestRunner()
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: runner.cfg.CheckNamespace}}
+	runner.client = fake.NewClientset(service)
+
+	// Count the re-issued deletes, which remove the service.
+	var deletes atomic.Int32
+	deleteAgain := func(ctx context.Context) error {
+		deletes.Add(1)
+		return runner.client.CoreV1().Services(runner.cfg.CheckNamespace).Delete(ctx, "svc", metav1.DeleteOptions{})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.waitForObjectDeleted(ctx, "service", "svc", serviceInformer, deleteAgain)
+	if err != nil {
+		t.Fatalf("expected the wait to finish but got %v", err)
+	}
+	if deletes.Load() != 1 {
+		t.Fatalf("expected one re-issued delete but got %d", deletes.Load())
+	}
+}
+
+// TestWaitForObjectDeletedWaitsForTerminating validates a terminating object is waited on without another delete.
+func TestWaitForObjectDeletedWaitsForTerminating(t *testing.T) {
+	// Seed a service held by a finalizer after its delete was accepted.
+	runner := buildTestRunner()
+	deleted := metav1.NewTime(time.Now())
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:              "svc",
+		Namespace:         runner.cfg.CheckNamespace,
+		DeletionTimestamp: &deleted,
+		Finalizers:        []string{"example.com/hold"},
+	}}
+	runner.client = fake.NewClientset(service)
+
+	// Release the finalizer shortly after the wait starts.
+	go func() {
+		time.Sleep(time.Millisecond * 200)
+		_ = runner.client.CoreV1().Services(runner.cfg.CheckNamespace).Delete(context.Background(), "svc", metav1.DeleteOptions{})
+	}()
+	var deletes atomic.Int32
+	deleteAgain := func(context.Context) error {
+		deletes.Add(1)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.waitForObjectDeleted(ctx, "service", "svc", serviceInformer, deleteAgain)
+	if err != nil {
+		t.Fatalf("expected the wait to finish but got %v", err)
+	}
+	if deletes.Load() != 0 {
+		t.Fatalf("expected no re-issued delete for a terminating object but got %d", deletes.Load())
+	}
+}
+
+// TestWaitForObjectDeletedAlreadyGone validates a missing object finishes the wait without a delete.
+func TestWaitForObjectDeletedAlreadyGone(t *testing.T) {
+	// Start with an empty cluster.
+	runner := buildTestRunner()
+	runner.client = fake.NewClientset()
+	var deletes atomic.Int32
+	deleteAgain := func(context.Context) error {
+		deletes.Add(1)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.waitForObjectDeleted(ctx, "service", "svc", serviceInformer, deleteAgain)
+	if err != nil || deletes.Load() != 0 {
+		t.Fatalf("expected an immediate finish without deletes but got %v after %d delete(s)", err, deletes.Load())
+	}
+}
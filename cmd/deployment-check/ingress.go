@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createIngressConfig builds an ingress that routes to the check service.
+func (r *CheckRunner) createIngressConfig() *networkingv1.Ingress {
+	// Route the configured path to the primary service port.
+	pathType := networkingv1.PathTypePrefix
+	rule := networkingv1.IngressRule{
+		Host: r.cfg.IngressHost,
+		IngressRuleValue: networkingv1.IngressRuleValue{
+			HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{
+					Path:     r.cfg.IngressPath,
+					PathType: &pathType,
+					Backend: networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: r.cfg.CheckServiceName,
+							Port: networkingv1.ServiceBackendPort{Number: r.cfg.CheckLoadBalancerPort},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	// Assemble the ingress, selecting a class only when configured.
+	ingress := &networkingv1.Ingress{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{rule},
+		},
+	}
+	if len(r.cfg.IngressClassName) != 0 {
+		ingress.Spec.IngressClassName = &r.cfg.IngressClassName
+	}
+	ingress.Name = r.cfg.CheckServiceName
+	ingress.Namespace = r.cfg.CheckNamespace
+	ingress.Labels = copyStringMap(r.cfg.ExtraLabels)
+	ingress.Annotations = r.resourceAnnotations(nil)
+
+	return ingress
+}
+
+// verifyIngress creates the ingress and validates a response through the ingress controller.
+func (r *CheckRunner) verifyIngress(ctx context.Context) error {
+	// Create the ingress.
+	_, err := r.client.NetworkingV1().Ingresses(r.cfg.CheckNamespace).Create(ctx, r.createIngressConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create ingress: %w", err)
+	}
+	log.Infoln("Created ingress", r.cfg.CheckServiceName, "in", r.cfg.CheckNamespace, "namespace.")
+	r.timeline.recordf("created ingress %s", r.cfg.CheckServiceName)
+
+	// Use the configured controller address or wait for the controller to publish one.
+	address := r.cfg.IngressAddress
+	if len(address) == 0 {
+		address, err = r.waitForIngressAddress(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	r.timeline.recordf("requesting ingress through %s", address)
+
+	// Request the path through the controller with the ingress host.
+	path := r.cfg.IngressPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	err = r.requestEndpoint(ctx, "http://"+ingressHostPort(address)+path, r.cfg.IngressHost)
+	if err != nil {
+		return fmt.Errorf("request through ingress address %s failed: %w", address, err)
+	}
+
+	return nil
+}
+
+// ingressHostPort returns the controller address as host:port, defaulting to port 80 and bracketing IPv6 addresses.
+func ingressHostPort(address string) string {
+	// Keep an address that already names its port.
+	_, _, err := net.SplitHostPort(address)
+	if err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(strings.Trim(address, "[]"), "80")
+}
+
+// waitForIngressAddress polls the ingress until the controller publishes an IP or hostname.
+func (r *CheckRunner) waitForIngressAddress(ctx context.Context) (string, error) {
+	// Bound the wait by the ingress timeout.
+	deadline := time.Now().Add(r.cfg.IngressTimeout)
+	for {
+		ingress, err := r.client.NetworkingV1().Ingresses(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+		if err != nil {
+			log.Debugln("Failed to fetch ingress:", err.Error())
+		}
+		if err == nil {
+			for _, status := range ingress.Status.LoadBalancer.Ingress {
+				if len(status.IP) != 0 {
+					return status.IP, nil
+				}
+				if len(status.Hostname) != 0 {
+					return status.Hostname, nil
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("ingress controller did not publish an address within %s", r.cfg.IngressTimeout)
+		}
+
+		// Wait before polling again.
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("context expired while waiting for an ingress address")
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// deleteIngressAndWait deletes the ingress and waits for it to disappear.
+func (r *CheckRunner) deleteIngressAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "ingress", func(ctx context.Context) error {
+		return r.client.NetworkingV1().Ingresses(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckServiceName, metav1.DeleteOptions{})
+	}, r.ingressExists)
+}
+
+// ingressExists reports whether the check ingress is present.
+func (r *CheckRunner) ingressExists(ctx context.Context) (bool, error) {
+	_, err := r.client.NetworkingV1().Ingresses(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+	return objectFound(err)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestIngressHostPort validates controller addresses gain the default port and IPv6 brackets.
+func TestIngressHostPort(t *testing.T) {
+	// Define addresses and the host:port expected for each.
+	cases := map[string]string{
+		"10.0.0.5":         "10.0.0.5:80",
+		"lb.example.com":   "lb.example.com:80",
+		"10.0.0.5:8080":    "10.0.0.5:8080",
+		"fd00::5":          "[fd00::5]:80",
+		"[fd00::5]":        "[fd00::5]:80",
+		"[fd00::5]:8080":   "[fd00::5]:8080",
+		"lb.example.com:8": "lb.example.com:8",
+	}
+
+	// Validate the host:port for each address.
+	for address, expected := range cases {
+		if ingressHostPort(address) != expected {
+			t.Fatalf("expected %s for %s but got %s", expected, address, ingressHostPort(address))
+		}
+	}
+}
+
+// TestWaitForIngressAddress validates the published IP is returned and a missing one ends the wait.
+func TestWaitForIngressAddress(t *testing.T) {
+	// Publish an IPv6 address on the check ingress.
+	runner := buildTestRunner()
+	runner.cfg.IngressTimeout = time.Minute
+	ingress := runner.createIngressConfig()
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "fd00::5"}}
+	runner.client = fake.NewClientset(ingress)
+
+	address, err := runner.waitForIngressAddress(context.Background())
+	if err != nil || address != "fd00::5" {
+		t.Fatalf("expected the published address but got %q and %v", address, err)
+	}
+
+	// An ingress without an address gives up when the wait ends.
+	runner.client = fake.NewClientset(runner.createIngressConfig())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = runner.waitForIngressAddress(ctx)
+	if err == nil {
+		t.Fatalf("expected a timeout without a published address")
+	}
+}
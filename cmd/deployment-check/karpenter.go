@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// karpenterNodeClaimResource identifies Karpenter NodeClaims.
+var karpenterNodeClaimResource = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"}
+
+// verifyKarpenterProvisioning confirms Karpenter launched a NodeClaim for the new capacity and reports its provisioning time.
+func (r *CheckRunner) verifyKarpenterProvisioning(ctx context.Context, existingNodes map[string]bool, createdAt time.Time) error {
+	// Confirm the pods landed on new nodes first.
+	err := r.verifyNodeProvisioning(ctx, existingNodes, createdAt)
+	if err != nil {
+		return err
+	}
+
+	// List NodeClaims through the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return err
+	}
+	nodeClaimList, err := client.Resource(karpenterNodeClaimResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list Karpenter NodeClaims: %w", err)
+	}
+
+	// Find NodeClaims created for this run and backing a node hosting check pods.
+	podNodes, err := r.deploymentPodNodes(ctx)
+	if err != nil {
+		return err
+	}
+	found := 0
+	for _, nodeClaim := range nodeClaimList.Items {
+		if nodeClaim.GetCreationTimestamp().Time.Before(createdAt) {
+			continue
+		}
+		nodeName, _, _ := unstructured.NestedString(nodeClaim.Object, "status", "nodeName")
+		if !podNodes[nodeName] {
+			continue
+		}
+		found++
+
+		// Report how long each provisioning milestone took.
+		launched := nodeClaimConditionTime(nodeClaim, "Launched")
+		initialized := nodeClaimConditionTime(nodeClaim, "Initialized")
+		createLatency := nodeClaim.GetCreationTimestamp().Time.Sub(createdAt).Round(time.Second)
+		log.Infoln("Karpenter NodeClaim", nodeClaim.GetName(), "for node", nodeName, "created", createLatency, "after the deployment.")
+		if !launched.IsZero() && !initialized.IsZero() {
+			log.Infoln("NodeClaim", nodeClaim.GetName(), "launched after", launched.Sub(createdAt).Round(time.Second), "and initialized after", initialized.Sub(createdAt).Round(time.Second))
+			r.timeline.recordf("karpenter nodeclaim %s for node %s launched %s and initialized %s after deployment creation", nodeClaim.GetName(), nodeName, launched.Sub(createdAt).Round(time.Second), initialized.Sub(createdAt).Round(time.Second))
+			continue
+		}
+		r.timeline.recordf("karpenter nodeclaim %s for node %s created %s after deployment creation", nodeClaim.GetName(), nodeName, createLatency)
+	}
+
+	// Fail when the new nodes were not provisioned by Karpenter.
+	if found == 0 {
+		return fmt.Errorf("check pods landed on new node(s) but no Karpenter NodeClaim created during the run backs them")
+	}
+
+	log.Infoln("Karpenter provisioned", found, "NodeClaim(s) for the check pods. Nodes will be consolidated after cleanup.")
+	return nil
+}
+
+// deploymentPodNodes returns the set of nodes hosting live check pods.
+func (r *CheckRunner) deploymentPodNodes(ctx context.Context) (map[string]bool, error) {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+
+	// Collect the node of each scheduled pod.
+	nodes := make(map[string]bool)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 || pod.DeletionTimestamp != nil {
+			continue
+		}
+		nodes[pod.Spec.NodeName] = true
+	}
+
+	return nodes, nil
+}
+
+// nodeClaimConditionTime returns when a true NodeClaim condition last transitioned, or the zero time.
+func nodeClaimConditionTime(nodeClaim unstructured.Unstructured, conditionType string) time.Time {
+	// Read the conditions from the unstructured status.
+	conditions, _, _ := unstructured.NestedSlice(nodeClaim.Object, "status", "conditions")
+	for _, rawCondition := range conditions {
+		condition, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] != conditionType || condition["status"] != "True" {
+			continue
+		}
+		transition, ok := condition["lastTransitionTime"].(string)
+		if !ok {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, transition)
+		if err != nil {
+			continue
+		}
+		return parsed
+	}
+
+	return time.Time{}
+}
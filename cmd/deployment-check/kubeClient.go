@@ -3,28 +3,101 @@ package main
 import (
 	"fmt"
 
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-// createKubeClient builds a Kubernetes clientset for in-cluster or kubeconfig use.
-func createKubeClient(kubeConfigPath string) (*kubernetes.Clientset, error) {
-	// Attempt in-cluster configuration first.
-	config, err := rest.InClusterConfig()
+// createKubeClient builds a Kubernetes clientset and its rest config for the targeted cluster.
+func createKubeClient(cfg *CheckConfig) (kubernetes.Interface, *rest.Config, error) {
+	// Resolve which cluster to talk to.
+	config, err := restConfigFor(cfg)
 	if err != nil {
-		// Fall back to kubeconfig for local development.
-		config, err = clientcmd.BuildConfigFromFlags("", kubeConfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create kubeconfig: %w", err)
+		return nil, nil, err
+	}
+
+	// Act as the configured identity so its RBAC, not the checker's, is exercised.
+	if len(cfg.ImpersonateUser) != 0 {
+		log.Infoln("Impersonating user", cfg.ImpersonateUser, "with groups", cfg.ImpersonateGroups)
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: cfg.ImpersonateUser,
+			Groups:   cfg.ImpersonateGroups,
 		}
 	}
 
+	// Apply the configured rate limits and request timeout, keeping client-go defaults otherwise.
+	if cfg.KubeClientQPS > 0 {
+		config.QPS = cfg.KubeClientQPS
+	}
+	if cfg.KubeClientBurst > 0 {
+		config.Burst = cfg.KubeClientBurst
+	}
+	if cfg.KubeClientTimeout > 0 {
+		config.Timeout = cfg.KubeClientTimeout
+	}
+
 	// Build the clientset for typed API access.
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+		return nil, nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return clientset, config, nil
+}
+
+// restConfigFor resolves an explicit API server, a kubeconfig context, or the in-cluster config with a kubeconfig fallback.
+func restConfigFor(cfg *CheckConfig) (*rest.Config, error) {
+	// Target an explicit API server with a token file that is re-read as it rotates.
+	if len(cfg.KubeAPIServer) != 0 {
+		log.Infoln("Targeting API server", cfg.KubeAPIServer+".")
+		return &rest.Config{
+			Host:            cfg.KubeAPIServer,
+			BearerTokenFile: cfg.KubeTokenFile,
+			TLSClientConfig: rest.TLSClientConfig{CAFile: cfg.KubeCAFile},
+		}, nil
+	}
+
+	// Target a named kubeconfig context, skipping the in-cluster config.
+	if len(cfg.KubeContext) != 0 {
+		log.Infoln("Targeting kubeconfig context", cfg.KubeContext+".")
+		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: cfg.KubeConfigPath}
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.KubeContext}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig context %s: %w", cfg.KubeContext, err)
+		}
+		return config, nil
+	}
+
+	// Attempt in-cluster configuration first.
+	config, err := rest.InClusterConfig()
+	if err == nil {
+		return config, nil
+	}
+
+	// Fall back to kubeconfig for local development.
+	config, err = clientcmd.BuildConfigFromFlags("", cfg.KubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubeconfig: %w", err)
+	}
+
+	return config, nil
+}
+
+// dynamicClient builds a dynamic client for custom resources from the runner's rest config.
+func (r *CheckRunner) dynamicClient() (dynamic.Interface, error) {
+	// Guard against runners built without a rest config.
+	if r.restConfig == nil {
+		return nil, fmt.Errorf("no rest config available for dynamic client")
+	}
+
+	// Build the dynamic client.
+	client, err := dynamic.NewForConfig(r.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	return clientset, nil
+	return client, nil
 }
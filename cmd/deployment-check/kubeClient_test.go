@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestRestConfigFor validates explicit API servers and kubeconfig contexts are targeted.
+func TestRestConfigFor(t *testing.T) {
+	// An explicit API server uses the token and CA files as given.
+	config, err := restConfigFor(&CheckConfig{KubeAPIServer: "https://spoke.example.com:6443", KubeTokenFile: "/var/run/token", KubeCAFile: "/var/run/ca.crt"})
+	if err != nil {
+		t.Fatalf("unexpected error building API server config: %v", err)
+	}
+	if config.Host != "https://spoke.example.com:6443" || config.BearerTokenFile != "/var/run/token" || config.CAFile != "/var/run/ca.crt" {
+		t.Fatalf("expected the API server settings to carry through but got host %s token file %s CA file %s", config.Host, config.BearerTokenFile, config.CAFile)
+	}
+
+	// A context picks its cluster out of a kubeconfig with several.
+	kubeConfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://hub.example.com
+- name: spoke
+  cluster:
+    server: https://spoke.example.com
+users:
+- name: checker
+  user:
+    token: abc
+contexts:
+- name: hub
+  context:
+    cluster: hub
+    user: checker
+- name: spoke
+  context:
+    cluster: spoke
+    user: checker
+current-context: hub
+`
+	path := filepath.Join(t.TempDir(), "config")
+	err = os.WriteFile(path, []byte(kubeConfig), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	config, err = restConfigFor(&CheckConfig{KubeConfigPath: path, KubeContext: "spoke"})
+	if err != nil {
+		t.Fatalf("unexpected error building context config: %v", err)
+	}
+	if config.Host != "https://spoke.example.com" {
+		t.Fatalf("expected the spoke context to be targeted but got %s", config.Host)
+	}
+
+	// An unknown context is an error rather than a silent fallback.
+	_, err = restConfigFor(&CheckConfig{KubeConfigPath: path, KubeContext: "missing"})
+	if err == nil {
+		t.Fatalf("expected an error for an unknown context")
+	}
+}
+
+// TestRemoteClusterDataPathConflicts validates checks that need the target cluster's network are rejected for a remote cluster.
+func TestRemoteClusterDataPathConflicts(t *testing.T) {
+	// Service requests alone are allowed since they go through the API server.
+	t.Setenv("KUBE_CONTEXT", "spoke")
+	_, err := parseConfig()
+	if err != nil {
+		t.Fatalf("expected a remote cluster config to parse but got %v", err)
+	}
+
+	// Direct pod and DNS checks are named in the error.
+	t.Setenv("CHECK_POD_IP_VERIFY", "true")
+	t.Setenv("CHECK_SERVICE_DNS_VERIFY", "true")
+	_, err = parseConfig()
+	if err == nil || !strings.Contains(err.Error(), "CHECK_POD_IP_VERIFY, CHECK_SERVICE_DNS_VERIFY") {
+		t.Fatalf("expected the data path checks to be rejected but got %v", err)
+	}
+}
+
+// TestServiceProxyURL validates service requests for a remote cluster go through the API server proxy.
+func TestServiceProxyURL(t *testing.T) {
+	// Build the proxy path for the service port.
+	runner := buildTestRunner()
+	runner.restConfig = &rest.Config{Host: "spoke.example.com:6443"}
+	runner.cfg.HTTPPath = "/healthz"
+	expected := "https://spoke.example.com:6443/api/v1/namespaces/" + runner.cfg.CheckNamespace + "/services/http:" + runner.cfg.CheckServiceName + ":80/proxy/healthz"
+	if runner.serviceProxyURL(80) != expected {
+		t.Fatalf("expected %s but got %s", expected, runner.serviceProxyURL(80))
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// validateLoadBalancer waits for the cloud load balancer and requests every service port on its external address.
+func (r *CheckRunner) validateLoadBalancer(ctx context.Context) error {
+	// Wait for the cloud provider to publish an ingress address.
+	provisionStart := time.Now()
+	address, err := r.waitForLoadBalancerIngress(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Load balancer ingress", address, "provisioned in", time.Since(provisionStart).Round(time.Second))
+	r.timeline.recordf("load balancer ingress %s provisioned in %s", address, time.Since(provisionStart).Round(time.Second))
+
+	// Request each service port on the external address.
+	failures := make([]string, 0)
+	for _, port := range r.cfg.checkPorts() {
+		endpoint := net.JoinHostPort(address, strconv.Itoa(int(port.ServicePort)))
+		err = r.requestServiceEndpoint(ctx, endpoint)
+		if err != nil {
+			log.Errorln("Load balancer port", port.ServicePort, "failed validation:", err.Error())
+			r.timeline.recordf("load balancer port %d failed validation: %s", port.ServicePort, err.Error())
+			failures = append(failures, fmt.Sprintf("port %d: %s", port.ServicePort, err.Error()))
+			continue
+		}
+		log.Infoln("Load balancer port", port.ServicePort, "passed validation.")
+	}
+
+	// Report every failing port together.
+	if len(failures) != 0 {
+		return fmt.Errorf("%d of %d load balancer port(s) at %s failed validation: %s", len(failures), len(r.cfg.checkPorts()), address, strings.Join(failures, " | "))
+	}
+
+	return nil
+}
+
+// waitForLoadBalancerIngress watches the service until the load balancer reports an IP or hostname.
+func (r *CheckRunner) waitForLoadBalancerIngress(ctx context.Context) (string, error) {
+	// Bound the wait by the load balancer provisioning timeout.
+	waitCtx, cancel := context.WithTimeout(ctx, r.cfg.LoadBalancerTimeout)
+	defer cancel()
+
+	// Watch the service for status updates.
+	watcher, err := r.watchService(waitCtx, r.cfg.CheckServiceName, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to watch service for load balancer ingress: %w", err)
+	}
+	defer watcher.Stop()
+	events := watcher.ResultChan()
+
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				// The resumable watch only closes once ctx ends.
+				events = nil
+				continue
+			}
+			service, ok := event.Object.(*corev1.Service)
+			if !ok {
+				log.Debugln("Got a watch event for a non-service object -- ignoring.")
+				continue
+			}
+			address := loadBalancerAddress(service)
+			if len(address) != 0 {
+				return address, nil
+			}
+		case <-waitCtx.Done():
+			return "", fmt.Errorf("load balancer ingress was not provisioned within %s", r.cfg.LoadBalancerTimeout)
+		}
+	}
+}
+
+// loadBalancerAddress returns the first ingress IP or hostname published for the service.
+func loadBalancerAddress(service *corev1.Service) string {
+	// Prefer IPs, falling back to hostnames for DNS-based load balancers.
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if len(ingress.IP) != 0 {
+			return ingress.IP
+		}
+		if len(ingress.Hostname) != 0 {
+			return ingress.Hostname
+		}
+	}
+
+	return ""
+}
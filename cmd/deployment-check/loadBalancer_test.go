@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestLoadBalancerAddress validates IPs are preferred and hostnames cover DNS-based load balancers.
+func TestLoadBalancerAddress(t *testing.T) {
+	// Define published ingress entries and the address expected for each.
+	cases := []struct {
+		ingress  []corev1.LoadBalancerIngress
+		expected string
+	}{
+		{ingress: nil, expected: ""},
+		{ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}, expected: "203.0.113.10"},
+		{ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}, expected: "lb.example.com"},
+		{ingress: []corev1.LoadBalancerIngress{{}, {Hostname: "lb.example.com", IP: "203.0.113.10"}}, expected: "203.0.113.10"},
+	}
+
+	// Validate the address for each service status.
+	for _, c := range cases {
+		service := &corev1.Service{}
+		service.Status.LoadBalancer.Ingress = c.ingress
+		if loadBalancerAddress(service) != c.expected {
+			t.Fatalf("expected %q for %v but got %q", c.expected, c.ingress, loadBalancerAddress(service))
+		}
+	}
+}
+
+// TestWaitForLoadBalancerIngress validates the wait gives up after the provisioning timeout.
+func TestWaitForLoadBalancerIngress(t *testing.T) {
+	// Seed a load balancer service the cloud provider never provisions.
+	runner := buildTestRunner()
+	runner.cfg.LoadBalancerTimeout = time.Millisecond * 100
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckServiceName, Namespace: runner.cfg.CheckNamespace}}
+	service.Spec.Type = corev1.ServiceTypeLoadBalancer
+	runner.client = fake.NewClientset(service)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	_, err := runner.waitForLoadBalancerIngress(ctx)
+	if err == nil || !strings.Contains(err.Error(), "load balancer ingress was not provisioned within 100ms") || ctx.Err() != nil {
+		t.Fatalf("expected the provisioning timeout before the deadline but got %v", err)
+	}
+}
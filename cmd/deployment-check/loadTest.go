@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// loadTestResult summarizes a burst of requests against the service.
+type loadTestResult struct {
+	// requests is the number of requests sent.
+	requests int
+	// failures is the number of requests that errored or returned an unacceptable status.
+	failures int
+	// errors counts each distinct failure message.
+	errors map[string]int
+	// latencies are the durations of the successful requests.
+	latencies []time.Duration
+}
+
+// errorRate returns the fraction of requests that failed.
+func (result loadTestResult) errorRate() float64 {
+	// Guard against an empty run.
+	if result.requests == 0 {
+		return 0
+	}
+
+	return float64(result.failures) / float64(result.requests)
+}
+
+// percentile returns the nearest-rank latency percentile of the successful requests.
+func (result loadTestResult) percentile(p float64) time.Duration {
+	// Guard against a run without successes.
+	if len(result.latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(result.latencies))
+	copy(sorted, result.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+
+	return sorted[max(rank, 0)]
+}
+
+// runLoadTest fires the configured number of requests at the service with the configured concurrency.
+func (r *CheckRunner) runLoadTest(ctx context.Context, serviceIP string) error {
+	// Hand the requests out to the workers over a channel.
+	address := net.JoinHostPort(serviceIP, strconv.Itoa(int(r.cfg.CheckLoadBalancerPort)))
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.freshConnectionTransport()}
+	work := make(chan struct{}, r.cfg.HTTPRequestCount)
+	for i := 0; i < r.cfg.HTTPRequestCount; i++ {
+		work <- struct{}{}
+	}
+	close(work)
+	log.Infoln("Sending", r.cfg.HTTPRequestCount, "request(s) to", address, "with concurrency", r.cfg.HTTPConcurrency)
+
+	// Time each request and collect the outcomes.
+	result := loadTestResult{errors: make(map[string]int)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	started := time.Now()
+	for i := 0; i < r.cfg.HTTPConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range work {
+				if ctx.Err() != nil {
+					return
+				}
+				requestStart := time.Now()
+				err := r.requestPodOnce(ctx, client, address)
+				latency := time.Since(requestStart)
+				mu.Lock()
+				result.requests++
+				if err != nil {
+					result.failures++
+					result.errors[err.Error()]++
+				}
+				if err == nil {
+					result.latencies = append(result.latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	// Report the error rate and latency percentiles.
+	p50 := result.percentile(50)
+	p90 := result.percentile(90)
+	p99 := result.percentile(99)
+	r.metrics.set(metricLoadErrorRate, result.errorRate())
+	r.metrics.observe(metricLoadLatencyP50, p50)
+	r.metrics.observe(metricLoadLatencyP99, p99)
+	summary := fmt.Sprintf("%d request(s) in %s, %d failed (%.2f%%), latency p50 %s p90 %s p99 %s", result.requests, elapsed, result.failures, result.errorRate()*100, p50, p90, p99)
+	log.Infoln("Load test:", summary)
+	r.timeline.record("load test: " + summary)
+	if ctx.Err() != nil {
+		return fmt.Errorf("context expired during the load test after %s", summary)
+	}
+
+	// Fail when more requests failed than allowed, naming the most common errors.
+	if result.errorRate() > r.cfg.HTTPMaxErrorRate {
+		return fmt.Errorf("load test error rate %.2f%% exceeds %.2f%%: %s; errors: %s", result.errorRate()*100, r.cfg.HTTPMaxErrorRate*100, summary, describeLoadErrors(result.errors))
+	}
+
+	return nil
+}
+
+// describeLoadErrors renders the distinct errors with their counts, most frequent first.
+func describeLoadErrors(errors map[string]int) string {
+	// Sort by count, then message, so the rendering is stable.
+	messages := make([]string, 0, len(errors))
+	for message := range errors {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if errors[messages[i]] != errors[messages[j]] {
+			return errors[messages[i]] > errors[messages[j]]
+		}
+		return messages[i] < messages[j]
+	})
+	parts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		parts = append(parts, fmt.Sprintf("%dx %s", errors[message], message))
+	}
+
+	return strings.Join(parts, "; ")
+}
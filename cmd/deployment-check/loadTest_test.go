@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLoadTestResult validates the error rate, nearest-rank percentiles, and error rendering.
+func TestLoadTestResult(t *testing.T) {
+	// Build a run of ten requests with two failures.
+	result := loadTestResult{requests: 10, failures: 2, errors: map[string]int{"received 503": 1, "connection reset by peer": 1}}
+	for i := 1; i <= 8; i++ {
+		result.latencies = append(result.latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	if result.errorRate() != 0.2 {
+		t.Fatalf("expected an error rate of 0.2 but got %v", result.errorRate())
+	}
+
+	if result.percentile(50) != 4*time.Millisecond || result.percentile(99) != 8*time.Millisecond {
+		t.Fatalf("expected p50 4ms and p99 8ms but got %s and %s", result.percentile(50), result.percentile(99))
+	}
+
+	// Render equal counts in message order.
+	if describeLoadErrors(result.errors) != "1x connection reset by peer; 1x received 503" {
+		t.Fatalf("unexpected error rendering %q", describeLoadErrors(result.errors))
+	}
+
+	// An empty run has no errors or latencies.
+	if (loadTestResult{}).errorRate() != 0 || (loadTestResult{}).percentile(99) != 0 {
+		t.Fatalf("expected an empty run to report zeros")
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,10 +11,25 @@ import (
 
 	"github.com/kuberhealthy/kuberhealthy/v3/pkg/checkclient"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 // main initializes configuration, dependencies, and executes the deployment check.
 func main() {
+	// Print the build information and exit for the version subcommand.
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		fmt.Println(versionString())
+		return
+	}
+	log.Infoln("Starting", versionString()+".")
+
+	// Apply command-line flags over the environment.
+	err := applyFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalln("Failed to parse flags:", err.Error())
+	}
+
 	// Parse configuration from environment variables.
 	cfg, err := parseConfig()
 	if err != nil {
@@ -20,9 +37,14 @@ func main() {
 	}
 
 	// Build a Kubernetes clientset for API access.
-	clientset, err := createKubeClient(cfg.KubeConfigPath)
+	clientset, restConfig, err := createKubeClient(cfg)
+	if err != nil && cfg.CleanupOnly {
+		log.Fatalln("Failed to create a kubernetes client:", err.Error())
+	}
 	if err != nil {
-		reportFailure([]string{"failed to create a kubernetes client: " + err.Error()})
+		report := []string{"failed to create a kubernetes client: " + err.Error()}
+		writeCheckResult(cfg, time.Now(), report, nil)
+		reportFailure(report)
 		return
 	}
 	log.Infoln("Kubernetes client created.")
@@ -32,26 +54,124 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.CheckTimeLimit)
 	defer cancel()
 
-	// Build the runner that will execute the check.
-	runner := newCheckRunner(cfg, clientset, now)
+	// Skip the run while another checker is working on the same deployment.
+	var lock *runLock
+	if cfg.RunLock {
+		lock, err = acquireRunLock(ctx, cfg, clientset)
+		if err != nil && cfg.CleanupOnly {
+			log.Fatalln("Failed to acquire the run lock:", err.Error())
+		}
+		if errors.Is(err, errRunLockHeld) && !cfg.RunLockFailWhenHeld {
+			log.Warnln("Skipping this run:", err.Error())
+			reportSuccess()
+			return
+		}
+		if err != nil {
+			report := []string{"failed to acquire the run lock: " + err.Error()}
+			writeCheckResult(cfg, now, report, nil)
+			reportFailure(report)
+			return
+		}
+		defer lock.release()
+	}
+
+	// Only remove leftovers from earlier runs in cleanup-only mode, which runs by hand and never reports to Kuberhealthy.
+	if cfg.CleanupOnly {
+		err = runCleanupOnly(ctx, cfg, clientset, restConfig, now)
+		if err != nil {
+			log.Errorln("Failed to clean up check resources:", err.Error())
+			if lock != nil {
+				lock.release()
+			}
+			os.Exit(1)
+		}
+		log.Infoln("Cleanup-only run finished.")
+		return
+	}
 
-	// Start interrupt handling in the background.
+	// Expose phase metrics when requested, keeping them up briefly after the run.
+	registry := &metricsRegistry{}
+	if len(cfg.MetricsAddress) != 0 {
+		metricsServer := serveMetrics(cfg.MetricsAddress, registry)
+		defer stopMetrics(metricsServer, cfg.MetricsLinger)
+	}
+
+	// Start interrupt handling in the background, cleaning up whichever runners have started.
+	active := &activeRunners{}
 	interrupts := make(chan os.Signal, 3)
 	signal.Notify(interrupts, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGINT)
-	go runner.handleInterrupts(ctx, cancel, interrupts)
+	go handleInterrupts(ctx, cancel, interrupts, active, lock, cfg.ShutdownGracePeriod)
+
+	// Run the check once per node pool when requested.
+	if len(cfg.NodePoolLabel) != 0 {
+		report, results := runNodePools(ctx, cfg, clientset, restConfig, registry, active)
+		pushRunMetrics(cfg, registry)
+		writeCheckResult(cfg, now, report, results)
+		if len(report) != 0 {
+			reportFailure(report)
+			return
+		}
+		reportSuccess()
+		return
+	}
+
+	// Run the check once per namespace when requested.
+	if len(cfg.CheckNamespaces) != 0 {
+		report, results := runNamespaces(ctx, cfg, clientset, restConfig, registry, active)
+		pushRunMetrics(cfg, registry)
+		writeCheckResult(cfg, now, report, results)
+		if len(report) != 0 {
+			reportFailure(report)
+			return
+		}
+		reportSuccess()
+		return
+	}
 
 	// Run the check and report status.
-	err = runner.run(ctx)
+	runner := newCheckRunner(cfg, clientset, restConfig, now)
+	active.add(runner)
+	registry.track(runner.metrics, "")
+	err = runner.runAndRecord(ctx)
+	pushRunMetrics(cfg, registry)
+	results := []runResult{runner.runResult(err, "")}
 	if err != nil {
-		reportFailure([]string{err.Error()})
+		report := runner.failureReport(err)
+		writeCheckResult(cfg, now, report, results)
+		reportFailure(report)
 		return
 	}
+	writeCheckResult(cfg, now, nil, results)
 
 	reportSuccess()
 }
 
-// handleInterrupts listens for signals and performs cleanup before exit.
-func (r *CheckRunner) handleInterrupts(ctx context.Context, cancel context.CancelFunc, interrupts chan os.Signal) {
+// runCleanupOnly removes leftovers from earlier runs in the check namespace or each configured namespace.
+func runCleanupOnly(ctx context.Context, cfg *CheckConfig, client kubernetes.Interface, restConfig *rest.Config, now time.Time) error {
+	// Sweep every configured namespace when several are checked.
+	if len(cfg.CheckNamespaces) != 0 {
+		return cleanupNamespaces(ctx, cfg, client, restConfig)
+	}
+
+	return newCheckRunner(cfg, client, restConfig, now).cleanupOnly(ctx)
+}
+
+// pushRunMetrics pushes the final metrics to the configured Pushgateway, logging rather than failing on errors.
+func pushRunMetrics(cfg *CheckConfig, registry *metricsRegistry) {
+	// Skip when no Pushgateway is configured.
+	if len(cfg.PushgatewayURL) == 0 {
+		return
+	}
+
+	// Use a fresh context so an expired check deadline still allows the push.
+	err := pushMetrics(context.Background(), cfg.PushgatewayURL, cfg.PushgatewayJob, cfg.CheckNamespace, registry)
+	if err != nil {
+		log.Warnln("Failed to push metrics:", err.Error())
+	}
+}
+
+// handleInterrupts listens for signals and cleans up every active runner before exit.
+func handleInterrupts(ctx context.Context, cancel context.CancelFunc, interrupts chan os.Signal, active *activeRunners, lock *runLock, gracePeriod time.Duration) {
 	// Wait for the first interrupt signal.
 	sig := <-interrupts
 	log.Infoln("Received an interrupt signal from the signal channel.")
@@ -65,7 +185,7 @@ func (r *CheckRunner) handleInterrupts(ctx context.Context, cancel context.Cance
 	log.Infoln("Shutting down.")
 
 	cleanupChan := make(chan error, 1)
-	go r.runCleanupAsync(ctx, cleanupChan)
+	go func() { cleanupChan <- active.cleanup(ctx) }()
 
 	select {
 	case sig = <-interrupts:
@@ -76,10 +196,15 @@ func (r *CheckRunner) handleInterrupts(ctx context.Context, cancel context.Cance
 		if cleanupErr != nil {
 			log.Errorln("Failed to clean up check resources properly:", cleanupErr.Error())
 		}
-	case <-time.After(r.cfg.ShutdownGracePeriod):
+	case <-time.After(gracePeriod):
 		log.Infoln("Clean up took too long to complete and timed out.")
 	}
 
+	// Free the run lock so the next checker does not wait for it to expire.
+	if lock != nil {
+		lock.release()
+	}
+
 	os.Exit(0)
 }
 
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// meshModeIstio enables Istio sidecar handling.
+	meshModeIstio = "istio"
+	// istioProxyContainerName is the container Istio injects into meshed pods.
+	istioProxyContainerName = "istio-proxy"
+	// istioInjectLabel turns sidecar injection on or off for a pod.
+	istioInjectLabel = "sidecar.istio.io/inject"
+	// istioProxyConfigAnnotation carries per-pod proxy configuration.
+	istioProxyConfigAnnotation = "proxy.istio.io/config"
+	// istioHoldApplicationConfig makes the application wait for the proxy to start.
+	istioHoldApplicationConfig = `{"holdApplicationUntilProxyStarts": true}`
+	// meshQuitTimeout bounds each proxy shutdown request.
+	meshQuitTimeout = time.Second * 10
+)
+
+// applyMeshPodMetadata sets the pod labels and annotations that control sidecar injection.
+func (r *CheckRunner) applyMeshPodMetadata(meta *metav1.ObjectMeta) {
+	// Leave the pod alone outside of mesh mode.
+	if r.cfg.MeshMode != meshModeIstio {
+		return
+	}
+
+	// Ask for injection explicitly so namespace defaults do not decide.
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string, 1)
+	}
+	meta.Labels[istioInjectLabel] = fmt.Sprint(r.cfg.MeshInject)
+
+	// Start the application only once the proxy can carry its traffic.
+	if r.cfg.MeshInject && r.cfg.MeshHoldApplication {
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string, 1)
+		}
+		meta.Annotations[istioProxyConfigAnnotation] = istioHoldApplicationConfig
+	}
+}
+
+// meshProxyStatus returns the proxy container status of a pod, whether injected as a container or a native sidecar.
+func meshProxyStatus(pod corev1.Pod) (corev1.ContainerStatus, bool) {
+	// Search every container status for the proxy.
+	for _, status := range podContainerStatuses(pod) {
+		if status.Name == istioProxyContainerName {
+			return status, true
+		}
+	}
+
+	return corev1.ContainerStatus{}, false
+}
+
+// verifyMeshInjection confirms every check pod has a ready proxy when injection is on, and none when it is off.
+func (r *CheckRunner) verifyMeshInjection(ctx context.Context) error {
+	// List the pods of the current run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for mesh verification: %w", err)
+	}
+
+	// Inspect every live pod and report each one in the wrong state.
+	problems := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		status, injected := meshProxyStatus(pod)
+		if !r.cfg.MeshInject && injected {
+			problems = append(problems, fmt.Sprintf("pod: %s was injected with %s although injection is disabled", pod.Name, istioProxyContainerName))
+			continue
+		}
+		if !r.cfg.MeshInject {
+			continue
+		}
+		if !injected {
+			problems = append(problems, fmt.Sprintf("pod: %s node: %s has no %s container; check the injection webhook", pod.Name, pod.Spec.NodeName, istioProxyContainerName))
+			continue
+		}
+		if !status.Ready {
+			problems = append(problems, fmt.Sprintf("pod: %s node: %s proxy state: %s", pod.Name, pod.Spec.NodeName, describeContainerState(status)))
+		}
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("mesh sidecar injection is not as configured: %s", strings.Join(problems, "; "))
+	}
+
+	log.Infoln("Mesh injection verified in", len(podList.Items), "check pod(s) with injection", r.cfg.MeshInject)
+	r.timeline.recordf("verified mesh injection %t in %d pod(s)", r.cfg.MeshInject, len(podList.Items))
+	return nil
+}
+
+// quitMeshProxies asks every injected proxy to exit so terminating pods are not held up by it.
+func (r *CheckRunner) quitMeshProxies(ctx context.Context) {
+	// List the pods of the current run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		log.Warnln("Failed to list deployment pods to stop mesh proxies:", err.Error())
+		return
+	}
+
+	// Request a shutdown through the proxy agent in each running pod, tolerating failures.
+	for _, pod := range podList.Items {
+		status, injected := meshProxyStatus(pod)
+		if !injected || status.State.Running == nil {
+			continue
+		}
+		quitCtx, cancel := context.WithTimeout(ctx, meshQuitTimeout)
+		_, stderr, execErr := r.execInPod(quitCtx, pod, istioProxyContainerName, []string{"pilot-agent", "request", "POST", "quitquitquit"})
+		cancel()
+		if execErr != nil {
+			log.Warnln("Failed to stop the mesh proxy in pod", pod.Name+":", strings.TrimSpace(stderr), execErr.Error())
+			continue
+		}
+		log.Debugln("Stopped the mesh proxy in pod", pod.Name)
+	}
+}
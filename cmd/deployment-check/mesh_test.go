@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestMeshPodMetadata validates the injection label and hold annotation follow the mesh settings.
+func TestMeshPodMetadata(t *testing.T) {
+	// Leave the pod template untouched outside of mesh mode.
+	runner := buildTestRunner()
+	template := runner.createDeploymentConfig("nginx:test").Spec.Template
+	if _, ok := template.Labels[istioInjectLabel]; ok {
+		t.Fatalf("expected no injection label outside of mesh mode but got %v", template.Labels)
+	}
+
+	// Request injection and hold the application in mesh mode.
+	runner.cfg.MeshMode = meshModeIstio
+	runner.cfg.MeshInject = true
+	runner.cfg.MeshHoldApplication = true
+	template = runner.createDeploymentConfig("nginx:test").Spec.Template
+	if template.Labels[istioInjectLabel] != "true" {
+		t.Fatalf("expected the injection label to be true but got %v", template.Labels)
+	}
+
+	if template.Annotations[istioProxyConfigAnnotation] != istioHoldApplicationConfig {
+		t.Fatalf("expected the hold annotation but got %v", template.Annotations)
+	}
+
+	// Opt the pods out of the mesh without the hold annotation.
+	runner.cfg.MeshInject = false
+	template = runner.createDeploymentConfig("nginx:test").Spec.Template
+	if template.Labels[istioInjectLabel] != "false" {
+		t.Fatalf("expected the injection label to be false but got %v", template.Labels)
+	}
+
+	if _, ok := template.Annotations[istioProxyConfigAnnotation]; ok {
+		t.Fatalf("expected no hold annotation without injection but got %v", template.Annotations)
+	}
+}
+
+// TestMeshProxyStatus validates the proxy is found as a regular container or a native sidecar.
+func TestMeshProxyStatus(t *testing.T) {
+	// Find the proxy among the regular containers.
+	pod := corev1.Pod{}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "deployment-container"}, {Name: istioProxyContainerName, Ready: true}}
+	status, found := meshProxyStatus(pod)
+	if !found || !status.Ready {
+		t.Fatalf("expected a ready proxy in the containers but got %v %v", found, status)
+	}
+
+	// Find the proxy among the init containers.
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "deployment-container"}}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: istioProxyContainerName}}
+	_, found = meshProxyStatus(pod)
+	if !found {
+		t.Fatalf("expected the proxy among the init containers")
+	}
+
+	// Report a pod without a proxy.
+	pod.Status.InitContainerStatuses = nil
+	_, found = meshProxyStatus(pod)
+	if found {
+		t.Fatalf("expected no proxy in an uninjected pod")
+	}
+}
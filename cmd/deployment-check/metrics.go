@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// metricDeploymentCreateDuration is the time from deployment creation to availability.
+	metricDeploymentCreateDuration = "deployment_check_deployment_create_duration_seconds"
+	// metricRolloutDuration is the time for the rolling update to complete.
+	metricRolloutDuration = "deployment_check_rollout_duration_seconds"
+	// metricHTTPFirstSuccess is the time from service creation to the first successful response on every port.
+	metricHTTPFirstSuccess = "deployment_check_http_first_success_seconds"
+	// metricLoadErrorRate is the fraction of load test requests that failed.
+	metricLoadErrorRate = "deployment_check_load_error_rate"
+	// metricLoadLatencyP50 is the median latency of successful load test requests.
+	metricLoadLatencyP50 = "deployment_check_load_latency_p50_seconds"
+	// metricLoadLatencyP99 is the 99th percentile latency of successful load test requests.
+	metricLoadLatencyP99 = "deployment_check_load_latency_p99_seconds"
+	// metricCleanupDuration is the time the last cleanup took.
+	metricCleanupDuration = "deployment_check_cleanup_duration_seconds"
+	// metricRunDuration is the time the whole run took.
+	metricRunDuration = "deployment_check_run_duration_seconds"
+	// metricSuccess is 1 when the run passed and 0 when it failed.
+	metricSuccess = "deployment_check_success"
+	// metricPhaseDuration is the time each phase of the run took, labeled by phase.
+	metricPhaseDuration = "deployment_check_phase_duration_seconds"
+	// metricsPushTimeout bounds the push to a Pushgateway.
+	metricsPushTimeout = time.Second * 10
+	// metricsShutdownTimeout bounds the metrics server shutdown.
+	metricsShutdownTimeout = time.Second * 5
+)
+
+// metricHelp documents each metric in the exposition output, in render order.
+var metricHelp = []struct {
+	// name is the metric name.
+	name string
+	// help is the HELP text.
+	help string
+}{
+	{name: metricDeploymentCreateDuration, help: "Seconds from deployment creation until it was available."},
+	{name: metricRolloutDuration, help: "Seconds the rolling update took to complete."},
+	{name: metricHTTPFirstSuccess, help: "Seconds from service creation until every service port first responded successfully."},
+	{name: metricLoadErrorRate, help: "Fraction of load test requests that failed."},
+	{name: metricLoadLatencyP50, help: "Median seconds of successful load test requests."},
+	{name: metricLoadLatencyP99, help: "99th percentile seconds of successful load test requests."},
+	{name: metricCleanupDuration, help: "Seconds the last cleanup took."},
+	{name: metricRunDuration, help: "Seconds the whole check run took."},
+	{name: metricSuccess, help: "Whether the check run passed (1) or failed (0)."},
+	{name: metricPhaseDuration, help: "Seconds each phase of the check run took."},
+}
+
+// runMetrics holds the gauge values recorded during one check run.
+type runMetrics struct {
+	// mu guards values and phases.
+	mu sync.Mutex
+	// pool is the node pool the run was pinned to, if any.
+	pool string
+	// namespace is the check namespace when the run is one of several namespaces, if any.
+	namespace string
+	// values maps metric names to their latest value.
+	values map[string]float64
+	// phases are the phase durations of the finished run.
+	phases []phaseTiming
+}
+
+// newRunMetrics creates an empty metric set for a run.
+func newRunMetrics() *runMetrics {
+	return &runMetrics{values: make(map[string]float64)}
+}
+
+// set records the latest value of a metric.
+func (m *runMetrics) set(name string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[name] = value
+}
+
+// observe records a duration metric in seconds.
+func (m *runMetrics) observe(name string, duration time.Duration) {
+	m.set(name, duration.Seconds())
+}
+
+// observePhases records the phase durations of the run.
+func (m *runMetrics) observePhases(phases []phaseTiming) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phases = phases
+}
+
+// snapshot copies the recorded values.
+func (m *runMetrics) snapshot() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := make(map[string]float64, len(m.values))
+	for name, value := range m.values {
+		values[name] = value
+	}
+
+	return values
+}
+
+// metricsRegistry collects the metric sets of every run in this process.
+type metricsRegistry struct {
+	// mu guards runs.
+	mu sync.Mutex
+	// runs are the tracked metric sets.
+	runs []*runMetrics
+}
+
+// track adds a run's metrics to the registry under an optional node pool.
+func (reg *metricsRegistry) track(metrics *runMetrics, pool string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	metrics.mu.Lock()
+	metrics.pool = pool
+	metrics.mu.Unlock()
+	reg.runs = append(reg.runs, metrics)
+}
+
+// trackNamespace adds a run's metrics to the registry under its check namespace.
+func (reg *metricsRegistry) trackNamespace(metrics *runMetrics, namespace string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	metrics.mu.Lock()
+	metrics.namespace = namespace
+	metrics.mu.Unlock()
+	reg.runs = append(reg.runs, metrics)
+}
+
+// render writes every recorded metric in the Prometheus text exposition format.
+func (reg *metricsRegistry) render() string {
+	// Snapshot the runs so rendering does not hold locks on live runs.
+	reg.mu.Lock()
+	runs := make([]*runMetrics, len(reg.runs))
+	copy(runs, reg.runs)
+	reg.mu.Unlock()
+	labels := make([]string, len(runs))
+	runPairs := make([][]string, len(runs))
+	snapshots := make([]map[string]float64, len(runs))
+	phases := make([][]phaseTiming, len(runs))
+	for i, run := range runs {
+		snapshots[i] = run.snapshot()
+		run.mu.Lock()
+		pairs := make([]string, 0, 2)
+		if len(run.pool) != 0 {
+			pairs = append(pairs, "node_pool="+strconv.Quote(run.pool))
+		}
+		if len(run.namespace) != 0 {
+			pairs = append(pairs, "check_namespace="+strconv.Quote(run.namespace))
+		}
+		phases[i] = run.phases
+		run.mu.Unlock()
+		runPairs[i] = pairs
+		if len(pairs) != 0 {
+			labels[i] = "{" + strings.Join(pairs, ",") + "}"
+		}
+	}
+
+	// Emit each metric family with a sample per run that recorded it.
+	var builder strings.Builder
+	for _, metric := range metricHelp {
+		samples := make([]string, 0)
+		for i, values := range snapshots {
+			// Emit a sample per phase, labeled with the phase name.
+			if metric.name == metricPhaseDuration {
+				for _, phase := range phases[i] {
+					pairs := append([]string{"phase=" + strconv.Quote(phase.Phase)}, runPairs[i]...)
+					samples = append(samples, metric.name+"{"+strings.Join(pairs, ",")+"} "+strconv.FormatFloat(phase.Seconds, 'g', -1, 64))
+				}
+				continue
+			}
+			value, found := values[metric.name]
+			if !found {
+				continue
+			}
+			samples = append(samples, metric.name+labels[i]+" "+strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		sort.Strings(samples)
+		fmt.Fprintf(&builder, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name)
+		for _, sample := range samples {
+			builder.WriteString(sample + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// serveMetrics exposes the registry on /metrics at the address until the server is shut down.
+func serveMetrics(address string, reg *metricsRegistry) *http.Server {
+	// Route /metrics to the registry.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write([]byte(reg.render()))
+	})
+	server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: time.Second * 10}
+
+	// Serve in the background, logging anything but a clean shutdown.
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorln("Metrics server failed:", err.Error())
+		}
+	}()
+	log.Infoln("Serving metrics on", address+"/metrics")
+
+	return server
+}
+
+// stopMetrics keeps serving final values for the linger period so they can be scraped, then shuts the server down.
+func stopMetrics(server *http.Server, linger time.Duration) {
+	// Give scrapers a chance to collect the final values.
+	if linger > 0 {
+		log.Infoln("Serving final metrics for", linger, "before exiting.")
+		time.Sleep(linger)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(ctx)
+	if err != nil {
+		log.Warnln("Failed to shut down the metrics server:", err.Error())
+	}
+}
+
+// runAndRecord runs the check and records its duration and outcome.
+func (r *CheckRunner) runAndRecord(ctx context.Context) error {
+	// Time the full run.
+	started := time.Now()
+	err := r.run(ctx)
+	r.metrics.observe(metricRunDuration, time.Since(started))
+	r.phases.finish()
+	r.metrics.observePhases(r.phases.timings())
+	log.Infoln("Phase timings:", r.phases.summary())
+
+	// Record the outcome.
+	success := 1.0
+	if err != nil {
+		success = 0
+	}
+	r.metrics.set(metricSuccess, success)
+
+	// Notify the failure webhook alongside the Kuberhealthy report.
+	if err != nil && len(r.cfg.FailureWebhookURL) != 0 {
+		r.notifyFailureWebhook(err)
+	}
+
+	return err
+}
+
+// pushMetrics replaces this check's metric group on a Pushgateway with the final values.
+func pushMetrics(ctx context.Context, gatewayURL string, job string, namespace string, reg *metricsRegistry) error {
+	// Group by job and check namespace so several checks can share a Pushgateway.
+	target := strings.TrimRight(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job) + "/namespace/" + url.PathEscape(namespace)
+	pushCtx, cancel := context.WithTimeout(ctx, metricsPushTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(pushCtx, http.MethodPut, target, strings.NewReader(reg.render()))
+	if err != nil {
+		return fmt.Errorf("failed to build Pushgateway request: %w", err)
+	}
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	// Push and require a success status.
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", target, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("failed to push metrics to %s: unexpected status %d", target, response.StatusCode)
+	}
+	log.Infoln("Pushed metrics to", target)
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMetricsRender validates the exposition output, including node pool labels and skipped metrics.
+func TestMetricsRender(t *testing.T) {
+	// Record a passing pool run and a failing one.
+	reg := &metricsRegistry{}
+	passing := newRunMetrics()
+	passing.observe(metricDeploymentCreateDuration, time.Millisecond*1500)
+	passing.set(metricSuccess, 1)
+	passing.observePhases([]phaseTiming{{Phase: "deployment_create", Seconds: 14.2}, {Phase: "cleanup", Seconds: 4.1}})
+	reg.track(passing, "general")
+	failing := newRunMetrics()
+	failing.set(metricSuccess, 0)
+	reg.track(failing, "spot")
+
+	output := reg.render()
+	expected := []string{
+		"# TYPE deployment_check_deployment_create_duration_seconds gauge",
+		`deployment_check_deployment_create_duration_seconds{node_pool="general"} 1.5`,
+		`deployment_check_success{node_pool="general"} 1`,
+		`deployment_check_success{node_pool="spot"} 0`,
+		"# TYPE deployment_check_phase_duration_seconds gauge",
+		`deployment_check_phase_duration_seconds{phase="cleanup",node_pool="general"} 4.1`,
+		`deployment_check_phase_duration_seconds{phase="deployment_create",node_pool="general"} 14.2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line+"\n") {
+			t.Fatalf("expected line %q in output:\n%s", line, output)
+		}
+	}
+
+	// Metrics nobody recorded are left out entirely.
+	if strings.Contains(output, metricRolloutDuration) {
+		t.Fatalf("expected no rollout metric in output:\n%s", output)
+	}
+
+	// Runs outside per-pool mode have no labels.
+	reg = &metricsRegistry{}
+	single := newRunMetrics()
+	single.set(metricSuccess, 1)
+	reg.track(single, "")
+	if !strings.Contains(reg.render(), "deployment_check_success 1\n") {
+		t.Fatalf("expected an unlabeled sample but got:\n%s", reg.render())
+	}
+
+	// Runs in several namespaces are labeled with their namespace.
+	reg = &metricsRegistry{}
+	tenant := newRunMetrics()
+	tenant.set(metricSuccess, 0)
+	reg.trackNamespace(tenant, "tenant-a")
+	if !strings.Contains(reg.render(), `deployment_check_success{check_namespace="tenant-a"} 0`+"\n") {
+		t.Fatalf("expected a namespace labeled sample but got:\n%s", reg.render())
+	}
+}
+
+// TestPushMetrics validates the push replaces the job and namespace group with the rendered metrics.
+func TestPushMetrics(t *testing.T) {
+	// Capture the push on a fake Pushgateway.
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		method = request.Method
+		path = request.URL.Path
+		payload, _ := io.ReadAll(request.Body)
+		body = string(payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Push a single successful run.
+	reg := &metricsRegistry{}
+	run := newRunMetrics()
+	run.set(metricSuccess, 1)
+	reg.track(run, "")
+	err := pushMetrics(context.Background(), server.URL+"/", "deployment-check", "kuberhealthy", reg)
+	if err != nil {
+		t.Fatalf("unexpected error pushing metrics: %v", err)
+	}
+
+	if method != http.MethodPut || path != "/metrics/job/deployment-check/namespace/kuberhealthy" {
+		t.Fatalf("expected PUT to the job and namespace group but got %s %s", method, path)
+	}
+	if !strings.Contains(body, "deployment_check_success 1\n") {
+		t.Fatalf("expected rendered metrics in the push body but got:\n%s", body)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// namespaceOwnerLabelKey records which check deployment an ephemeral namespace was created for.
+	namespaceOwnerLabelKey = "deployment-check"
+	// namespaceReadyTimeout bounds the wait for the default service account in a new namespace.
+	namespaceReadyTimeout = time.Minute
+)
+
+// ephemeralNamespaceName returns the namespace name for a run started at now.
+func ephemeralNamespaceName(deploymentName string, now time.Time) string {
+	return deploymentName + "-" + strconv.FormatInt(now.Unix(), 10)
+}
+
+// forNamespace returns a copy of the config that runs the check in another namespace.
+func (cfg *CheckConfig) forNamespace(namespace string) *CheckConfig {
+	// Copy the config so the caller's namespace is left alone.
+	namespaceCfg := *cfg
+	namespaceCfg.CheckNamespace = namespace
+
+	return &namespaceCfg
+}
+
+// namespaceSelector matches the ephemeral namespaces created for this check.
+func (r *CheckRunner) namespaceSelector() string {
+	return sourceLabelKey + "=kuberhealthy," + namespaceOwnerLabelKey + "=" + r.cfg.CheckDeploymentName
+}
+
+// namespaceLabels returns the labels for the run's ephemeral namespace.
+func (r *CheckRunner) namespaceLabels() map[string]string {
+	// Layer the namespace labels over the resource labels, then the labels the check manages.
+	labels := r.cfg.resourceLabels()
+	for key, value := range r.cfg.NamespaceLabels {
+		labels[key] = value
+	}
+	labels[sourceLabelKey] = "kuberhealthy"
+	labels[namespaceOwnerLabelKey] = r.cfg.CheckDeploymentName
+	labels[deploymentLabelKey] = deploymentLabelValueBase + strconv.FormatInt(r.now.Unix(), 10)
+
+	return labels
+}
+
+// createEphemeralNamespace creates the run's namespace and moves the runner into it.
+func (r *CheckRunner) createEphemeralNamespace(ctx context.Context) error {
+	// Reclaim namespaces left behind by runs that never cleaned up.
+	r.reclaimCheckNamespaces(ctx)
+
+	// Create the namespace for this run.
+	name := ephemeralNamespaceName(r.cfg.CheckDeploymentName, r.now)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      r.namespaceLabels(),
+			Annotations: r.resourceAnnotations(nil),
+		},
+	}
+	_, err := r.client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+	log.Infoln("Created namespace", name, "for this run.")
+	r.timeline.recordf("created namespace %s", name)
+
+	// Pods cannot be admitted until the service account controller fills in the namespace.
+	err = r.waitForServiceAccount(ctx, name)
+	if err != nil {
+		deleteErr := r.deleteNamespace(ctx, name)
+		if deleteErr != nil {
+			log.Warnln("Failed to delete namespace", name+":", deleteErr.Error())
+		}
+		return err
+	}
+
+	// Run everything else in the new namespace.
+	r.ephemeralNamespace = name
+	r.cfg = r.cfg.forNamespace(name)
+	return nil
+}
+
+// waitForServiceAccount waits until the check's service account exists in the namespace.
+func (r *CheckRunner) waitForServiceAccount(ctx context.Context, namespace string) error {
+	// Poll for the service account until the timeout.
+	deadline := time.After(namespaceReadyTimeout)
+	for {
+		_, err := r.client.CoreV1().ServiceAccounts(namespace).Get(ctx, r.cfg.CheckServiceAccount, metav1.GetOptions{})
+		if err == nil {
+			return nil
+		}
+		if !k8serrors.IsNotFound(err) {
+			log.Debugln("Failed to get service account", r.cfg.CheckServiceAccount, "in namespace", namespace+":", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context done while waiting for service account %s in namespace %s: %w", r.cfg.CheckServiceAccount, namespace, ctx.Err())
+		case <-deadline:
+			return fmt.Errorf("service account %s was not created in namespace %s within %s", r.cfg.CheckServiceAccount, namespace, namespaceReadyTimeout)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// listCheckNamespaces returns the names of the ephemeral namespaces created for this check.
+func (r *CheckRunner) listCheckNamespaces(ctx context.Context) ([]string, error) {
+	// Select on the labels every ephemeral namespace carries.
+	namespaceList, err := r.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: r.namespaceSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check namespaces: %w", err)
+	}
+	names := make([]string, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		names = append(names, namespace.Name)
+	}
+
+	return names, nil
+}
+
+// reclaimCheckNamespaces deletes ephemeral namespaces from earlier runs without waiting for them to go away.
+func (r *CheckRunner) reclaimCheckNamespaces(ctx context.Context) {
+	// Find leftovers by label.
+	names, err := r.listCheckNamespaces(ctx)
+	if err != nil {
+		log.Warnln("Failed to look for namespaces from earlier runs:", err.Error())
+		return
+	}
+
+	// Issue the deletes and let the namespace controller finish them in the background.
+	for _, name := range names {
+		err = r.deleteNamespace(ctx, name)
+		if err != nil {
+			log.Warnln("Failed to delete namespace", name, "from an earlier run:", err.Error())
+			continue
+		}
+		log.Infoln("Deleting namespace", name, "left behind by an earlier run.")
+		r.timeline.recordf("deleted namespace %s from an earlier run", name)
+	}
+}
+
+// deleteNamespace deletes a namespace, tolerating one that is already gone.
+func (r *CheckRunner) deleteNamespace(ctx context.Context, name string) error {
+	// Delete in the background so the namespace controller removes its contents.
+	background := metav1.DeletePropagationBackground
+	err := r.client.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &background})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete namespace %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// deleteNamespaceAndWait deletes a namespace and waits until it and everything in it are gone.
+func (r *CheckRunner) deleteNamespaceAndWait(ctx context.Context, name string) error {
+	err := deleteAndWait(ctx, "namespace "+name, func(ctx context.Context) error {
+		return r.deleteNamespace(ctx, name)
+	}, func(ctx context.Context) (bool, error) {
+		return r.namespaceExists(ctx, name)
+	})
+	if err != nil {
+		return err
+	}
+	log.Infoln("Namespace", name, "is gone.")
+
+	return nil
+}
+
+// namespaceExists reports whether a namespace is present, including while it terminates.
+func (r *CheckRunner) namespaceExists(ctx context.Context, name string) (bool, error) {
+	_, err := r.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	return objectFound(err)
+}
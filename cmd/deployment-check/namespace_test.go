@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNamespaceLabels validates the ephemeral namespace carries the configured labels under the ones the check manages.
+func TestNamespaceLabels(t *testing.T) {
+	// Configure a Pod Security level and a label that tries to override a managed one.
+	runner := buildTestRunner()
+	runner.now = time.Unix(1700000000, 0)
+	runner.cfg.ExtraLabels = map[string]string{"team": "platform"}
+	runner.cfg.NamespaceLabels = map[string]string{"pod-security.kubernetes.io/enforce": "restricted", "team": "sre"}
+
+	labels := runner.namespaceLabels()
+	if labels["pod-security.kubernetes.io/enforce"] != "restricted" || labels["team"] != "sre" {
+		t.Fatalf("expected the namespace labels to be applied but got %v", labels)
+	}
+	if labels[sourceLabelKey] != "kuberhealthy" || labels[namespaceOwnerLabelKey] != runner.cfg.CheckDeploymentName || labels[deploymentLabelKey] != "unix-1700000000" {
+		t.Fatalf("expected the managed labels to be set but got %v", labels)
+	}
+
+	// The namespace is named after the deployment and run, and the selector finds it.
+	name := ephemeralNamespaceName(runner.cfg.CheckDeploymentName, runner.now)
+	if name != runner.cfg.CheckDeploymentName+"-1700000000" {
+		t.Fatalf("unexpected namespace name %q", name)
+	}
+	if runner.namespaceSelector() != "source=kuberhealthy,deployment-check="+runner.cfg.CheckDeploymentName {
+		t.Fatalf("unexpected namespace selector %q", runner.namespaceSelector())
+	}
+}
+
+// TestForNamespace validates the namespace copy leaves the original config alone.
+func TestForNamespace(t *testing.T) {
+	// Move a copy of the config to another namespace.
+	runner := buildTestRunner()
+	original := runner.cfg.CheckNamespace
+	moved := runner.cfg.forNamespace("deployment-deployment-1700000000")
+	if moved.CheckNamespace != "deployment-deployment-1700000000" || runner.cfg.CheckNamespace != original {
+		t.Fatalf("expected only the copy to move but got %q and %q", moved.CheckNamespace, runner.cfg.CheckNamespace)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// runNamespaces runs the full check once in each configured namespace and returns a failure report, or nil when every namespace passed.
+func runNamespaces(ctx context.Context, cfg *CheckConfig, client kubernetes.Interface, restConfig *rest.Config, reg *metricsRegistry, active *activeRunners) ([]string, []runResult) {
+	// Keep results in configuration order even when runs finish out of order.
+	namespaces := cfg.CheckNamespaces
+	results := make([]runResult, len(namespaces))
+	reports := make([][]string, len(namespaces))
+	log.Infoln("Running the check in", len(namespaces), "namespace(s):", strings.Join(namespaces, ", "))
+
+	// Run the deploy, verify, and cleanup cycle in each namespace.
+	runNamespace := func(i int, remainingRuns int) {
+		namespace := namespaces[i]
+		namespaceCfg := cfg.forNamespace(namespace)
+		namespaceCfg.CheckTimeLimit = nodePoolBudget(ctx, cfg.CheckTimeLimit, remainingRuns)
+		namespaceCtx, cancel := context.WithTimeout(ctx, namespaceCfg.CheckTimeLimit)
+		defer cancel()
+		runner := newCheckRunner(namespaceCfg, client, restConfig, time.Now())
+		active.add(runner)
+		reg.trackNamespace(runner.metrics, namespace)
+		log.Infoln("Starting check in namespace", namespace, "with a budget of", namespaceCfg.CheckTimeLimit.Round(time.Second).String()+".")
+		runErr := runner.runAndRecord(namespaceCtx)
+		results[i] = runner.runResult(runErr, "")
+		if runErr == nil {
+			log.Infoln("Check passed in namespace", namespace+".")
+			return
+		}
+		log.Errorln("Check failed in namespace", namespace+":", runErr.Error())
+		reports[i] = runner.failureReport(runErr)
+	}
+	// Parallel runs share the remaining time, while sequential runs split it over the namespaces still to run.
+	if cfg.NamespacesParallel {
+		var wg sync.WaitGroup
+		for i := range namespaces {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				runNamespace(i, 1)
+			}()
+		}
+		wg.Wait()
+	}
+	if !cfg.NamespacesParallel {
+		for i := range namespaces {
+			runNamespace(i, len(namespaces)-i)
+		}
+	}
+
+	// Collect the failed namespaces and their reports.
+	failedNamespaces := make([]string, 0)
+	details := make([]string, 0)
+	for i, namespace := range namespaces {
+		if reports[i] == nil {
+			continue
+		}
+		failedNamespaces = append(failedNamespaces, namespace)
+		for _, line := range reports[i] {
+			details = append(details, "namespace "+namespace+": "+line)
+		}
+	}
+	if len(failedNamespaces) == 0 {
+		return nil, results
+	}
+
+	// Lead with the failed namespaces so the headline names them.
+	headline := fmt.Sprintf("%d of %d namespace(s) failed: %s", len(failedNamespaces), len(namespaces), strings.Join(failedNamespaces, ", "))
+	return append([]string{headline}, details...), results
+}
+
+// cleanupNamespaces removes leftover check resources from each configured namespace.
+func cleanupNamespaces(ctx context.Context, cfg *CheckConfig, client kubernetes.Interface, restConfig *rest.Config) error {
+	// Clean every namespace, collecting failures.
+	failures := make([]string, 0)
+	for _, namespace := range cfg.CheckNamespaces {
+		err := newCheckRunner(cfg.forNamespace(namespace), client, restConfig, time.Now()).cleanupOnly(ctx)
+		if err != nil {
+			failures = append(failures, "namespace "+namespace+": "+err.Error())
+		}
+	}
+	if len(failures) != 0 {
+		return fmt.Errorf("%s", strings.Join(failures, " | "))
+	}
+
+	return nil
+}
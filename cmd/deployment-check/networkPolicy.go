@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// networkPolicyDenySuffix names the deny-all policy after the deployment.
+	networkPolicyDenySuffix = "-deny-all"
+	// networkPolicyAllowSuffix names the allow policy after the deployment.
+	networkPolicyAllowSuffix = "-allow-check"
+	// networkPolicyPollInterval is the pause between enforcement probes.
+	networkPolicyPollInterval = time.Second * 2
+)
+
+// networkPolicyNames returns the names of the policies created by the check.
+func (r *CheckRunner) networkPolicyNames() []string {
+	return []string{r.cfg.CheckDeploymentName + networkPolicyDenySuffix, r.cfg.CheckDeploymentName + networkPolicyAllowSuffix}
+}
+
+// createDenyPolicyConfig builds a policy that denies all ingress to the check pods.
+func (r *CheckRunner) createDenyPolicyConfig(labels map[string]string) *networkingv1.NetworkPolicy {
+	// Select the check pods and allow no ingress.
+	policy := &networkingv1.NetworkPolicy{
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+	policy.Name = r.cfg.CheckDeploymentName + networkPolicyDenySuffix
+	policy.Namespace = r.cfg.CheckNamespace
+	policy.Labels = copyStringMap(r.cfg.ExtraLabels)
+	policy.Annotations = r.resourceAnnotations(nil)
+
+	return policy
+}
+
+// createAllowPolicyConfig builds a policy that lets the checker namespace reach the check pods on the container ports.
+func (r *CheckRunner) createAllowPolicyConfig(labels map[string]string, checkerNamespace string) *networkingv1.NetworkPolicy {
+	// Open only the container ports.
+	protocol := corev1.ProtocolTCP
+	ports := make([]networkingv1.NetworkPolicyPort, 0)
+	for _, port := range r.cfg.checkPorts() {
+		containerPort := intstr.FromInt32(port.ContainerPort)
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &containerPort})
+	}
+
+	// Allow traffic from the namespace the checker runs in.
+	policy := &networkingv1.NetworkPolicy{
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{corev1.LabelMetadataName: checkerNamespace},
+					},
+				}},
+				Ports: ports,
+			}},
+		},
+	}
+	policy.Name = r.cfg.CheckDeploymentName + networkPolicyAllowSuffix
+	policy.Namespace = r.cfg.CheckNamespace
+	policy.Labels = copyStringMap(r.cfg.ExtraLabels)
+	policy.Annotations = r.resourceAnnotations(nil)
+
+	return policy
+}
+
+// verifyNetworkPolicy checks that a deny-all policy blocks the service and an allow rule restores it.
+func (r *CheckRunner) verifyNetworkPolicy(ctx context.Context, labels map[string]string, serviceIP string) error {
+	// Deny all ingress to the check pods and wait for traffic to stop.
+	policies := r.client.NetworkingV1().NetworkPolicies(r.cfg.CheckNamespace)
+	_, err := policies.Create(ctx, r.createDenyPolicyConfig(labels), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create deny-all network policy: %w", err)
+	}
+	r.timeline.record("created deny-all network policy")
+	err = r.waitForPolicyEnforcement(ctx, serviceIP, false)
+	if err != nil {
+		return err
+	}
+	r.timeline.record("deny-all network policy blocked the service")
+
+	// Allow the checker namespace and wait for traffic to return.
+	_, err = policies.Create(ctx, r.createAllowPolicyConfig(labels, checkerNamespace(r.cfg.CheckNamespace)), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create allow network policy: %w", err)
+	}
+	r.timeline.record("created allow network policy")
+	err = r.waitForPolicyEnforcement(ctx, serviceIP, true)
+	if err != nil {
+		return err
+	}
+	r.timeline.record("allow network policy restored the service")
+
+	// Remove the policies so later stages see unrestricted traffic.
+	err = r.deleteNetworkPoliciesAndWait(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Network policy enforcement verified.")
+
+	return nil
+}
+
+// waitForPolicyEnforcement probes every service port until all are reachable or all are blocked, as expected.
+func (r *CheckRunner) waitForPolicyEnforcement(ctx context.Context, serviceIP string, reachable bool) error {
+	// Describe the expected state for logs and errors.
+	expectation := "blocked by the deny-all policy"
+	if reachable {
+		expectation = "allowed by the allow policy"
+	}
+
+	// Poll until the expectation holds or the window runs out, keeping the last observation for the report.
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.transport}
+	deadline := time.Now().Add(r.cfg.NetworkPolicyTimeout)
+	for {
+		mismatched := make([]string, 0)
+		for _, port := range r.cfg.checkPorts() {
+			address := net.JoinHostPort(serviceIP, strconv.Itoa(int(port.ServicePort)))
+			err := r.requestPodOnce(ctx, client, address)
+			if reachable && err != nil {
+				mismatched = append(mismatched, address+" failed: "+err.Error())
+			}
+			if !reachable && err == nil {
+				mismatched = append(mismatched, address+" still responds")
+			}
+		}
+		if len(mismatched) == 0 {
+			log.Infoln("Service traffic is", expectation+".")
+			return nil
+		}
+		log.Debugln("Service traffic not yet", expectation+":", strings.Join(mismatched, "; "))
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service traffic was not %s within %s: %s", expectation, r.cfg.NetworkPolicyTimeout, strings.Join(mismatched, "; "))
+		}
+
+		// Wait before probing again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for service traffic to be %s", expectation)
+		case <-time.After(networkPolicyPollInterval):
+		}
+	}
+}
+
+// deleteNetworkPoliciesAndWait removes the check network policies and waits for them to disappear.
+func (r *CheckRunner) deleteNetworkPoliciesAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "network policies", func(ctx context.Context) error {
+		// Issue every delete, tolerating policies that were never created.
+		policies := r.client.NetworkingV1().NetworkPolicies(r.cfg.CheckNamespace)
+		for _, name := range r.networkPolicyNames() {
+			err := policies.Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("network policy %s: %w", name, err)
+			}
+		}
+		return nil
+	}, r.networkPoliciesExist)
+}
+
+// networkPoliciesExist reports whether any check network policy is present.
+func (r *CheckRunner) networkPoliciesExist(ctx context.Context) (bool, error) {
+	// Look up each policy by name.
+	for _, name := range r.networkPolicyNames() {
+		_, err := r.client.NetworkingV1().NetworkPolicies(r.cfg.CheckNamespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// checkerNamespace returns the namespace of the checker pod, falling back to the check namespace.
+func checkerNamespace(fallback string) string {
+	// Read the namespace mounted with the service account.
+	namespaceBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil || len(strings.TrimSpace(string(namespaceBytes))) == 0 {
+		return fallback
+	}
+
+	return strings.TrimSpace(string(namespaceBytes))
+}
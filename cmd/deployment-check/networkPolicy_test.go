@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestNetworkPolicyConfigs validates the deny-all and allow policies select the check pods.
+func TestNetworkPolicyConfigs(t *testing.T) {
+	// Build a runner with an additional port.
+	runner := buildTestRunner()
+	runner.cfg.CheckAdditionalPorts = []checkPort{{ContainerPort: 9090, ServicePort: 90}}
+	labels := map[string]string{deploymentLabelKey: "test"}
+
+	// The deny policy selects the pods and allows nothing.
+	deny := runner.createDenyPolicyConfig(labels)
+	if deny.Spec.PodSelector.MatchLabels[deploymentLabelKey] != "test" {
+		t.Fatalf("expected deny policy to select the check pods but got: %v", deny.Spec.PodSelector.MatchLabels)
+	}
+	if len(deny.Spec.Ingress) != 0 || len(deny.Spec.PolicyTypes) != 1 {
+		t.Fatalf("expected deny policy to block all ingress but got: %+v", deny.Spec)
+	}
+
+	// The allow policy admits the checker namespace on every container port.
+	allow := runner.createAllowPolicyConfig(labels, "kuberhealthy")
+	if len(allow.Spec.Ingress) != 1 {
+		t.Fatalf("expected one allow rule but got %d", len(allow.Spec.Ingress))
+	}
+	rule := allow.Spec.Ingress[0]
+	if rule.From[0].NamespaceSelector.MatchLabels[corev1.LabelMetadataName] != "kuberhealthy" {
+		t.Fatalf("expected allow rule for the checker namespace but got: %v", rule.From[0].NamespaceSelector.MatchLabels)
+	}
+	if len(rule.Ports) != 2 || rule.Ports[1].Port.IntVal != 9090 {
+		t.Fatalf("expected allow rule on both container ports but got: %+v", rule.Ports)
+	}
+	if deny.Name == allow.Name {
+		t.Fatalf("expected distinct policy names but both were %s", deny.Name)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// listNodePools returns the distinct values of the pool label across ready, schedulable nodes.
+func listNodePools(ctx context.Context, client kubernetes.Interface, label string) ([]string, error) {
+	// List every node in the cluster.
+	nodeList, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return nodePoolValues(nodeList.Items, label), nil
+}
+
+// nodePoolValues collects the sorted distinct pool label values of ready, schedulable nodes.
+func nodePoolValues(nodes []corev1.Node, label string) []string {
+	// Keep pools that can actually take the check pods.
+	pools := make(map[string]bool)
+	for _, node := range nodes {
+		pool, found := node.Labels[label]
+		if !found || len(pool) == 0 || node.Spec.Unschedulable || !nodeIsReady(&node) {
+			continue
+		}
+		pools[pool] = true
+	}
+
+	return sortedKeys(pools)
+}
+
+// runNodePools runs the full check once per node pool and returns a failure report, or nil when every pool passed.
+func runNodePools(ctx context.Context, cfg *CheckConfig, client kubernetes.Interface, restConfig *rest.Config, reg *metricsRegistry, active *activeRunners) ([]string, []runResult) {
+	// Discover the pools to run against.
+	pools, err := listNodePools(ctx, client, cfg.NodePoolLabel)
+	if err != nil {
+		return []string{"failed to discover node pools: " + err.Error()}, nil
+	}
+	if len(pools) == 0 {
+		return []string{fmt.Sprintf("no ready, schedulable nodes carry the %s label", cfg.NodePoolLabel)}, nil
+	}
+	log.Infoln("Running the check against", len(pools), "node pool(s) labeled", cfg.NodePoolLabel+":", strings.Join(pools, ", "))
+
+	// Run the deploy, verify, and cleanup cycle pinned to each pool in turn.
+	failedPools := make([]string, 0)
+	details := make([]string, 0)
+	results := make([]runResult, 0, len(pools))
+	for i, pool := range pools {
+		poolCfg := cfg.forNodePool(pool)
+		poolCfg.CheckTimeLimit = nodePoolBudget(ctx, cfg.CheckTimeLimit, len(pools)-i)
+		poolCtx, cancel := context.WithTimeout(ctx, poolCfg.CheckTimeLimit)
+		runner := newCheckRunner(poolCfg, client, restConfig, time.Now())
+		active.add(runner)
+		reg.track(runner.metrics, pool)
+		log.Infoln("Starting check for node pool", pool, "with a budget of", poolCfg.CheckTimeLimit.Round(time.Second).String()+".")
+		runErr := runner.runAndRecord(poolCtx)
+		cancel()
+		results = append(results, runner.runResult(runErr, pool))
+		if runErr == nil {
+			log.Infoln("Check passed for node pool", pool+".")
+			continue
+		}
+		log.Errorln("Check failed for node pool", pool+":", runErr.Error())
+		failedPools = append(failedPools, pool)
+		for _, line := range runner.failureReport(runErr) {
+			details = append(details, "node pool "+pool+": "+line)
+		}
+	}
+	if len(failedPools) == 0 {
+		return nil, results
+	}
+
+	// Lead with the failed pools so the headline names them.
+	sort.Strings(failedPools)
+	headline := fmt.Sprintf("%d of %d node pool(s) failed: %s", len(failedPools), len(pools), strings.Join(failedPools, ", "))
+	return append([]string{headline}, details...), results
+}
+
+// nodePoolBudget splits the time left before the check deadline evenly over the pools or namespaces still to run.
+func nodePoolBudget(ctx context.Context, limit time.Duration, remainingRuns int) time.Duration {
+	// Fall back to the configured limit when the context carries no deadline.
+	remaining := limit
+	deadline, ok := ctx.Deadline()
+	if ok {
+		remaining = time.Until(deadline)
+	}
+
+	return remaining / time.Duration(remainingRuns)
+}
+
+// forNodePool returns a copy of the config that pins the check pods to one node pool.
+func (cfg *CheckConfig) forNodePool(pool string) *CheckConfig {
+	// Copy the config and its node selector so pools never share state.
+	poolCfg := *cfg
+	poolCfg.CheckDeploymentNodeSelectors = make(map[string]string, len(cfg.CheckDeploymentNodeSelectors)+1)
+	for key, value := range cfg.CheckDeploymentNodeSelectors {
+		poolCfg.CheckDeploymentNodeSelectors[key] = value
+	}
+	poolCfg.CheckDeploymentNodeSelectors[cfg.NodePoolLabel] = pool
+
+	return &poolCfg
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestNodePoolValues validates pools come only from ready, schedulable nodes.
+func TestNodePoolValues(t *testing.T) {
+	// Build ready nodes in two pools plus a cordoned and an unready node in a third.
+	poolLabel := "cloud.google.com/gke-nodepool"
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+	nodes := make([]corev1.Node, 0)
+	for _, pool := range []string{"general", "highmem", "general", "spot", "spot"} {
+		node := corev1.Node{}
+		node.Labels = map[string]string{poolLabel: pool}
+		node.Status.Conditions = []corev1.NodeCondition{ready}
+		nodes = append(nodes, node)
+	}
+	nodes[3].Spec.Unschedulable = true
+	nodes[4].Status.Conditions = nil
+
+	pools := nodePoolValues(nodes, poolLabel)
+	if strings.Join(pools, ",") != "general,highmem" {
+		t.Fatalf("expected pools general and highmem but got %v", pools)
+	}
+}
+
+// TestForNodePool validates the pool config pins the pool without touching the base config.
+func TestForNodePool(t *testing.T) {
+	// Pin a copy of the config to one pool.
+	runner := buildTestRunner()
+	runner.cfg.NodePoolLabel = "pool"
+	runner.cfg.CheckDeploymentNodeSelectors["disk"] = "ssd"
+	poolCfg := runner.cfg.forNodePool("highmem")
+
+	if poolCfg.CheckDeploymentNodeSelectors["pool"] != "highmem" || poolCfg.CheckDeploymentNodeSelectors["disk"] != "ssd" {
+		t.Fatalf("expected pool and existing selectors but got %v", poolCfg.CheckDeploymentNodeSelectors)
+	}
+
+	_, leaked := runner.cfg.CheckDeploymentNodeSelectors["pool"]
+	if leaked {
+		t.Fatalf("expected the base config selectors to stay unchanged but got %v", runner.cfg.CheckDeploymentNodeSelectors)
+	}
+}
+
+// TestNodePoolBudget validates the time left is shared by the pools still to run.
+func TestNodePoolBudget(t *testing.T) {
+	// Without a deadline the configured limit is split.
+	if budget := nodePoolBudget(context.Background(), time.Minute*15, 3); budget != time.Minute*5 {
+		t.Fatalf("expected a five minute budget but got %s", budget)
+	}
+
+	// With a deadline only the time left is split.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+	defer cancel()
+	budget := nodePoolBudget(ctx, time.Minute*15, 2)
+	if budget > time.Minute*5 || budget < time.Minute*4 {
+		t.Fatalf("expected about a five minute budget but got %s", budget)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodePortRequestTimeout bounds the requests to one node port on one node, so an unreachable node fails fast.
+const nodePortRequestTimeout = time.Second * 20
+
+// validateNodePorts requests every allocated NodePort on a sample of node IPs to validate kube-proxy programming.
+func (r *CheckRunner) validateNodePorts(ctx context.Context) error {
+	// Fetch the service to read the allocated node ports.
+	service, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch service for node ports: %w", err)
+	}
+
+	// Pick the nodes to request.
+	nodeAddresses, err := r.nodePortTargets(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodeAddresses) == 0 {
+		return fmt.Errorf("no ready nodes with an internal IP to request node ports on")
+	}
+
+	// Request each node port on each node so one bad node does not hide another.
+	failures := make([]string, 0)
+	attempts := 0
+	for _, port := range service.Spec.Ports {
+		if port.NodePort == 0 {
+			failures = append(failures, fmt.Sprintf("port %d: no node port allocated", port.Port))
+			continue
+		}
+		for _, nodeName := range sortedNodeNames(nodeAddresses) {
+			attempts++
+			address := net.JoinHostPort(nodeAddresses[nodeName], strconv.Itoa(int(port.NodePort)))
+			nodeCtx, cancel := context.WithTimeout(ctx, nodePortRequestTimeout)
+			err = r.requestAddress(nodeCtx, address)
+			cancel()
+			if err != nil {
+				log.Errorln("Node port", port.NodePort, "on node", nodeName, "failed validation:", err.Error())
+				r.timeline.recordf("node port %d on node %s failed validation: %s", port.NodePort, nodeName, err.Error())
+				failures = append(failures, fmt.Sprintf("node %s port %d: %s", nodeName, port.NodePort, err.Error()))
+				continue
+			}
+			log.Infoln("Node port", port.NodePort, "on node", nodeName, "passed validation.")
+		}
+	}
+
+	// Report every failing node and port together.
+	if len(failures) != 0 {
+		return fmt.Errorf("%d of %d node port request(s) failed validation: %s", len(failures), attempts, strings.Join(failures, " | "))
+	}
+
+	return nil
+}
+
+// nodePortTargets returns internal IPs for up to the configured number of ready nodes, keyed by node name.
+func (r *CheckRunner) nodePortTargets(ctx context.Context) (map[string]string, error) {
+	// List nodes to find candidate addresses.
+	nodeList, err := r.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes for node port requests: %w", err)
+	}
+
+	// Keep ready nodes with an internal IP, in name order for stable results.
+	sort.Slice(nodeList.Items, func(i, j int) bool {
+		return nodeList.Items[i].Name < nodeList.Items[j].Name
+	})
+	targets := make(map[string]string)
+	for i := range nodeList.Items {
+		if len(targets) >= r.cfg.NodePortNodeCount {
+			break
+		}
+		node := &nodeList.Items[i]
+		if nodeReadyTime(node).IsZero() || node.Spec.Unschedulable {
+			continue
+		}
+		address := nodeInternalIP(node)
+		if len(address) == 0 {
+			continue
+		}
+		targets[node.Name] = address
+	}
+
+	return targets, nil
+}
+
+// nodeInternalIP returns the node's internal IP address, if any.
+func nodeInternalIP(node *corev1.Node) string {
+	// Scan the reported addresses.
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address
+		}
+	}
+
+	return ""
+}
+
+// sortedNodeNames returns the node names of an address map in sorted order.
+func sortedNodeNames(addresses map[string]string) []string {
+	// Collect and sort the names.
+	names := make([]string, 0, len(addresses))
+	for name := range addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestValidateNodePorts validates every node port is requested on the sampled nodes and failures are named.
+func TestValidateNodePorts(t *testing.T) {
+	// Expose one healthy and one failing node port on a ready loopback node.
+	runner := buildTestRunner()
+	runner.cfg.NodePortNodeCount = defaultNodePortNodeCount
+	runner.cfg.RequestRetryTimeout = time.Second * 5
+	runner.cfg.RequestMaxAttempts = 1
+	healthyPort := probeTestServer(t, http.StatusOK)
+	failingPort := probeTestServer(t, http.StatusInternalServerError)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckServiceName, Namespace: runner.cfg.CheckNamespace},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Port: 80, NodePort: healthyPort},
+			{Port: 81, NodePort: failingPort},
+		}},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "127.0.0.1"}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Now()}},
+		},
+	}
+	runner.client = fake.NewClientset(service, node)
+
+	// Only the failing port is reported.
+	err := runner.validateNodePorts(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") || !strings.Contains(err.Error(), "node node-a port") {
+		t.Fatalf("expected one failing node port but got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// snapshotNodeNames records the nodes that exist before the deployment is created.
+func (r *CheckRunner) snapshotNodeNames(ctx context.Context) (map[string]bool, error) {
+	// List every node in the cluster.
+	nodeList, err := r.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes before provisioning: %w", err)
+	}
+
+	// Index nodes by name.
+	nodes := make(map[string]bool, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodes[node.Name] = true
+	}
+	log.Infoln("Recorded", len(nodes), "existing node(s) before creating the deployment.")
+
+	return nodes, nil
+}
+
+// provisioningWaitTimeout fires once the node provisioning window passes, or never when no provisioning mode is enabled.
+func (r *CheckRunner) provisioningWaitTimeout() <-chan time.Time {
+	// Use the window for whichever provisioning mode is enabled.
+	if r.cfg.KarpenterMode {
+		return time.After(r.cfg.KarpenterTimeout)
+	}
+	if r.cfg.AutoscalerMode {
+		return time.After(r.cfg.AutoscalerTimeout)
+	}
+
+	// A nil channel blocks forever, leaving the overall deadline in charge.
+	return nil
+}
+
+// verifyProvisioning runs the provisioning verification for the enabled mode.
+func (r *CheckRunner) verifyProvisioning(ctx context.Context, existingNodes map[string]bool, createdAt time.Time) error {
+	// Karpenter mode also verifies the backing NodeClaims.
+	if r.cfg.KarpenterMode {
+		return r.verifyKarpenterProvisioning(ctx, existingNodes, createdAt)
+	}
+
+	return r.verifyNodeProvisioning(ctx, existingNodes, createdAt)
+}
+
+// verifyNodeProvisioning confirms the check pods landed on nodes created after the deployment and reports provisioning latency.
+func (r *CheckRunner) verifyNodeProvisioning(ctx context.Context, existingNodes map[string]bool, createdAt time.Time) error {
+	// Measure how long the pods took to become ready.
+	readyLatency := time.Since(createdAt)
+
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for provisioning verification: %w", err)
+	}
+
+	// Split the nodes hosting check pods into new and pre-existing ones.
+	newNodes := make(map[string]bool)
+	oldNodes := make(map[string]bool)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if existingNodes[pod.Spec.NodeName] {
+			oldNodes[pod.Spec.NodeName] = true
+			continue
+		}
+		newNodes[pod.Spec.NodeName] = true
+	}
+
+	// Fail when no new capacity was provisioned for the pods.
+	if len(newNodes) == 0 {
+		return fmt.Errorf("no new node was provisioned; check pods landed on existing node(s): %s", strings.Join(sortedKeys(oldNodes), ", "))
+	}
+
+	// Report provisioning latency for each new node.
+	for _, nodeName := range sortedKeys(newNodes) {
+		node, getErr := r.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if getErr != nil {
+			log.Warnln("Failed to fetch provisioned node", nodeName+":", getErr.Error())
+			continue
+		}
+		createLatency := node.CreationTimestamp.Sub(createdAt)
+		readyAt := nodeReadyTime(node)
+		if readyAt.IsZero() {
+			log.Infoln("Node", nodeName, "was provisioned", createLatency.Round(time.Second), "after the deployment was created.")
+			r.timeline.recordf("node %s provisioned %s after deployment creation", nodeName, createLatency.Round(time.Second))
+			continue
+		}
+		readyNodeLatency := readyAt.Sub(createdAt)
+		log.Infoln("Node", nodeName, "was provisioned", createLatency.Round(time.Second), "and ready", readyNodeLatency.Round(time.Second), "after the deployment was created.")
+		r.timeline.recordf("node %s provisioned %s and ready %s after deployment creation", nodeName, createLatency.Round(time.Second), readyNodeLatency.Round(time.Second))
+	}
+
+	log.Infoln("Check pods became ready on newly provisioned capacity in", readyLatency.Round(time.Second))
+	r.timeline.recordf("check pods ready on %d new node(s) %s after deployment creation", len(newNodes), readyLatency.Round(time.Second))
+	return nil
+}
+
+// nodeReadyTime returns when the node last became Ready, or the zero time if it is not ready.
+func nodeReadyTime(node *corev1.Node) time.Time {
+	// Scan node conditions for readiness.
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+
+	return time.Time{}
+}
+
+// nodeIsReady reports whether the node has a true Ready condition.
+func nodeIsReady(node *corev1.Node) bool {
+	// Scan node conditions for readiness.
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortedKeys returns the keys of a set in sorted order for stable messages.
+func sortedKeys(set map[string]bool) []string {
+	// Collect and sort the keys.
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// openShiftRouteGroup is the API group served only by OpenShift clusters.
+	openShiftRouteGroup = "route.openshift.io"
+)
+
+var (
+	// openShiftRouteResource identifies OpenShift Routes.
+	openShiftRouteResource = schema.GroupVersionResource{Group: openShiftRouteGroup, Version: "v1", Resource: "routes"}
+)
+
+// createOpenShiftRouteConfig builds a Route exposing the primary service port through the OpenShift router.
+func (r *CheckRunner) createOpenShiftRouteConfig() *unstructured.Unstructured {
+	// Target the primary service port by the name the service gives it.
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{"kind": "Service", "name": r.cfg.CheckServiceName, "weight": int64(100)},
+		"port": map[string]interface{}{
+			"targetPort": "tcp-" + strconv.Itoa(int(r.cfg.CheckLoadBalancerPort)),
+		},
+	}
+	if len(r.cfg.OpenShiftRouteHost) != 0 {
+		spec["host"] = r.cfg.OpenShiftRouteHost
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": openShiftRouteGroup + "/v1",
+		"kind":       "Route",
+		"metadata": map[string]interface{}{
+			"name":      r.cfg.CheckServiceName,
+			"namespace": r.cfg.CheckNamespace,
+		},
+		"spec": spec,
+	}}
+	if len(r.cfg.ExtraLabels) != 0 {
+		route.SetLabels(r.cfg.resourceLabels())
+	}
+	annotations := r.resourceAnnotations(nil)
+	if len(annotations) != 0 {
+		route.SetAnnotations(annotations)
+	}
+
+	return route
+}
+
+// openShiftRoutesAvailable reports whether the cluster serves the OpenShift Route API.
+func (r *CheckRunner) openShiftRoutesAvailable() (bool, error) {
+	// Look for the route group among the served API groups.
+	groups, err := r.client.Discovery().ServerGroups()
+	if err != nil {
+		return false, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	for _, group := range groups.Groups {
+		if group.Name == openShiftRouteGroup {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// verifyOpenShiftRoute creates the Route, waits for a router to admit it, and validates traffic through the router.
+func (r *CheckRunner) verifyOpenShiftRoute(ctx context.Context) error {
+	// Skip clusters that are not OpenShift rather than failing them.
+	available, err := r.openShiftRoutesAvailable()
+	if err != nil {
+		return err
+	}
+	if !available {
+		log.Infoln("The", openShiftRouteGroup, "API group is not served; skipping OpenShift Route validation.")
+		r.timeline.recordf("skipped openshift route validation: %s not served", openShiftRouteGroup)
+		return nil
+	}
+
+	// Create the route through the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(openShiftRouteResource).Namespace(r.cfg.CheckNamespace).Create(ctx, r.createOpenShiftRouteConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create OpenShift Route: %w", err)
+	}
+	log.Infoln("Created OpenShift Route", r.cfg.CheckServiceName, "in", r.cfg.CheckNamespace, "namespace.")
+	r.timeline.recordf("created openshift route %s", r.cfg.CheckServiceName)
+
+	// Wait for a router to admit the route and learn the host it serves.
+	host, err := r.waitForRouteAdmission(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Request the route host directly, or through the configured router address.
+	address := host
+	if len(r.cfg.OpenShiftRouteAddress) != 0 {
+		address = r.cfg.OpenShiftRouteAddress
+	}
+	r.timeline.recordf("requesting openshift route host %s through %s", host, address)
+	err = r.requestEndpoint(ctx, "http://"+address+"/", host)
+	if err != nil {
+		return fmt.Errorf("request through the OpenShift router for host %s failed: %w", host, err)
+	}
+
+	return nil
+}
+
+// waitForRouteAdmission polls the Route until a router admits it and returns the admitted host.
+func (r *CheckRunner) waitForRouteAdmission(ctx context.Context) (string, error) {
+	// Poll the route status until a router admits it or the wait runs out.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(r.cfg.OpenShiftRouteTimeout)
+	lastStatus := "no router reported"
+	for {
+		route, err := client.Resource(openShiftRouteResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+		if err != nil {
+			log.Debugln("Failed to fetch OpenShift Route:", err.Error())
+		}
+		if err == nil {
+			host, status, admitted := routeAdmission(route)
+			if len(status) != 0 {
+				lastStatus = status
+				r.timeline.observe("openshift-route-admission", lastStatus, "openshift route admission: "+lastStatus)
+			}
+			if admitted {
+				log.Infoln("OpenShift Route admitted with host", host)
+				return host, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("OpenShift Route was not admitted by a router within %s; last status: %s", r.cfg.OpenShiftRouteTimeout, lastStatus)
+		}
+
+		// Wait before polling again.
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("context expired while waiting for OpenShift Route admission; last status: %s", lastStatus)
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// routeAdmission returns the first admitted host, a description of every router's verdict, and whether any router admitted the route.
+func routeAdmission(route *unstructured.Unstructured) (string, string, bool) {
+	// Walk the per-router ingress entries in the route status.
+	ingresses, _, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+	host := ""
+	status := ""
+	for _, ingress := range ingresses {
+		ingressMap, ok := ingress.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		routerName, _, _ := unstructured.NestedString(ingressMap, "routerName")
+		routerHost, _, _ := unstructured.NestedString(ingressMap, "host")
+
+		// Record the Admitted condition, keeping the reason when the router refused the route.
+		conditions, _, _ := unstructured.NestedSlice(ingressMap, "conditions")
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _, _ := unstructured.NestedString(conditionMap, "type")
+			if conditionType != "Admitted" {
+				continue
+			}
+			conditionStatus, _, _ := unstructured.NestedString(conditionMap, "status")
+			if len(status) != 0 {
+				status = status + " "
+			}
+			status = status + routerName + "=" + conditionStatus
+			if conditionStatus != "True" {
+				reason, _, _ := unstructured.NestedString(conditionMap, "reason")
+				status = status + "(" + reason + ")"
+				continue
+			}
+			if len(host) == 0 {
+				host = routerHost
+			}
+		}
+	}
+
+	return host, status, len(host) != 0
+}
+
+// deleteOpenShiftRouteAndWait deletes the Route and waits for it to disappear.
+func (r *CheckRunner) deleteOpenShiftRouteAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "OpenShift Route", func(ctx context.Context) error {
+		client, err := r.dynamicClient()
+		if err != nil {
+			return err
+		}
+		return client.Resource(openShiftRouteResource).Namespace(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckServiceName, metav1.DeleteOptions{})
+	}, r.openShiftRouteExists)
+}
+
+// openShiftRouteExists reports whether the check Route is present.
+func (r *CheckRunner) openShiftRouteExists(ctx context.Context) (bool, error) {
+	// Look up the route by name; an API the cluster does not serve is NotFound, so no route.
+	client, err := r.dynamicClient()
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Resource(openShiftRouteResource).Namespace(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+	return objectFound(err)
+}
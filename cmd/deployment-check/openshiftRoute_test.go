@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestRouteAdmission validates the admitted host is taken from a router that admitted the route.
+func TestRouteAdmission(t *testing.T) {
+	// Build a route refused by one router and admitted by another.
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"ingress": []interface{}{
+				map[string]interface{}{
+					"routerName": "sharded",
+					"host":       "check.sharded.example.com",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Admitted", "status": "False", "reason": "HostAlreadyClaimed"},
+					},
+				},
+				map[string]interface{}{
+					"routerName": "default",
+					"host":       "check.apps.example.com",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Admitted", "status": "True"},
+					},
+				},
+			},
+		},
+	}}
+
+	host, status, admitted := routeAdmission(route)
+	if !admitted || host != "check.apps.example.com" {
+		t.Fatalf("expected the route admitted with the default router host but got %v %q", admitted, host)
+	}
+
+	if status != "sharded=False(HostAlreadyClaimed) default=True" {
+		t.Fatalf("unexpected admission status %q", status)
+	}
+
+	// Report a route no router has admitted yet.
+	_, _, admitted = routeAdmission(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	if admitted {
+		t.Fatalf("expected a route without status to not be admitted")
+	}
+}
+
+// TestOpenShiftRouteConfig validates the Route targets the primary service port by name.
+func TestOpenShiftRouteConfig(t *testing.T) {
+	// Build the route with a custom host.
+	runner := buildTestRunner()
+	runner.cfg.OpenShiftRouteHost = "check.apps.example.com"
+	route := runner.createOpenShiftRouteConfig()
+	targetPort, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort")
+	if targetPort != "tcp-80" {
+		t.Fatalf("expected the route to target port tcp-80 but got %q", targetPort)
+	}
+
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	if host != runner.cfg.OpenShiftRouteHost {
+		t.Fatalf("expected route host %q but got %q", runner.cfg.OpenShiftRouteHost, host)
+	}
+}
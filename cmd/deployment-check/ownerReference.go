@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkerPodName returns the checker pod name from the downward API, falling back to the hostname.
+func checkerPodName() (string, error) {
+	// Prefer an explicit name since a pod hostname can be overridden.
+	podName := os.Getenv("POD_NAME")
+	if len(podName) != 0 {
+		return podName, nil
+	}
+
+	return os.Hostname()
+}
+
+// resolveCheckerOwner looks up the checker pod so created resources can be garbage collected with it.
+func (r *CheckRunner) resolveCheckerOwner(ctx context.Context) error {
+	// Owner references cannot cross namespaces; the garbage collector would delete the dependents at once.
+	namespace := checkerNamespace("")
+	if namespace != r.cfg.CheckNamespace {
+		return fmt.Errorf("checker pod namespace %q differs from the check namespace %q", namespace, r.cfg.CheckNamespace)
+	}
+
+	// Fetch the checker pod for its UID.
+	podName, err := checkerPodName()
+	if err != nil {
+		return fmt.Errorf("failed to determine the checker pod name: %w", err)
+	}
+	pod, err := r.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch checker pod %s: %w", podName, err)
+	}
+
+	r.owner = checkerOwnerReference(pod)
+	log.Infoln("Check resources will be owned by checker pod", pod.Name+".")
+	return nil
+}
+
+// checkerOwnerReference builds a non-blocking owner reference to the checker pod.
+func checkerOwnerReference(pod *corev1.Pod) *metav1.OwnerReference {
+	return &metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}
+}
+
+// ownerReferences returns the owner references for created resources, or nil when none was resolved.
+func (r *CheckRunner) ownerReferences() []metav1.OwnerReference {
+	// Leave resources unowned unless the checker pod was resolved.
+	if r.owner == nil {
+		return nil
+	}
+
+	return []metav1.OwnerReference{*r.owner}
+}
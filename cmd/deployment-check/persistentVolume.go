@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// pvcVolumeName names the claim volume in the check pods.
+	pvcVolumeName = "check-data"
+	// pvcPollInterval is the pause between claim binding observations.
+	pvcPollInterval = time.Second * 2
+)
+
+// checkPVCName returns the name of the claim created for the check pods.
+func (cfg *CheckConfig) checkPVCName() string {
+	// Derive the name from the deployment so both are cleaned up together.
+	return cfg.CheckDeploymentName + "-data"
+}
+
+// createPVCConfig builds the claim the check pods mount, provisioned from the configured StorageClass.
+func (r *CheckRunner) createPVCConfig() *corev1.PersistentVolumeClaim {
+	// Request the configured size and access mode from the StorageClass.
+	storageClass := r.cfg.PVCStorageClass
+	pvc := &corev1.PersistentVolumeClaim{
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{r.cfg.PVCAccessMode},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: r.cfg.PVCSize.DeepCopy()},
+			},
+		},
+	}
+	pvc.Name = r.cfg.checkPVCName()
+	pvc.Namespace = r.cfg.CheckNamespace
+	pvc.Labels = copyStringMap(r.cfg.ExtraLabels)
+	pvc.Annotations = r.resourceAnnotations(nil)
+	pvc.OwnerReferences = r.ownerReferences()
+
+	return pvc
+}
+
+// createPVC creates the claim the check pods mount.
+func (r *CheckRunner) createPVC(ctx context.Context) error {
+	// Create the claim before the deployment so the pods never reference a missing claim.
+	_, err := r.client.CoreV1().PersistentVolumeClaims(r.cfg.CheckNamespace).Create(ctx, r.createPVCConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create persistent volume claim: %w", err)
+	}
+	log.Infoln("Created persistent volume claim", r.cfg.checkPVCName(), "using storage class", r.cfg.PVCStorageClass+".")
+	r.timeline.recordf("created persistent volume claim %s using storage class %s", r.cfg.checkPVCName(), r.cfg.PVCStorageClass)
+
+	return nil
+}
+
+// verifyPVCBound waits for the claim to bind to a provisioned volume now that a pod consumes it.
+func (r *CheckRunner) verifyPVCBound(ctx context.Context, created time.Time) error {
+	// Poll the claim, since WaitForFirstConsumer classes only bind once a pod is scheduled.
+	var pvc *corev1.PersistentVolumeClaim
+	for {
+		current, err := r.client.CoreV1().PersistentVolumeClaims(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkPVCName(), metav1.GetOptions{})
+		if err == nil && current.Status.Phase == corev1.ClaimBound {
+			pvc = current
+			break
+		}
+		if err == nil {
+			pvc = current
+		}
+		if err != nil {
+			log.Debugln("Failed to fetch persistent volume claim:", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			phase := "unknown"
+			if pvc != nil {
+				phase = string(pvc.Status.Phase)
+			}
+			return fmt.Errorf("persistent volume claim %s did not bind using storage class %s (phase %s)", r.cfg.checkPVCName(), r.cfg.PVCStorageClass, phase)
+		case <-time.After(pvcPollInterval):
+		}
+	}
+
+	// Record the provisioned volume and how long provisioning took.
+	bound := time.Since(created).Round(time.Second)
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	log.Infoln("Persistent volume claim", pvc.Name, "bound to volume", pvc.Spec.VolumeName, "with", capacity.String(), "after", bound)
+	r.timeline.recordf("persistent volume claim %s bound to volume %s (%s) within %s", pvc.Name, pvc.Spec.VolumeName, capacity.String(), bound)
+
+	return nil
+}
+
+// deletePVCAndWait removes the claim and waits for it to disappear once no pod uses it.
+func (r *CheckRunner) deletePVCAndWait(ctx context.Context) error {
+	// The claim's protection finalizer holds it while pods still mount it.
+	return deleteAndWait(ctx, "persistent volume claim", func(ctx context.Context) error {
+		return r.client.CoreV1().PersistentVolumeClaims(r.cfg.CheckNamespace).Delete(ctx, r.cfg.checkPVCName(), metav1.DeleteOptions{})
+	}, r.pvcExists)
+}
+
+// pvcExists reports whether the check claim is present.
+func (r *CheckRunner) pvcExists(ctx context.Context) (bool, error) {
+	_, err := r.client.CoreV1().PersistentVolumeClaims(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkPVCName(), metav1.GetOptions{})
+	return objectFound(err)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TestPVCConfig validates the claim requests the configured class and size and is mounted in the check container.
+func TestPVCConfig(t *testing.T) {
+	// Configure a claim from a fast storage class.
+	runner := buildTestRunner()
+	runner.cfg.PVCStorageClass = "fast-ssd"
+	runner.cfg.PVCSize = resource.MustParse("2Gi")
+	runner.cfg.PVCAccessMode = corev1.ReadWriteOnce
+	runner.cfg.PVCMountPath = defaultPVCMountPath
+	pvc := runner.createPVCConfig()
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if *pvc.Spec.StorageClassName != "fast-ssd" || size.String() != "2Gi" || pvc.Spec.AccessModes[0] != corev1.ReadWriteOnce {
+		t.Fatalf("expected a 2Gi ReadWriteOnce claim from fast-ssd but got %v", pvc.Spec)
+	}
+
+	// Mount the claim in the check container.
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].PersistentVolumeClaim == nil || podSpec.Volumes[0].PersistentVolumeClaim.ClaimName != runner.cfg.checkPVCName() {
+		t.Fatalf("expected the claim volume in the pod but got %v", podSpec.Volumes)
+	}
+
+	if podSpec.Containers[0].VolumeMounts[0].MountPath != defaultPVCMountPath {
+		t.Fatalf("expected the claim mounted at %s but got %v", defaultPVCMountPath, podSpec.Containers[0].VolumeMounts)
+	}
+}
+
+// TestParsePVCAccessModeReplicas validates a single-writer claim is rejected whenever the check would run two pods.
+func TestParsePVCAccessModeReplicas(t *testing.T) {
+	// One replica on a ReadWriteOnce claim is allowed.
+	t.Setenv("CHECK_PVC_STORAGE_CLASS", "fast-ssd")
+	t.Setenv("CHECK_DEPLOYMENT_REPLICAS", "1")
+	_, err := parseConfig()
+	if err != nil {
+		t.Fatalf("expected a single replica to parse but got %v", err)
+	}
+
+	// Scaling up or autoscaling needs a second pod on the claim.
+	for _, env := range [][2]string{{"CHECK_SCALE_REPLICAS", "3"}, {"CHECK_HPA_VERIFY", "true"}} {
+		t.Run(env[0], func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			_, err := parseConfig()
+			if err == nil || !strings.Contains(err.Error(), "CHECK_PVC_ACCESS_MODE") {
+				t.Fatalf("expected %s=%s to be rejected but got %v", env[0], env[1], err)
+			}
+
+			// A ReadWriteMany claim can follow every pod.
+			t.Setenv("CHECK_PVC_ACCESS_MODE", string(corev1.ReadWriteMany))
+			_, err = parseConfig()
+			if err != nil {
+				t.Fatalf("expected ReadWriteMany to allow %s but got %v", env[0], err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phaseTiming is how long one phase of a run took.
+type phaseTiming struct {
+	// Phase names the phase.
+	Phase string `json:"phase"`
+	// Seconds is the phase duration rounded to milliseconds.
+	Seconds float64 `json:"seconds"`
+}
+
+// phaseTimer tracks sequential run phases so reports show where the time budget went.
+type phaseTimer struct {
+	// mu guards the fields below across the run and interrupt goroutines.
+	mu sync.Mutex
+	// completed holds finished phases in order.
+	completed []phaseTiming
+	// current is the phase in progress, or empty.
+	current string
+	// started is when the current phase began.
+	started time.Time
+	// passed marks every check stage as succeeded so later failures belong to teardown.
+	passed bool
+}
+
+// begin ends the phase in progress and starts a new one.
+func (p *phaseTimer) begin(phase string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endLocked()
+	p.current = phase
+	p.started = time.Now()
+}
+
+// finish ends the phase in progress.
+func (p *phaseTimer) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endLocked()
+}
+
+// endLocked closes the current phase; the caller must hold mu.
+func (p *phaseTimer) endLocked() {
+	// Nothing to close before the first phase.
+	if len(p.current) == 0 {
+		return
+	}
+
+	seconds := math.Round(time.Since(p.started).Seconds()*1000) / 1000
+	p.completed = append(p.completed, phaseTiming{Phase: p.current, Seconds: seconds})
+	p.current = ""
+}
+
+// markPassed records that every check stage succeeded before the final teardown.
+func (p *phaseTimer) markPassed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.passed = true
+}
+
+// failedPhase names the phase a failed run stopped in, looking past error-path cleanups.
+func (p *phaseTimer) failedPhase() string {
+	// Gather the phases in order, including one still in progress.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phases := make([]string, 0, len(p.completed)+1)
+	for _, phase := range p.completed {
+		phases = append(phases, phase.Phase)
+	}
+	if len(p.current) != 0 {
+		phases = append(phases, p.current)
+	}
+
+	// A cleanup that follows a failed stage is not where the run failed.
+	for i := len(phases) - 1; i >= 0; i-- {
+		if phases[i] == "cleanup" && !p.passed && i > 0 {
+			continue
+		}
+		return phases[i]
+	}
+
+	return ""
+}
+
+// timings copies the phases, including one still in progress.
+func (p *phaseTimer) timings() []phaseTiming {
+	// Copy the phases and the running one under lock.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phases := make([]phaseTiming, len(p.completed), len(p.completed)+1)
+	copy(phases, p.completed)
+	if len(p.current) != 0 {
+		seconds := math.Round(time.Since(p.started).Seconds()*1000) / 1000
+		phases = append(phases, phaseTiming{Phase: p.current + " (unfinished)", Seconds: seconds})
+	}
+
+	return phases
+}
+
+// summary renders the phases, including one still in progress, as compact JSON.
+func (p *phaseTimer) summary() string {
+	phases := p.timings()
+
+	// Fall back to a readable form if encoding ever fails.
+	encoded, err := json.Marshal(phases)
+	if err != nil {
+		parts := make([]string, 0, len(phases))
+		for _, phase := range phases {
+			parts = append(parts, phase.Phase)
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPhaseTimerSummary validates phases are closed in order and an unfinished phase is flagged.
+func TestPhaseTimerSummary(t *testing.T) {
+	// Run two phases and leave a third in progress.
+	timer := &phaseTimer{}
+	timer.begin("deployment_create")
+	timer.begin("service_validate")
+	timer.begin("cleanup")
+
+	phases := make([]phaseTiming, 0)
+	err := json.Unmarshal([]byte(timer.summary()), &phases)
+	if err != nil {
+		t.Fatalf("expected JSON summary but got %q: %v", timer.summary(), err)
+	}
+	if len(phases) != 3 || phases[0].Phase != "deployment_create" || phases[2].Phase != "cleanup (unfinished)" {
+		t.Fatalf("unexpected phases: %+v", phases)
+	}
+
+	// Finishing closes the last phase.
+	timer.finish()
+	if strings.Contains(timer.summary(), "unfinished") {
+		t.Fatalf("expected every phase closed but got %s", timer.summary())
+	}
+}
+
+// TestFailureReportIncludesPhaseTimings validates the report carries the phase summary.
+func TestFailureReportIncludesPhaseTimings(t *testing.T) {
+	// Fail during a phase.
+	runner := buildTestRunner()
+	runner.phases.begin("service_validate")
+	report := runner.failureReport(classify(failureClassNetworking, errors.New("service request failed")))
+
+	found := false
+	for _, line := range report {
+		found = found || strings.HasPrefix(line, "phase timings: ") && strings.Contains(line, "service_validate (unfinished)")
+	}
+	if !found {
+		t.Fatalf("expected phase timings in report but got %v", report)
+	}
+}
+
+// TestPhaseTimerFailedPhase validates the failed phase looks past error-path cleanups only.
+func TestPhaseTimerFailedPhase(t *testing.T) {
+	// A stage failure followed by cleanup names the stage.
+	timer := &phaseTimer{}
+	timer.begin("deployment_create")
+	timer.begin("service_validate")
+	timer.begin("cleanup")
+	timer.finish()
+	if timer.failedPhase() != "service_validate" {
+		t.Fatalf("expected service_validate but got %q", timer.failedPhase())
+	}
+
+	// A failed final cleanup after every stage passed names the cleanup.
+	timer = &phaseTimer{}
+	timer.begin("service_validate")
+	timer.markPassed()
+	timer.begin("cleanup")
+	if timer.failedPhase() != "cleanup" {
+		t.Fatalf("expected cleanup but got %q", timer.failedPhase())
+	}
+}
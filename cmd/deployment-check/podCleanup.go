@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// waitForPodsGone waits for check pods to disappear, force deleting any stuck in Terminating past the threshold.
+func (r *CheckRunner) waitForPodsGone(ctx context.Context) {
+	// Skip the wait entirely when force deletion is disabled.
+	if r.cfg.PodForceDeleteAfter <= 0 {
+		return
+	}
+
+	// Give terminating pods the threshold plus a lookup window to go away.
+	deadline := time.Now().Add(r.cfg.PodForceDeleteAfter + deleteWatchFallbackTimeout)
+	forceDeleted := make(map[string]bool)
+	for {
+		// Stop when the context or wait window closes.
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Warnln("Check pods are still present after cleanup; leaving them for the next run.")
+			return
+		}
+
+		// List every pod created by this check, including earlier runs.
+		podList, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: deploymentLabelKey,
+		})
+		if err != nil {
+			log.WithError(err).Warnln("Failed to list check pods during cleanup.")
+			time.Sleep(time.Second * 2)
+			continue
+		}
+		if len(podList.Items) == 0 {
+			return
+		}
+
+		// Force delete pods that have been terminating for too long.
+		r.forceDeleteStuckPods(ctx, podList.Items, forceDeleted)
+
+		// Sleep briefly to avoid hammering the API.
+		time.Sleep(time.Second * 2)
+	}
+}
+
+// forceDeleteStuckPods force deletes the pods that have been terminating past the threshold, recording each one deleted.
+func (r *CheckRunner) forceDeleteStuckPods(ctx context.Context, pods []corev1.Pod, forceDeleted map[string]bool) {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil || forceDeleted[pod.Name] {
+			continue
+		}
+		terminatingFor := time.Since(pod.DeletionTimestamp.Time)
+		if terminatingFor < r.cfg.PodForceDeleteAfter {
+			continue
+		}
+		if r.forceDeletePod(ctx, pod, terminatingFor) {
+			forceDeleted[pod.Name] = true
+		}
+	}
+}
+
+// forceDeleteDuringDelete force deletes the run's pods stuck terminating while the deployment delete waits on them, until ctx ends.
+func (r *CheckRunner) forceDeleteDuringDelete(ctx context.Context) {
+	// Keep checking so pods that start terminating later are not left wedged.
+	forceDeleted := make(map[string]bool)
+	for {
+		podList, err := r.listDeploymentPods(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.WithError(err).Warnln("Failed to list check pods while waiting for the deployment delete.")
+		}
+		if err == nil {
+			r.forceDeleteStuckPods(ctx, podList.Items, forceDeleted)
+		}
+
+		// Sleep briefly to avoid hammering the API.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+// forceDeletePod deletes a terminating pod with a zero grace period and reports whether it succeeded.
+func (r *CheckRunner) forceDeletePod(ctx context.Context, pod corev1.Pod, terminatingFor time.Duration) bool {
+	// Skip the kubelet's confirmation by deleting with no grace period.
+	graceSeconds := int64(0)
+	err := r.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &graceSeconds})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.WithError(err).Warnln("Failed to force delete pod", pod.Name)
+		return false
+	}
+
+	log.Warnln("Force deleted pod", pod.Name, "on node", pod.Spec.NodeName, "after it was stuck terminating for", terminatingFor.Round(time.Second))
+	r.timeline.recordf("force deleted pod %s on node %s after it was stuck terminating for %s", pod.Name, pod.Spec.NodeName, terminatingFor.Round(time.Second))
+	return true
+}
+
+// decorateStuckDeletion adds the pods stuck terminating to a deployment delete error so the wedged nodes are named.
+func (r *CheckRunner) decorateStuckDeletion(err error) error {
+	// Use a fresh context since the delete wait has usually run out of time.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	podList, listErr := r.listDeploymentPods(ctx)
+	if listErr != nil {
+		log.WithError(listErr).Warnln("Failed to list check pods stuck terminating.")
+		return err
+	}
+
+	// Name every pod that is still terminating.
+	stuck := describeStuckPods(podList.Items, time.Now())
+	if len(stuck) == 0 {
+		return err
+	}
+	log.Errorln(len(stuck), "check pod(s) are stuck terminating:", strings.Join(stuck, "; "))
+	r.timeline.recordf("%d pod(s) stuck terminating during cleanup", len(stuck))
+	return fmt.Errorf("%w; %d pod(s) stuck terminating: [%s]", err, len(stuck), strings.Join(stuck, "; "))
+}
+
+// describeStuckPods describes each terminating pod with its node, how long it has been terminating, and its finalizers.
+func describeStuckPods(pods []corev1.Pod, now time.Time) []string {
+	// Skip pods that were never asked to terminate.
+	stuck := make([]string, 0)
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			continue
+		}
+		node := pod.Spec.NodeName
+		if len(node) == 0 {
+			node = "<unscheduled>"
+		}
+		description := fmt.Sprintf("pod %s on node %s terminating for %s", pod.Name, node, now.Sub(pod.DeletionTimestamp.Time).Round(time.Second))
+		if len(pod.Finalizers) != 0 {
+			description = description + " with finalizers [" + strings.Join(pod.Finalizers, ", ") + "]"
+		}
+		stuck = append(stuck, description)
+	}
+
+	return stuck
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// TestDescribeStuckPods validates terminating pods are named with their node, age, and finalizers.
+func TestDescribeStuckPods(t *testing.T) {
+	// Build a wedged pod, an unscheduled terminating pod, and a running pod.
+	now := time.Unix(1700000000, 0)
+	deleted := metav1.NewTime(now.Add(-time.Minute * 4))
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "wedged", DeletionTimestamp: &deleted, Finalizers: []string{"example.com/drain"}},
+			Spec:       corev1.PodSpec{NodeName: "node-a"},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending", DeletionTimestamp: &deleted}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Spec: corev1.PodSpec{NodeName: "node-b"}},
+	}
+
+	stuck := describeStuckPods(pods, now)
+	if len(stuck) != 2 {
+		t.Fatalf("expected two stuck pods but got %v", stuck)
+	}
+	if stuck[0] != "pod wedged on node node-a terminating for 4m0s with finalizers [example.com/drain]" {
+		t.Fatalf("unexpected description %q", stuck[0])
+	}
+	if stuck[1] != "pod pending on node <unscheduled> terminating for 4m0s" {
+		t.Fatalf("unexpected description %q", stuck[1])
+	}
+}
+
+// TestForceDeleteStuckPods validates only pods terminating past the threshold are force deleted, once each, with no grace period.
+func TestForceDeleteStuckPods(t *testing.T) {
+	// Seed a wedged pod, a pod that only just started terminating, and a running pod.
+	runner := buildTestRunner()
+	runner.cfg.PodForceDeleteAfter = time.Minute
+	longAgo := metav1.NewTime(time.Now().Add(-time.Minute * 5))
+	recently := metav1.NewTime(time.Now().Add(-time.Second * 10))
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "wedged", Namespace: runner.cfg.CheckNamespace, DeletionTimestamp: &longAgo}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "terminating", Namespace: runner.cfg.CheckNamespace, DeletionTimestamp: &recently}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: runner.cfg.CheckNamespace}},
+	}
+	client := fake.NewClientset()
+	runner.client = client
+
+	forceDeleted := make(map[string]bool)
+	runner.forceDeleteStuckPods(context.Background(), pods, forceDeleted)
+	runner.forceDeleteStuckPods(context.Background(), pods, forceDeleted)
+	if len(forceDeleted) != 1 || !forceDeleted["wedged"] {
+		t.Fatalf("expected only the wedged pod to be force deleted but got %v", forceDeleted)
+	}
+
+	// The wedged pod is deleted once with a zero grace period.
+	deletes := 0
+	for _, action := range client.Actions() {
+		deleteAction, isDelete := action.(k8stesting.DeleteAction)
+		if !isDelete {
+			continue
+		}
+		deletes++
+		grace := deleteAction.GetDeleteOptions().GracePeriodSeconds
+		if deleteAction.GetName() != "wedged" || grace == nil || *grace != 0 {
+			t.Fatalf("expected a zero grace delete of the wedged pod but got %s with grace %v", deleteAction.GetName(), grace)
+		}
+	}
+	if deletes != 1 {
+		t.Fatalf("expected one force delete but got %d", deletes)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// podDNSLookupTimeout bounds each in-pod DNS lookup.
+	podDNSLookupTimeout = time.Second * 15
+)
+
+// verifyPodDNS execs a DNS lookup inside each running check pod to confirm cluster DNS works from the workload.
+func (r *CheckRunner) verifyPodDNS(ctx context.Context) error {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for DNS verification: %w", err)
+	}
+
+	// Resolve the configured name from every running pod.
+	log.Infoln("Verifying DNS resolution of", r.cfg.PodDNSName, "from inside", len(podList.Items), "check pod(s).")
+	failures := make([]string, 0)
+	checked := 0
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		checked++
+
+		lookupCtx, cancel := context.WithTimeout(ctx, podDNSLookupTimeout)
+		stdout, stderr, execErr := r.execInPod(lookupCtx, pod, r.cfg.CheckContainerName, []string{"getent", "hosts", r.cfg.PodDNSName})
+		cancel()
+		if execErr != nil {
+			detail := strings.TrimSpace(stderr)
+			if len(detail) == 0 {
+				detail = execErr.Error()
+			}
+			failures = append(failures, fmt.Sprintf("pod: %s node: %s error: %s", pod.Name, pod.Spec.NodeName, detail))
+			continue
+		}
+		log.Infoln("Pod", pod.Name, "resolved", r.cfg.PodDNSName, "to", strings.Join(strings.Fields(stdout), " "))
+	}
+
+	// Fail rather than pass vacuously when no pod could be asked.
+	if checked == 0 {
+		return fmt.Errorf("no running check pods to resolve %s from", r.cfg.PodDNSName)
+	}
+
+	// Report every pod that could not resolve.
+	if len(failures) != 0 {
+		return fmt.Errorf("workload pods could not resolve %s: %s", r.cfg.PodDNSName, strings.Join(failures, "; "))
+	}
+
+	r.timeline.recordf("check pods resolved %s", r.cfg.PodDNSName)
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestVerifyPodDNS validates the lookup fails without a running pod to exec in and reports pods that cannot resolve.
+func TestVerifyPodDNS(t *testing.T) {
+	// Fail when no pods were listed.
+	runner := buildTestRunner()
+	runner.cfg.PodDNSName = "kubernetes.default"
+	runner.client = fake.NewClientset()
+	err := runner.verifyPodDNS(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no running check pods") {
+		t.Fatalf("expected an error without pods but got %v", err)
+	}
+
+	// Fail when no listed pod is running.
+	pending := probeTestPod(runner, "pending", false)
+	pending.Status.Phase = corev1.PodPending
+	runner.client = fake.NewClientset(pending)
+	err = runner.verifyPodDNS(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no running check pods") {
+		t.Fatalf("expected an error without running pods but got %v", err)
+	}
+
+	// Report a running pod whose exec fails.
+	running := probeTestPod(runner, "running", true)
+	running.Status.Phase = corev1.PodRunning
+	runner.client = fake.NewClientset(running)
+	err = runner.verifyPodDNS(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "pod: running node: node-running") {
+		t.Fatalf("expected the failing pod to be named but got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// pdbPollInterval is the pause between disruption budget and replacement observations.
+	pdbPollInterval = time.Second * 2
+)
+
+// createPDBConfig builds a disruption budget for the check pods with the given minimum available.
+func (r *CheckRunner) createPDBConfig(labels map[string]string, minAvailable int) *policyv1.PodDisruptionBudget {
+	// Select the check pods.
+	available := intstr.FromInt32(int32(minAvailable))
+	pdb := &policyv1.PodDisruptionBudget{
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:     &metav1.LabelSelector{MatchLabels: labels},
+			MinAvailable: &available,
+		},
+	}
+	pdb.Name = r.cfg.CheckDeploymentName
+	pdb.Namespace = r.cfg.CheckNamespace
+	pdb.Labels = copyStringMap(r.cfg.ExtraLabels)
+	pdb.Annotations = r.resourceAnnotations(nil)
+
+	return pdb
+}
+
+// verifyPodDisruption checks that a disruption budget blocks an eviction, then allows one that is replaced.
+func (r *CheckRunner) verifyPodDisruption(ctx context.Context, labels map[string]string) error {
+	// Protect every replica so no disruption is allowed.
+	budgets := r.client.PolicyV1().PodDisruptionBudgets(r.cfg.CheckNamespace)
+	_, err := budgets.Create(ctx, r.createPDBConfig(labels, r.cfg.CheckDeploymentReplicas), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create pod disruption budget: %w", err)
+	}
+	r.timeline.recordf("created pod disruption budget requiring %d available pod(s)", r.cfg.CheckDeploymentReplicas)
+	err = r.waitForDisruptionsAllowed(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	// The eviction API must refuse to evict a protected pod.
+	pod, err := r.pickEvictionPod(ctx)
+	if err != nil {
+		return err
+	}
+	err = r.evictPod(ctx, pod)
+	if err == nil {
+		return fmt.Errorf("eviction of pod %s was allowed although the pod disruption budget allows no disruptions", pod.Name)
+	}
+	if !k8serrors.IsTooManyRequests(err) {
+		return fmt.Errorf("eviction of pod %s failed for a reason other than the pod disruption budget: %w", pod.Name, err)
+	}
+	log.Infoln("Eviction of pod", pod.Name, "was blocked by the pod disruption budget.")
+	r.timeline.recordf("pod disruption budget blocked eviction of pod %s", pod.Name)
+
+	// Allow one disruption and require the eviction to succeed.
+	current, err := budgets.Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch pod disruption budget: %w", err)
+	}
+	current.Spec.MinAvailable = r.createPDBConfig(labels, r.cfg.CheckDeploymentReplicas-1).Spec.MinAvailable
+	_, err = budgets.Update(ctx, current, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update pod disruption budget: %w", err)
+	}
+	err = r.waitForDisruptionsAllowed(ctx, 1)
+	if err != nil {
+		return err
+	}
+	err = r.evictPod(ctx, pod)
+	if err != nil {
+		return fmt.Errorf("eviction of pod %s was refused although the pod disruption budget allows one disruption: %w", pod.Name, err)
+	}
+	log.Infoln("Evicted pod", pod.Name+".")
+	r.timeline.recordf("evicted pod %s", pod.Name)
+
+	// Wait for the deployment to replace the evicted pod.
+	err = r.waitForEvictionReplacement(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	// Remove the budget so later stages can disrupt pods freely.
+	err = r.deletePDBAndWait(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Pod disruption budget enforcement verified.")
+
+	return nil
+}
+
+// pickEvictionPod returns a running check pod to evict.
+func (r *CheckRunner) pickEvictionPod(ctx context.Context) (corev1.Pod, error) {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return corev1.Pod{}, fmt.Errorf("failed to list deployment pods for eviction: %w", err)
+	}
+
+	// Take the first running pod that is not already going away.
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod, nil
+		}
+	}
+
+	return corev1.Pod{}, fmt.Errorf("no running check pod found to evict")
+}
+
+// evictPod asks the eviction API to evict a pod.
+func (r *CheckRunner) evictPod(ctx context.Context, pod corev1.Pod) error {
+	// Submit an eviction for the pod.
+	eviction := &policyv1.Eviction{}
+	eviction.Name = pod.Name
+	eviction.Namespace = pod.Namespace
+
+	return r.client.CoreV1().Pods(pod.Namespace).EvictV1(ctx, eviction)
+}
+
+// waitForDisruptionsAllowed polls the budget until its status reflects the spec with the expected disruptions allowed.
+func (r *CheckRunner) waitForDisruptionsAllowed(ctx context.Context, expected int32) error {
+	// Poll until the disruption controller catches up or the timeout passes.
+	deadline := time.Now().Add(r.cfg.PDBTimeout)
+	for {
+		pdb, err := r.client.PolicyV1().PodDisruptionBudgets(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			log.Warnln("Failed to fetch pod disruption budget:", err.Error())
+		}
+		if err == nil && pdb.Status.ObservedGeneration >= pdb.Generation && pdb.Status.DisruptionsAllowed == expected {
+			return nil
+		}
+
+		// Report what the disruption controller last published once the timeout passes.
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("failed to observe pod disruption budget status: %w", err)
+			}
+			return fmt.Errorf("pod disruption budget did not allow %d disruption(s) within %s: disruptions allowed: %d current healthy: %d desired healthy: %d observed generation: %d of %d", expected, r.cfg.PDBTimeout, pdb.Status.DisruptionsAllowed, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy, pdb.Status.ObservedGeneration, pdb.Generation)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for the pod disruption budget status")
+		case <-time.After(pdbPollInterval):
+		}
+	}
+}
+
+// waitForEvictionReplacement waits for the evicted pod to go away and the deployment to be fully ready again.
+func (r *CheckRunner) waitForEvictionReplacement(ctx context.Context, evicted corev1.Pod) error {
+	// Poll until the replacement is ready or the timeout passes.
+	deadline := time.Now().Add(r.cfg.PDBTimeout)
+	for {
+		_, err := r.client.CoreV1().Pods(evicted.Namespace).Get(ctx, evicted.Name, metav1.GetOptions{})
+		evictedGone := k8serrors.IsNotFound(err)
+		deployment, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err == nil && evictedGone && deploymentAvailable(deployment, r.cfg.CheckDeploymentReplicas) {
+			r.timeline.recordf("replacement for evicted pod %s is ready", evicted.Name)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("deployment did not replace evicted pod %s within %s (evicted pod gone: %t)", evicted.Name, r.cfg.PDBTimeout, evictedGone)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for evicted pod %s to be replaced", evicted.Name)
+		case <-time.After(pdbPollInterval):
+		}
+	}
+}
+
+// deletePDBAndWait removes the check disruption budget and waits for it to disappear.
+func (r *CheckRunner) deletePDBAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "pod disruption budget", func(ctx context.Context) error {
+		return r.client.PolicyV1().PodDisruptionBudgets(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckDeploymentName, metav1.DeleteOptions{})
+	}, r.pdbExists)
+}
+
+// pdbExists reports whether the check disruption budget is present.
+func (r *CheckRunner) pdbExists(ctx context.Context) (bool, error) {
+	_, err := r.client.PolicyV1().PodDisruptionBudgets(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	return objectFound(err)
+}
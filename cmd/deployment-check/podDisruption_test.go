@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestCreatePDBConfig validates the disruption budget selects the check pods with the requested minimum.
+func TestCreatePDBConfig(t *testing.T) {
+	// Build a budget protecting every replica.
+	runner := buildTestRunner()
+	labels := map[string]string{deploymentLabelKey: "deployment-123"}
+	pdb := runner.createPDBConfig(labels, runner.cfg.CheckDeploymentReplicas)
+
+	if pdb.Name != runner.cfg.CheckDeploymentName || pdb.Namespace != runner.cfg.CheckNamespace {
+		t.Fatalf("unexpected budget name %s/%s", pdb.Namespace, pdb.Name)
+	}
+	if pdb.Spec.Selector.MatchLabels[deploymentLabelKey] != "deployment-123" {
+		t.Fatalf("expected the budget to select the check pods but got %v", pdb.Spec.Selector.MatchLabels)
+	}
+	if pdb.Spec.MinAvailable.IntValue() != runner.cfg.CheckDeploymentReplicas {
+		t.Fatalf("expected min available %d but got %s", runner.cfg.CheckDeploymentReplicas, pdb.Spec.MinAvailable.String())
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// execInPod runs a command in a pod container and returns its stdout and stderr.
+func (r *CheckRunner) execInPod(ctx context.Context, pod corev1.Pod, container string, command []string) (string, string, error) {
+	// Guard against runners built without streaming access.
+	if r.restConfig == nil {
+		return "", "", fmt.Errorf("no rest config available for pod exec")
+	}
+
+	// Build the exec subresource request.
+	request := r.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	// Open the exec stream.
+	executor, err := remotecommand.NewSPDYExecutor(r.restConfig, "POST", request.URL())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create exec stream for pod %s: %w", pod.Name, err)
+	}
+
+	// Run the command and capture output.
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// podIPTimeout bounds the wait for every ready pod to answer on its IP.
+	podIPTimeout = time.Minute
+)
+
+// verifyPodIPs connects to every ready pod IP on each container port and names the pods and nodes that cannot be reached.
+func (r *CheckRunner) verifyPodIPs(ctx context.Context) error {
+	// Retry the whole set so a pod that just turned ready gets a fair chance.
+	deadline := time.Now().Add(podIPTimeout)
+	for {
+		results, err := r.probePodEndpoints(ctx)
+		if err != nil {
+			return err
+		}
+		failures, unreachableNodes := podIPFailures(results)
+		if len(failures) == 0 && len(results) == r.cfg.CheckDeploymentReplicas {
+			log.Infoln("Every ready pod answered directly on its IP.")
+			r.timeline.recordf("all %d pod(s) answered directly on their IPs", len(results))
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if len(failures) == 0 {
+				return fmt.Errorf("expected %d ready pod(s) to connect to but found %d", r.cfg.CheckDeploymentReplicas, len(results))
+			}
+			return fmt.Errorf("%d of %d pod(s) unreachable on their pod IP, pointing at the pod network (CNI) rather than the service; unreachable nodes: [%s]; %s", len(failures), len(results), strings.Join(sortedKeys(unreachableNodes), ", "), strings.Join(failures, "; "))
+		}
+
+		// Wait before connecting again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while connecting to pod IPs")
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// podIPFailures returns one failure line per unreachable pod and the set of nodes hosting them.
+func podIPFailures(results []podProbeResult) ([]string, map[string]bool) {
+	// Keep only the pods that failed on at least one port.
+	failures := make([]string, 0)
+	unreachableNodes := make(map[string]bool)
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("pod: %s node: %s errors: %s", result.pod, result.node, result.err.Error()))
+		unreachableNodes[result.node] = true
+	}
+
+	return failures, unreachableNodes
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestVerifyPodIPs validates the check passes once every replica answers on its pod IP.
+func TestVerifyPodIPs(t *testing.T) {
+	// Run two ready replicas behind a healthy server.
+	runner := buildTestRunner()
+	runner.cfg.CheckDeploymentReplicas = 2
+	runner.cfg.CheckContainerPort = probeTestServer(t, http.StatusOK)
+	runner.client = fake.NewClientset(probeTestPod(runner, "a", true), probeTestPod(runner, "b", true))
+
+	err := runner.verifyPodIPs(context.Background())
+	if err != nil {
+		t.Fatalf("expected every pod IP to answer but got %v", err)
+	}
+}
+
+// TestPodIPFailures validates failing pods are listed with the nodes that host them.
+func TestPodIPFailures(t *testing.T) {
+	// Mix a healthy pod with two failing ones on the same node.
+	results := []podProbeResult{
+		{pod: "a", node: "node-1", address: "10.0.0.1"},
+		{pod: "b", node: "node-2", address: "10.0.0.2", err: errors.New("10.0.0.2:8080: connection refused")},
+		{pod: "c", node: "node-2", address: "10.0.0.3", err: errors.New("10.0.0.3:9090: i/o timeout")},
+	}
+
+	failures, unreachableNodes := podIPFailures(results)
+	if len(failures) != 2 || !strings.Contains(failures[0], "pod: b node: node-2") || !strings.Contains(failures[1], "i/o timeout") {
+		t.Fatalf("unexpected failures %v", failures)
+	}
+	if len(unreachableNodes) != 1 || !unreachableNodes["node-2"] {
+		t.Fatalf("expected only node-2 to be unreachable but got %v", unreachableNodes)
+	}
+}
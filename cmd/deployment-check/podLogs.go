@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// streamPodLogs follows the container logs of every check pod into the checker's debug output until ctx ends.
+func (r *CheckRunner) streamPodLogs(ctx context.Context) {
+	// Track which container instances are already being followed.
+	streaming := make(map[string]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// List pods for the current run.
+		podList, err := r.listDeploymentPods(ctx)
+		if err != nil {
+			log.Debugln("Failed to list deployment pods for log streaming:", err.Error())
+		}
+
+		// Start a follower for each started container instance that is not yet streamed.
+		if err == nil {
+			for _, pod := range podList.Items {
+				for _, containerStat := range podContainerStatuses(pod) {
+					if containerStat.State.Running == nil && containerStat.State.Terminated == nil {
+						continue
+					}
+					key := fmt.Sprintf("%s/%s/%d", pod.Name, containerStat.Name, containerStat.RestartCount)
+					if streaming[key] {
+						continue
+					}
+					streaming[key] = true
+					go r.followContainerLogs(ctx, pod.Name, containerStat.Name)
+				}
+			}
+		}
+
+		// Sleep briefly to avoid hammering the API.
+		time.Sleep(time.Second * 2)
+	}
+}
+
+// followContainerLogs copies one container's log stream into the checker's debug output.
+func (r *CheckRunner) followContainerLogs(ctx context.Context, podName string, containerName string) {
+	// Open a follow stream for the container.
+	stream, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		log.Debugln("Failed to stream logs for pod", podName, "container", containerName+":", err.Error())
+		return
+	}
+	defer stream.Close()
+
+	// Emit each line with the pod and container it came from.
+	prefix := "[" + podName + "/" + containerName + "]"
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		log.Debugln(prefix, scanner.Text())
+	}
+	log.Debugln(prefix, "log stream ended.")
+}
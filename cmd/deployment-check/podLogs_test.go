@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestFollowContainerLogs validates container log lines reach the debug output tagged with their pod and container.
+func TestFollowContainerLogs(t *testing.T) {
+	// Capture debug output for the duration of the test.
+	var output bytes.Buffer
+	previousOutput := log.StandardLogger().Out
+	previousLevel := log.GetLevel()
+	log.SetOutput(&output)
+	log.SetLevel(log.DebugLevel)
+	defer func() {
+		log.SetOutput(previousOutput)
+		log.SetLevel(previousLevel)
+	}()
+
+	// Follow a container whose log stream the fake client serves.
+	runner := buildTestRunner()
+	runner.client = fake.NewClientset()
+	runner.followContainerLogs(context.Background(), "deployment-pod", defaultCheckContainerName)
+
+	if !strings.Contains(output.String(), "[deployment-pod/"+defaultCheckContainerName+"] fake logs") {
+		t.Fatalf("expected the tagged log line in the output but got:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), "log stream ended.") {
+		t.Fatalf("expected the end of the stream to be logged but got:\n%s", output.String())
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// pssProfileRestricted is the Pod Security Standards profile the check can comply with.
+	pssProfileRestricted = "restricted"
+)
+
+// applyRestrictedProfile makes the pod spec compliant with the restricted Pod Security Standard.
+func applyRestrictedProfile(podSpec *corev1.PodSpec) {
+	// Run as non-root with a confined seccomp profile at the pod level.
+	runAsNonRoot := true
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	podSpec.SecurityContext.RunAsNonRoot = &runAsNonRoot
+	if podSpec.SecurityContext.SeccompProfile == nil || podSpec.SecurityContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		podSpec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	// Harden every container, including init containers.
+	for i := range podSpec.InitContainers {
+		applyRestrictedContainer(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		applyRestrictedContainer(&podSpec.Containers[i])
+	}
+}
+
+// applyRestrictedContainer hardens a single container for the restricted Pod Security Standard.
+func applyRestrictedContainer(container *corev1.Container) {
+	// Forbid privilege escalation and privileged mode.
+	allowPrivilegeEscalation := false
+	privileged := false
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+	container.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+	container.SecurityContext.Privileged = &privileged
+
+	// Drop every capability, keeping only an explicit NET_BIND_SERVICE add.
+	added := make([]corev1.Capability, 0)
+	if container.SecurityContext.Capabilities != nil {
+		for _, capability := range container.SecurityContext.Capabilities.Add {
+			if capability == "NET_BIND_SERVICE" {
+				added = append(added, capability)
+			}
+		}
+	}
+	if len(added) == 0 {
+		added = nil
+	}
+	container.SecurityContext.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: added}
+
+	// Clear container-level overrides that would undo the pod-level settings.
+	if container.SecurityContext.RunAsNonRoot != nil && !*container.SecurityContext.RunAsNonRoot {
+		container.SecurityContext.RunAsNonRoot = nil
+	}
+	if container.SecurityContext.SeccompProfile != nil && container.SecurityContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		container.SecurityContext.SeccompProfile = nil
+	}
+}
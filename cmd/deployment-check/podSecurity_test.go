@@ -0,0 +1,156 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restrictedViolations evaluates a pod spec against the baseline and restricted Pod Security Standards rules.
+func restrictedViolations(podSpec corev1.PodSpec) []string {
+	// Check the host namespace and volume rules.
+	violations := make([]string, 0)
+	if podSpec.HostNetwork || podSpec.HostPID || podSpec.HostIPC {
+		violations = append(violations, "host namespaces")
+	}
+	for _, volume := range podSpec.Volumes {
+		allowed := volume.ConfigMap != nil || volume.CSI != nil || volume.DownwardAPI != nil || volume.EmptyDir != nil ||
+			volume.Ephemeral != nil || volume.PersistentVolumeClaim != nil || volume.Projected != nil || volume.Secret != nil
+		if !allowed {
+			violations = append(violations, "volume type of "+volume.Name)
+		}
+	}
+
+	// Read the pod-level settings that containers may inherit.
+	podRunAsNonRoot := false
+	podSeccomp := false
+	if podSpec.SecurityContext != nil {
+		podRunAsNonRoot = podSpec.SecurityContext.RunAsNonRoot != nil && *podSpec.SecurityContext.RunAsNonRoot
+		if podSpec.SecurityContext.RunAsUser != nil && *podSpec.SecurityContext.RunAsUser == 0 {
+			violations = append(violations, "pod runAsUser 0")
+		}
+		profile := podSpec.SecurityContext.SeccompProfile
+		if profile != nil && profile.Type == corev1.SeccompProfileTypeUnconfined {
+			violations = append(violations, "pod seccomp Unconfined")
+		}
+		podSeccomp = profile != nil && (profile.Type == corev1.SeccompProfileTypeRuntimeDefault || profile.Type == corev1.SeccompProfileTypeLocalhost)
+	}
+
+	// Check each container, including init containers.
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		securityContext := container.SecurityContext
+		if securityContext == nil {
+			securityContext = &corev1.SecurityContext{}
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				violations = append(violations, container.Name+" hostPort")
+			}
+		}
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			violations = append(violations, container.Name+" privileged")
+		}
+		if securityContext.AllowPrivilegeEscalation == nil || *securityContext.AllowPrivilegeEscalation {
+			violations = append(violations, container.Name+" allowPrivilegeEscalation")
+		}
+		runAsNonRoot := podRunAsNonRoot
+		if securityContext.RunAsNonRoot != nil {
+			runAsNonRoot = *securityContext.RunAsNonRoot
+		}
+		if !runAsNonRoot {
+			violations = append(violations, container.Name+" runAsNonRoot")
+		}
+		if securityContext.RunAsUser != nil && *securityContext.RunAsUser == 0 {
+			violations = append(violations, container.Name+" runAsUser 0")
+		}
+		seccomp := podSeccomp
+		if securityContext.SeccompProfile != nil {
+			seccomp = securityContext.SeccompProfile.Type == corev1.SeccompProfileTypeRuntimeDefault || securityContext.SeccompProfile.Type == corev1.SeccompProfileTypeLocalhost
+		}
+		if !seccomp {
+			violations = append(violations, container.Name+" seccompProfile")
+		}
+		droppedAll := false
+		if securityContext.Capabilities != nil {
+			for _, capability := range securityContext.Capabilities.Drop {
+				droppedAll = droppedAll || capability == "ALL"
+			}
+			for _, capability := range securityContext.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					violations = append(violations, container.Name+" adds "+string(capability))
+				}
+			}
+		}
+		if !droppedAll {
+			violations = append(violations, container.Name+" capabilities drop ALL")
+		}
+	}
+
+	return violations
+}
+
+// TestRestrictedProfileCompliance validates the generated deployment passes the restricted Pod Security Standard.
+func TestRestrictedProfileCompliance(t *testing.T) {
+	// The default spec is not restricted-compliant, which keeps the rules honest.
+	runner := buildTestRunner()
+	violations := restrictedViolations(runner.createDeploymentConfig("nginx:test").Spec.Template.Spec)
+	if len(violations) == 0 {
+		t.Fatalf("expected the default pod spec to violate the restricted profile")
+	}
+
+	// The restricted profile clears every violation, alone and combined with other security settings.
+	localhostProfile := "profiles/check.json"
+	runAsUser := int64(101)
+	cases := map[string]func(cfg *CheckConfig){
+		"profile only": func(cfg *CheckConfig) {},
+		"with hardening and localhost seccomp": func(cfg *CheckConfig) {
+			cfg.ContainerReadOnlyRootFilesystem = true
+			cfg.PodRunAsUser = &runAsUser
+			cfg.PodSeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}
+		},
+		"with autopilot": func(cfg *CheckConfig) {
+			cfg.AutopilotMode = true
+		},
+	}
+	for name, configure := range cases {
+		runner = buildTestRunner()
+		runner.cfg.PSSProfile = pssProfileRestricted
+		configure(runner.cfg)
+		podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+		violations = restrictedViolations(podSpec)
+		if len(violations) != 0 {
+			t.Fatalf("%s: expected a restricted-compliant pod spec but found: %s", name, strings.Join(violations, ", "))
+		}
+	}
+}
+
+// TestApplyRestrictedContainer validates unsafe container overrides are removed.
+func TestApplyRestrictedContainer(t *testing.T) {
+	// Start from a container with unsafe settings.
+	privileged := true
+	runAsNonRoot := false
+	container := corev1.Container{
+		Name: "unsafe",
+		SecurityContext: &corev1.SecurityContext{
+			Privileged:     &privileged,
+			RunAsNonRoot:   &runAsNonRoot,
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+			Capabilities:   &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_BIND_SERVICE"}},
+		},
+	}
+
+	// Apply the profile to a pod holding the container.
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{container}}
+	applyRestrictedProfile(&podSpec)
+	violations := restrictedViolations(podSpec)
+	if len(violations) != 0 {
+		t.Fatalf("expected unsafe settings to be removed but found: %s", strings.Join(violations, ", "))
+	}
+
+	added := podSpec.Containers[0].SecurityContext.Capabilities.Add
+	if len(added) != 1 || added[0] != "NET_BIND_SERVICE" {
+		t.Fatalf("expected only NET_BIND_SERVICE to stay added but got: %v", added)
+	}
+}
@@ -3,13 +3,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// terminationMessageSummaryLength caps termination messages included in pod summaries.
+	terminationMessageSummaryLength = 256
+	// warningEventSummaryLimit caps how many namespace warning events are included in failures.
+	warningEventSummaryLimit = 5
+)
+
 // decorateDeploymentError adds deployment stage and pod context to an error.
 func (r *CheckRunner) decorateDeploymentError(ctx context.Context, stage string, err error) error {
 	// Guard against nil errors.
@@ -17,11 +27,78 @@ func (r *CheckRunner) decorateDeploymentError(ctx context.Context, stage string,
 		return nil
 	}
 
-	// Capture the deployment pod snapshot for troubleshooting.
+	// Capture the deployment pod snapshot and recent warnings for troubleshooting.
 	podSummary := r.deploymentPodSummary(ctx)
+	r.podSummary = podSummary
+	warningSummary := r.namespaceWarningSummary()
+	if len(warningSummary) != 0 {
+		return fmt.Errorf("%s failed: %w; pod status: %s; recent warnings: %s", stage, err, podSummary, warningSummary)
+	}
 	return fmt.Errorf("%s failed: %w; pod status: %s", stage, err, podSummary)
 }
 
+// namespaceWarningSummary summarizes the most recent warning events in the check namespace since the run started.
+func (r *CheckRunner) namespaceWarningSummary() string {
+	// Bound the event lookup to a short timeout.
+	summaryCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	eventList, err := r.client.CoreV1().Events(r.cfg.CheckNamespace).List(summaryCtx, metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning})
+	if err != nil {
+		return "failed to list events: " + err.Error()
+	}
+
+	return formatWarningEvents(eventList.Items, r.now, warningEventSummaryLimit)
+}
+
+// formatWarningEvents renders the newest warning events since a time, one per object and reason.
+func formatWarningEvents(events []corev1.Event, since time.Time, limit int) string {
+	// Newest first so the limit keeps the most relevant events.
+	sort.Slice(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
+	})
+
+	// Keep one entry per object and reason.
+	seen := make(map[string]bool)
+	summaries := make([]string, 0, limit)
+	for _, event := range events {
+		if len(summaries) == limit {
+			break
+		}
+		if event.Type != corev1.EventTypeWarning || eventTime(event).Before(since) {
+			continue
+		}
+		object := event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+		key := object + " " + event.Reason
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		summary := object + " " + event.Reason
+		if event.Count > 1 {
+			summary = summary + " (x" + strconv.Itoa(int(event.Count)) + ")"
+		}
+		summaries = append(summaries, summary+": "+compactTerminationMessage(event.Message))
+	}
+
+	return strings.Join(summaries, "; ")
+}
+
+// eventTime returns the most specific time an event was last seen.
+func eventTime(event corev1.Event) time.Time {
+	// Prefer the last occurrence, then the series or event time, then creation.
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
+
 // deploymentPodSummary lists pods for the current run and summarizes their state.
 func (r *CheckRunner) deploymentPodSummary(ctx context.Context) string {
 	// Bound the pod lookup to a short timeout.
@@ -29,10 +106,7 @@ func (r *CheckRunner) deploymentPodSummary(ctx context.Context) string {
 	defer cancel()
 
 	// Use the current run timestamp label to locate pods.
-	labelSelector := deploymentLabelKey + "=" + deploymentLabelValueBase + fmt.Sprint(r.now.Unix())
-	podList, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).List(summaryCtx, metav1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+	podList, err := r.listDeploymentPods(summaryCtx)
 	if err != nil {
 		return "failed to list deployment pods: " + err.Error()
 	}
@@ -76,6 +150,16 @@ func formatDeploymentPodSummary(pod corev1.Pod) string {
 		containerStates = append(containerStates, "none")
 	}
 
+	// Append init container states, which explain pods stuck before their containers start.
+	initStates := ""
+	if len(pod.Status.InitContainerStatuses) != 0 {
+		states := make([]string, 0, len(pod.Status.InitContainerStatuses))
+		for _, status := range pod.Status.InitContainerStatuses {
+			states = append(states, status.Name+"="+describeContainerState(status))
+		}
+		initStates = " init=[" + strings.Join(states, ",") + "]"
+	}
+
 	// Include pod-level reason when present.
 	reason := pod.Status.Reason
 	if len(reason) == 0 {
@@ -83,7 +167,7 @@ func formatDeploymentPodSummary(pod corev1.Pod) string {
 	}
 
 	return fmt.Sprintf(
-		"pod=%s node=%s phase=%s reason=%s ready=%d/%d containers=[%s]",
+		"pod=%s node=%s phase=%s reason=%s ready=%d/%d containers=[%s]%s",
 		pod.Name,
 		nodeName,
 		pod.Status.Phase,
@@ -91,6 +175,7 @@ func formatDeploymentPodSummary(pod corev1.Pod) string {
 		readyCount,
 		totalCount,
 		strings.Join(containerStates, ","),
+		initStates,
 	)
 }
 
@@ -98,19 +183,18 @@ func formatDeploymentPodSummary(pod corev1.Pod) string {
 func describeContainerState(status corev1.ContainerStatus) string {
 	// Prefer terminated states when present.
 	if status.State.Terminated != nil {
-		reason := status.State.Terminated.Reason
-		if len(reason) == 0 {
-			reason = "terminated"
-		}
-		return "terminated:" + reason
+		return describeTerminatedState(status.State.Terminated)
 	}
 
-	// Prefer waiting states over running.
+	// Prefer waiting states over running, noting why the last run ended.
 	if status.State.Waiting != nil {
 		reason := status.State.Waiting.Reason
 		if len(reason) == 0 {
 			reason = "waiting"
 		}
+		if status.LastTerminationState.Terminated != nil {
+			return "waiting:" + reason + "(last " + describeTerminatedState(status.LastTerminationState.Terminated) + ")"
+		}
 		return "waiting:" + reason
 	}
 
@@ -121,3 +205,39 @@ func describeContainerState(status corev1.ContainerStatus) string {
 
 	return "unknown"
 }
+
+// describeTerminatedState renders a terminated state with its exit code and termination message.
+func describeTerminatedState(terminated *corev1.ContainerStateTerminated) string {
+	// Default the reason when the runtime did not provide one.
+	reason := terminated.Reason
+	if len(reason) == 0 {
+		reason = "terminated"
+	}
+	description := fmt.Sprintf("terminated:%s exit=%d", reason, terminated.ExitCode)
+
+	// Include the termination message since exit reasons rarely explain a crash.
+	message := compactTerminationMessage(terminated.Message)
+	if len(message) != 0 {
+		description = description + " msg=" + strconv.Quote(message)
+	}
+
+	return description
+}
+
+// compactTerminationMessage flattens and truncates a termination message for single-line summaries.
+func compactTerminationMessage(message string) string {
+	// Collapse whitespace so multi-line log output stays on one line.
+	compact := strings.Join(strings.Fields(message), " ")
+
+	// Keep the tail since the last lines usually explain the failure.
+	if len(compact) > terminationMessageSummaryLength {
+		// Advance the cut past continuation bytes so a multi-byte character is never split.
+		cut := len(compact) - terminationMessageSummaryLength
+		for cut < len(compact) && !utf8.RuneStart(compact[cut]) {
+			cut++
+		}
+		compact = "..." + compact[cut:]
+	}
+
+	return compact
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestDescribeContainerStateTermination validates termination details in container summaries.
+func TestDescribeContainerStateTermination(t *testing.T) {
+	// Build a crash-looping container whose last run wrote a termination message.
+	status := corev1.ContainerStatus{
+		Name: "deployment-container",
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				Reason:   "Error",
+				ExitCode: 1,
+				Message:  "nginx: [emerg] bind() failed\n(13: Permission denied)",
+			},
+		},
+	}
+
+	state := describeContainerState(status)
+
+	if !strings.Contains(state, "waiting:CrashLoopBackOff") {
+		t.Fatalf("expected waiting reason in state but got: %s", state)
+	}
+
+	if !strings.Contains(state, "exit=1") {
+		t.Fatalf("expected exit code in state but got: %s", state)
+	}
+
+	if !strings.Contains(state, "bind() failed (13: Permission denied)") {
+		t.Fatalf("expected flattened termination message in state but got: %s", state)
+	}
+}
+
+// TestFormatWarningEvents validates ordering, deduplication, and filtering of warning events.
+func TestFormatWarningEvents(t *testing.T) {
+	// Build events from before and during the run, including a repeated reason.
+	start := time.Now()
+	event := func(name string, reason string, age time.Duration, count int32) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        "details for\n" + reason,
+			Count:          count,
+			LastTimestamp:  metav1.NewTime(start.Add(age)),
+		}
+	}
+	events := []corev1.Event{
+		event("pod-a", "FailedScheduling", time.Second, 3),
+		event("pod-a", "FailedScheduling", 2*time.Second, 1),
+		event("pod-b", "FailedMount", 3*time.Second, 1),
+		event("pod-c", "BackOff", -time.Minute, 1),
+	}
+
+	summary := formatWarningEvents(events, start, 5)
+	expected := "Pod/pod-b FailedMount: details for FailedMount; Pod/pod-a FailedScheduling: details for FailedScheduling"
+	if summary != expected {
+		t.Fatalf("expected %q but got %q", expected, summary)
+	}
+
+	// Respect the limit.
+	summary = formatWarningEvents(events, start, 1)
+	if summary != "Pod/pod-b FailedMount: details for FailedMount" {
+		t.Fatalf("expected only the newest event but got %q", summary)
+	}
+}
+
+// TestFormatDeploymentPodSummaryInitContainers validates init container states are summarized.
+func TestFormatDeploymentPodSummaryInitContainers(t *testing.T) {
+	// Build a pod stuck on a crash-looping init container.
+	pod := corev1.Pod{}
+	pod.Name = "deployment-check-abc"
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "deployment-container-init-0",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+
+	summary := formatDeploymentPodSummary(pod)
+	if !strings.Contains(summary, "init=[deployment-container-init-0=waiting:CrashLoopBackOff") {
+		t.Fatalf("expected the init container state in the summary but got: %s", summary)
+	}
+}
+
+// TestCompactTerminationMessage validates long messages keep their tail without splitting a character.
+func TestCompactTerminationMessage(t *testing.T) {
+	// Collapse whitespace in a short message.
+	if compactTerminationMessage("panic:\n  boom") != "panic: boom" {
+		t.Fatalf("expected whitespace to collapse but got %q", compactTerminationMessage("panic:\n  boom"))
+	}
+
+	// Cut a long run of two-byte characters one byte into a character.
+	message := "x" + strings.Repeat("é", terminationMessageSummaryLength) + "!"
+	compact := compactTerminationMessage(message)
+	if !utf8.ValidString(compact) || !strings.HasPrefix(compact, "...é") || !strings.HasSuffix(compact, "é!") {
+		t.Fatalf("expected a valid tail of the message but got %q", compact)
+	}
+	if len(compact) > len("...")+terminationMessageSummaryLength {
+		t.Fatalf("expected at most %d bytes after the ellipsis but got %d", terminationMessageSummaryLength, len(compact)-len("..."))
+	}
+}
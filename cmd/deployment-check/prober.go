@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultProberImage is the curl image the prober pod runs.
+	defaultProberImage = "curlimages/curl:8.11.1"
+	// proberContainerName names the container in the prober pod.
+	proberContainerName = "prober"
+	// proberTimeout bounds the prober pod from creation to completion, including its image pull.
+	proberTimeout = time.Minute * 3
+)
+
+// proberPodName returns the name of the auxiliary prober pod.
+func (cfg *CheckConfig) proberPodName() string {
+	// Derive the name from the deployment so both are cleaned up together.
+	return cfg.CheckDeploymentName + "-prober"
+}
+
+// checkerNodeName returns the node the checker pod runs on, from the downward API or the checker pod itself.
+func (r *CheckRunner) checkerNodeName(ctx context.Context) (string, error) {
+	// Prefer an explicit node name from the downward API.
+	nodeName := os.Getenv("NODE_NAME")
+	if len(nodeName) != 0 {
+		return nodeName, nil
+	}
+
+	// Fall back to looking up the checker pod.
+	podName, err := checkerPodName()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the checker pod name: %w", err)
+	}
+	pod, err := r.client.CoreV1().Pods(checkerNamespace(r.cfg.CheckNamespace)).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checker pod %s: %w", podName, err)
+	}
+
+	return pod.Spec.NodeName, nil
+}
+
+// createProberPodConfig builds a run-once pod that requests the service from a node other than the checker's.
+func (r *CheckRunner) createProberPodConfig(serviceIP string, checkerNode string) *corev1.Pod {
+	// Request the primary service port with retries, printing the status code for the report.
+	address := r.cfg.EndpointScheme + "://" + net.JoinHostPort(serviceIP, strconv.Itoa(int(r.cfg.CheckLoadBalancerPort))) + r.cfg.HTTPPath
+	command := []string{"curl", "-sS", "-o", "/dev/null", "-w", "%{http_code}", "--max-time", "5", "--retry", "5", "--retry-all-errors", "--retry-delay", "2"}
+	if r.cfg.EndpointScheme == endpointSchemeHTTPS {
+		command = append(command, "-k")
+	}
+	for name, values := range r.cfg.HTTPHeaders {
+		for _, value := range values {
+			command = append(command, "-H", name+": "+value)
+		}
+	}
+	command = append(command, address)
+
+	// Run the prober once with the check's resources and hardening so admission treats it alike.
+	graceSeconds := int64(1)
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:                     proberContainerName,
+				Image:                    r.cfg.ProberImage,
+				Command:                  command,
+				ImagePullPolicy:          deploymentImagePullPolicy,
+				Resources:                r.createResourceRequirements(),
+				SecurityContext:          r.createContainerSecurityContext(),
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			}},
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &graceSeconds,
+			ServiceAccountName:            r.cfg.CheckServiceAccount,
+			Tolerations:                   r.cfg.CheckDeploymentTolerations,
+			PriorityClassName:             r.cfg.PriorityClassName,
+			HostNetwork:                   r.cfg.ProberHostNetwork,
+			SecurityContext:               r.createPodSecurityContext(),
+		},
+	}
+	if r.cfg.ProberHostNetwork {
+		pod.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+
+	// Keep the prober off the checker's node so the request crosses nodes.
+	if len(checkerNode) != 0 {
+		pod.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchFields: []corev1.NodeSelectorRequirement{{
+							Key:      "metadata.name",
+							Operator: corev1.NodeSelectorOpNotIn,
+							Values:   []string{checkerNode},
+						}},
+					}},
+				},
+			},
+		}
+	}
+	if len(r.cfg.CheckImagePullSecret) != 0 {
+		pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: r.cfg.CheckImagePullSecret}}
+	}
+	if r.cfg.AutopilotMode {
+		applyAutopilotPodSpec(&pod.Spec)
+	}
+	pod.Name = r.cfg.proberPodName()
+	pod.Namespace = r.cfg.CheckNamespace
+	pod.Labels = r.cfg.resourceLabels()
+	pod.Annotations = r.resourceAnnotations(nil)
+	pod.OwnerReferences = r.ownerReferences()
+
+	return pod
+}
+
+// verifyFromProber runs the prober pod against the service and requires it to get an acceptable response.
+func (r *CheckRunner) verifyFromProber(ctx context.Context, serviceIP string) error {
+	// Find the checker's node so the prober can avoid it.
+	checkerNode, err := r.checkerNodeName(ctx)
+	if err != nil {
+		log.Warnln("Failed to find the checker node; the prober may share it:", err.Error())
+	}
+
+	// Create the prober pod.
+	_, err = r.client.CoreV1().Pods(r.cfg.CheckNamespace).Create(ctx, r.createProberPodConfig(serviceIP, checkerNode), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create prober pod: %w", err)
+	}
+	log.Infoln("Created prober pod", r.cfg.proberPodName(), "with host network", r.cfg.ProberHostNetwork)
+	r.timeline.recordf("created prober pod %s (host network %t) avoiding node %s", r.cfg.proberPodName(), r.cfg.ProberHostNetwork, checkerNode)
+
+	// Wait for the prober to finish.
+	pod, err := r.waitForProberPod(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Read the status code the prober printed.
+	logs, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: proberContainerName}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read prober pod logs: %w", err)
+	}
+	output := strings.TrimSpace(string(logs))
+	if pod.Status.Phase != corev1.PodSucceeded {
+		return fmt.Errorf("prober pod on node %s could not reach the service: %s", pod.Spec.NodeName, proberFailureDetail(pod, output))
+	}
+	statusCode, err := strconv.Atoi(output)
+	if err != nil {
+		return fmt.Errorf("prober pod on node %s printed %q instead of a status code", pod.Spec.NodeName, output)
+	}
+	if !r.cfg.HTTPExpectedCodes.matches(statusCode) {
+		return fmt.Errorf("prober pod on node %s received %d from the service instead of %s", pod.Spec.NodeName, statusCode, r.cfg.HTTPExpectedCodes)
+	}
+
+	log.Infoln("Prober pod on node", pod.Spec.NodeName, "received", statusCode, "from the service.")
+	r.timeline.recordf("prober pod on node %s received %d from the service", pod.Spec.NodeName, statusCode)
+	return nil
+}
+
+// waitForProberPod polls the prober pod until it completes, reporting why it did not when the wait runs out.
+func (r *CheckRunner) waitForProberPod(ctx context.Context) (*corev1.Pod, error) {
+	// Poll the pod phase until it succeeds or fails.
+	deadline := time.Now().Add(proberTimeout)
+	lastState := "not observed"
+	for {
+		pod, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).Get(ctx, r.cfg.proberPodName(), metav1.GetOptions{})
+		if err != nil {
+			log.Debugln("Failed to fetch prober pod:", err.Error())
+		}
+		if err == nil {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				return pod, nil
+			}
+			lastState = proberFailureDetail(pod, "")
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("prober pod did not complete within %s: %s", proberTimeout, lastState)
+		}
+
+		// Wait before polling again.
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("context expired while waiting for the prober pod: %s", lastState)
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+// proberFailureDetail describes the prober pod's phase, conditions, container state, and output.
+func proberFailureDetail(pod *corev1.Pod, output string) string {
+	// Start with the phase and any unsatisfied condition, such as an unschedulable pod.
+	parts := []string{"phase: " + string(pod.Status.Phase)}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue && len(condition.Reason) != 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s %s", condition.Type, condition.Reason, condition.Message))
+		}
+	}
+
+	// Add the container state, which carries curl's exit code and error.
+	for _, status := range pod.Status.ContainerStatuses {
+		parts = append(parts, "container state: "+describeContainerState(status))
+	}
+	if len(output) != 0 {
+		parts = append(parts, "output: "+output)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// deleteProberPodAndWait removes the prober pod and waits for it to disappear.
+func (r *CheckRunner) deleteProberPodAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "prober pod", func(ctx context.Context) error {
+		return r.client.CoreV1().Pods(r.cfg.CheckNamespace).Delete(ctx, r.cfg.proberPodName(), metav1.DeleteOptions{})
+	}, r.proberPodExists)
+}
+
+// proberPodExists reports whether the prober pod is present.
+func (r *CheckRunner) proberPodExists(ctx context.Context) (bool, error) {
+	_, err := r.client.CoreV1().Pods(r.cfg.CheckNamespace).Get(ctx, r.cfg.proberPodName(), metav1.GetOptions{})
+	return objectFound(err)
+}
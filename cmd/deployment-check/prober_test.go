@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestProberPodConfig validates the prober runs once, avoids the checker node, and requests the service.
+func TestProberPodConfig(t *testing.T) {
+	// Build a host network prober for a checker on node-a.
+	runner := buildTestRunner()
+	runner.cfg.ProberImage = defaultProberImage
+	runner.cfg.ProberHostNetwork = true
+	pod := runner.createProberPodConfig("10.96.0.10", "node-a")
+	if pod.Spec.RestartPolicy != corev1.RestartPolicyNever || !pod.Spec.HostNetwork || pod.Spec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Fatalf("expected a run-once host network pod but got restart %s host network %t dns %s", pod.Spec.RestartPolicy, pod.Spec.HostNetwork, pod.Spec.DNSPolicy)
+	}
+
+	fields := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchFields
+	if len(fields) != 1 || fields[0].Operator != corev1.NodeSelectorOpNotIn || fields[0].Values[0] != "node-a" {
+		t.Fatalf("expected the prober to avoid node-a but got %v", fields)
+	}
+
+	command := pod.Spec.Containers[0].Command
+	if command[len(command)-1] != "http://10.96.0.10:80/" {
+		t.Fatalf("expected the prober to request the service but got %v", command)
+	}
+
+	// Leave the pod unconstrained when the checker node is unknown.
+	pod = runner.createProberPodConfig("10.96.0.10", "")
+	if pod.Spec.Affinity != nil || slices.Contains(pod.Spec.Containers[0].Command, "-k") {
+		t.Fatalf("expected no affinity and no insecure flag but got %v %v", pod.Spec.Affinity, pod.Spec.Containers[0].Command)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// projectedTokenVolumeName names the projected service account token volume.
+	projectedTokenVolumeName = "check-token"
+	// projectedTokenFile is the token file name inside the projected volume.
+	projectedTokenFile = "token"
+	// defaultProjectedTokenMountPath is where the projected token is mounted by default.
+	defaultProjectedTokenMountPath = "/var/run/secrets/deployment-check"
+	// defaultProjectedTokenExpirationSeconds is the default requested token lifetime.
+	defaultProjectedTokenExpirationSeconds = 3600
+	// minProjectedTokenExpirationSeconds is the shortest lifetime the API server accepts.
+	minProjectedTokenExpirationSeconds = 600
+)
+
+// createProjectedTokenVolume builds the projected service account token volume and its mount.
+func (r *CheckRunner) createProjectedTokenVolume() (corev1.Volume, corev1.VolumeMount) {
+	// Request a token for the configured audience and lifetime.
+	expiration := r.cfg.ProjectedTokenExpirationSeconds
+	volume := corev1.Volume{
+		Name: projectedTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{
+				ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+					Audience:          r.cfg.ProjectedTokenAudience,
+					ExpirationSeconds: &expiration,
+					Path:              projectedTokenFile,
+				},
+			}},
+		}},
+	}
+	mount := corev1.VolumeMount{Name: projectedTokenVolumeName, MountPath: r.cfg.ProjectedTokenMountPath, ReadOnly: true}
+
+	return volume, mount
+}
+
+// verifyProjectedToken execs into each running check pod to confirm the projected token is present for the configured audience.
+func (r *CheckRunner) verifyProjectedToken(ctx context.Context) error {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for token verification: %w", err)
+	}
+
+	// Read the token from every running pod and check its audience.
+	tokenPath := path.Join(r.cfg.ProjectedTokenMountPath, projectedTokenFile)
+	failures := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		execCtx, cancel := context.WithTimeout(ctx, volumeExecTimeout)
+		stdout, stderr, execErr := r.execInPod(execCtx, pod, r.cfg.CheckContainerName, []string{"cat", tokenPath})
+		cancel()
+		if execErr != nil {
+			detail := strings.TrimSpace(stderr)
+			if len(detail) == 0 {
+				detail = execErr.Error()
+			}
+			failures = append(failures, fmt.Sprintf("pod: %s node: %s error: %s", pod.Name, pod.Spec.NodeName, detail))
+			continue
+		}
+		audiences, err := tokenAudiences(stdout)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("pod: %s node: %s error: %s", pod.Name, pod.Spec.NodeName, err.Error()))
+			continue
+		}
+		if !slices.Contains(audiences, r.cfg.ProjectedTokenAudience) {
+			failures = append(failures, fmt.Sprintf("pod: %s node: %s token audiences %v do not include %s", pod.Name, pod.Spec.NodeName, audiences, r.cfg.ProjectedTokenAudience))
+		}
+	}
+	if len(failures) != 0 {
+		return fmt.Errorf("projected service account token is not usable: %s", strings.Join(failures, "; "))
+	}
+
+	log.Infoln("Verified the projected token for audience", r.cfg.ProjectedTokenAudience, "in", len(podList.Items), "check pod(s).")
+	r.timeline.recordf("verified projected token for audience %s in %d pod(s)", r.cfg.ProjectedTokenAudience, len(podList.Items))
+	return nil
+}
+
+// tokenAudiences decodes the audience claim from a JWT without verifying its signature.
+func tokenAudiences(token string) ([]string, error) {
+	// Split the token into header, payload, and signature.
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token file does not hold a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	// The audience claim may be a single string or a list.
+	claims := struct {
+		Audience json.RawMessage `json:"aud"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+	audiences := make([]string, 0)
+	err = json.Unmarshal(claims.Audience, &audiences)
+	if err == nil {
+		return audiences, nil
+	}
+	audience := ""
+	err = json.Unmarshal(claims.Audience, &audience)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token audience: %w", err)
+	}
+
+	return []string{audience}, nil
+}
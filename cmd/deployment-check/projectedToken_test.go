@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// TestTokenAudiences validates audience decoding for list and string claims.
+func TestTokenAudiences(t *testing.T) {
+	// Build unsigned tokens around each payload shape.
+	token := func(payload string) string {
+		return "e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+	}
+
+	audiences, err := tokenAudiences(token(`{"aud":["vault","api"]}`) + "\n")
+	if err != nil || len(audiences) != 2 || audiences[0] != "vault" {
+		t.Fatalf("expected the vault and api audiences but got %v: %v", audiences, err)
+	}
+
+	audiences, err = tokenAudiences(token(`{"aud":"vault"}`))
+	if err != nil || len(audiences) != 1 || audiences[0] != "vault" {
+		t.Fatalf("expected the vault audience but got %v: %v", audiences, err)
+	}
+
+	// Reject files that do not hold a JWT.
+	_, err = tokenAudiences("not-a-token")
+	if err == nil {
+		t.Fatalf("expected an error for a file without a JWT")
+	}
+}
+
+// TestProjectedTokenVolume validates the projected token is requested for the configured audience and mounted read-only.
+func TestProjectedTokenVolume(t *testing.T) {
+	// Request a token for a custom audience.
+	runner := buildTestRunner()
+	runner.cfg.ProjectedTokenAudience = "vault"
+	runner.cfg.ProjectedTokenExpirationSeconds = defaultProjectedTokenExpirationSeconds
+	runner.cfg.ProjectedTokenMountPath = defaultProjectedTokenMountPath
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Projected == nil {
+		t.Fatalf("expected one projected volume but got %v", podSpec.Volumes)
+	}
+
+	projection := podSpec.Volumes[0].Projected.Sources[0].ServiceAccountToken
+	if projection == nil || projection.Audience != "vault" || *projection.ExpirationSeconds != defaultProjectedTokenExpirationSeconds {
+		t.Fatalf("expected a token projection for vault but got %v", podSpec.Volumes[0].Projected.Sources)
+	}
+
+	if !podSpec.Containers[0].VolumeMounts[0].ReadOnly || podSpec.Containers[0].VolumeMounts[0].MountPath != defaultProjectedTokenMountPath {
+		t.Fatalf("expected a read-only token mount but got %v", podSpec.Containers[0].VolumeMounts)
+	}
+}
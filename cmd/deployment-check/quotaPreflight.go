@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// verifyQuotaHeadroom requires every ResourceQuota in the namespace to have room for the pods the run will create.
+func (r *CheckRunner) verifyQuotaHeadroom(ctx context.Context) error {
+	// List the quotas that apply to the check namespace.
+	quotaList, err := r.client.CoreV1().ResourceQuotas(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	if len(quotaList.Items) == 0 {
+		log.Infoln("No resource quotas in namespace", r.cfg.CheckNamespace+"; skipping quota preflight.")
+		return nil
+	}
+
+	// Compare the peak demand of the run with what each quota has left.
+	pods := r.peakCheckPods()
+	demand := r.quotaDemand(pods)
+	shortfalls := quotaShortfalls(quotaList.Items, demand)
+	if len(shortfalls) != 0 {
+		return fmt.Errorf("namespace %s lacks resource quota for %d check pod(s): %s", r.cfg.CheckNamespace, pods, strings.Join(shortfalls, "; "))
+	}
+
+	log.Infoln("Resource quotas in namespace", r.cfg.CheckNamespace, "have room for", pods, "check pod(s).")
+	r.timeline.recordf("%d resource quota(s) have room for %d check pod(s)", len(quotaList.Items), pods)
+	return nil
+}
+
+// peakCheckPods returns the most check pods the run will have at once, counting rollout surge, scaling, autoscaling, and the prober.
+func (r *CheckRunner) peakCheckPods() int {
+	// Start from the steady replica count.
+	peak := r.cfg.CheckDeploymentReplicas
+
+	// A rolling update or rollback surges above the replica count.
+	if r.cfg.RollingUpdate || r.cfg.RollbackVerify {
+		deployment := r.createDeploymentConfig(r.cfg.CheckImageURL)
+		peak = r.cfg.CheckDeploymentReplicas + int(deployment.Spec.Strategy.RollingUpdate.MaxSurge.IntVal)
+	}
+
+	// Scaling and autoscaling may go higher still.
+	if r.cfg.ScaleReplicas > peak {
+		peak = r.cfg.ScaleReplicas
+	}
+	if r.cfg.HPAVerify && int(r.cfg.HPAMaxReplicas) > peak {
+		peak = int(r.cfg.HPAMaxReplicas)
+	}
+
+	// The prober pod runs alongside the deployment.
+	if r.cfg.ProberVerify {
+		peak++
+	}
+
+	return peak
+}
+
+// quotaDemand returns the quota usage of the given number of check pods, keyed by quota resource name.
+func (r *CheckRunner) quotaDemand(pods int) corev1.ResourceList {
+	// Compute the effective requests and limits of one check pod.
+	deployment := r.createDeploymentConfig(r.cfg.CheckImageURL)
+	requests, limits := podEffectiveResources(deployment.Spec.Template.Spec)
+
+	// Scale the per-pod usage by the pod count under each name a quota may use.
+	demand := corev1.ResourceList{
+		corev1.ResourcePods:                           *resource.NewQuantity(int64(pods), resource.DecimalSI),
+		corev1.ResourceName("count/pods"):             *resource.NewQuantity(int64(pods), resource.DecimalSI),
+		corev1.ResourceName("count/deployments.apps"): *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	for name, quantity := range requests {
+		total := multiplyQuantity(quantity, pods)
+		demand[corev1.ResourceName("requests."+string(name))] = total
+		if name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage {
+			demand[name] = total.DeepCopy()
+		}
+	}
+	for name, quantity := range limits {
+		demand[corev1.ResourceName("limits."+string(name))] = multiplyQuantity(quantity, pods)
+	}
+
+	return demand
+}
+
+// podEffectiveResources sums a pod's container requests and limits, raising each to its largest init container as the scheduler and quota do.
+func podEffectiveResources(podSpec corev1.PodSpec) (corev1.ResourceList, corev1.ResourceList) {
+	// Sum the regular containers and native sidecars, which run for the life of the pod.
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range podSpec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(requests, container.Resources.Requests)
+			addResources(limits, container.Resources.Limits)
+		}
+	}
+
+	// Regular init containers run alone, so only the largest one can raise the total.
+	for _, container := range podSpec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			continue
+		}
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+
+	return requests, limits
+}
+
+// addResources adds each quantity in extra to total.
+func addResources(total corev1.ResourceList, extra corev1.ResourceList) {
+	// Accumulate per resource name.
+	for name, quantity := range extra {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// maxResources raises each quantity in total to at least the one in other.
+func maxResources(total corev1.ResourceList, other corev1.ResourceList) {
+	// Keep the larger quantity per resource name.
+	for name, quantity := range other {
+		current, found := total[name]
+		if !found || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// multiplyQuantity returns quantity times count.
+func multiplyQuantity(quantity resource.Quantity, count int) resource.Quantity {
+	// Multiply in milli-units so fractional CPU stays exact.
+	return *resource.NewMilliQuantity(quantity.MilliValue()*int64(count), quantity.Format)
+}
+
+// quotaShortfalls describes every quota resource whose remaining headroom is below the demand.
+func quotaShortfalls(quotas []corev1.ResourceQuota, demand corev1.ResourceList) []string {
+	// Compare each hard limit the demand touches with what is left of it.
+	shortfalls := make([]string, 0)
+	for _, quota := range quotas {
+		// Scoped quotas only cover some pods, so their applicability cannot be judged up front.
+		if len(quota.Spec.Scopes) != 0 || quota.Spec.ScopeSelector != nil {
+			log.Debugln("Skipping scoped resource quota", quota.Name, "in the quota preflight.")
+			continue
+		}
+
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			need, found := demand[corev1.ResourceName(name)]
+			if !found {
+				continue
+			}
+			hard := quota.Status.Hard[corev1.ResourceName(name)]
+			used := quota.Status.Used[corev1.ResourceName(name)]
+			remaining := hard.DeepCopy()
+			remaining.Sub(used)
+			if need.Cmp(remaining) > 0 {
+				shortfalls = append(shortfalls, fmt.Sprintf("quota %s %s needs %s but only %s of %s remains", quota.Name, name, need.String(), remaining.String(), hard.String()))
+			}
+		}
+	}
+
+	return shortfalls
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestQuotaShortfalls validates remaining headroom is compared with the demand and scoped quotas are skipped.
+func TestQuotaShortfalls(t *testing.T) {
+	// Build a quota with room for pods but not for the CPU requests.
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10"), corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4"), corev1.ResourceRequestsCPU: resource.MustParse("950m")},
+		},
+	}
+	demand := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2"), corev1.ResourceRequestsCPU: resource.MustParse("30m")}
+
+	shortfalls := quotaShortfalls([]corev1.ResourceQuota{quota}, demand)
+	if len(shortfalls) != 0 {
+		t.Fatalf("expected the demand to fit but got %v", shortfalls)
+	}
+
+	demand[corev1.ResourceRequestsCPU] = resource.MustParse("60m")
+	shortfalls = quotaShortfalls([]corev1.ResourceQuota{quota}, demand)
+	if len(shortfalls) != 1 || !strings.Contains(shortfalls[0], "requests.cpu needs 60m but only 50m of 1 remains") {
+		t.Fatalf("expected a requests.cpu shortfall but got %v", shortfalls)
+	}
+
+	// Scoped quotas may not cover the check pods, so they are skipped.
+	quota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	shortfalls = quotaShortfalls([]corev1.ResourceQuota{quota}, demand)
+	if len(shortfalls) != 0 {
+		t.Fatalf("expected scoped quotas to be skipped but got %v", shortfalls)
+	}
+}
+
+// TestQuotaDemand validates per-pod requests and limits are multiplied by the pod count, counting init containers.
+func TestQuotaDemand(t *testing.T) {
+	// Default check pods request 15m CPU and 20Mi memory each.
+	runner := buildTestRunner()
+	runner.cfg.CheckImageURL = "nginx:test"
+	demand := runner.quotaDemand(3)
+	requestsCPU := demand[corev1.ResourceRequestsCPU]
+	limitsMemory := demand[corev1.ResourceLimitsMemory]
+	pods := demand[corev1.ResourcePods]
+	if requestsCPU.MilliValue() != 45 || limitsMemory.Value() != 3*int64(defaultMemoryLimit) || pods.Value() != 3 {
+		t.Fatalf("expected 45m CPU, three memory limits, and 3 pods but got %s %s %s", requestsCPU.String(), limitsMemory.String(), pods.String())
+	}
+
+	// A regular init container larger than the app containers raises the effective request.
+	spec := corev1.PodSpec{
+		Containers:     []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}}},
+		InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}}}},
+	}
+	requests, _ := podEffectiveResources(spec)
+	cpu := requests[corev1.ResourceCPU]
+	if cpu.MilliValue() != 250 {
+		t.Fatalf("expected the init container to raise the request to 250m but got %s", cpu.String())
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// readinessGateReason is the reason set on the readiness gate condition.
+	readinessGateReason = "DeploymentCheck"
+	// readinessGatePollInterval is the longest pause between passes of the readiness gate controller when no pod changes.
+	readinessGatePollInterval = time.Second * 2
+)
+
+// readinessGateController records what the checker's readiness gate loop observed.
+type readinessGateController struct {
+	// mu guards the observation sets.
+	mu sync.Mutex
+	// gated holds the pods whose gate condition the checker set.
+	gated map[string]bool
+	// early holds the pods that were Ready before their gate condition was set.
+	early map[string]bool
+}
+
+// newReadinessGateController builds an empty readiness gate controller.
+func newReadinessGateController() *readinessGateController {
+	return &readinessGateController{
+		gated: make(map[string]bool),
+		early: make(map[string]bool),
+	}
+}
+
+// podConditionStatus returns the status of a pod condition, or Unknown when it is absent.
+func podConditionStatus(pod corev1.Pod, conditionType corev1.PodConditionType) corev1.ConditionStatus {
+	// Scan pod conditions for the type.
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+
+	return corev1.ConditionUnknown
+}
+
+// readinessGatePatch builds the status patch that marks a readiness gate condition true.
+func readinessGatePatch(conditionType string, now time.Time) ([]byte, error) {
+	// Conditions merge by type, so the patch leaves the kubelet's conditions alone.
+	condition := map[string]any{
+		"type":               conditionType,
+		"status":             corev1.ConditionTrue,
+		"reason":             readinessGateReason,
+		"message":            "set by the deployment check after its containers became ready",
+		"lastTransitionTime": metav1.NewTime(now),
+	}
+
+	return json.Marshal(map[string]any{"status": map[string]any{"conditions": []any{condition}}})
+}
+
+// runReadinessGateController sets the readiness gate on every check pod whose containers are ready until ctx ends.
+func (r *CheckRunner) runReadinessGateController(ctx context.Context, gate *readinessGateController) {
+	conditionType := corev1.PodConditionType(r.cfg.ReadinessGate)
+	podChanges, unsubscribe := r.pods.subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// Read pods for the current run from the informer cache.
+		pods, err := r.cachedDeploymentPods(ctx)
+		if err != nil {
+			log.Debugln("Failed to list deployment pods for the readiness gate:", err.Error())
+		}
+
+		// Open the gate on each pod whose containers are ready, noting pods the kubelet let through early.
+		if err == nil {
+			for _, pod := range pods {
+				if pod.DeletionTimestamp != nil || podConditionStatus(pod, conditionType) == corev1.ConditionTrue {
+					continue
+				}
+				if podConditionStatus(pod, corev1.ContainersReady) != corev1.ConditionTrue {
+					continue
+				}
+				if podIsReady(pod) {
+					log.Warnln("Pod", pod.Name, "is Ready before its readiness gate", r.cfg.ReadinessGate, "was set.")
+					r.timeline.recordf("pod %s was Ready before readiness gate %s was set", pod.Name, r.cfg.ReadinessGate)
+					gate.mu.Lock()
+					gate.early[pod.Name] = true
+					gate.mu.Unlock()
+				}
+				r.setReadinessGate(ctx, gate, pod.Name)
+			}
+		}
+
+		// Wake on pod changes, re-checking periodically in case a patch failed.
+		select {
+		case <-ctx.Done():
+			return
+		case <-podChanges:
+		case <-time.After(readinessGatePollInterval):
+		}
+	}
+}
+
+// setReadinessGate patches the readiness gate condition true on one pod.
+func (r *CheckRunner) setReadinessGate(ctx context.Context, gate *readinessGateController, podName string) {
+	// Build and apply the status patch.
+	patch, err := readinessGatePatch(r.cfg.ReadinessGate, time.Now())
+	if err != nil {
+		log.Warnln("Failed to build readiness gate patch:", err.Error())
+		return
+	}
+	_, err = r.client.CoreV1().Pods(r.cfg.CheckNamespace).Patch(ctx, podName, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		log.Warnln("Failed to set readiness gate on pod", podName+":", err.Error())
+		return
+	}
+
+	log.Infoln("Set readiness gate", r.cfg.ReadinessGate, "on pod", podName)
+	r.timeline.recordf("set readiness gate %s on pod %s", r.cfg.ReadinessGate, podName)
+	gate.mu.Lock()
+	gate.gated[podName] = true
+	gate.mu.Unlock()
+}
+
+// verifyReadinessGate requires every ready pod to have been held back until the checker opened its gate.
+func (r *CheckRunner) verifyReadinessGate(ctx context.Context, gate *readinessGateController) error {
+	// Fail when any pod turned Ready without waiting for its gate.
+	gate.mu.Lock()
+	early := sortedKeys(gate.early)
+	gated := copyBoolMap(gate.gated)
+	gate.mu.Unlock()
+	if len(early) != 0 {
+		return fmt.Errorf("%d pod(s) became Ready before readiness gate %s was set, so the kubelet is ignoring readiness gates: [%s]", len(early), r.cfg.ReadinessGate, strings.Join(early, ", "))
+	}
+
+	// Every ready pod must carry the gate the checker set.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	ungated := make([]string, 0)
+	ready := 0
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil || !podIsReady(pod) {
+			continue
+		}
+		ready++
+		if podConditionStatus(pod, corev1.PodConditionType(r.cfg.ReadinessGate)) != corev1.ConditionTrue || !gated[pod.Name] {
+			ungated = append(ungated, pod.Name)
+		}
+	}
+	if len(ungated) != 0 {
+		return fmt.Errorf("%d ready pod(s) were not gated by the checker on %s: [%s]", len(ungated), r.cfg.ReadinessGate, strings.Join(ungated, ", "))
+	}
+
+	log.Infoln("All", ready, "ready pod(s) waited for readiness gate", r.cfg.ReadinessGate)
+	r.timeline.recordf("all %d ready pod(s) waited for readiness gate %s", ready, r.cfg.ReadinessGate)
+	return nil
+}
+
+// copyBoolMap returns a shallow copy of a set.
+func copyBoolMap(source map[string]bool) map[string]bool {
+	// Copy each member into a new map.
+	copied := make(map[string]bool, len(source))
+	for key, value := range source {
+		copied[key] = value
+	}
+
+	return copied
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestReadinessGatePatch validates the status patch sets only the gate condition true.
+func TestReadinessGatePatch(t *testing.T) {
+	// Build and decode the patch.
+	patch, err := readinessGatePatch("example.com/ready", time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("failed to build patch: %v", err)
+	}
+	decoded := struct {
+		Status corev1.PodStatus `json:"status"`
+	}{}
+	err = json.Unmarshal(patch, &decoded)
+	if err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+
+	conditions := decoded.Status.Conditions
+	if len(conditions) != 1 || conditions[0].Type != "example.com/ready" || conditions[0].Status != corev1.ConditionTrue {
+		t.Fatalf("expected one true example.com/ready condition but got %v", conditions)
+	}
+}
+
+// TestReadinessGateSpec validates the gate is only added to the pod template when configured.
+func TestReadinessGateSpec(t *testing.T) {
+	// Build the default deployment without a gate.
+	runner := buildTestRunner()
+	deployment := runner.createDeploymentConfig("nginx:test")
+	if len(deployment.Spec.Template.Spec.ReadinessGates) != 0 {
+		t.Fatalf("expected no readiness gates but got %v", deployment.Spec.Template.Spec.ReadinessGates)
+	}
+
+	// Configure a gate.
+	runner.cfg.ReadinessGate = "example.com/ready"
+	deployment = runner.createDeploymentConfig("nginx:test")
+	gates := deployment.Spec.Template.Spec.ReadinessGates
+	if len(gates) != 1 || gates[0].ConditionType != "example.com/ready" {
+		t.Fatalf("expected the example.com/ready gate but got %v", gates)
+	}
+}
+
+// TestPodConditionStatus validates condition lookup and the Unknown default.
+func TestPodConditionStatus(t *testing.T) {
+	// Build a pod with ready containers only.
+	pod := corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}}}}
+
+	if podConditionStatus(pod, corev1.ContainersReady) != corev1.ConditionTrue {
+		t.Fatalf("expected ContainersReady to be true")
+	}
+	if podConditionStatus(pod, "example.com/ready") != corev1.ConditionUnknown {
+		t.Fatalf("expected a missing condition to be unknown")
+	}
+}
+
+// TestRunReadinessGateController validates the gate is opened on a pod whose containers become ready after the controller starts.
+func TestRunReadinessGateController(t *testing.T) {
+	// Start the controller before any pod is ready.
+	runner := buildTestRunner()
+	runner.cfg.ReadinessGate = "example.com/ready"
+	pod := probeTestPod(runner, "gated", false)
+	runner.client = fake.NewClientset(pod)
+	defer runner.stopInformers()
+	gate := newReadinessGateController()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	go runner.runReadinessGateController(ctx, gate)
+
+	// Report the containers ready, as the kubelet would.
+	time.Sleep(time.Millisecond * 100)
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue})
+	_, err := runner.client.CoreV1().Pods(runner.cfg.CheckNamespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("failed to update pod status: %v", err)
+	}
+
+	// The pod change wakes the controller well before its periodic pass.
+	for {
+		gate.mu.Lock()
+		gated := gate.gated["gated"]
+		gate.mu.Unlock()
+		if gated {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("expected the readiness gate to be opened on the pod")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if gate.early["gated"] {
+		t.Fatalf("expected the pod not to be reported ready before its gate")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// oldReplicaSetDrainTimeout bounds how long old ReplicaSets may take to scale down after a roll.
+	oldReplicaSetDrainTimeout = time.Minute * 2
+	// deploymentRevisionAnnotation is set by the deployment controller on deployments and ReplicaSets.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	// podTemplateHashLabel ties pods to the ReplicaSet that created them.
+	podTemplateHashLabel = "pod-template-hash"
+)
+
+// verifyOldReplicaSetsDrained waits for superseded ReplicaSets to reach zero replicas with no surviving pods.
+func (r *CheckRunner) verifyOldReplicaSetsDrained(ctx context.Context, deployment *appsv1.Deployment) error {
+	// Guard against nil inputs.
+	if deployment == nil {
+		return fmt.Errorf("deployment reference was nil")
+	}
+
+	// Poll until the old ReplicaSets drain or the timeout passes.
+	log.Infoln("Verifying old ReplicaSets for deployment", deployment.Name, "scaled to zero.")
+	deadline := time.Now().Add(oldReplicaSetDrainTimeout)
+	currentRevision := deployment.Annotations[deploymentRevisionAnnotation]
+	for {
+		lingering, err := r.findLingeringOldReplicaSets(ctx, currentRevision)
+		if err != nil {
+			log.Warnln("Failed to inspect old ReplicaSets:", err.Error())
+		}
+		if err == nil && len(lingering) == 0 {
+			log.Infoln("Old ReplicaSets for deployment", deployment.Name, "have scaled to zero.")
+			r.timeline.record("old ReplicaSets scaled to zero with no surviving pods")
+			return nil
+		}
+
+		// Report what is still hanging around once the timeout passes.
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("failed to verify old ReplicaSets scaled to zero: %w", err)
+			}
+			return fmt.Errorf("old ReplicaSets did not scale to zero within %s: %s", oldReplicaSetDrainTimeout, strings.Join(lingering, "; "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for old ReplicaSets to scale to zero")
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+// findLingeringOldReplicaSets describes old ReplicaSets and pods that have not yet gone away.
+func (r *CheckRunner) findLingeringOldReplicaSets(ctx context.Context, currentRevision string) ([]string, error) {
+	// List ReplicaSets created for this run.
+	labelSelector := r.runSelector()
+	replicaSetList, err := r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect old ReplicaSets that still want or have replicas.
+	lingering := make([]string, 0)
+	oldHashes := make(map[string]string)
+	for _, replicaSet := range replicaSetList.Items {
+		if replicaSet.Annotations[deploymentRevisionAnnotation] == currentRevision {
+			continue
+		}
+		oldHashes[replicaSet.Labels[podTemplateHashLabel]] = replicaSet.Name
+
+		desired := int32(0)
+		if replicaSet.Spec.Replicas != nil {
+			desired = *replicaSet.Spec.Replicas
+		}
+		if desired != 0 || replicaSet.Status.Replicas != 0 {
+			lingering = append(lingering, fmt.Sprintf("replicaset: %s desired: %d current: %d", replicaSet.Name, desired, replicaSet.Status.Replicas))
+		}
+	}
+
+	// Collect pods that still belong to an old ReplicaSet.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range podList.Items {
+		replicaSetName, old := oldHashes[pod.Labels[podTemplateHashLabel]]
+		if !old {
+			continue
+		}
+		state := "running"
+		if pod.DeletionTimestamp != nil {
+			state = "terminating since " + pod.DeletionTimestamp.UTC().Format(time.RFC3339)
+		}
+		lingering = append(lingering, fmt.Sprintf("pod: %s replicaset: %s node: %s state: %s finalizers: %v", pod.Name, replicaSetName, pod.Spec.NodeName, state, pod.Finalizers))
+	}
+
+	return lingering, nil
+}
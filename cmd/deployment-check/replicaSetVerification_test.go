@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestFindLingeringOldReplicaSets validates old ReplicaSets that still want replicas and their surviving pods are reported.
+func TestFindLingeringOldReplicaSets(t *testing.T) {
+	// Seed the current ReplicaSet, a drained old one, and an old one still scaled up.
+	runner := buildTestRunner()
+	labels := func(hash string) map[string]string {
+		return map[string]string{deploymentLabelKey: deploymentLabelValueBase + strconv.FormatInt(runner.now.Unix(), 10), podTemplateHashLabel: hash}
+	}
+	replicaSet := func(name string, revision string, desired int32) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: runner.cfg.CheckNamespace, Labels: labels(name), Annotations: map[string]string{deploymentRevisionAnnotation: revision}},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: &desired},
+		}
+	}
+	current := replicaSet("current", "3", 2)
+	drained := replicaSet("drained", "2", 0)
+	scaled := replicaSet("scaled", "1", 1)
+
+	// Leave a terminating pod behind from the drained ReplicaSet and a pod from the current one.
+	deleted := metav1.NewTime(time.Now())
+	survivor := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "survivor", Namespace: runner.cfg.CheckNamespace, Labels: labels("drained"), DeletionTimestamp: &deleted, Finalizers: []string{"example.com/hold"}}}
+	fresh := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "fresh", Namespace: runner.cfg.CheckNamespace, Labels: labels("current")}}
+	runner.client = fake.NewClientset(current, drained, scaled, survivor, fresh)
+
+	lingering, err := runner.findLingeringOldReplicaSets(context.Background(), "3")
+	if err != nil {
+		t.Fatalf("unexpected error finding old ReplicaSets: %v", err)
+	}
+	report := strings.Join(lingering, "; ")
+	if len(lingering) != 2 || !strings.Contains(report, "replicaset: scaled desired: 1") || !strings.Contains(report, "pod: survivor replicaset: drained") {
+		t.Fatalf("expected the scaled ReplicaSet and the surviving pod but got %v", lingering)
+	}
+	if !strings.Contains(report, "terminating since") {
+		t.Fatalf("expected the surviving pod to be described as terminating but got %v", lingering)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// replicaTrafficTimeout bounds the wait for every ready replica to serve a request.
+	replicaTrafficTimeout = time.Minute * 2
+	// sessionAffinityRequests is the number of requests that must land on one backend.
+	sessionAffinityRequests = 20
+)
+
+// verifyEveryReplicaServes requires a response from every ready replica, through the service when pods identify themselves and directly otherwise.
+func (r *CheckRunner) verifyEveryReplicaServes(ctx context.Context, serviceIP string) error {
+	// Without an identifying header, the only way to reach each replica is its pod IP.
+	if len(r.cfg.BackendHeader) == 0 {
+		return r.verifyEveryReplicaDirectly(ctx)
+	}
+
+	// Collect the ready pods that must answer, identified by name or IP.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	identities := podIdentities(podList.Items)
+	pending := make(map[string]bool)
+	for _, name := range identities {
+		pending[name] = true
+	}
+	if len(pending) != r.cfg.CheckDeploymentReplicas {
+		return fmt.Errorf("expected %d ready pod(s) but found %d", r.cfg.CheckDeploymentReplicas, len(pending))
+	}
+
+	// Request the service over fresh connections until every pod has answered or the wait runs out.
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.freshConnectionTransport()}
+	address := r.cfg.EndpointScheme + "://" + net.JoinHostPort(serviceIP, strconv.Itoa(int(r.cfg.CheckLoadBalancerPort))) + r.cfg.HTTPPath
+	deadline := time.Now().Add(replicaTrafficTimeout)
+	requests := 0
+	unknown := make(map[string]bool)
+	for len(pending) != 0 {
+		if time.Now().After(deadline) {
+			detail := fmt.Sprintf("%d ready replica(s) never served traffic after %d request(s): [%s]", len(pending), requests, strings.Join(sortedKeys(pending), ", "))
+			if len(unknown) != 0 {
+				detail = detail + fmt.Sprintf("; unrecognized %s values [%s]", r.cfg.BackendHeader, strings.Join(sortedKeys(unknown), ", "))
+			}
+			return fmt.Errorf("%s", detail)
+		}
+		requests++
+		backend, err := r.requestBackend(ctx, client, address)
+		if err != nil {
+			log.Debugln("Request for replica coverage failed:", err.Error())
+		}
+		if err == nil {
+			name, known := identities[backend]
+			if !known {
+				unknown[backend] = true
+			}
+			if known && pending[name] {
+				log.Infoln("Replica", name, "served request", requests)
+				delete(pending, name)
+			}
+		}
+
+		// Pause briefly so the requests spread over the backends.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for every replica to serve traffic")
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	log.Infoln("Every ready replica served traffic through the service within", requests, "request(s).")
+	r.timeline.recordf("every ready replica served traffic through the service within %d request(s)", requests)
+	return nil
+}
+
+// verifyEveryReplicaDirectly requests every ready pod on its IP and reports each one that never answers.
+func (r *CheckRunner) verifyEveryReplicaDirectly(ctx context.Context) error {
+	// Retry the whole set until every pod answers or the wait runs out.
+	deadline := time.Now().Add(replicaTrafficTimeout)
+	for {
+		results, err := r.probePodEndpoints(ctx)
+		if err != nil {
+			return err
+		}
+		failures := make([]string, 0)
+		for _, result := range results {
+			if result.err != nil {
+				failures = append(failures, fmt.Sprintf("pod: %s node: %s address: %s error: %s", result.pod, result.node, result.address, result.err.Error()))
+			}
+		}
+		if len(results) == r.cfg.CheckDeploymentReplicas && len(failures) == 0 {
+			log.Infoln("Every ready replica served a direct request.")
+			r.timeline.recordf("every ready replica served a direct request")
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if len(failures) == 0 {
+				return fmt.Errorf("expected %d ready replica(s) to request but found %d", r.cfg.CheckDeploymentReplicas, len(results))
+			}
+			return fmt.Errorf("%d of %d ready replica(s) never served traffic: %s", len(failures), len(results), strings.Join(failures, "; "))
+		}
+
+		// Wait before requesting the pods again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for every replica to serve traffic")
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// requestBackend issues a single GET and returns the identifying header of the backend that answered.
+func (r *CheckRunner) requestBackend(ctx context.Context, client *http.Client, address string) (string, error) {
+	// Build the request bound to the caller context.
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return "", err
+	}
+	r.applyRequestHeaders(request)
+
+	// Perform the request and require an acceptable status and the identifying header.
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	closeErr := response.Body.Close()
+	if closeErr != nil {
+		log.Debugln("Failed to close response body:", closeErr.Error())
+	}
+	if !r.cfg.HTTPExpectedCodes.matches(response.StatusCode) {
+		return "", fmt.Errorf("received %d", response.StatusCode)
+	}
+	backend := strings.TrimSpace(response.Header.Get(r.cfg.BackendHeader))
+	if len(backend) == 0 {
+		return "", fmt.Errorf("response has no %s header", r.cfg.BackendHeader)
+	}
+
+	return backend, nil
+}
+
+// freshConnectionTransport returns the endpoint transport with keep-alives off so each request can reach a different backend.
+func (r *CheckRunner) freshConnectionTransport() http.RoundTripper {
+	// Reusing a connection would pin every request to one pod.
+	transport, ok := r.transport.(*http.Transport)
+	if !ok {
+		return r.transport
+	}
+	fresh := transport.Clone()
+	fresh.DisableKeepAlives = true
+
+	return fresh
+}
+
+// podIdentities maps the names and IPs of ready pods to the pod name, for matching identifying headers.
+func podIdentities(pods []corev1.Pod) map[string]string {
+	// Index each ready pod by name and IP.
+	identities := make(map[string]string)
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !podIsReady(pod) {
+			continue
+		}
+		identities[pod.Name] = pod.Name
+		if len(pod.Status.PodIP) != 0 {
+			identities[pod.Status.PodIP] = pod.Name
+		}
+	}
+
+	return identities
+}
+
+// verifySessionAffinity requires repeated requests from the checker to land on one backend pod.
+func (r *CheckRunner) verifySessionAffinity(ctx context.Context, serviceIP string) error {
+	// Request the service over fresh connections so only kube-proxy affinity can keep them together.
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.freshConnectionTransport()}
+	address := r.cfg.EndpointScheme + "://" + net.JoinHostPort(serviceIP, strconv.Itoa(int(r.cfg.CheckLoadBalancerPort))) + r.cfg.HTTPPath
+	deadline := time.Now().Add(replicaTrafficTimeout)
+	backends := make(map[string]int)
+	answered := 0
+	for answered < sessionAffinityRequests {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("only %d of %d request(s) succeeded within %s", answered, sessionAffinityRequests, replicaTrafficTimeout)
+		}
+		backend, err := r.requestBackend(ctx, client, address)
+		if err != nil {
+			log.Debugln("Request for session affinity failed:", err.Error())
+		}
+		if err == nil {
+			answered++
+			backends[backend]++
+		}
+
+		// Pause briefly between requests.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while verifying session affinity")
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	// Every request must have been served by the same pod.
+	if len(backends) != 1 {
+		spread := make([]string, 0, len(backends))
+		for backend, count := range backends {
+			spread = append(spread, fmt.Sprintf("%s=%d", backend, count))
+		}
+		sort.Strings(spread)
+		return fmt.Errorf("%d request(s) with ClientIP session affinity were spread across %d backends: %s", answered, len(backends), strings.Join(spread, ", "))
+	}
+
+	log.Infoln("All", answered, "request(s) stuck to one backend with ClientIP session affinity.")
+	r.timeline.recordf("all %d request(s) stuck to one backend with ClientIP session affinity", answered)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPodIdentities validates only ready, live pods are indexed by name and IP.
+func TestPodIdentities(t *testing.T) {
+	// Build a ready pod, an unready pod, and a terminating pod.
+	readyCondition := []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	deleted := metav1.NewTime(time.Now())
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ready"}, Status: corev1.PodStatus{PodIP: "10.0.0.1", Conditions: readyCondition}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unready"}, Status: corev1.PodStatus{PodIP: "10.0.0.2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "terminating", DeletionTimestamp: &deleted}, Status: corev1.PodStatus{PodIP: "10.0.0.3", Conditions: readyCondition}},
+	}
+
+	identities := podIdentities(pods)
+	if len(identities) != 2 || identities["ready"] != "ready" || identities["10.0.0.1"] != "ready" {
+		t.Fatalf("expected only the ready pod by name and IP but got %v", identities)
+	}
+}
+
+// TestRequestBackend validates the identifying header is read from an acceptable response.
+func TestRequestBackend(t *testing.T) {
+	// Serve a response naming the backend pod.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Pod-Name", "deployment-check-abc")
+	}))
+	defer server.Close()
+
+	runner := buildTestRunner()
+	runner.cfg.BackendHeader = "X-Pod-Name"
+	backend, err := runner.requestBackend(context.Background(), server.Client(), server.URL)
+	if err != nil || backend != "deployment-check-abc" {
+		t.Fatalf("expected backend deployment-check-abc but got %q with error %v", backend, err)
+	}
+
+	// Reject a response without the header.
+	runner.cfg.BackendHeader = "X-Missing"
+	_, err = runner.requestBackend(context.Background(), server.Client(), server.URL)
+	if err == nil {
+		t.Fatalf("expected an error for a response without the identifying header")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// failureReport builds the error list sent to Kuberhealthy for a failed run.
+func (r *CheckRunner) failureReport(err error) []string {
+	// Lead with the error itself so the headline stays readable.
+	report := []string{err.Error()}
+
+	// Tag the failure class so alerts can be routed to the owning team.
+	report = append(report, "failure class: "+string(classifyFailure(err)))
+
+	// Name the build so fleet operators can tell which version failed.
+	report = append(report, "check version: "+versionString())
+
+	// Show where the time budget went.
+	report = append(report, "phase timings: "+r.phases.summary())
+
+	// Append the run timeline so the failure can be reconstructed.
+	for _, line := range r.timeline.lines() {
+		report = append(report, "timeline: "+line)
+	}
+
+	return report
+}
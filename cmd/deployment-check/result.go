@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// resultOutputStdout is the RESULT_OUTPUT_PATH value that writes the result to stdout.
+	resultOutputStdout = "-"
+	// resultStatusSuccess marks a passing result.
+	resultStatusSuccess = "success"
+	// resultStatusFailure marks a failing result.
+	resultStatusFailure = "failure"
+)
+
+// checkResult is the machine-readable document written at the end of the check.
+type checkResult struct {
+	// Status is success or failure.
+	Status string `json:"status"`
+	// Version describes the check build.
+	Version string `json:"version"`
+	// StartedAt is when the check started.
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the result was written.
+	FinishedAt time.Time `json:"finished_at"`
+	// Errors holds the lines reported to Kuberhealthy on failure.
+	Errors []string `json:"errors,omitempty"`
+	// Runs describes each deploy and verify cycle, one per node pool or namespace when several are used.
+	Runs []runResult `json:"runs"`
+}
+
+// runResult describes one deploy and verify cycle.
+type runResult struct {
+	// NodePool is the node pool the run was pinned to, if any.
+	NodePool string `json:"node_pool,omitempty"`
+	// Namespace is where the check resources were created.
+	Namespace string `json:"namespace"`
+	// Deployment is the check deployment name.
+	Deployment string `json:"deployment"`
+	// Status is success or failure.
+	Status string `json:"status"`
+	// FailedStage is the phase the run failed in.
+	FailedStage string `json:"failed_stage,omitempty"`
+	// FailureClass is the coarse failure category.
+	FailureClass string `json:"failure_class,omitempty"`
+	// Error is the full failure message.
+	Error string `json:"error,omitempty"`
+	// Phases lists how long each phase took.
+	Phases []phaseTiming `json:"phases"`
+	// PodSummary describes the check pods at the time of failure.
+	PodSummary string `json:"pod_summary,omitempty"`
+}
+
+// runResult summarizes the finished run for the result document.
+func (r *CheckRunner) runResult(runErr error, nodePool string) runResult {
+	// Describe the run itself.
+	result := runResult{
+		NodePool:   nodePool,
+		Namespace:  r.cfg.CheckNamespace,
+		Deployment: r.cfg.CheckDeploymentName,
+		Status:     resultStatusSuccess,
+		Phases:     r.phases.timings(),
+	}
+	if runErr == nil {
+		return result
+	}
+
+	// Add where and why the run failed, reusing the pod summary captured before cleanup.
+	result.Status = resultStatusFailure
+	result.FailedStage = r.phases.failedPhase()
+	result.FailureClass = string(classifyFailure(runErr))
+	result.Error = runErr.Error()
+	result.PodSummary = r.podSummary
+	if len(result.PodSummary) == 0 {
+		result.PodSummary = r.deploymentPodSummary(context.Background())
+	}
+
+	return result
+}
+
+// writeCheckResult writes the result document when an output path is configured, logging rather than failing on errors.
+func writeCheckResult(cfg *CheckConfig, started time.Time, report []string, runs []runResult) {
+	// Skip when no output is configured.
+	if len(cfg.ResultOutputPath) == 0 {
+		return
+	}
+
+	// Assemble the document.
+	result := checkResult{
+		Status:     resultStatusSuccess,
+		Version:    versionString(),
+		StartedAt:  started.UTC(),
+		FinishedAt: time.Now().UTC(),
+		Errors:     report,
+		Runs:       runs,
+	}
+	if len(report) != 0 {
+		result.Status = resultStatusFailure
+	}
+
+	err := writeResultDocument(cfg.ResultOutputPath, result)
+	if err != nil {
+		log.Warnln("Failed to write the check result:", err.Error())
+		return
+	}
+	log.Infoln("Wrote the check result to", cfg.ResultOutputPath+".")
+}
+
+// writeResultDocument encodes the result to stdout or a file.
+func writeResultDocument(path string, result checkResult) error {
+	// Encode with indentation so the file is readable by hand too.
+	encoded, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode check result: %w", err)
+	}
+	encoded = append(encoded, '\n')
+
+	// Write to stdout or the file.
+	if path == resultOutputStdout {
+		_, err = os.Stdout.Write(encoded)
+		return err
+	}
+	err = os.WriteFile(path, encoded, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write check result to %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWriteCheckResult validates the result document records the status, errors, and per-run details.
+func TestWriteCheckResult(t *testing.T) {
+	// Write a failed single-run result to a file.
+	path := filepath.Join(t.TempDir(), "result.json")
+	cfg := &CheckConfig{ResultOutputPath: path, CheckNamespace: "kuberhealthy", CheckDeploymentName: "deployment-deployment"}
+	r := &CheckRunner{cfg: cfg, phases: &phaseTimer{}, podSummary: "pod a: Pending"}
+	r.phases.begin("deployment_create")
+	runErr := classify(failureClassRollout, errors.New("deployment never became available"))
+	writeCheckResult(cfg, time.Unix(1700000000, 0), []string{runErr.Error()}, []runResult{r.runResult(runErr, "")})
+
+	// Read it back.
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read result: %v", err)
+	}
+	result := checkResult{}
+	err = json.Unmarshal(encoded, &result)
+	if err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if result.Status != resultStatusFailure || len(result.Errors) != 1 || len(result.Runs) != 1 {
+		t.Fatalf("expected one failed run with one error but got %s", encoded)
+	}
+	run := result.Runs[0]
+	if run.FailedStage != "deployment_create" || run.FailureClass != string(failureClassRollout) || run.PodSummary != "pod a: Pending" || len(run.Phases) != 1 {
+		t.Fatalf("expected the failed stage, class, pod summary, and phases to be recorded but got %+v", run)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// rollbackDeploymentAndVerify rolls the deployment back to its previous revision and verifies it serves again.
+func (r *CheckRunner) rollbackDeploymentAndVerify(ctx context.Context) error {
+	// Compute the deadline for rollback operations.
+	deadline := time.Now().Add(r.cfg.CheckTimeLimit)
+
+	// Restore the previous revision's template.
+	rolledBack, previousReplicaSet, err := r.rollbackDeploymentAndWait(ctx, deadline)
+	if err != nil {
+		return err
+	}
+	log.Infoln("Rolled back deployment in", rolledBack.Namespace, "namespace:", rolledBack.Name)
+
+	// Confirm the controller revived the original ReplicaSet instead of creating a new one.
+	err = r.verifyRollbackReplicaSet(ctx, rolledBack, previousReplicaSet)
+	if err != nil {
+		return classify(failureClassRollout, err)
+	}
+
+	// The previous revision ran the image before the last roll step.
+	expectedImage := r.cfg.CheckImageURL
+	steps := len(r.cfg.CheckImageRollSequence)
+	if steps > 1 {
+		expectedImage = r.cfg.CheckImageRollSequence[steps-2]
+	}
+
+	return r.verifyRolledDeployment(ctx, rolledBack, expectedImage)
+}
+
+// rollbackDeploymentAndWait applies the previous revision's pod template, like kubectl rollout undo, and waits for it.
+func (r *CheckRunner) rollbackDeploymentAndWait(ctx context.Context, deadline time.Time) (*appsv1.Deployment, string, error) {
+	// Fetch the current deployment for its revision.
+	current, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch deployment for rollback: %w", err)
+	}
+
+	// Find the ReplicaSet recorded for the previous revision.
+	replicaSetList, err := r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: r.runSelector(),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list ReplicaSets for rollback: %w", err)
+	}
+	previous, err := previousRevisionReplicaSet(replicaSetList.Items, current.Annotations[deploymentRevisionAnnotation])
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Copy the previous template without the hash label the controller adds.
+	template := previous.Spec.Template.DeepCopy()
+	delete(template.Labels, podTemplateHashLabel)
+	log.Infoln("Rolling back deployment", current.Name, "to revision", previous.Annotations[deploymentRevisionAnnotation], "from ReplicaSet", previous.Name)
+
+	// Submit the rollback, re-reading the deployment on conflicts.
+	var deployment *appsv1.Deployment
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to fetch deployment for rollback: %w", err)
+		}
+		latest.Spec.Template = *template
+		deployment, err = r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Update(ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to roll back deployment: %w", err)
+	}
+	r.timeline.recordf("submitted rollback of deployment %s to revision %s", deployment.Name, previous.Annotations[deploymentRevisionAnnotation])
+
+	rolledBack, err := r.waitForRollout(ctx, deadline, deployment, "deployment rollback")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return rolledBack, previous.Name, nil
+}
+
+// verifyRollbackReplicaSet confirms the previous ReplicaSet was promoted to the deployment's current revision.
+func (r *CheckRunner) verifyRollbackReplicaSet(ctx context.Context, deployment *appsv1.Deployment, replicaSetName string) error {
+	// Fetch the revived ReplicaSet.
+	replicaSet, err := r.client.AppsV1().ReplicaSets(r.cfg.CheckNamespace).Get(ctx, replicaSetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch ReplicaSet %s after rollback: %w", replicaSetName, err)
+	}
+
+	// The controller renumbers a revived ReplicaSet to the newest revision.
+	currentRevision := deployment.Annotations[deploymentRevisionAnnotation]
+	if replicaSet.Annotations[deploymentRevisionAnnotation] != currentRevision {
+		return fmt.Errorf("rollback did not reuse ReplicaSet %s: it has revision %s but the deployment is at revision %s", replicaSetName, replicaSet.Annotations[deploymentRevisionAnnotation], currentRevision)
+	}
+
+	r.timeline.recordf("rollback reused ReplicaSet %s as revision %s", replicaSetName, currentRevision)
+	return nil
+}
+
+// previousRevisionReplicaSet picks the ReplicaSet with the highest revision below the current one.
+func previousRevisionReplicaSet(replicaSets []appsv1.ReplicaSet, currentRevision string) (*appsv1.ReplicaSet, error) {
+	// Parse the current revision to compare against.
+	current, err := strconv.ParseInt(currentRevision, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deployment revision %q: %w", currentRevision, err)
+	}
+
+	// Keep the newest revision older than the current one.
+	var previous *appsv1.ReplicaSet
+	previousRevision := int64(0)
+	for i := range replicaSets {
+		revision, err := strconv.ParseInt(replicaSets[i].Annotations[deploymentRevisionAnnotation], 10, 64)
+		if err != nil || revision >= current || revision <= previousRevision {
+			continue
+		}
+		previous = &replicaSets[i]
+		previousRevision = revision
+	}
+	if previous == nil {
+		return nil, fmt.Errorf("no ReplicaSet found for a revision before %s", currentRevision)
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// TestPreviousRevisionReplicaSet validates the newest older revision is chosen for a rollback.
+func TestPreviousRevisionReplicaSet(t *testing.T) {
+	// Build ReplicaSets for revisions one through three plus one without a revision.
+	replicaSet := func(name string, revision string) appsv1.ReplicaSet {
+		rs := appsv1.ReplicaSet{}
+		rs.Name = name
+		rs.Annotations = map[string]string{deploymentRevisionAnnotation: revision}
+		return rs
+	}
+	replicaSets := []appsv1.ReplicaSet{
+		replicaSet("rs-2", "2"),
+		replicaSet("rs-3", "3"),
+		replicaSet("rs-1", "1"),
+		replicaSet("rs-unknown", ""),
+	}
+
+	previous, err := previousRevisionReplicaSet(replicaSets, "3")
+	if err != nil || previous.Name != "rs-2" {
+		t.Fatalf("expected rs-2 but got %v, %v", previous, err)
+	}
+
+	// Fail when there is no older revision.
+	_, err = previousRevisionReplicaSet(replicaSets, "1")
+	if err == nil {
+		t.Fatalf("expected an error without an older revision")
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// errRunLockHeld reports that another checker holds the run lock.
+var errRunLockHeld = errors.New("run lock is held by another checker")
+
+// runLock is the Lease a checker holds for the length of its run.
+type runLock struct {
+	// client provides typed Kubernetes API access.
+	client kubernetes.Interface
+	// lease is the Lease as last written by this checker.
+	lease *coordinationv1.Lease
+}
+
+// runLockDuration returns how long a run lock stays valid, covering the whole run and its shutdown cleanup.
+func runLockDuration(cfg *CheckConfig) int32 {
+	return int32(math.Ceil((cfg.CheckTimeLimit + cfg.ShutdownGracePeriod).Seconds()))
+}
+
+// leaseHolder returns the identity holding a lease at now, or empty when it is free or expired.
+func leaseHolder(lease *coordinationv1.Lease, now time.Time) string {
+	// A lease without a holder, renew time, or duration is free.
+	spec := lease.Spec
+	if spec.HolderIdentity == nil || len(*spec.HolderIdentity) == 0 || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return ""
+	}
+
+	// A holder that stopped renewing past the duration has expired.
+	expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+	if !now.Before(expiry) {
+		return ""
+	}
+
+	return *spec.HolderIdentity
+}
+
+// claimLease points a lease at a new holder for the given duration.
+func claimLease(lease *coordinationv1.Lease, identity string, duration int32, now time.Time) {
+	// Count a transition whenever the holder changes.
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != identity {
+		transitions := int32(1)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions + 1
+		}
+		lease.Spec.LeaseTransitions = &transitions
+	}
+
+	// Stamp the holder and its validity window.
+	acquired := metav1.NewMicroTime(now)
+	lease.Spec.HolderIdentity = &identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.AcquireTime = &acquired
+	lease.Spec.RenewTime = &acquired
+}
+
+// acquireRunLock takes the Lease named after the check deployment, returning errRunLockHeld when another checker holds it.
+func acquireRunLock(ctx context.Context, cfg *CheckConfig, client kubernetes.Interface) (*runLock, error) {
+	// Identify this checker by its pod.
+	identity, err := checkerPodName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine the checker pod name: %w", err)
+	}
+	duration := runLockDuration(cfg)
+	leases := client.CoordinationV1().Leases(cfg.CheckNamespace)
+
+	// Create the lease when no run has taken it before.
+	lease, err := leases.Get(ctx, cfg.CheckDeploymentName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cfg.CheckDeploymentName,
+				Namespace: cfg.CheckNamespace,
+				Labels:    map[string]string{sourceLabelKey: "kuberhealthy"},
+			},
+		}
+		claimLease(lease, identity, duration, time.Now())
+		created, err := leases.Create(ctx, lease, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("%w: another checker created lease %s first", errRunLockHeld, cfg.CheckDeploymentName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lease %s: %w", cfg.CheckDeploymentName, err)
+		}
+		log.Infoln("Acquired run lock", cfg.CheckDeploymentName, "as", identity+".")
+		return &runLock{client: client, lease: created}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lease %s: %w", cfg.CheckDeploymentName, err)
+	}
+
+	// Leave a live lease held by another checker alone.
+	now := time.Now()
+	holder := leaseHolder(lease, now)
+	if len(holder) != 0 && holder != identity {
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		return nil, fmt.Errorf("%w: lease %s is held by %s until %s", errRunLockHeld, cfg.CheckDeploymentName, holder, expiry.UTC().Format(time.RFC3339))
+	}
+
+	// Take over the free or expired lease; a conflict means another checker got there first.
+	claimLease(lease, identity, duration, now)
+	updated, err := leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if k8serrors.IsConflict(err) {
+		return nil, fmt.Errorf("%w: another checker took lease %s first", errRunLockHeld, cfg.CheckDeploymentName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update lease %s: %w", cfg.CheckDeploymentName, err)
+	}
+	log.Infoln("Acquired run lock", cfg.CheckDeploymentName, "as", identity+".")
+	return &runLock{client: client, lease: updated}, nil
+}
+
+// release frees the run lock so the next run does not wait for it to expire, leaving it alone if another checker took it over.
+func (l *runLock) release() {
+	// Use a fresh context so an expired check deadline still allows the release.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// Clear the holder only if the lease is unchanged since this checker wrote it.
+	lease := l.lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	_, err := l.client.CoordinationV1().Leases(lease.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		log.Warnln("Failed to release run lock", lease.Name+":", err.Error())
+		return
+	}
+	log.Infoln("Released run lock", lease.Name+".")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+)
+
+// TestLeaseHolder validates a lease is held only while its holder's duration has not passed.
+func TestLeaseHolder(t *testing.T) {
+	// A new lease has no holder.
+	now := time.Unix(1700000000, 0)
+	lease := &coordinationv1.Lease{}
+	if holder := leaseHolder(lease, now); len(holder) != 0 {
+		t.Fatalf("expected a new lease to be free but got holder %q", holder)
+	}
+
+	// A claimed lease is held until it expires.
+	claimLease(lease, "checker-a", 60, now)
+	if holder := leaseHolder(lease, now.Add(time.Second*59)); holder != "checker-a" {
+		t.Fatalf("expected checker-a to hold the lease but got %q", holder)
+	}
+	if holder := leaseHolder(lease, now.Add(time.Second*60)); len(holder) != 0 {
+		t.Fatalf("expected the lease to expire but got holder %q", holder)
+	}
+
+	// Taking over from another holder counts a transition.
+	claimLease(lease, "checker-b", 60, now.Add(time.Minute*2))
+	if *lease.Spec.HolderIdentity != "checker-b" || lease.Spec.LeaseTransitions == nil || *lease.Spec.LeaseTransitions != 1 {
+		t.Fatalf("expected checker-b to take over with one transition but got %+v", lease.Spec)
+	}
+}
+
+// TestRunLockDuration validates the lock covers the run and its shutdown cleanup.
+func TestRunLockDuration(t *testing.T) {
+	// Round partial seconds up.
+	cfg := &CheckConfig{CheckTimeLimit: time.Minute*15 - time.Millisecond*500, ShutdownGracePeriod: time.Second * 30}
+	if duration := runLockDuration(cfg); duration != 930 {
+		t.Fatalf("expected a 930 second lock but got %d", duration)
+	}
+}
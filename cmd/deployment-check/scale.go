@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// scalePollInterval is the pause between scaling status observations.
+	scalePollInterval = time.Second * 2
+)
+
+// verifyScaling patches the deployment to the scale target and back, waiting for the status to converge each time.
+func (r *CheckRunner) verifyScaling(ctx context.Context) error {
+	// Scale to the target, then return to the configured replicas.
+	for _, replicas := range []int{r.cfg.ScaleReplicas, r.cfg.CheckDeploymentReplicas} {
+		err := r.scaleDeploymentAndWait(ctx, replicas)
+		if err != nil {
+			return err
+		}
+	}
+	log.Infoln("Deployment scaling verified.")
+
+	return nil
+}
+
+// scaleDeploymentAndWait patches the deployment replicas and waits for every replica to be ready and available.
+func (r *CheckRunner) scaleDeploymentAndWait(ctx context.Context, replicas int) error {
+	// Patch only the replica count, as kubectl scale would.
+	log.Infoln("Scaling deployment", r.cfg.CheckDeploymentName, "to", replicas, "replica(s).")
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	_, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Patch(ctx, r.cfg.CheckDeploymentName, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment to %d replica(s): %w", replicas, err)
+	}
+	r.timeline.recordf("scaled deployment %s to %d replica(s)", r.cfg.CheckDeploymentName, replicas)
+
+	// Poll until the status converges or the timeout passes.
+	started := time.Now()
+	deadline := started.Add(r.cfg.ScaleTimeout)
+	for {
+		deployment, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			log.Warnln("Failed to fetch deployment while waiting for scaling:", err.Error())
+		}
+		if err == nil && deploymentAvailable(deployment, replicas) {
+			r.timeline.recordf("deployment %s converged on %d replica(s) in %s", deployment.Name, replicas, time.Since(started).Round(time.Millisecond))
+			return nil
+		}
+
+		// Report the last observed status once the timeout passes.
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("failed to observe deployment scaling to %d replica(s): %w", replicas, err)
+			}
+			return r.decorateDeploymentError(ctx, "deployment scale", fmt.Errorf("deployment did not converge on %d replica(s) within %s: replicas: %d ready: %d available: %d observed generation: %d of %d", replicas, r.cfg.ScaleTimeout, deployment.Status.Replicas, deployment.Status.ReadyReplicas, deployment.Status.AvailableReplicas, deployment.Status.ObservedGeneration, deployment.Generation))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for deployment to scale to %d replica(s)", replicas)
+		case <-time.After(scalePollInterval):
+		}
+	}
+}
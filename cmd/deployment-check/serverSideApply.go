@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// createDeployment creates the deployment, or server-side applies it when enabled.
+func (r *CheckRunner) createDeployment(ctx context.Context, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	if r.cfg.ServerSideApply {
+		return r.applyDeployment(ctx, deployment)
+	}
+
+	return r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Create(ctx, deployment, metav1.CreateOptions{})
+}
+
+// createService creates the service, or server-side applies it when enabled.
+func (r *CheckRunner) createService(ctx context.Context, service *corev1.Service) (*corev1.Service, error) {
+	if r.cfg.ServerSideApply {
+		return r.applyService(ctx, service)
+	}
+
+	return r.client.CoreV1().Services(r.cfg.CheckNamespace).Create(ctx, service, metav1.CreateOptions{})
+}
+
+// applyDeployment server-side applies the deployment under the check's field manager.
+func (r *CheckRunner) applyDeployment(ctx context.Context, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	// Encode the desired state as an apply patch.
+	patch, err := applyPatch(deployment, &deployment.TypeMeta, "apps/v1", "Deployment")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Patch(ctx, deployment.Name, k8stypes.ApplyPatchType, patch, r.applyOptions())
+}
+
+// applyService server-side applies the service under the check's field manager.
+func (r *CheckRunner) applyService(ctx context.Context, service *corev1.Service) (*corev1.Service, error) {
+	// Encode the desired state as an apply patch.
+	patch, err := applyPatch(service, &service.TypeMeta, "v1", "Service")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.client.CoreV1().Services(r.cfg.CheckNamespace).Patch(ctx, service.Name, k8stypes.ApplyPatchType, patch, r.applyOptions())
+}
+
+// applyOptions returns patch options that apply as the check's field manager, taking over conflicting fields.
+func (r *CheckRunner) applyOptions() metav1.PatchOptions {
+	// Force so a leftover from an interrupted run is taken over instead of failing on conflicts.
+	force := true
+	return metav1.PatchOptions{
+		FieldManager: r.cfg.FieldManager,
+		Force:        &force,
+	}
+}
+
+// applyPatch sets the type information apply requires and encodes the object without status or zero-valued fields.
+func applyPatch(object any, typeMeta *metav1.TypeMeta, apiVersion string, kind string) ([]byte, error) {
+	// Apply patches must name their type since the body is the whole object.
+	typeMeta.APIVersion = apiVersion
+	typeMeta.Kind = kind
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s apply patch: %w", kind, err)
+	}
+
+	// Decode into generic fields so the ones the check never set can be dropped.
+	fields := map[string]any{}
+	err = json.Unmarshal(encoded, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s apply patch: %w", kind, err)
+	}
+
+	// Drop status and unset fields so the field manager only owns what the check declares.
+	delete(fields, "status")
+	pruneApplyFields(reflect.ValueOf(object), fields)
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s apply patch: %w", kind, err)
+	}
+
+	return patch, nil
+}
+
+// pruneApplyFields removes the encoded fields of value that are unset structs or nil references, keeping deliberately empty objects such as an emptyDir source.
+func pruneApplyFields(value reflect.Value, fields map[string]any) {
+	// Follow pointers down to the struct the fields were encoded from.
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Inlined structs share the parent's fields.
+		if len(name) == 0 && (field.Anonymous || strings.Contains(options, "inline")) {
+			pruneApplyFields(value.Field(i), fields)
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		// Drop struct fields left at their zero value and references that were never set.
+		fieldValue := value.Field(i)
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			if fieldValue.IsZero() {
+				delete(fields, name)
+				continue
+			}
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			if fieldValue.IsNil() {
+				delete(fields, name)
+				continue
+			}
+		}
+
+		// Descend into nested objects and lists of objects.
+		switch nested := fields[name].(type) {
+		case map[string]any:
+			pruneApplyFields(fieldValue, nested)
+		case []any:
+			if fieldValue.Kind() != reflect.Slice {
+				continue
+			}
+			for j, item := range nested {
+				itemFields, isObject := item.(map[string]any)
+				if isObject && j < fieldValue.Len() {
+					pruneApplyFields(fieldValue.Index(j), itemFields)
+				}
+			}
+		}
+	}
+}
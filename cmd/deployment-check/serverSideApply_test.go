@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestApplyPatch validates apply patches carry their type and the desired object.
+func TestApplyPatch(t *testing.T) {
+	// Encode a deployment that was built without type information.
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "deployment-deployment"
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "deployment-container", Image: "nginx:1.17.9"}}
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	patch, err := applyPatch(deployment, &deployment.TypeMeta, "apps/v1", "Deployment")
+	if err != nil {
+		t.Fatalf("unexpected error encoding apply patch: %v", err)
+	}
+
+	// Decode the patch and confirm the type and name survived.
+	decoded := map[string]any{}
+	err = json.Unmarshal(patch, &decoded)
+	if err != nil {
+		t.Fatalf("failed to decode apply patch: %v", err)
+	}
+	metadata, _ := decoded["metadata"].(map[string]any)
+	if decoded["apiVersion"] != "apps/v1" || decoded["kind"] != "Deployment" || metadata["name"] != "deployment-deployment" {
+		t.Fatalf("expected an apps/v1 Deployment patch named deployment-deployment but got %s", patch)
+	}
+
+	// Status and fields the check never set are left out so the field manager does not own them.
+	for _, field := range []string{`"status"`, `"creationTimestamp"`, `"resources"`, `"strategy"`} {
+		if strings.Contains(string(patch), field) {
+			t.Fatalf("expected no %s field in the apply patch but got %s", field, patch)
+		}
+	}
+	if !strings.Contains(string(patch), `"image":"nginx:1.17.9"`) || !strings.Contains(string(patch), `"emptyDir":{}`) {
+		t.Fatalf("expected the container image and emptyDir source in the apply patch but got %s", patch)
+	}
+}
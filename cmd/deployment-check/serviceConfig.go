@@ -14,29 +14,40 @@ func (r *CheckRunner) createServiceConfig(labels map[string]string) *corev1.Serv
 	service := &corev1.Service{}
 	log.Infoln("Creating service resource for", r.cfg.CheckNamespace, "namespace.")
 
-	// Build the service ports.
+	// Build the service ports, naming each so multiple ports are accepted.
 	ports := make([]corev1.ServicePort, 0)
-	basicPort := corev1.ServicePort{
-		Port: r.cfg.CheckLoadBalancerPort,
-		TargetPort: intstr.IntOrString{
-			IntVal: r.cfg.CheckContainerPort,
-			StrVal: strconv.Itoa(int(r.cfg.CheckContainerPort)),
-		},
-		Protocol: corev1.ProtocolTCP,
+	for _, port := range r.cfg.checkPorts() {
+		servicePort := corev1.ServicePort{
+			Name: "tcp-" + strconv.Itoa(int(port.ServicePort)),
+			Port: port.ServicePort,
+			TargetPort: intstr.IntOrString{
+				IntVal: port.ContainerPort,
+				StrVal: strconv.Itoa(int(port.ContainerPort)),
+			},
+			Protocol: corev1.ProtocolTCP,
+		}
+		ports = append(ports, servicePort)
 	}
-	ports = append(ports, basicPort)
 
 	// Build the service spec.
 	serviceSpec := corev1.ServiceSpec{
-		Type:     corev1.ServiceTypeClusterIP,
+		Type:     r.cfg.CheckServiceType,
 		Ports:    ports,
 		Selector: labels,
 	}
 
+	// Pin each client to one backend when session affinity is verified.
+	if r.cfg.SessionAffinityVerify {
+		serviceSpec.SessionAffinity = corev1.ServiceAffinityClientIP
+	}
+
 	// Populate the service metadata.
 	service.Spec = serviceSpec
 	service.Name = r.cfg.CheckServiceName
 	service.Namespace = r.cfg.CheckNamespace
+	service.Labels = copyStringMap(r.cfg.ExtraLabels)
+	service.Annotations = r.resourceAnnotations(r.cfg.ServiceAnnotations)
+	service.OwnerReferences = r.ownerReferences()
 
 	return service
 }
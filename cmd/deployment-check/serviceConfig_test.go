@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
 
 // TestCreateServiceConfig validates service metadata and ports.
 func TestCreateServiceConfig(t *testing.T) {
@@ -37,3 +41,43 @@ func TestCreateServiceConfig(t *testing.T) {
 		}
 	}
 }
+
+// TestCreateServiceConfigAdditionalPorts validates that every declared port is exposed.
+func TestCreateServiceConfigAdditionalPorts(t *testing.T) {
+	// Build a runner with an extra port pair.
+	runner := buildTestRunner()
+	runner.cfg.CheckAdditionalPorts = []checkPort{{ContainerPort: 8081, ServicePort: 81}}
+
+	// Build the service and container configs.
+	serviceConfig := runner.createServiceConfig(map[string]string{"app": "test"})
+	containerConfig := runner.createContainerConfig("nginx:test")
+
+	if len(serviceConfig.Spec.Ports) != 2 {
+		t.Fatalf("expected 2 service ports but got %d", len(serviceConfig.Spec.Ports))
+	}
+
+	if serviceConfig.Spec.Ports[0].Name == serviceConfig.Spec.Ports[1].Name {
+		t.Fatalf("expected unique service port names but got %s twice", serviceConfig.Spec.Ports[0].Name)
+	}
+
+	if len(containerConfig.Ports) != 2 {
+		t.Fatalf("expected 2 container ports but got %d", len(containerConfig.Ports))
+	}
+}
+
+// TestCreateServiceConfigSessionAffinity validates ClientIP affinity is only set when verified.
+func TestCreateServiceConfigSessionAffinity(t *testing.T) {
+	// Build the default service without affinity.
+	runner := buildTestRunner()
+	serviceConfig := runner.createServiceConfig(map[string]string{"app": "test"})
+	if len(serviceConfig.Spec.SessionAffinity) != 0 {
+		t.Fatalf("expected no session affinity but got %s", serviceConfig.Spec.SessionAffinity)
+	}
+
+	// Enable session affinity verification.
+	runner.cfg.SessionAffinityVerify = true
+	serviceConfig = runner.createServiceConfig(map[string]string{"app": "test"})
+	if serviceConfig.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		t.Fatalf("expected ClientIP session affinity but got %s", serviceConfig.Spec.SessionAffinity)
+	}
+}
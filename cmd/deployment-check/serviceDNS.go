@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// serviceDNSTimeout bounds the wait for CoreDNS to publish the service record.
+	serviceDNSTimeout = time.Minute
+	// serviceDNSLookupTimeout bounds a single lookup of the service name.
+	serviceDNSLookupTimeout = time.Second * 10
+)
+
+// verifyServiceDNS resolves the service FQDN and checks it answers with the service cluster IPs within the slow threshold.
+func (r *CheckRunner) verifyServiceDNS(ctx context.Context) error {
+	// Collect the cluster IPs the name should resolve to.
+	service, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Get(ctx, r.cfg.CheckServiceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch service for DNS verification: %w", err)
+	}
+	expected := make(map[string]bool)
+	for _, clusterIP := range service.Spec.ClusterIPs {
+		expected[clusterIP] = true
+	}
+	if len(expected) == 0 && len(service.Spec.ClusterIP) != 0 {
+		expected[service.Spec.ClusterIP] = true
+	}
+	if len(expected) == 0 {
+		return fmt.Errorf("service %s has no cluster IP to compare DNS answers against", service.Name)
+	}
+
+	// Poll until the name resolves to the cluster IPs, keeping the last mismatch for the report.
+	fqdn := r.cfg.CheckServiceName + "." + r.cfg.CheckNamespace + ".svc." + r.cfg.ClusterDomain
+	deadline := time.Now().Add(serviceDNSTimeout)
+	for {
+		latency, err := r.resolveServiceOnce(ctx, fqdn, expected)
+		if err == nil {
+			r.timeline.recordf("resolved %s in %s", fqdn, latency.Round(time.Millisecond))
+			if latency > r.cfg.ServiceDNSSlowThreshold {
+				return fmt.Errorf("resolving %s took %s, over the %s threshold", fqdn, latency.Round(time.Millisecond), r.cfg.ServiceDNSSlowThreshold)
+			}
+			log.Infoln("Resolved", fqdn, "to the service cluster IP in", latency.Round(time.Millisecond))
+			return nil
+		}
+		log.Debugln("Service DNS not ready yet:", err.Error())
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not resolve to the service cluster IP within %s: %w", fqdn, serviceDNSTimeout, err)
+		}
+
+		// Wait before resolving again.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while verifying service DNS: %w", err)
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+// resolveServiceOnce resolves the name once, returning the lookup latency when the answer matches the expected IPs.
+func (r *CheckRunner) resolveServiceOnce(ctx context.Context, fqdn string, expected map[string]bool) (time.Duration, error) {
+	// Time a single bounded lookup.
+	lookupCtx, cancel := context.WithTimeout(ctx, serviceDNSLookupTimeout)
+	defer cancel()
+	started := time.Now()
+	addresses, err := net.DefaultResolver.LookupHost(lookupCtx, fqdn)
+	latency := time.Since(started)
+	if err != nil {
+		return latency, fmt.Errorf("failed to resolve %s after %s: %w", fqdn, latency.Round(time.Millisecond), err)
+	}
+
+	// Compare the answer with the cluster IPs.
+	resolved := make(map[string]bool)
+	for _, address := range addresses {
+		resolved[address] = true
+	}
+	if !sameKeys(expected, resolved) {
+		return latency, fmt.Errorf("%s resolved to [%s] but the service cluster IPs are [%s]", fqdn, strings.Join(sortedKeys(resolved), ", "), strings.Join(sortedKeys(expected), ", "))
+	}
+
+	return latency, nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
+	"slices"
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
 )
 
 // createServiceAndWait creates the service and waits for a cluster IP.
@@ -18,7 +21,7 @@ func (r *CheckRunner) createServiceAndWait(ctx context.Context, labels map[strin
 	log.Infoln("Created service resource.")
 
 	// Create the service in the cluster.
-	service, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Create(ctx, serviceConfig, metav1.CreateOptions{})
+	service, err := r.createService(ctx, serviceConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service: %w", err)
 	}
@@ -26,26 +29,31 @@ func (r *CheckRunner) createServiceAndWait(ctx context.Context, labels map[strin
 		return nil, fmt.Errorf("service creation returned nil")
 	}
 	log.Infoln("Created service in", service.Namespace, "namespace:", service.Name)
+	r.timeline.recordf("created service %s", service.Name)
 
 	// Start a watch for the service to become available.
-	watcher, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).Watch(ctx, metav1.ListOptions{
-		Watch:         true,
-		FieldSelector: "metadata.name=" + service.Name,
-	})
+	watcher, err := r.watchService(ctx, service.Name, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to watch service: %w", err)
 	}
 	defer watcher.Stop()
+	events := watcher.ResultChan()
 
 	for {
 		select {
-		case event := <-watcher.ResultChan():
+		case event, open := <-events:
+			if !open {
+				// The resumable watch only closes once ctx ends.
+				events = nil
+				continue
+			}
 			serviceEvent, ok := event.Object.(*corev1.Service)
 			if !ok {
 				log.Debugln("Got a watch event for a non-service object -- ignoring.")
 				continue
 			}
 			if serviceAvailable(serviceEvent) {
+				r.timeline.recordf("service %s assigned cluster IP %s", serviceEvent.Name, serviceEvent.Spec.ClusterIP)
 				return serviceEvent, nil
 			}
 		case <-ctx.Done():
@@ -58,54 +66,24 @@ func (r *CheckRunner) createServiceAndWait(ctx context.Context, labels map[strin
 	}
 }
 
-// deleteServiceAndWait deletes the service and waits for removal.
-func (r *CheckRunner) deleteServiceAndWait(ctx context.Context) error {
-	// Attempt a foreground delete with a short grace period.
-	err := r.deleteService(ctx)
-	if err != nil {
-		log.Infoln("Could not delete service:", r.cfg.CheckServiceName)
+// deleteServiceAndWait deletes the named service and waits for removal.
+func (r *CheckRunner) deleteServiceAndWait(ctx context.Context, name string) error {
+	// Attempt a background delete with a short grace period.
+	err := r.deleteService(ctx, name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Infoln("Could not delete service:", name)
 	}
 
-	// Loop until the service is no longer present.
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("timed out while waiting for service to delete")
-		default:
-			log.Debugln("Delete service and wait has not yet timed out.")
-		}
-
-		log.Debugln("Waiting 5 seconds before trying again.")
-		time.Sleep(time.Second * 5)
-
-		serviceList, listErr := r.client.CoreV1().Services(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{
-			FieldSelector: "metadata.name=" + r.cfg.CheckServiceName,
-		})
-		if listErr != nil {
-			log.Errorln("Error listing services:", listErr.Error())
-			continue
-		}
-
-		serviceExists := false
-		for _, svc := range serviceList.Items {
-			if svc.GetName() == r.cfg.CheckServiceName {
-				serviceExists = true
-				deleteErr := r.deleteService(ctx)
-				if deleteErr != nil {
-					log.Errorln("Error deleting service", r.cfg.CheckServiceName+":", deleteErr.Error())
-				}
-				break
-			}
-		}
-
-		if !serviceExists {
-			return nil
-		}
-	}
+	// Wait for the informer cache to drop the service.
+	return r.waitForObjectDeleted(ctx, "service", name, func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Core().V1().Services().Informer()
+	}, func(ctx context.Context) error {
+		return r.deleteService(ctx, name)
+	})
 }
 
-// deleteService issues the delete call for the service resource.
-func (r *CheckRunner) deleteService(ctx context.Context) error {
+// deleteService issues the delete call for the named service.
+func (r *CheckRunner) deleteService(ctx context.Context, name string) error {
 	// Prepare background delete options to avoid foreground finalizer stalls.
 	deletePolicy := metav1.DeletePropagationBackground
 	graceSeconds := int64(1)
@@ -115,8 +93,8 @@ func (r *CheckRunner) deleteService(ctx context.Context) error {
 	}
 
 	// Issue the delete request.
-	log.Infoln("Attempting to delete service", r.cfg.CheckServiceName, "in", r.cfg.CheckNamespace, "namespace.")
-	return r.client.CoreV1().Services(r.cfg.CheckNamespace).Delete(ctx, r.cfg.CheckServiceName, deleteOpts)
+	log.Infoln("Attempting to delete service", name, "in", r.cfg.CheckNamespace, "namespace.")
+	return r.client.CoreV1().Services(r.cfg.CheckNamespace).Delete(ctx, name, deleteOpts)
 }
 
 // findPreviousService checks whether a prior service exists in the namespace.
@@ -133,8 +111,9 @@ func (r *CheckRunner) findPreviousService(ctx context.Context) (bool, error) {
 	log.Debugln("Found", len(serviceList.Items), "service(s).")
 
 	// Scan for a matching service name.
+	names := r.checkServiceNames()
 	for _, svc := range serviceList.Items {
-		if svc.Name == r.cfg.CheckServiceName {
+		if slices.Contains(names, svc.Name) {
 			log.Infoln("Found an old service belonging to this check:", svc.Name)
 			return true, nil
 		}
@@ -144,6 +123,17 @@ func (r *CheckRunner) findPreviousService(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// checkServiceNames returns the names of every service the check creates.
+func (r *CheckRunner) checkServiceNames() []string {
+	// Include the headless service when it is enabled.
+	names := []string{r.cfg.CheckServiceName}
+	if r.cfg.HeadlessServiceVerify {
+		names = append(names, r.cfg.headlessServiceName())
+	}
+
+	return names
+}
+
 // getServiceClusterIP fetches the cluster IP for the service.
 func (r *CheckRunner) getServiceClusterIP(ctx context.Context, service *corev1.Service) (string, error) {
 	// Validate the service input.
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestDeleteServiceAndWait validates the service is removed with a single delete call.
+func TestDeleteServiceAndWait(t *testing.T) {
+	// Seed the check service.
+	runner := buildTestRunner()
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: runner.cfg.CheckServiceName, Namespace: runner.cfg.CheckNamespace}}
+	client := fake.NewClientset(service)
+	runner.client = client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := runner.deleteServiceAndWait(ctx, runner.cfg.CheckServiceName)
+	if err != nil {
+		t.Fatalf("expected the service to delete but got %v", err)
+	}
+	_, err = client.CoreV1().Services(runner.cfg.CheckNamespace).Get(ctx, runner.cfg.CheckServiceName, metav1.GetOptions{})
+	if !k8serrors.IsNotFound(err) {
+		t.Fatalf("expected the service to be gone but got %v", err)
+	}
+
+	// The wait does not re-issue a delete that already took effect.
+	deletes := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			deletes++
+		}
+	}
+	if deletes != 1 {
+		t.Fatalf("expected one delete call but got %d", deletes)
+	}
+}
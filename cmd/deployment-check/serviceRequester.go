@@ -4,79 +4,154 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
 )
 
-const (
-	// requestBackoffTimeout caps the HTTP retry window.
-	requestBackoffTimeout = time.Minute * 3
-	// requestBackoffMaxRetries caps the number of HTTP attempts.
-	requestBackoffMaxRetries = 10
-)
+// validateServicePorts requests every declared service port and aggregates the per-port results.
+func (r *CheckRunner) validateServicePorts(ctx context.Context, serviceIP string) error {
+	// Validate each port independently so one failure does not hide the others.
+	failures := make([]string, 0)
+	for _, port := range r.cfg.checkPorts() {
+		err := r.requestServicePort(ctx, serviceIP, port.ServicePort)
+		if err != nil {
+			log.Errorln("Service port", port.ServicePort, "failed validation:", err.Error())
+			r.timeline.recordf("service port %d failed validation: %s", port.ServicePort, err.Error())
+			failures = append(failures, fmt.Sprintf("port %d: %s", port.ServicePort, err.Error()))
+			continue
+		}
+		log.Infoln("Service port", port.ServicePort, "passed validation.")
+	}
+
+	// Report every failing port together.
+	if len(failures) != 0 {
+		return fmt.Errorf("%d of %d service port(s) failed validation: %s", len(failures), len(r.cfg.checkPorts()), strings.Join(failures, " | "))
+	}
+
+	return nil
+}
+
+// requestServicePort requests one service port, through the API server's service proxy when the cluster is remote.
+func (r *CheckRunner) requestServicePort(ctx context.Context, serviceIP string, port int32) error {
+	// The service network of a remote cluster cannot be reached from the checker pod.
+	if r.cfg.targetsRemoteCluster() {
+		transport, err := rest.TransportFor(r.restConfig)
+		if err != nil {
+			return fmt.Errorf("failed to build the API server transport: %w", err)
+		}
+		return r.requestEndpointWithTransport(ctx, transport, r.serviceProxyURL(port), "")
+	}
+
+	return r.requestServiceEndpoint(ctx, net.JoinHostPort(serviceIP, strconv.Itoa(int(port))))
+}
 
-// requestServiceEndpoint performs a GET against the service endpoint with retries.
+// serviceProxyURL returns the API server proxy URL for the configured path on a service port.
+func (r *CheckRunner) serviceProxyURL(port int32) string {
+	// Default to https for hosts given without a scheme, as client-go does.
+	host := strings.TrimSuffix(r.restConfig.Host, "/")
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	return host + "/api/v1/namespaces/" + r.cfg.CheckNamespace + "/services/" + r.cfg.EndpointScheme + ":" + r.cfg.CheckServiceName + ":" + strconv.Itoa(int(port)) + "/proxy" + r.cfg.HTTPPath
+}
+
+// requestServiceEndpoint performs a GET against the service endpoint with retries, cleaning up if the wait runs out.
 func (r *CheckRunner) requestServiceEndpoint(ctx context.Context, address string) error {
+	// Request the address, noting when the retry window started.
+	started := time.Now()
+	err := r.requestAddress(ctx, address)
+	if err == nil {
+		return nil
+	}
+
+	// Clean up when the context or retry window ran out, as the check always has.
+	if ctx.Err() != nil || time.Since(started) >= r.cfg.RequestRetryTimeout {
+		cleanupErr := r.cleanup(ctx)
+		if cleanupErr != nil {
+			return fmt.Errorf("%w; cleanup failed: %w", err, cleanupErr)
+		}
+	}
+
+	return err
+}
+
+// requestAddress performs a GET, or a TCP connect, against an address with retries.
+func (r *CheckRunner) requestAddress(ctx context.Context, address string) error {
 	// Validate address before attempting the request.
 	if len(address) == 0 {
 		return fmt.Errorf("given blank service address for HTTP call")
 	}
 
-	// Ensure the address is an HTTP URL.
-	if !strings.HasPrefix(address, "http://") {
-		address = "http://" + address
+	// Connect over TCP instead when the workload is not HTTP.
+	if r.cfg.Protocol == protocolTCP {
+		return r.connectEndpoint(ctx, address)
 	}
 
+	// Ensure the address is a URL using the configured scheme and path.
+	if !strings.Contains(address, "://") {
+		address = r.cfg.EndpointScheme + "://" + address + r.cfg.HTTPPath
+	}
+
+	return r.requestEndpoint(ctx, address, "")
+}
+
+// requestEndpoint performs a GET against a URL with retries, overriding the Host header when host is set.
+func (r *CheckRunner) requestEndpoint(ctx context.Context, address string, host string) error {
+	return r.requestEndpointWithTransport(ctx, r.transport, address, host)
+}
+
+// requestEndpointWithTransport performs a GET against a URL with retries over the given transport.
+func (r *CheckRunner) requestEndpointWithTransport(ctx context.Context, transport http.RoundTripper, address string, host string) error {
 	// Log the request intent.
 	log.Infoln("Looking for a response from the endpoint.")
-	log.Debugln("Setting timeout for backoff loop to:", requestBackoffTimeout)
+	log.Debugln("Setting timeout for backoff loop to:", r.cfg.RequestRetryTimeout)
 
 	// Bound the backoff loop by time.
-	deadline := time.Now().Add(requestBackoffTimeout)
+	deadline := time.Now().Add(r.cfg.RequestRetryTimeout)
+	backoff := r.cfg.retryBackoff()
 	attempt := 1
 
+	lastResult := "no response"
 	for {
 		// Check context cancellation.
 		select {
 		case <-ctx.Done():
-			cleanupErr := r.cleanup(ctx)
-			if cleanupErr != nil {
-				return fmt.Errorf("context expired while waiting for %d from %s and cleanup failed: %w", http.StatusOK, address, cleanupErr)
-			}
-			return fmt.Errorf("context expired while waiting for %d from %s", http.StatusOK, address)
+			return fmt.Errorf("context expired while waiting for %s from %s; last result: %s", r.cfg.HTTPExpectedCodes, address, lastResult)
 		default:
 		}
 
 		// Exit on timeout.
 		if time.Now().After(deadline) {
-			cleanupErr := r.cleanup(ctx)
-			if cleanupErr != nil {
-				return fmt.Errorf("backoff loop timed out and cleanup failed: %w", cleanupErr)
-			}
-			return fmt.Errorf("backoff loop for a %d response took too long and timed out", http.StatusOK)
+			return fmt.Errorf("backoff loop for a %s response took too long and timed out; last result: %s", r.cfg.HTTPExpectedCodes, lastResult)
 		}
 
 		// Stop after max retries.
-		if attempt > requestBackoffMaxRetries {
-			return fmt.Errorf("could not successfully make an HTTP request after %d attempts", attempt-1)
+		if attempt > r.cfg.RequestMaxAttempts {
+			return fmt.Errorf("could not get a %s response after %d attempts; last result: %s", r.cfg.HTTPExpectedCodes, attempt-1, lastResult)
 		}
 
 		// Perform the request.
 		log.Debugln("Making", http.MethodGet, "to", address)
-		response, err := http.Get(address)
+		response, err := r.getWithHost(ctx, transport, address, host)
 		if err == nil && response != nil {
 			statusCode := response.StatusCode
 			log.Debugln("Got a", statusCode)
-			if statusCode == http.StatusOK {
+			lastResult = "received " + strconv.Itoa(statusCode)
+			if r.cfg.HTTPExpectedCodes.matches(statusCode) {
 				closeErr := response.Body.Close()
 				if closeErr != nil {
 					log.Debugln("Failed to close response body:", closeErr.Error())
 				}
 				log.Infoln("Successfully made an HTTP request on attempt:", attempt)
 				log.Infoln("Got a", statusCode, "with a", http.MethodGet, "to", address)
+				r.timeline.recordf("received first %d from %s on attempt %d", statusCode, address, attempt)
 				return nil
 			}
 
@@ -93,15 +168,46 @@ func (r *CheckRunner) requestServiceEndpoint(ctx context.Context, address string
 
 		// Log errors except for DNS delays.
 		if err != nil {
+			lastResult = err.Error()
 			if !strings.Contains(err.Error(), "no such host") {
 				log.Debugln("An error occurred making a", http.MethodGet, "request:", err)
 			}
 		}
 
-		// Sleep with backoff before retrying.
-		retrySleepSeconds := attempt * 5
-		log.Infoln("Retrying in", retrySleepSeconds, "seconds.")
-		time.Sleep(time.Duration(retrySleepSeconds) * time.Second)
+		// Sleep with jittered exponential backoff before retrying.
+		delay := backoff.Step()
+		log.Infoln("Retrying in", delay.Round(time.Millisecond).String()+".")
+		sleepForRetry(ctx, delay)
 		attempt++
 	}
 }
+
+// getWithHost issues a GET bound to ctx over transport, overriding the Host header when host is set.
+func (r *CheckRunner) getWithHost(ctx context.Context, transport http.RoundTripper, address string, host string) (*http.Response, error) {
+	// Build the request with the configured headers and apply the host override.
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.applyRequestHeaders(request)
+	if len(host) != 0 {
+		request.Host = host
+	}
+
+	client := &http.Client{Transport: transport}
+	return client.Do(request)
+}
+
+// applyRequestHeaders adds the configured headers to a request, moving any Host entry onto the request host.
+func (r *CheckRunner) applyRequestHeaders(request *http.Request) {
+	// Copy each configured header; Go only honors request.Host for the Host header.
+	for name, values := range r.cfg.HTTPHeaders {
+		if name == "Host" {
+			request.Host = values[0]
+			continue
+		}
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+}
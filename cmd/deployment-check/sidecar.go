@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// sidecarContainerName returns the name of the sidecar container in the check pods.
+func (cfg *CheckConfig) sidecarContainerName() string {
+	return cfg.CheckContainerName + "-sidecar"
+}
+
+// createSidecarContainerConfig builds the sidecar container, as a restartable init container in native mode.
+func (r *CheckRunner) createSidecarContainerConfig() corev1.Container {
+	// Give the sidecar the check container's resources and hardening so admission treats them alike.
+	container := corev1.Container{
+		Name:            r.cfg.sidecarContainerName(),
+		Image:           r.cfg.SidecarImage,
+		Command:         r.cfg.SidecarCommand,
+		ImagePullPolicy: deploymentImagePullPolicy,
+		Resources:       r.createResourceRequirements(),
+		SecurityContext: r.createContainerSecurityContext(),
+	}
+	if r.cfg.ContainerReadOnlyRootFilesystem {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: scratchVolumeName, MountPath: scratchVolumeMountPath})
+	}
+	if r.cfg.TerminationMessageFallbackToLogs {
+		container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	}
+
+	// Probe the sidecar port so readiness reflects the sidecar actually serving.
+	if r.cfg.SidecarPort > 0 {
+		container.Ports = []corev1.ContainerPort{{ContainerPort: r.cfg.SidecarPort, Protocol: corev1.ProtocolTCP}}
+		container.ReadinessProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(r.cfg.SidecarPort)},
+			},
+			InitialDelaySeconds: r.cfg.ProbeInitialDelaySeconds,
+			TimeoutSeconds:      r.cfg.ProbeTimeoutSeconds,
+			PeriodSeconds:       r.cfg.ProbePeriodSeconds,
+			SuccessThreshold:    probeSuccessThreshold,
+			FailureThreshold:    r.cfg.ProbeFailureThreshold,
+		}
+	}
+
+	// Keep a native sidecar running alongside the main container instead of blocking it.
+	if r.cfg.SidecarNative {
+		restartAlways := corev1.ContainerRestartPolicyAlways
+		container.RestartPolicy = &restartAlways
+	}
+
+	return container
+}
+
+// verifySidecarReady confirms every check pod runs a ready sidecar next to the check container.
+func (r *CheckRunner) verifySidecarReady(ctx context.Context) error {
+	// List the pods of the current run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+
+	// Inspect every live pod and report each one without a ready sidecar.
+	name := r.cfg.sidecarContainerName()
+	problems := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		// A cluster without native sidecar support drops the restart policy, leaving a plain init container.
+		if r.cfg.SidecarNative && !hasNativeSidecar(pod, name) {
+			problems = append(problems, fmt.Sprintf("pod: %s has no restartable init container %s; the cluster may not support native sidecars", pod.Name, name))
+			continue
+		}
+
+		// Require the sidecar to be running and ready.
+		found := false
+		for _, status := range podContainerStatuses(pod) {
+			if status.Name != name {
+				continue
+			}
+			found = true
+			if !status.Ready || status.State.Running == nil {
+				problems = append(problems, fmt.Sprintf("pod: %s node: %s sidecar state: %s", pod.Name, pod.Spec.NodeName, describeContainerState(status)))
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("pod: %s node: %s reports no status for sidecar %s", pod.Name, pod.Spec.NodeName, name))
+		}
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("sidecar containers are not ready: %s", strings.Join(problems, "; "))
+	}
+
+	log.Infoln("Sidecar", name, "is ready in all", len(podList.Items), "check pod(s).")
+	r.timeline.recordf("verified sidecar %s ready in %d pod(s)", name, len(podList.Items))
+	return nil
+}
+
+// hasNativeSidecar reports whether the pod kept the named init container as a restartable sidecar.
+func hasNativeSidecar(pod corev1.Pod, name string) bool {
+	// Look up the init container and its restart policy.
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name != name {
+			continue
+		}
+		return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestSidecarContainerPlacement validates the sidecar lands in the containers or, natively, leads the init containers.
+func TestSidecarContainerPlacement(t *testing.T) {
+	// Add a regular sidecar with a probed port.
+	runner := buildTestRunner()
+	runner.cfg.SidecarImage = "busybox:1.36"
+	runner.cfg.SidecarPort = 9090
+	runner.cfg.InitContainers = []initContainerSpec{{Image: "busybox:1.36"}}
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != runner.cfg.sidecarContainerName() {
+		t.Fatalf("expected the sidecar as the second container but got %v", podSpec.Containers)
+	}
+
+	if podSpec.Containers[1].ReadinessProbe == nil || podSpec.Containers[1].ReadinessProbe.TCPSocket.Port.IntVal != 9090 {
+		t.Fatalf("expected a TCP readiness probe on the sidecar port but got %v", podSpec.Containers[1].ReadinessProbe)
+	}
+
+	// Move the sidecar ahead of the init containers in native mode.
+	runner.cfg.SidecarNative = true
+	pod := corev1.Pod{Spec: runner.createDeploymentConfig("nginx:test").Spec.Template.Spec}
+	if len(pod.Spec.Containers) != 1 || len(pod.Spec.InitContainers) != 2 {
+		t.Fatalf("expected one container and two init containers but got %d and %d", len(pod.Spec.Containers), len(pod.Spec.InitContainers))
+	}
+
+	if !hasNativeSidecar(pod, runner.cfg.sidecarContainerName()) || pod.Spec.InitContainers[0].Name != runner.cfg.sidecarContainerName() {
+		t.Fatalf("expected the native sidecar first in the init containers but got %v", pod.Spec.InitContainers)
+	}
+
+	// Detect a cluster that dropped the restart policy.
+	pod.Spec.InitContainers[0].RestartPolicy = nil
+	if hasNativeSidecar(pod, runner.cfg.sidecarContainerName()) {
+		t.Fatalf("expected a sidecar without a restart policy to not count as native")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// soakProbeInterval is how often the deployment is validated during a soak.
+	soakProbeInterval = time.Second * 15
+	// soakReportLimit caps how many instability events are listed in the failure.
+	soakReportLimit = 10
+)
+
+// soakState tracks what has been observed about the check pods across soak probes.
+type soakState struct {
+	// restarts holds the last seen restart count per pod/container.
+	restarts map[string]int32
+	// pods holds the pods seen so far.
+	pods map[string]bool
+	// notReady holds the pods currently not ready.
+	notReady map[string]bool
+	// failingPorts holds the service ports currently failing.
+	failingPorts map[int32]bool
+	// podEvents counts pod instability events.
+	podEvents int
+	// events lists every instability observed, in order.
+	events []string
+}
+
+// soakDeployment keeps the deployment running for the soak duration while validating it periodically.
+func (r *CheckRunner) soakDeployment(ctx context.Context, serviceIP string) error {
+	// Refuse to start a soak that cannot finish before the check deadline.
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline && time.Until(deadline) < r.cfg.SoakDuration {
+		return fmt.Errorf("soak duration %s exceeds the %s remaining before the check deadline", r.cfg.SoakDuration, time.Until(deadline).Round(time.Second))
+	}
+
+	// Capture the healthy baseline so only changes are reported.
+	state := &soakState{
+		restarts:     make(map[string]int32),
+		pods:         make(map[string]bool),
+		notReady:     make(map[string]bool),
+		failingPorts: make(map[int32]bool),
+		events:       make([]string, 0),
+	}
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods before soaking: %w", err)
+	}
+	for _, pod := range podList.Items {
+		state.pods[pod.Name] = true
+		for _, containerStat := range pod.Status.ContainerStatuses {
+			state.restarts[pod.Name+"/"+containerStat.Name] = containerStat.RestartCount
+		}
+	}
+
+	// Probe until the soak window closes.
+	log.Infoln("Soaking deployment for", r.cfg.SoakDuration, "with validation every", soakProbeInterval)
+	r.timeline.recordf("soak started for %s", r.cfg.SoakDuration)
+	client := &http.Client{Timeout: podProbeTimeout, Transport: r.transport}
+	ticker := time.NewTicker(soakProbeInterval)
+	defer ticker.Stop()
+	soakEnd := time.After(r.cfg.SoakDuration)
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired during soak: %w", ctx.Err())
+		case <-soakEnd:
+			r.timeline.recordf("soak finished with %d instability event(s)", len(state.events))
+			return state.result(r.cfg.SoakDuration)
+		case <-ticker.C:
+		}
+
+		// Validate every service port once.
+		for _, port := range r.cfg.checkPorts() {
+			address := net.JoinHostPort(serviceIP, strconv.Itoa(int(port.ServicePort)))
+			requestErr := r.requestPodOnce(ctx, client, address)
+			if requestErr != nil && !state.failingPorts[port.ServicePort] {
+				state.failingPorts[port.ServicePort] = true
+				r.recordSoakEvent(state, fmt.Sprintf("service port %d request failed: %s", port.ServicePort, requestErr.Error()))
+			}
+			if requestErr == nil {
+				delete(state.failingPorts, port.ServicePort)
+			}
+		}
+
+		// Inspect pod health for restarts, readiness loss, and replacements.
+		podList, err = r.listDeploymentPods(ctx)
+		if err != nil {
+			log.WithError(err).Warnln("Failed to list deployment pods during soak.")
+			continue
+		}
+		r.observePodPhases(podList.Items)
+		r.inspectSoakPods(state, podList.Items)
+	}
+}
+
+// inspectSoakPods compares the current pods with the soak state and records any instability.
+func (r *CheckRunner) inspectSoakPods(state *soakState, pods []corev1.Pod) {
+	// Note pods that were not part of the deployment before.
+	for _, pod := range pods {
+		if !state.pods[pod.Name] {
+			state.pods[pod.Name] = true
+			state.podEvents++
+			r.recordSoakEvent(state, fmt.Sprintf("pod %s appeared on node %s, replacing an earlier pod", pod.Name, pod.Spec.NodeName))
+		}
+
+		// Note readiness transitions.
+		ready := podIsReady(pod)
+		if !ready && !state.notReady[pod.Name] {
+			state.notReady[pod.Name] = true
+			state.podEvents++
+			r.recordSoakEvent(state, fmt.Sprintf("pod %s on node %s lost readiness", pod.Name, pod.Spec.NodeName))
+		}
+		if ready {
+			delete(state.notReady, pod.Name)
+		}
+
+		// Note container restarts since the last probe.
+		for _, containerStat := range pod.Status.ContainerStatuses {
+			key := pod.Name + "/" + containerStat.Name
+			if containerStat.RestartCount > state.restarts[key] {
+				state.podEvents++
+				r.recordSoakEvent(state, fmt.Sprintf("pod %s container %s restarted (%d -> %d): %s",
+					pod.Name,
+					containerStat.Name,
+					state.restarts[key],
+					containerStat.RestartCount,
+					describeContainerState(containerStat),
+				))
+			}
+			state.restarts[key] = containerStat.RestartCount
+		}
+	}
+}
+
+// recordSoakEvent stores an instability event and adds it to the timeline.
+func (r *CheckRunner) recordSoakEvent(state *soakState, message string) {
+	// Keep the event for the report and surface it immediately.
+	state.events = append(state.events, message)
+	log.Warnln("Soak instability:", message)
+	r.timeline.record("soak: " + message)
+}
+
+// result summarizes the soak as an error when any instability was observed.
+func (s *soakState) result(duration time.Duration) error {
+	// A quiet soak is a success.
+	if len(s.events) == 0 {
+		return nil
+	}
+
+	// List the first events and count the rest.
+	listed := s.events
+	if len(listed) > soakReportLimit {
+		listed = listed[:soakReportLimit]
+	}
+	summary := strings.Join(listed, "; ")
+	if len(s.events) > soakReportLimit {
+		summary += fmt.Sprintf("; and %d more", len(s.events)-soakReportLimit)
+	}
+
+	// Pod instability points at the workload; otherwise the service path is at fault.
+	class := failureClassNetworking
+	if s.podEvents > 0 {
+		class = failureClassRollout
+	}
+
+	return classify(class, fmt.Errorf("observed %d instability event(s) during %s soak: %s", len(s.events), duration, summary))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestInspectSoakPods validates that restarts and readiness loss are reported once each.
+func TestInspectSoakPods(t *testing.T) {
+	// Start from a baseline with one healthy pod.
+	runner := buildTestRunner()
+	state := &soakState{
+		restarts:     map[string]int32{"pod-a/web": 0},
+		pods:         map[string]bool{"pod-a": true},
+		notReady:     make(map[string]bool),
+		failingPorts: make(map[int32]bool),
+		events:       make([]string, 0),
+	}
+
+	// Report the pod as restarted and not ready twice in a row.
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-a"},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "web", RestartCount: 1}},
+		},
+	}
+	runner.inspectSoakPods(state, []corev1.Pod{pod})
+	runner.inspectSoakPods(state, []corev1.Pod{pod})
+
+	if len(state.events) != 2 {
+		t.Fatalf("expected 2 soak events but got %d: %v", len(state.events), state.events)
+	}
+
+	if classifyFailure(state.result(0)) != failureClassRollout {
+		t.Fatalf("expected pod instability to classify as rollout")
+	}
+}
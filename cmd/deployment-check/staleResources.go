@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// staleSelectorAge returns how long ago a check selector was stamped, and whether it belongs to a check run at all.
+func staleSelectorAge(selector map[string]string, now time.Time) (time.Duration, bool) {
+	// Only consider resources selecting pods the check created.
+	if selector[sourceLabelKey] != "kuberhealthy" {
+		return 0, false
+	}
+	stamp, found := selector[deploymentLabelKey]
+	if !found || !strings.HasPrefix(stamp, deploymentLabelValueBase) {
+		return 0, false
+	}
+
+	// Parse the unix timestamp the run was labeled with.
+	seconds, err := strconv.ParseInt(strings.TrimPrefix(stamp, deploymentLabelValueBase), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return now.Sub(time.Unix(seconds, 0)), true
+}
+
+// resourceExpired reports whether the expiry annotation on a resource has passed.
+func resourceExpired(annotations map[string]string, key string, now time.Time) bool {
+	// Treat a missing or unreadable stamp as not expired.
+	stamp, found := annotations[key]
+	if !found {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, stamp)
+	if err != nil {
+		return false
+	}
+
+	return !now.Before(expiresAt)
+}
+
+// staleResource reports whether a check resource is past the stale age or its expiry annotation.
+func (r *CheckRunner) staleResource(age time.Duration, annotations map[string]string) bool {
+	// Age only counts when a stale threshold is configured.
+	if r.cfg.StaleResourceAge > 0 && age >= r.cfg.StaleResourceAge {
+		return true
+	}
+
+	return resourceExpired(annotations, r.cfg.ExpiryAnnotation, r.now)
+}
+
+// cleanupStaleResources deletes check deployments and services that are older than the threshold or past their expiry, and every other expired check resource.
+func (r *CheckRunner) cleanupStaleResources(ctx context.Context) {
+	// Match on the selectors so leftovers from any deployment or service name are found.
+	deleted := make([]string, 0)
+	background := metav1.DeletePropagationBackground
+	deleteOpts := metav1.DeleteOptions{PropagationPolicy: &background}
+	deployments, err := r.client.AppsV1().Deployments(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Warnln("Failed to list deployments for stale resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for _, deployment := range deployments.Items {
+			if deployment.Spec.Selector == nil {
+				continue
+			}
+			age, owned := staleSelectorAge(deployment.Spec.Selector.MatchLabels, r.now)
+			if !owned || !r.staleResource(age, deployment.Annotations) {
+				continue
+			}
+			err = r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Delete(ctx, deployment.Name, deleteOpts)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				log.Warnln("Failed to delete stale deployment", deployment.Name+":", err.Error())
+				continue
+			}
+			deleted = append(deleted, fmt.Sprintf("deployment %s (age %s)", deployment.Name, age.Round(time.Second)))
+		}
+	}
+
+	// Services select the same labels as the pods they front.
+	services, err := r.client.CoreV1().Services(r.cfg.CheckNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Warnln("Failed to list services for stale resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for _, service := range services.Items {
+			age, owned := staleSelectorAge(service.Spec.Selector, r.now)
+			if !owned || !r.staleResource(age, service.Annotations) {
+				continue
+			}
+			err = r.client.CoreV1().Services(r.cfg.CheckNamespace).Delete(ctx, service.Name, deleteOpts)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				log.Warnln("Failed to delete stale service", service.Name+":", err.Error())
+				continue
+			}
+			deleted = append(deleted, fmt.Sprintf("service %s (age %s)", service.Name, age.Round(time.Second)))
+		}
+	}
+
+	// The other kinds carry no run selector, so they are matched on their expiry stamp alone.
+	deleted = append(deleted, r.cleanupExpiredResources(ctx)...)
+
+	// Record what was reclaimed.
+	if len(deleted) == 0 {
+		log.Infoln("No stale or expired check resources found.")
+		return
+	}
+	log.Infoln("Deleted stale check resources:", strings.Join(deleted, ", "))
+	r.timeline.record("deleted stale check resources: " + strings.Join(deleted, ", "))
+}
+
+// expiryCandidate is a listed object the expiry sweep may delete.
+type expiryCandidate struct {
+	// kind names the object in logs.
+	kind string
+	// object is the listed object's metadata.
+	object metav1.Object
+	// remove deletes an object of this kind by name.
+	remove func(context.Context, string, metav1.DeleteOptions) error
+}
+
+// cleanupExpiredResources deletes the check's ingresses, autoscalers, disruption budgets, network policies, config maps, claims, prober pods, and custom resources whose expiry has passed.
+func (r *CheckRunner) cleanupExpiredResources(ctx context.Context) []string {
+	// Without an annotation key there is nothing to match on.
+	if len(r.cfg.ExpiryAnnotation) == 0 {
+		return nil
+	}
+
+	// Gather every annotated built-in kind.
+	namespace := r.cfg.CheckNamespace
+	listOpts := metav1.ListOptions{}
+	candidates := make([]expiryCandidate, 0)
+	ingresses, err := r.client.NetworkingV1().Ingresses(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list ingresses for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range ingresses.Items {
+			candidates = append(candidates, expiryCandidate{kind: "ingress", object: &ingresses.Items[i], remove: r.client.NetworkingV1().Ingresses(namespace).Delete})
+		}
+	}
+	autoscalers, err := r.client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list horizontal pod autoscalers for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range autoscalers.Items {
+			candidates = append(candidates, expiryCandidate{kind: "horizontal pod autoscaler", object: &autoscalers.Items[i], remove: r.client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Delete})
+		}
+	}
+	budgets, err := r.client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list pod disruption budgets for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range budgets.Items {
+			candidates = append(candidates, expiryCandidate{kind: "pod disruption budget", object: &budgets.Items[i], remove: r.client.PolicyV1().PodDisruptionBudgets(namespace).Delete})
+		}
+	}
+	policies, err := r.client.NetworkingV1().NetworkPolicies(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list network policies for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range policies.Items {
+			candidates = append(candidates, expiryCandidate{kind: "network policy", object: &policies.Items[i], remove: r.client.NetworkingV1().NetworkPolicies(namespace).Delete})
+		}
+	}
+	configMaps, err := r.client.CoreV1().ConfigMaps(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list config maps for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range configMaps.Items {
+			candidates = append(candidates, expiryCandidate{kind: "config map", object: &configMaps.Items[i], remove: r.client.CoreV1().ConfigMaps(namespace).Delete})
+		}
+	}
+	claims, err := r.client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list persistent volume claims for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range claims.Items {
+			candidates = append(candidates, expiryCandidate{kind: "persistent volume claim", object: &claims.Items[i], remove: r.client.CoreV1().PersistentVolumeClaims(namespace).Delete})
+		}
+	}
+	pods, err := r.client.CoreV1().Pods(namespace).List(ctx, listOpts)
+	if err != nil {
+		log.Warnln("Failed to list pods for expired resource cleanup:", err.Error())
+	}
+	if err == nil {
+		for i := range pods.Items {
+			candidates = append(candidates, expiryCandidate{kind: "pod", object: &pods.Items[i], remove: r.client.CoreV1().Pods(namespace).Delete})
+		}
+	}
+
+	// Gather the custom resources whose APIs the cluster serves.
+	candidates = append(candidates, r.expiredCustomResourceCandidates(ctx)...)
+
+	// Delete the candidates whose expiry has passed.
+	deleted := make([]string, 0)
+	background := metav1.DeletePropagationBackground
+	deleteOpts := metav1.DeleteOptions{PropagationPolicy: &background}
+	for _, candidate := range candidates {
+		if !resourceExpired(candidate.object.GetAnnotations(), r.cfg.ExpiryAnnotation, r.now) {
+			continue
+		}
+		err = candidate.remove(ctx, candidate.object.GetName(), deleteOpts)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			log.Warnln("Failed to delete expired", candidate.kind, candidate.object.GetName()+":", err.Error())
+			continue
+		}
+		deleted = append(deleted, fmt.Sprintf("%s %s (expired)", candidate.kind, candidate.object.GetName()))
+	}
+
+	return deleted
+}
+
+// expiredCustomResourceCandidates lists the HTTPRoutes, OpenShift Routes, and cert-manager Certificates the expiry sweep may delete.
+func (r *CheckRunner) expiredCustomResourceCandidates(ctx context.Context) []expiryCandidate {
+	// Custom resources need the dynamic client.
+	client, err := r.dynamicClient()
+	if err != nil {
+		log.Debugln("Skipping custom resources in expired resource cleanup:", err.Error())
+		return nil
+	}
+
+	// List each kind, skipping APIs the cluster does not serve.
+	kinds := []struct {
+		kind     string
+		resource schema.GroupVersionResource
+	}{
+		{kind: "HTTPRoute", resource: httpRouteResource},
+		{kind: "route", resource: openShiftRouteResource},
+		{kind: "certificate", resource: certificateResource},
+	}
+	candidates := make([]expiryCandidate, 0)
+	for _, kind := range kinds {
+		resources := client.Resource(kind.resource).Namespace(r.cfg.CheckNamespace)
+		list, err := resources.List(ctx, metav1.ListOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			log.Warnln("Failed to list", kind.kind+"s for expired resource cleanup:", err.Error())
+			continue
+		}
+		for i := range list.Items {
+			remove := func(ctx context.Context, name string, options metav1.DeleteOptions) error {
+				return resources.Delete(ctx, name, options)
+			}
+			candidates = append(candidates, expiryCandidate{kind: kind.kind, object: &list.Items[i], remove: remove})
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestStaleSelectorAge validates check selectors are recognized and aged from their timestamp label.
+func TestStaleSelectorAge(t *testing.T) {
+	// Age a selector stamped an hour ago.
+	now := time.Unix(1700003600, 0)
+	age, owned := staleSelectorAge(map[string]string{sourceLabelKey: "kuberhealthy", deploymentLabelKey: deploymentLabelValueBase + "1700000000"}, now)
+	if !owned || age != time.Hour {
+		t.Fatalf("expected an owned selector aged 1h but got %t, %s", owned, age)
+	}
+
+	// Ignore selectors from other sources or without a parseable timestamp.
+	for _, selector := range []map[string]string{
+		{"app": "web"},
+		{sourceLabelKey: "kuberhealthy"},
+		{sourceLabelKey: "other", deploymentLabelKey: deploymentLabelValueBase + "1700000000"},
+		{sourceLabelKey: "kuberhealthy", deploymentLabelKey: "yesterday"},
+	} {
+		_, owned = staleSelectorAge(selector, now)
+		if owned {
+			t.Fatalf("expected %v not to be treated as a check selector", selector)
+		}
+	}
+}
+
+// TestResourceExpired validates expiry stamps are honored and unreadable ones are ignored.
+func TestResourceExpired(t *testing.T) {
+	// Stamp a resource the way the check does and read it back on either side of the expiry.
+	started := time.Unix(1700000000, 0)
+	r := &CheckRunner{cfg: &CheckConfig{ResourceTTL: time.Hour, ExpiryAnnotation: defaultExpiryAnnotation}, now: started}
+	annotations := r.resourceAnnotations(map[string]string{"team": "platform"})
+	if annotations["team"] != "platform" {
+		t.Fatalf("expected base annotations to be kept but got %v", annotations)
+	}
+	if resourceExpired(annotations, defaultExpiryAnnotation, started.Add(time.Minute)) {
+		t.Fatalf("expected %v not to be expired a minute after the run started", annotations)
+	}
+	if !resourceExpired(annotations, defaultExpiryAnnotation, started.Add(time.Hour)) {
+		t.Fatalf("expected %v to be expired once the TTL passed", annotations)
+	}
+
+	// Ignore missing and malformed stamps.
+	for _, annotations := range []map[string]string{
+		nil,
+		{defaultExpiryAnnotation: "tomorrow"},
+		{"janitor/expires": started.Format(time.RFC3339)},
+	} {
+		if resourceExpired(annotations, defaultExpiryAnnotation, started.Add(time.Hour)) {
+			t.Fatalf("expected %v not to be treated as expired", annotations)
+		}
+	}
+}
+
+// TestCleanupExpiredResources validates expired check resources of every kind are deleted and live ones kept.
+func TestCleanupExpiredResources(t *testing.T) {
+	// Seed an expired config map and PDB, a live ingress, and an unstamped network policy.
+	runner := buildTestRunner()
+	runner.now = time.Unix(1700003600, 0)
+	runner.cfg.ExpiryAnnotation = defaultExpiryAnnotation
+	namespace := runner.cfg.CheckNamespace
+	expired := map[string]string{defaultExpiryAnnotation: time.Unix(1700000000, 0).UTC().Format(time.RFC3339)}
+	live := map[string]string{defaultExpiryAnnotation: time.Unix(1700007200, 0).UTC().Format(time.RFC3339)}
+	client := fake.NewClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "old-config", Namespace: namespace, Annotations: expired}},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "old-pdb", Namespace: namespace, Annotations: expired}},
+		&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "live-ingress", Namespace: namespace, Annotations: live}},
+		&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "other-policy", Namespace: namespace}},
+	)
+	runner.client = client
+
+	// Only the expired resources are reported and removed.
+	deleted := runner.cleanupExpiredResources(context.Background())
+	if strings.Join(deleted, ", ") != "pod disruption budget old-pdb (expired), config map old-config (expired)" {
+		t.Fatalf("unexpected deletions %v", deleted)
+	}
+	configMaps, _ := client.CoreV1().ConfigMaps(namespace).List(context.Background(), metav1.ListOptions{})
+	ingresses, _ := client.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{})
+	policies, _ := client.NetworkingV1().NetworkPolicies(namespace).List(context.Background(), metav1.ListOptions{})
+	if len(configMaps.Items) != 0 || len(ingresses.Items) != 1 || len(policies.Items) != 1 {
+		t.Fatalf("expected only the expired resources to be deleted but found %d config map(s), %d ingress(es), %d policy(ies)", len(configMaps.Items), len(ingresses.Items), len(policies.Items))
+	}
+}
+
+// TestParseStaleResourceAge validates the stale age cannot reclaim a run that may still be live.
+func TestParseStaleResourceAge(t *testing.T) {
+	// An age inside the run and its shutdown grace period is rejected.
+	t.Setenv("CHECK_STALE_RESOURCE_AGE", "5m")
+	_, err := parseConfig()
+	if err == nil || !strings.Contains(err.Error(), "CHECK_STALE_RESOURCE_AGE") {
+		t.Fatalf("expected a short stale age to be rejected but got %v", err)
+	}
+
+	// An age past them is accepted.
+	t.Setenv("CHECK_STALE_RESOURCE_AGE", "1h")
+	cfg, err := parseConfig()
+	if err != nil || cfg.StaleResourceAge != time.Hour {
+		t.Fatalf("expected a one hour stale age but got %v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// protocolHTTP validates endpoints with HTTP requests.
+	protocolHTTP = "http"
+	// protocolTCP validates endpoints with TCP connects.
+	protocolTCP = "tcp"
+	// tcpDialTimeout bounds each TCP connect attempt.
+	tcpDialTimeout = time.Second * 5
+	// tcpBannerReadTimeout bounds the wait for a banner after connecting.
+	tcpBannerReadTimeout = time.Second * 5
+	// tcpBannerMaxBytes caps how much of the banner is read.
+	tcpBannerMaxBytes = 1024
+)
+
+// connectEndpoint validates a TCP connect (and banner when configured) to the address with the HTTP retry semantics.
+func (r *CheckRunner) connectEndpoint(ctx context.Context, address string) error {
+	// Log the connect intent.
+	log.Infoln("Looking for a TCP connection to the endpoint.")
+	log.Debugln("Setting timeout for backoff loop to:", r.cfg.RequestRetryTimeout)
+
+	// Bound the backoff loop by time.
+	deadline := time.Now().Add(r.cfg.RequestRetryTimeout)
+	backoff := r.cfg.retryBackoff()
+	attempt := 1
+	lastResult := "no connection"
+
+	for {
+		// Check context cancellation.
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for a TCP connection to %s; last result: %s", address, lastResult)
+		default:
+		}
+
+		// Exit on timeout.
+		if time.Now().After(deadline) {
+			return fmt.Errorf("backoff loop for a TCP connection took too long and timed out; last result: %s", lastResult)
+		}
+
+		// Stop after max retries.
+		if attempt > r.cfg.RequestMaxAttempts {
+			return fmt.Errorf("could not connect over TCP after %d attempts; last result: %s", attempt-1, lastResult)
+		}
+
+		// Attempt the connection.
+		log.Debugln("Connecting over TCP to", address)
+		err := r.connectOnce(ctx, address)
+		if err == nil {
+			log.Infoln("Successfully connected over TCP on attempt:", attempt)
+			r.timeline.recordf("first TCP connection to %s on attempt %d", address, attempt)
+			return nil
+		}
+		lastResult = err.Error()
+		log.Debugln("An error occurred connecting over TCP:", err)
+
+		// Sleep with jittered exponential backoff before retrying.
+		delay := backoff.Step()
+		log.Infoln("Retrying in", delay.Round(time.Millisecond).String()+".")
+		sleepForRetry(ctx, delay)
+		attempt++
+	}
+}
+
+// connectOnce dials the address once and checks the banner when one is expected.
+func (r *CheckRunner) connectOnce(ctx context.Context, address string) error {
+	// Dial with a bounded timeout.
+	dialer := net.Dialer{Timeout: tcpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Stop at the connect when no banner is expected.
+	if len(r.cfg.TCPBanner) == 0 {
+		return nil
+	}
+
+	// Read the banner and look for the expected text.
+	err = conn.SetReadDeadline(time.Now().Add(tcpBannerReadTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to set banner read deadline: %w", err)
+	}
+	buffer := make([]byte, tcpBannerMaxBytes)
+	read, err := conn.Read(buffer)
+	if err != nil {
+		return fmt.Errorf("connected but failed to read a banner: %w", err)
+	}
+	banner := string(buffer[:read])
+	if !strings.Contains(banner, r.cfg.TCPBanner) {
+		return fmt.Errorf("connected but banner %q does not contain %q", compactTerminationMessage(banner), r.cfg.TCPBanner)
+	}
+
+	return nil
+}
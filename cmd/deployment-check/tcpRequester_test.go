@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// TestConnectOnceBanner validates the TCP connect and banner matching.
+func TestConnectOnceBanner(t *testing.T) {
+	// Serve a banner on a local listener.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("+PONG\r\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	// A bare connect succeeds without a banner expectation.
+	runner := buildTestRunner()
+	runner.cfg.Protocol = protocolTCP
+	err = runner.connectOnce(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatalf("expected connect to succeed but got: %v", err)
+	}
+
+	// A matching banner succeeds and a different one fails.
+	runner.cfg.TCPBanner = "PONG"
+	err = runner.connectOnce(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatalf("expected banner match but got: %v", err)
+	}
+	runner.cfg.TCPBanner = "SSH-2.0"
+	err = runner.connectOnce(context.Background(), listener.Addr().String())
+	if err == nil {
+		t.Fatalf("expected banner mismatch to fail")
+	}
+}
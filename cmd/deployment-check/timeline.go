@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// timelineEntry is a single timestamped observation made during a run.
+type timelineEntry struct {
+	// at is when the observation was made.
+	at time.Time
+	// message describes what was observed.
+	message string
+}
+
+// runTimeline records notable observations so failures can be reconstructed.
+type runTimeline struct {
+	// mu guards entries and states across watch and monitor goroutines.
+	mu sync.Mutex
+	// start anchors relative offsets for each entry.
+	start time.Time
+	// entries holds observations in the order they were recorded.
+	entries []timelineEntry
+	// states tracks the last observed state per key to record only transitions.
+	states map[string]string
+}
+
+// newRunTimeline creates an empty timeline anchored at the run start.
+func newRunTimeline(start time.Time) *runTimeline {
+	// Allocate the timeline with an empty state map.
+	return &runTimeline{
+		start:   start,
+		entries: make([]timelineEntry, 0),
+		states:  make(map[string]string),
+	}
+}
+
+// record appends an observation to the timeline.
+func (t *runTimeline) record(message string) {
+	// Tolerate runners built without a timeline.
+	if t == nil {
+		return
+	}
+
+	// Store the entry under lock.
+	t.mu.Lock()
+	t.entries = append(t.entries, timelineEntry{at: time.Now(), message: message})
+	t.mu.Unlock()
+	log.Debugln("Timeline:", message)
+}
+
+// recordf formats and appends an observation to the timeline.
+func (t *runTimeline) recordf(format string, args ...interface{}) {
+	// Format the message before recording.
+	t.record(fmt.Sprintf(format, args...))
+}
+
+// observe records a message only when the state for key differs from the last one seen.
+func (t *runTimeline) observe(key string, state string, message string) {
+	// Tolerate runners built without a timeline.
+	if t == nil {
+		return
+	}
+
+	// Skip unchanged states to keep the timeline compact.
+	t.mu.Lock()
+	previous, seen := t.states[key]
+	if seen && previous == state {
+		t.mu.Unlock()
+		return
+	}
+	t.states[key] = state
+	t.mu.Unlock()
+
+	t.record(message)
+}
+
+// lines renders each entry with its offset from the run start.
+func (t *runTimeline) lines() []string {
+	// Tolerate runners built without a timeline.
+	if t == nil {
+		return nil
+	}
+
+	// Copy entries under lock and format them.
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := make([]string, 0, len(t.entries))
+	for _, entry := range t.entries {
+		offset := entry.at.Sub(t.start).Seconds()
+		lines = append(lines, fmt.Sprintf("[+%.1fs %s] %s", offset, entry.at.UTC().Format(time.RFC3339), entry.message))
+	}
+
+	return lines
+}
+
+// observeDeploymentConditions records deployment condition transitions.
+func (r *CheckRunner) observeDeploymentConditions(deployment *appsv1.Deployment) {
+	// Guard against nil inputs.
+	if deployment == nil {
+		return
+	}
+
+	// Record each condition whose status or reason changed.
+	for _, condition := range deployment.Status.Conditions {
+		key := "deployment-condition/" + string(condition.Type)
+		state := string(condition.Status) + "/" + condition.Reason
+		r.timeline.observe(key, state, fmt.Sprintf("deployment %s condition %s=%s reason=%s (ready %d/%d, updated %d)",
+			deployment.Name,
+			condition.Type,
+			condition.Status,
+			condition.Reason,
+			deployment.Status.ReadyReplicas,
+			deployment.Status.Replicas,
+			deployment.Status.UpdatedReplicas,
+		))
+	}
+}
+
+// observePodPhases records pod phase transitions for the current run.
+func (r *CheckRunner) observePodPhases(pods []corev1.Pod) {
+	// Record a transition whenever a pod changes phase or node.
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if len(nodeName) == 0 {
+			nodeName = "unscheduled"
+		}
+		state := string(pod.Status.Phase) + "/" + nodeName
+		r.timeline.observe("pod-phase/"+pod.Name, state, fmt.Sprintf("pod %s phase %s on node %s", pod.Name, pod.Status.Phase, nodeName))
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRunTimelineObserve validates that only state transitions are recorded.
+func TestRunTimelineObserve(t *testing.T) {
+	// Build a timeline anchored at the current time.
+	timeline := newRunTimeline(time.Now())
+
+	// Observe the same state twice and then a new state.
+	timeline.observe("pod-phase/a", "Pending", "pod a phase Pending")
+	timeline.observe("pod-phase/a", "Pending", "pod a phase Pending")
+	timeline.observe("pod-phase/a", "Running", "pod a phase Running")
+
+	lines := timeline.lines()
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 timeline entries but got %d: %v", len(lines), lines)
+	}
+
+	if !strings.HasSuffix(lines[1], "pod a phase Running") {
+		t.Fatalf("expected last entry to describe the Running transition but got: %s", lines[1])
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// unlabeledZone stands in for nodes without a zone label in zone reports.
+	unlabeledZone = "<no zone label>"
+)
+
+// createTopologySpreadConstraints binds the configured constraints to the check pod labels.
+func (r *CheckRunner) createTopologySpreadConstraints(labels map[string]string) []corev1.TopologySpreadConstraint {
+	// Leave the field unset when nothing is configured.
+	if len(r.cfg.TopologySpreadConstraints) == 0 {
+		return nil
+	}
+
+	// Copy each constraint and select this run's pods.
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(r.cfg.TopologySpreadConstraints))
+	for _, constraint := range r.cfg.TopologySpreadConstraints {
+		constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: copyStringMap(labels)}
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints
+}
+
+// verifyTopologySpread confirms the ready pods are spread across each configured topology key within its max skew.
+func (r *CheckRunner) verifyTopologySpread(ctx context.Context) error {
+	// Gather the nodes hosting ready check pods.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	podNodes := make([]string, 0)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 || pod.DeletionTimestamp != nil || !podIsReady(pod) {
+			continue
+		}
+		podNodes = append(podNodes, pod.Spec.NodeName)
+	}
+
+	// Gather every node so empty domains count toward the skew.
+	nodeList, err := r.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// Check each constraint and report every violation together.
+	violations := make([]string, 0)
+	for _, constraint := range r.cfg.TopologySpreadConstraints {
+		counts, skew, err := topologyDomainCounts(podNodes, nodeList.Items, constraint.TopologyKey, r.cfg.CheckDeploymentNodeSelectors, r.cfg.CheckNodeAffinityRequirements)
+		if err != nil {
+			violations = append(violations, err.Error())
+			continue
+		}
+		description := formatDomainCounts(counts)
+		log.Infoln("Pods spread across", constraint.TopologyKey, "as", description, "with skew", skew)
+		r.timeline.recordf("pods spread across %s as %s (skew %d, max %d)", constraint.TopologyKey, description, skew, constraint.MaxSkew)
+
+		// ScheduleAnyway lets the scheduler exceed the skew, so only DoNotSchedule is enforced.
+		if constraint.WhenUnsatisfiable == corev1.DoNotSchedule && skew > int(constraint.MaxSkew) {
+			violations = append(violations, fmt.Sprintf("skew %d across %s exceeds max skew %d (%s)", skew, constraint.TopologyKey, constraint.MaxSkew, description))
+		}
+	}
+	if len(violations) != 0 {
+		return fmt.Errorf("pods are not spread as configured: %s", strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// verifyZoneDistribution confirms the ready pods span at least the configured number of zones.
+func (r *CheckRunner) verifyZoneDistribution(ctx context.Context) error {
+	// Count ready pods per zone of their node.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods: %w", err)
+	}
+	zones := make(map[string]int)
+	nodeZones := make(map[string]string)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 || pod.DeletionTimestamp != nil || !podIsReady(pod) {
+			continue
+		}
+		zone, found := nodeZones[pod.Spec.NodeName]
+		if !found {
+			node, err := r.client.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get node %s: %w", pod.Spec.NodeName, err)
+			}
+			zone = node.Labels[corev1.LabelTopologyZone]
+			if len(zone) == 0 {
+				zone = unlabeledZone
+			}
+			nodeZones[pod.Spec.NodeName] = zone
+		}
+		zones[zone]++
+	}
+
+	// Fail with the observed zones when too few were used.
+	description := formatDomainCounts(zones)
+	_, unlabeled := zones[unlabeledZone]
+	distinct := len(zones)
+	if unlabeled {
+		distinct--
+	}
+	r.timeline.recordf("ready pods span %d zone(s): %s", distinct, description)
+	if distinct < r.cfg.MinZones {
+		return fmt.Errorf("ready pods span %d zone(s) but at least %d are required; observed pods per zone: %s", distinct, r.cfg.MinZones, description)
+	}
+	log.Infoln("Ready pods span", distinct, "zone(s):", description)
+
+	return nil
+}
+
+// topologyDomainCounts counts pods per topology domain across eligible nodes and returns the resulting skew.
+func topologyDomainCounts(podNodes []string, nodes []corev1.Node, topologyKey string, selectors map[string]string, requirements []corev1.NodeSelectorRequirement) (map[string]int, int, error) {
+	// Seed every eligible domain with zero pods.
+	counts := make(map[string]int)
+	nodeDomains := make(map[string]string)
+	for _, node := range nodes {
+		domain, found := node.Labels[topologyKey]
+		if !found || !nodeEligible(node, selectors, requirements) {
+			continue
+		}
+		_, seen := counts[domain]
+		if !seen {
+			counts[domain] = 0
+		}
+		nodeDomains[node.Name] = domain
+	}
+
+	// Count the pods in the domain of their node.
+	for _, nodeName := range podNodes {
+		domain, found := nodeDomains[nodeName]
+		if !found {
+			return nil, 0, fmt.Errorf("node %s hosting a check pod has no %s label", nodeName, topologyKey)
+		}
+		counts[domain]++
+	}
+	if len(counts) == 0 {
+		return nil, 0, fmt.Errorf("no eligible nodes carry the %s label", topologyKey)
+	}
+
+	// Skew is the gap between the fullest and emptiest domains.
+	minimum := -1
+	maximum := 0
+	for _, count := range counts {
+		if minimum == -1 || count < minimum {
+			minimum = count
+		}
+		if count > maximum {
+			maximum = count
+		}
+	}
+
+	return counts, maximum - minimum, nil
+}
+
+// nodeEligible reports whether a node satisfies the check pod node selectors and required affinity.
+func nodeEligible(node corev1.Node, selectors map[string]string, requirements []corev1.NodeSelectorRequirement) bool {
+	// Match every node selector label.
+	for key, value := range selectors {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+
+	// Match every required affinity expression with its operator.
+	for _, requirement := range requirements {
+		if !nodeRequirementMatches(node.Labels, requirement) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeRequirementMatches reports whether node labels satisfy one node selector requirement, as the scheduler evaluates it.
+func nodeRequirementMatches(nodeLabels map[string]string, requirement corev1.NodeSelectorRequirement) bool {
+	// Map the node selector operator onto its label selector equivalent.
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	operator, known := operators[requirement.Operator]
+	if !known {
+		return false
+	}
+
+	// An invalid requirement matches no node, like the scheduler treats it.
+	selector, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(nodeLabels))
+}
+
+// formatDomainCounts renders domain counts in a stable order.
+func formatDomainCounts(counts map[string]int) string {
+	// Sort the domains for readable reports.
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	parts := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		parts = append(parts, fmt.Sprintf("%s=%d", domain, counts[domain]))
+	}
+
+	return strings.Join(parts, ", ")
+}
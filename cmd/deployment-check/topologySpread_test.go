@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestTopologyDomainCounts validates skew includes empty eligible domains and skips ineligible nodes.
+func TestTopologyDomainCounts(t *testing.T) {
+	// Build three zones with one node in a different pool.
+	zoneKey := "topology.kubernetes.io/zone"
+	nodes := make([]corev1.Node, 0)
+	for _, zone := range []string{"a", "b", "c", "d"} {
+		node := corev1.Node{}
+		node.Name = "node-" + zone
+		node.Labels = map[string]string{zoneKey: zone, "pool": "check"}
+		nodes = append(nodes, node)
+	}
+	nodes[3].Labels["pool"] = "other"
+	selectors := map[string]string{"pool": "check"}
+
+	// Two pods in one zone leave an empty zone, for a skew of two.
+	counts, skew, err := topologyDomainCounts([]string{"node-a", "node-a", "node-b"}, nodes, zoneKey, selectors, nil)
+	if err != nil {
+		t.Fatalf("unexpected error counting domains: %v", err)
+	}
+	if len(counts) != 3 || counts["c"] != 0 || skew != 2 {
+		t.Fatalf("expected three eligible zones with skew 2 but got %v with skew %d", counts, skew)
+	}
+
+	// One pod per zone is perfectly spread.
+	_, skew, err = topologyDomainCounts([]string{"node-a", "node-b", "node-c"}, nodes, zoneKey, selectors, nil)
+	if err != nil || skew != 0 {
+		t.Fatalf("expected skew 0 but got %d, %v", skew, err)
+	}
+
+	// A pod on a node without the key is reported.
+	_, _, err = topologyDomainCounts([]string{"node-d"}, nodes, zoneKey, selectors, nil)
+	if err == nil {
+		t.Fatalf("expected an error for a pod on an ineligible node")
+	}
+}
+
+// TestNodeRequirementMatches validates every node selector operator is evaluated as the scheduler does.
+func TestNodeRequirementMatches(t *testing.T) {
+	nodeLabels := map[string]string{"pool": "check", "cpus": "8"}
+	cases := []struct {
+		requirement corev1.NodeSelectorRequirement
+		expected    bool
+	}{
+		{corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"check", "other"}}, true},
+		{corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"check"}}, false},
+		{corev1.NodeSelectorRequirement{Key: "spot", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}}, true},
+		{corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpExists}, true},
+		{corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpDoesNotExist}, false},
+		{corev1.NodeSelectorRequirement{Key: "cpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}}, true},
+		{corev1.NodeSelectorRequirement{Key: "cpus", Operator: corev1.NodeSelectorOpLt, Values: []string{"4"}}, false},
+		{corev1.NodeSelectorRequirement{Key: "cpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"many"}}, false},
+	}
+	for _, c := range cases {
+		if nodeRequirementMatches(nodeLabels, c.requirement) != c.expected {
+			t.Fatalf("expected %s %s %v to be %t", c.requirement.Key, c.requirement.Operator, c.requirement.Values, c.expected)
+		}
+	}
+}
+
+// TestVerifyTopologySpreadEnforcesDoNotSchedule validates only DoNotSchedule constraints fail on skew.
+func TestVerifyTopologySpreadEnforcesDoNotSchedule(t *testing.T) {
+	// Put both ready pods in one of two zones.
+	zoneKey := "topology.kubernetes.io/zone"
+	runner := buildTestRunner()
+	objects := make([]runtime.Object, 0)
+	for _, zone := range []string{"a", "b"} {
+		objects = append(objects, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + zone, Labels: map[string]string{zoneKey: zone}}})
+	}
+	for _, name := range []string{"first", "second"} {
+		pod := probeTestPod(runner, name, true)
+		pod.Spec.NodeName = "node-a"
+		objects = append(objects, pod)
+	}
+	runner.client = fake.NewClientset(objects...)
+
+	// ScheduleAnyway tolerates the skew.
+	runner.cfg.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{TopologyKey: zoneKey, MaxSkew: 1, WhenUnsatisfiable: corev1.ScheduleAnyway}}
+	err := runner.verifyTopologySpread(context.Background())
+	if err != nil {
+		t.Fatalf("expected ScheduleAnyway skew to be tolerated but got %v", err)
+	}
+
+	// DoNotSchedule fails on it.
+	runner.cfg.TopologySpreadConstraints[0].WhenUnsatisfiable = corev1.DoNotSchedule
+	err = runner.verifyTopologySpread(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "skew 2") {
+		t.Fatalf("expected a DoNotSchedule skew violation but got %v", err)
+	}
+}
+
+// TestVerifyZoneDistribution validates ready pods must span the minimum zones and unlabeled nodes do not count as a zone.
+func TestVerifyZoneDistribution(t *testing.T) {
+	// Place one ready pod in zone a and one on a node without a zone label.
+	runner := buildTestRunner()
+	runner.cfg.MinZones = 2
+	nodeA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{corev1.LabelTopologyZone: "a"}}}
+	nodeB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{corev1.LabelTopologyZone: "b"}}}
+	unlabeled := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-unlabeled"}}
+	first := probeTestPod(runner, "first", true)
+	first.Spec.NodeName = "node-a"
+	second := probeTestPod(runner, "second", true)
+	second.Spec.NodeName = "node-unlabeled"
+	runner.client = fake.NewClientset(nodeA, nodeB, unlabeled, first, second)
+
+	err := runner.verifyZoneDistribution(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ready pods span 1 zone(s) but at least 2 are required") || !strings.Contains(err.Error(), unlabeledZone) {
+		t.Fatalf("expected a zone shortfall listing the unlabeled node but got %v", err)
+	}
+
+	// Pods in two zones pass.
+	second.Spec.NodeName = "node-b"
+	runner.client = fake.NewClientset(nodeA, nodeB, unlabeled, first, second)
+	err = runner.verifyZoneDistribution(context.Background())
+	if err != nil {
+		t.Fatalf("expected two zones to satisfy the minimum but got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// version is the release, set at build time with -ldflags "-X main.version=...".
+	version = "dev"
+	// commit is the source revision, set at build time with -ldflags "-X main.commit=...".
+	commit = ""
+	// buildDate is when the binary was built, set at build time with -ldflags "-X main.buildDate=...".
+	buildDate = ""
+)
+
+// versionString describes the build so a failure can be traced to the binary that produced it.
+func versionString() string {
+	// Fall back to the VCS stamp Go embeds when ldflags were not set.
+	revision := commit
+	built := buildDate
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && len(revision) == 0 {
+				revision = setting.Value
+			}
+			if setting.Key == "vcs.time" && len(built) == 0 {
+				built = setting.Value
+			}
+		}
+	}
+	if len(revision) == 0 {
+		revision = "unknown"
+	}
+	if len(built) == 0 {
+		built = "unknown"
+	}
+
+	return fmt.Sprintf("deployment-check %s (commit %s, built %s, %s)", version, revision, built, runtime.Version())
+}
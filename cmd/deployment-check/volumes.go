@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// emptyDirVolumeName names the scratch emptyDir volume.
+	emptyDirVolumeName = "check-scratch"
+	// configMapVolumeName names the generated ConfigMap volume.
+	configMapVolumeName = "check-config"
+	// secretVolumeName names the existing Secret volume.
+	secretVolumeName = "check-secret"
+	// configMapDataKey is the file written into the generated ConfigMap.
+	configMapDataKey = "run"
+	// writeProbeFile is written to writable volumes to prove they accept writes.
+	writeProbeFile = ".deployment-check"
+	// volumeExecTimeout bounds each in-pod volume command.
+	volumeExecTimeout = time.Second * 15
+)
+
+// checkConfigMapName returns the name of the ConfigMap generated for the check pods.
+func (cfg *CheckConfig) checkConfigMapName() string {
+	// Derive the name from the deployment so both are cleaned up together.
+	return cfg.CheckDeploymentName + "-config"
+}
+
+// volumesConfigured reports whether any check volume is mounted.
+func (cfg *CheckConfig) volumesConfigured() bool {
+	return len(cfg.EmptyDirMountPath) != 0 || len(cfg.ConfigMapMountPath) != 0 || len(cfg.SecretName) != 0 || len(cfg.PVCStorageClass) != 0
+}
+
+// createCheckVolumes builds the configured volumes and the check container mounts for them.
+func (r *CheckRunner) createCheckVolumes() ([]corev1.Volume, []corev1.VolumeMount) {
+	// Collect each configured volume with its mount.
+	volumes := make([]corev1.Volume, 0)
+	mounts := make([]corev1.VolumeMount, 0)
+	if len(r.cfg.EmptyDirMountPath) != 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name:         emptyDirVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: emptyDirVolumeName, MountPath: r.cfg.EmptyDirMountPath})
+	}
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: configMapVolumeName,
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: r.cfg.checkConfigMapName()},
+			}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: configMapVolumeName, MountPath: r.cfg.ConfigMapMountPath, ReadOnly: true})
+	}
+	if len(r.cfg.SecretName) != 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name:         secretVolumeName,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: r.cfg.SecretName}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: secretVolumeName, MountPath: r.cfg.SecretMountPath, ReadOnly: true})
+	}
+	if len(r.cfg.PVCStorageClass) != 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: pvcVolumeName,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: r.cfg.checkPVCName(),
+			}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: pvcVolumeName, MountPath: r.cfg.PVCMountPath})
+	}
+	if len(r.cfg.ProjectedTokenAudience) != 0 {
+		volume, mount := r.createProjectedTokenVolume()
+		volumes = append(volumes, volume)
+		mounts = append(mounts, mount)
+	}
+	if len(r.cfg.CertManagerIssuer) != 0 {
+		volume, mount := r.createCertificateVolume()
+		volumes = append(volumes, volume)
+		mounts = append(mounts, mount)
+	}
+
+	return volumes, mounts
+}
+
+// createConfigMapConfig builds the ConfigMap mounted into the check pods, carrying the run label value.
+func (r *CheckRunner) createConfigMapConfig() *corev1.ConfigMap {
+	// Store the run label value so the pods can prove they see this run's data.
+	configMap := &corev1.ConfigMap{
+		Data: map[string]string{configMapDataKey: r.configMapRunValue()},
+	}
+	configMap.Name = r.cfg.checkConfigMapName()
+	configMap.Namespace = r.cfg.CheckNamespace
+	configMap.Labels = copyStringMap(r.cfg.ExtraLabels)
+	configMap.Annotations = r.resourceAnnotations(nil)
+	configMap.OwnerReferences = r.ownerReferences()
+
+	return configMap
+}
+
+// configMapRunValue returns the value stored in the generated ConfigMap for this run.
+func (r *CheckRunner) configMapRunValue() string {
+	return deploymentLabelValueBase + fmt.Sprint(r.now.Unix())
+}
+
+// createConfigMap creates the ConfigMap the check pods mount.
+func (r *CheckRunner) createConfigMap(ctx context.Context) error {
+	// Create the ConfigMap before the deployment so the pods never wait on a missing volume.
+	_, err := r.client.CoreV1().ConfigMaps(r.cfg.CheckNamespace).Create(ctx, r.createConfigMapConfig(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create configmap: %w", err)
+	}
+	log.Infoln("Created configmap", r.cfg.checkConfigMapName(), "in", r.cfg.CheckNamespace, "namespace.")
+	r.timeline.recordf("created configmap %s", r.cfg.checkConfigMapName())
+
+	return nil
+}
+
+// verifyVolumes execs into each running check pod to confirm the mounted volumes are usable.
+func (r *CheckRunner) verifyVolumes(ctx context.Context) error {
+	// List pods for the current deployment run.
+	podList, err := r.listDeploymentPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment pods for volume verification: %w", err)
+	}
+
+	// Build the commands that prove each volume works.
+	commands := make([][]string, 0)
+	if len(r.cfg.EmptyDirMountPath) != 0 {
+		commands = append(commands, []string{"touch", path.Join(r.cfg.EmptyDirMountPath, writeProbeFile)})
+	}
+	if len(r.cfg.ConfigMapMountPath) != 0 {
+		commands = append(commands, []string{"cat", path.Join(r.cfg.ConfigMapMountPath, configMapDataKey)})
+	}
+	if len(r.cfg.SecretName) != 0 {
+		commands = append(commands, []string{"ls", r.cfg.SecretMountPath})
+	}
+	if len(r.cfg.PVCStorageClass) != 0 {
+		commands = append(commands, []string{"touch", path.Join(r.cfg.PVCMountPath, writeProbeFile)})
+	}
+
+	// Run every command in every running pod and report each failure.
+	failures := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, command := range commands {
+			execCtx, cancel := context.WithTimeout(ctx, volumeExecTimeout)
+			stdout, stderr, execErr := r.execInPod(execCtx, pod, r.cfg.CheckContainerName, command)
+			cancel()
+			if execErr != nil {
+				detail := strings.TrimSpace(stderr)
+				if len(detail) == 0 {
+					detail = execErr.Error()
+				}
+				failures = append(failures, fmt.Sprintf("pod: %s node: %s command: %s error: %s", pod.Name, pod.Spec.NodeName, strings.Join(command, " "), detail))
+				continue
+			}
+
+			// The ConfigMap must hold this run's data, not a stale copy.
+			if command[0] == "cat" && strings.TrimSpace(stdout) != r.configMapRunValue() {
+				failures = append(failures, fmt.Sprintf("pod: %s node: %s configmap file holds %q instead of %q", pod.Name, pod.Spec.NodeName, strings.TrimSpace(stdout), r.configMapRunValue()))
+			}
+		}
+	}
+	if len(failures) != 0 {
+		return fmt.Errorf("check pod volumes are not usable: %s", strings.Join(failures, "; "))
+	}
+
+	log.Infoln("Verified the mounted volumes in", len(podList.Items), "check pod(s).")
+	r.timeline.recordf("verified mounted volumes in %d pod(s)", len(podList.Items))
+	return nil
+}
+
+// deleteConfigMapAndWait removes the generated ConfigMap and waits for it to disappear.
+func (r *CheckRunner) deleteConfigMapAndWait(ctx context.Context) error {
+	return deleteAndWait(ctx, "configmap", func(ctx context.Context) error {
+		return r.client.CoreV1().ConfigMaps(r.cfg.CheckNamespace).Delete(ctx, r.cfg.checkConfigMapName(), metav1.DeleteOptions{})
+	}, r.configMapExists)
+}
+
+// configMapExists reports whether the generated ConfigMap is present.
+func (r *CheckRunner) configMapExists(ctx context.Context) (bool, error) {
+	_, err := r.client.CoreV1().ConfigMaps(r.cfg.CheckNamespace).Get(ctx, r.cfg.checkConfigMapName(), metav1.GetOptions{})
+	return objectFound(err)
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+// TestCheckVolumes validates the configured volumes are added to the pod and mounted in the check container.
+func TestCheckVolumes(t *testing.T) {
+	// Mount nothing extra by default.
+	runner := buildTestRunner()
+	podSpec := runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	if len(podSpec.Volumes) != 0 || len(podSpec.Containers[0].VolumeMounts) != 0 {
+		t.Fatalf("expected no volumes by default but got %v", podSpec.Volumes)
+	}
+
+	// Mount all three volume kinds.
+	runner.cfg.EmptyDirMountPath = "/scratch"
+	runner.cfg.ConfigMapMountPath = "/etc/check"
+	runner.cfg.SecretName = "check-secret"
+	runner.cfg.SecretMountPath = "/etc/secret"
+	podSpec = runner.createDeploymentConfig("nginx:test").Spec.Template.Spec
+	if len(podSpec.Volumes) != 3 || len(podSpec.Containers[0].VolumeMounts) != 3 {
+		t.Fatalf("expected three volumes and mounts but got %v and %v", podSpec.Volumes, podSpec.Containers[0].VolumeMounts)
+	}
+
+	if podSpec.Volumes[1].ConfigMap == nil || podSpec.Volumes[1].ConfigMap.Name != runner.cfg.checkConfigMapName() {
+		t.Fatalf("expected the generated configmap volume but got %v", podSpec.Volumes[1])
+	}
+
+	if podSpec.Volumes[2].Secret == nil || podSpec.Volumes[2].Secret.SecretName != "check-secret" || !podSpec.Containers[0].VolumeMounts[2].ReadOnly {
+		t.Fatalf("expected a read-only secret mount but got %v", podSpec.Volumes[2])
+	}
+
+	// The generated ConfigMap carries this run's value.
+	configMap := runner.createConfigMapConfig()
+	if configMap.Data[configMapDataKey] != runner.configMapRunValue() {
+		t.Fatalf("expected the run value in the configmap but got %v", configMap.Data)
+	}
+}
+
+// TestValidateMountPath validates mount paths must be clean, absolute, and unique.
+func TestValidateMountPath(t *testing.T) {
+	// Accept a fresh absolute path.
+	used := map[string]string{scratchVolumeMountPath: "the scratch volume"}
+	err := validateMountPath("CHECK_EMPTYDIR_MOUNT_PATH", "/scratch", used)
+	if err != nil {
+		t.Fatalf("unexpected error validating mount path: %v", err)
+	}
+
+	// Reject relative, unclean, root, and reused paths.
+	for _, mountPath := range []string{"scratch", "/etc/../tmp", "/", "/tmp", "/scratch"} {
+		err = validateMountPath("CHECK_CONFIGMAP_MOUNT_PATH", mountPath, used)
+		if err == nil {
+			t.Fatalf("expected an error for mount path %q", mountPath)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// watchListOptions builds options for watching a single named object, bounded by the configured watch timeout.
+func (r *CheckRunner) watchListOptions(name string, resourceVersion string) metav1.ListOptions {
+	// Have the API server end the watch so a silently dead connection cannot hang a wait.
+	timeoutSeconds := int64(r.cfg.WatchTimeout.Seconds())
+	return metav1.ListOptions{
+		Watch:               true,
+		FieldSelector:       "metadata.name=" + name,
+		ResourceVersion:     resourceVersion,
+		TimeoutSeconds:      &timeoutSeconds,
+		AllowWatchBookmarks: true,
+	}
+}
+
+// watchDeployment opens a resumable watch on the named deployment.
+func (r *CheckRunner) watchDeployment(ctx context.Context, name string, resourceVersion string) (watch.Interface, error) {
+	return newResumableWatch(ctx, "deployment", resourceVersion, r.cfg.retryBackoff(), func(resourceVersion string) (watch.Interface, error) {
+		return r.client.AppsV1().Deployments(r.cfg.CheckNamespace).Watch(ctx, r.watchListOptions(name, resourceVersion))
+	})
+}
+
+// watchService opens a resumable watch on the named service.
+func (r *CheckRunner) watchService(ctx context.Context, name string, resourceVersion string) (watch.Interface, error) {
+	return newResumableWatch(ctx, "service", resourceVersion, r.cfg.retryBackoff(), func(resourceVersion string) (watch.Interface, error) {
+		return r.client.CoreV1().Services(r.cfg.CheckNamespace).Watch(ctx, r.watchListOptions(name, resourceVersion))
+	})
+}
+
+// resumableWatch relays events from a server watch, re-opening it from the last observed resource version whenever it drops.
+type resumableWatch struct {
+	// result carries relayed events and closes only once the watch is stopped or its context ends.
+	result chan watch.Event
+	// cancel stops the relay.
+	cancel context.CancelFunc
+}
+
+// newResumableWatch opens a watch from resourceVersion that survives the API server closing it.
+func newResumableWatch(ctx context.Context, kind string, resourceVersion string, backoff wait.Backoff, open func(resourceVersion string) (watch.Interface, error)) (watch.Interface, error) {
+	// Open the first watch directly so setup errors reach the caller.
+	watcher, err := open(resourceVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relay events in the background until stopped.
+	relayCtx, cancel := context.WithCancel(ctx)
+	w := &resumableWatch{
+		result: make(chan watch.Event),
+		cancel: cancel,
+	}
+	go w.relay(relayCtx, kind, resourceVersion, backoff, watcher, open)
+
+	return w, nil
+}
+
+// ResultChan returns the relayed events.
+func (w *resumableWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// Stop ends the relay and the underlying watch.
+func (w *resumableWatch) Stop() {
+	w.cancel()
+}
+
+// relay forwards events and re-opens the watch after each drop until ctx ends.
+func (w *resumableWatch) relay(ctx context.Context, kind string, resourceVersion string, backoff wait.Backoff, watcher watch.Interface, open func(resourceVersion string) (watch.Interface, error)) {
+	defer close(w.result)
+	for {
+		lastVersion, expired := w.forward(ctx, watcher)
+		watcher.Stop()
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Resume after the last delivered event, or start over from the current state once that version is compacted away.
+		if len(lastVersion) != 0 {
+			resourceVersion = lastVersion
+		}
+		if expired {
+			log.Debugln("The", kind, "watch resource version", resourceVersion, "expired; restarting from the current state.")
+			resourceVersion = ""
+		}
+		watcher = reopenWatch(ctx, kind, backoff, func() (watch.Interface, error) {
+			return open(resourceVersion)
+		})
+	}
+}
+
+// forward relays events from one watch until it closes, returning the last resource version seen and whether it expired.
+func (w *resumableWatch) forward(ctx context.Context, watcher watch.Interface) (string, bool) {
+	lastVersion := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return lastVersion, false
+		case event, open := <-watcher.ResultChan():
+			if !open {
+				return lastVersion, false
+			}
+
+			// Error events end the watch; an expired version needs a fresh start.
+			if event.Type == watch.Error {
+				err := k8serrors.FromObject(event.Object)
+				log.Debugln("Watch returned an error event:", err.Error())
+				return lastVersion, k8serrors.IsResourceExpired(err) || k8serrors.IsGone(err)
+			}
+
+			// Track the resource version so a re-opened watch misses nothing.
+			accessor, err := meta.Accessor(event.Object)
+			if err == nil && len(accessor.GetResourceVersion()) != 0 {
+				lastVersion = accessor.GetResourceVersion()
+			}
+
+			// Bookmarks only advance the resource version.
+			if event.Type == watch.Bookmark {
+				continue
+			}
+
+			select {
+			case w.result <- event:
+			case <-ctx.Done():
+				return lastVersion, false
+			}
+		}
+	}
+}
+
+// reopenWatch re-establishes an expired watch, retrying with backoff until it succeeds or ctx ends.
+func reopenWatch(ctx context.Context, kind string, backoff wait.Backoff, open func() (watch.Interface, error)) watch.Interface {
+	log.Debugln("The", kind, "watch expired; re-establishing it.")
+	for {
+		watcher, err := open()
+		if err == nil {
+			return watcher
+		}
+		log.Warnln("Failed to re-establish the", kind, "watch:", err.Error())
+
+		// Hand back a watch that never fires once the context ends so callers fall through to ctx.Done.
+		select {
+		case <-ctx.Done():
+			return watch.NewFake()
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
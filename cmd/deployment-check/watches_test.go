@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// TestWatchListOptions validates watches are scoped to one object and bounded by the configured watch timeout.
+func TestWatchListOptions(t *testing.T) {
+	// Configure a short watch timeout.
+	runner := buildTestRunner()
+	runner.cfg.WatchTimeout = time.Second * 90
+	options := runner.watchListOptions("deployment-deployment", "42")
+	if options.TimeoutSeconds == nil || *options.TimeoutSeconds != 90 {
+		t.Fatalf("expected a 90 second watch timeout but got %v", options.TimeoutSeconds)
+	}
+	if !options.Watch || options.FieldSelector != "metadata.name=deployment-deployment" || options.ResourceVersion != "42" {
+		t.Fatalf("expected a watch on deployment-deployment from version 42 but got %+v", options)
+	}
+
+	// Sub-second watch timeouts are rejected since the API server counts in seconds.
+	t.Setenv("CHECK_WATCH_TIMEOUT", "500ms")
+	_, err := parseConfig()
+	if err == nil || !strings.Contains(err.Error(), "CHECK_WATCH_TIMEOUT") {
+		t.Fatalf("expected a CHECK_WATCH_TIMEOUT error but got %v", err)
+	}
+}
+
+// TestResumableWatch validates dropped watches resume from the last seen version and expired ones start over.
+func TestResumableWatch(t *testing.T) {
+	// Hand out a fresh fake watch for every open and record the requested versions.
+	opened := make(chan string, 3)
+	fakes := make(chan *watch.FakeWatcher, 3)
+	open := func(resourceVersion string) (watch.Interface, error) {
+		fake := watch.NewFake()
+		opened <- resourceVersion
+		fakes <- fake
+		return fake, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	watcher, err := newResumableWatch(ctx, "deployment", "10", wait.Backoff{Duration: time.Millisecond}, open)
+	if err != nil {
+		t.Fatalf("failed to open resumable watch: %v", err)
+	}
+	defer watcher.Stop()
+
+	// Deliver an event, then drop the watch as the API server would.
+	if version := <-opened; version != "10" {
+		t.Fatalf("expected the first watch from version 10 but got %q", version)
+	}
+	first := <-fakes
+	deployment := &appsv1.Deployment{}
+	deployment.ResourceVersion = "12"
+	go first.Modify(deployment)
+	event := <-watcher.ResultChan()
+	if event.Type != watch.Modified {
+		t.Fatalf("expected a relayed modified event but got %s", event.Type)
+	}
+	first.Stop()
+	if version := <-opened; version != "12" {
+		t.Fatalf("expected the watch to resume from version 12 but got %q", version)
+	}
+
+	// An expired version restarts from the current state.
+	second := <-fakes
+	go second.Error(&k8serrors.NewResourceExpired("too old resource version").ErrStatus)
+	if version := <-opened; len(version) != 0 {
+		t.Fatalf("expected an expired watch to restart without a version but got %q", version)
+	}
+
+	// Stopping closes the relayed channel.
+	watcher.Stop()
+	for range watcher.ResultChan() {
+	}
+}
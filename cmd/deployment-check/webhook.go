@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// failureWebhookTimeout bounds the failure webhook request.
+	failureWebhookTimeout = time.Second * 10
+)
+
+// failureWebhookPayload is the JSON body posted to the failure webhook.
+type failureWebhookPayload struct {
+	// RunID identifies the Kuberhealthy run, or the check run label when unavailable.
+	RunID string `json:"run_id"`
+	// Namespace is where the check resources were created.
+	Namespace string `json:"namespace"`
+	// Deployment is the check deployment name.
+	Deployment string `json:"deployment"`
+	// Stage is the phase the run failed in.
+	Stage string `json:"stage"`
+	// FailureClass is the coarse failure category used for routing.
+	FailureClass string `json:"failure_class"`
+	// Error is the full failure message.
+	Error string `json:"error"`
+	// PodSummary describes the check pods at the time of failure.
+	PodSummary string `json:"pod_summary"`
+}
+
+// notifyFailureWebhook posts the failure payload to the webhook, logging rather than failing on errors.
+func (r *CheckRunner) notifyFailureWebhook(runErr error) {
+	// Use a fresh context so an expired check deadline still allows the notification.
+	payload := r.failureWebhookPayload(context.Background(), runErr)
+	err := postFailureWebhook(context.Background(), r.cfg.FailureWebhookURL, payload)
+	if err != nil {
+		log.Warnln("Failed to notify the failure webhook:", err.Error())
+	}
+}
+
+// failureWebhookPayload assembles the webhook body for a failed run.
+func (r *CheckRunner) failureWebhookPayload(ctx context.Context, runErr error) failureWebhookPayload {
+	// Prefer the Kuberhealthy run UUID so notifications match the check status.
+	runID := os.Getenv("KH_RUN_UUID")
+	if len(runID) == 0 {
+		runID = deploymentLabelValueBase + fmt.Sprint(r.now.Unix())
+	}
+
+	// Reuse the pod summary captured at failure time since cleanup removes the pods.
+	podSummary := r.podSummary
+	if len(podSummary) == 0 {
+		podSummary = r.deploymentPodSummary(ctx)
+	}
+
+	return failureWebhookPayload{
+		RunID:        runID,
+		Namespace:    r.cfg.CheckNamespace,
+		Deployment:   r.cfg.CheckDeploymentName,
+		Stage:        r.phases.failedPhase(),
+		FailureClass: string(classifyFailure(runErr)),
+		Error:        runErr.Error(),
+		PodSummary:   podSummary,
+	}
+}
+
+// postFailureWebhook sends the payload as JSON and requires a success status.
+func postFailureWebhook(ctx context.Context, webhookURL string, payload failureWebhookPayload) error {
+	// Encode and build the request.
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode failure webhook payload: %w", err)
+	}
+	postCtx, cancel := context.WithTimeout(ctx, failureWebhookTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(postCtx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build failure webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	// Post and require a success status.
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post failure webhook: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("failed to post failure webhook: unexpected status %d", response.StatusCode)
+	}
+	log.Infoln("Notified the failure webhook.")
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPostFailureWebhook validates the JSON payload and status handling.
+func TestPostFailureWebhook(t *testing.T) {
+	// Capture the posted payload.
+	var received failureWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err := json.NewDecoder(req.Body).Decode(&received)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Build the payload from a runner that failed service validation.
+	runner := buildTestRunner()
+	runner.podSummary = "pod-a: phase=Running ready=false"
+	runner.phases.begin("service_validate")
+	runner.phases.begin("cleanup")
+	payload := runner.failureWebhookPayload(context.Background(), classify(failureClassNetworking, errors.New("service request failed")))
+	err := postFailureWebhook(context.Background(), server.URL, payload)
+	if err != nil {
+		t.Fatalf("unexpected error posting the webhook: %v", err)
+	}
+
+	if received.Stage != "service_validate" || received.FailureClass != string(failureClassNetworking) || received.PodSummary != runner.podSummary || len(received.RunID) == 0 {
+		t.Fatalf("unexpected payload: %+v", received)
+	}
+
+	// Reject non-success statuses.
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err = postFailureWebhook(context.Background(), failing.URL, payload)
+	if err == nil {
+		t.Fatalf("expected an error for a failing webhook")
+	}
+}